@@ -512,6 +512,34 @@ teardown() {
     [[ "${lines[2]}" =~ "line only has 1 value" ]] || false
 }
 
+@test "import data with --continue writes rejected rows and an error report" {
+    cat <<DELIM > bad-rows.csv
+pk,c1,c2,c3,c4,c5
+0,1,2,3,4,5
+1,one,2,3,4,5
+,1,2,3,4,5
+3,1,2,3,4,5
+DELIM
+    run dolt table import test -u --continue --rejects rejects.csv --error-report errors.json bad-rows.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2 rows could not be imported." ]] || false
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt table select test
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 6 ]
+    run cat rejects.csv
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [ "${lines[0]}" = "pk,c1,c2,c3,c4,c5" ]
+    [[ "$output" =~ "1,one,2,3,4,5" ]] || false
+    [[ "$output" =~ ",1,2,3,4,5" ]] || false
+    run cat errors.json
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [[ "$output" =~ '"line":3,"column":"c1","reason":"type conversion"' ]] || false
+    [[ "$output" =~ '"line":4,"column":"pk","reason":"missing primary key"' ]] || false
+}
+
 @test "import data from a psv file after table created" {
     run dolt table import test -u  `batshelper 1pk5col-ints.psv`
     [ "$status" -eq 0 ]
@@ -522,6 +550,39 @@ teardown() {
     [ "${#lines[@]}" -eq 6 ]
 }
 
+@test "replace a table with the contents of a file" {
+    dolt table import test -u `batshelper 1pk5col-ints.csv`
+    dolt table put-row test pk:2 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added rows"
+    run dolt schema test
+    schema=$output
+    run dolt table import -r test `batshelper 1pk5col-ints.csv`
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt table select test
+    [ "$status" -eq 0 ]
+    # Number of lines offset by 3 for table printing style
+    [ "${#lines[@]}" -eq 6 ]
+    run dolt schema test
+    [ "$output" = "$schema" ]
+    run dolt diff
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ -[[:space:]]+\|[[:space:]]+2[[:space:]]+\| ]] || false
+}
+
+@test "replace a table that doesn't exist" {
+    run dolt table import -r nonexistent `batshelper 1pk5col-ints.csv`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Table nonexistent does not exist." ]] || false
+}
+
+@test "import with more than one of create, update and replace" {
+    run dolt table import -u -r test `batshelper 1pk5col-ints.csv`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "can't be used together" ]] || false
+}
+
 @test "overwrite a row. make sure it updates not inserts" {
     dolt table import test -u `batshelper 1pk5col-ints.csv`
     run dolt table put-row test pk:1 c1:2 c2:4 c3:6 c4:8 c5:10
@@ -582,6 +643,26 @@ teardown() {
     diff --strip-trailing-cr $BATS_TEST_DIRNAME/helper/1pk5col-ints.sql export.sql
 }
 
+@test "dolt table parquet export and import" {
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt table put-row test pk:1 c1:6
+    run dolt table export test export.parquet
+    [ "$status" -eq 0 ]
+    [ "$output" = "Successfully exported data." ]
+    [ -f export.parquet ]
+    run dolt table import -c imported export.parquet
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt schema imported
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "\`pk\` int not null comment 'tag:0'" ]] || false
+    [[ "$output" =~ "primary key (\`pk\`)" ]] || false
+    run dolt table select imported
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "6" ]] || false
+    [[ "$output" =~ "<NULL>" ]] || false
+}
+
 @test "dolt schema" {
     run dolt schema
     [ "$status" -eq 0 ]
@@ -0,0 +1,145 @@
+#!/usr/bin/env bats
+
+setup() {
+    load $BATS_TEST_DIRNAME/helper/common.bash
+    export PATH=$PATH:~/go/bin
+    export NOMS_VERSION_NEXT=1
+    cd $BATS_TMPDIR
+    mkdir "dolt-repo-$$"
+    cd "dolt-repo-$$"
+    mkdir backupdir
+    mkdir repo
+    cd repo
+    dolt init
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added a row"
+}
+
+teardown() {
+    rm -rf "$BATS_TMPDIR/dolt-repo-$$"
+}
+
+@test "dolt backup add, ls and rm" {
+    run dolt backup add bac1 file://../backupdir
+    [ "$status" -eq 0 ]
+    run dolt backup ls
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "bac1" ]] || false
+    run dolt backup add bac1 file://../backupdir
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "already exists" ]] || false
+    run dolt remote
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "bac1" ]] || false
+    run dolt backup rm bac1
+    [ "$status" -eq 0 ]
+    run dolt backup ls
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "bac1" ]] || false
+    run dolt backup sync bac1
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "unknown backup" ]] || false
+}
+
+@test "dolt backup restore recreates branches, the working set and staged tables" {
+    dolt branch other
+    dolt table put-row test pk:1 c1:11 c2:12 c3:13 c4:14 c5:15
+    dolt add test
+    dolt table put-row test pk:2 c1:21 c2:22 c3:23 c4:24 c5:25
+    dolt backup add bac1 file://../backupdir
+    run dolt backup sync bac1
+    [ "$status" -eq 0 ]
+
+    cd ..
+    run dolt backup restore file://./backupdir restored
+    [ "$status" -eq 0 ]
+    cd restored
+    run dolt branch
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "master" ]] || false
+    [[ "$output" =~ "other" ]] || false
+    run dolt log
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "added a row" ]] || false
+    run dolt table select test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "15" ]] || false
+    [[ "$output" =~ "25" ]] || false
+    run dolt diff
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "25" ]] || false
+    [[ ! "$output" =~ "15" ]] || false
+    run dolt diff HEAD
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "15" ]] || false
+    run dolt backup ls
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "bac1" ]] || false
+}
+
+@test "dolt backup sync mirrors deleted and moved branches" {
+    dolt branch other
+    dolt checkout -b feature
+    dolt table put-row test pk:1 c1:11 c2:12 c3:13 c4:14 c5:15
+    dolt add test
+    dolt commit -m "added another row"
+    dolt checkout master
+    dolt backup add bac1 file://../backupdir
+    dolt backup sync bac1
+    dolt branch -d -f other
+    dolt branch -f feature master
+    run dolt backup sync bac1
+    [ "$status" -eq 0 ]
+
+    cd ..
+    dolt backup restore file://./backupdir restored
+    cd restored
+    run dolt branch
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "feature" ]] || false
+    [[ ! "$output" =~ "other" ]] || false
+    dolt checkout feature
+    run dolt log
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "added another row" ]] || false
+}
+
+@test "dolt backup restore keeps a merge in progress" {
+    dolt checkout -b other
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:6
+    dolt add test
+    dolt commit -m "changed a row on other"
+    dolt checkout master
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:7
+    dolt add test
+    dolt commit -m "changed a row on master"
+    run dolt merge other
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "CONFLICT" ]] || false
+    # the commit being merged is only referenced by the merge state
+    dolt branch -d -f other
+    dolt backup add bac1 file://../backupdir
+    run dolt backup sync bac1
+    [ "$status" -eq 0 ]
+
+    cd ..
+    dolt backup restore file://./backupdir restored
+    cd restored
+    dolt conflicts resolve --ours test
+    dolt add test
+    run dolt commit -m "merged other"
+    [ "$status" -eq 0 ]
+    run dolt log
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "changed a row on other" ]] || false
+}
+
+@test "dolt backup restore fails without a backup" {
+    cd ..
+    run dolt backup restore file://./backupdir restored
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "no backup" ]] || false
+    [ ! -d restored ]
+}
@@ -112,6 +112,72 @@ teardown() {
     [ "${#lines[@]}" -eq 6 ]
 }
 
+@test "import infers column types from csv" {
+    run dolt table import -c test `batshelper 1pk5col-ints.csv`
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt schema test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "\`pk\` int not null" ]] || false
+    [[ "$output" =~ "\`c1\` int not null" ]] || false
+    [[ "$output" =~ "primary key (\`pk\`)" ]] || false
+    [[ ! "$output" =~ "varchar" ]] || false
+}
+
+@test "import only infers json columns from ndjson files" {
+    echo -e 'id,doc\n1,[1]\n2,"{""a"": 1}"' > docs.csv
+    run dolt table import -c --dry-run test docs.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "\`doc\` varchar" ]] || false
+    run dolt table import -c --dry-run test `batshelper events.ndjson`
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "\`tags\` json" ]] || false
+}
+
+@test "import dry run prints the inferred schema" {
+    run dolt table import -c --dry-run test `batshelper 1pk5col-ints.csv`
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "CREATE TABLE \`test\`" ]] || false
+    [[ "$output" =~ "\`c5\` int not null" ]] || false
+    [[ ! "$output" =~ "Import completed successfully." ]] || false
+    run dolt ls
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "test" ]] || false
+}
+
+@test "import a table from a mysqldump file" {
+    run dolt table import -c characters `batshelper mysqldump.sql`
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Rows inserted: 3" ]] || false
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt ls
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "characters" ]] || false
+    [[ ! "$output" =~ "episodes" ]] || false
+    run dolt sql -q "select catchphrase from characters where id = 2"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Eat my shorts; man" ]] || false
+    run dolt table import -c characters `batshelper mysqldump.sql`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Use -f to overwrite" ]] || false
+    run dolt table import -c -f characters `batshelper mysqldump.sql`
+    [ "$status" -eq 0 ]
+    run dolt table import -u characters `batshelper mysqldump.sql`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Duplicate primary key" ]] || false
+}
+
+@test "run a mysqldump file with dolt sql" {
+    dolt sql < `batshelper mysqldump.sql`
+    run dolt ls
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "characters" ]] || false
+    [[ "$output" =~ "episodes" ]] || false
+    run dolt sql -q "select name from episodes where id = 1"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Simpsons Roasting on an Open Fire" ]] || false
+}
+
 @test "try to create a table with a bad csv" {
     run dolt table import -c --pk=pk test `batshelper bad.csv`
     [ "$status" -eq 1 ]
@@ -194,6 +260,67 @@ teardown() {
     [[ ! "$output" =~ "bad-sheet-name" ]] || false
 }
 
+@test "import a chosen sheet of an excel file" {
+    run dolt table import -c --pk=number players --sheet basketball `batshelper employees.xlsx`
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt table select players
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "tim" ]] || false
+    [ "${#lines[@]}" -eq 8 ]
+    run dolt table import -c --sheet missing test `batshelper employees.xlsx`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "doesn't have a sheet named 'missing'" ]] || false
+    run dolt table import -c --pk=pk --sheet basketball test `batshelper 1pk5col-ints.csv`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "The --sheet parameter can only be used with xlsx files." ]] || false
+}
+
+@test "import every sheet of an excel file" {
+    run dolt table import -c --all-sheets `batshelper employees.xlsx`
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Imported sheet employees." ]] || false
+    [[ "$output" =~ "Imported sheet basketball." ]] || false
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt ls
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "employees" ]] || false
+    [[ "$output" =~ "basketball" ]] || false
+    run dolt table import -c --all-sheets `batshelper employees.xlsx`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Data already exists in employees." ]] || false
+    run dolt table import -r --all-sheets `batshelper employees.xlsx`
+    [ "$status" -eq 0 ]
+    run dolt table import -c --all-sheets --map `batshelper events-mapping.json` `batshelper employees.xlsx`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--map and --all-sheets can't be used together." ]] || false
+}
+
+@test "export several tables to an excel file" {
+    dolt table import -c --all-sheets `batshelper employees.xlsx`
+    run dolt table export employees basketball tables.xlsx
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully exported data." ]] || false
+    run dolt table import -c --pk=number players --sheet basketball tables.xlsx
+    [ "$status" -eq 0 ]
+    run dolt table select players
+    [[ "$output" =~ "tim" ]] || false
+    run dolt table export employees basketball tables.xlsx
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Data already exists in tables.xlsx." ]] || false
+    run dolt table export employees basketball tables.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Several tables can only be exported to an xlsx file." ]] || false
+    dolt add .
+    dolt commit -m "added tables"
+    dolt table rm basketball
+    run dolt table export -f --all --commit HEAD tables.xlsx
+    [ "$status" -eq 0 ]
+    run dolt table import -u --all-sheets tables.xlsx
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Table basketball does not exist." ]] || false
+}
+
 @test "import an .xlsx file that is not a valid excel spreadsheet" {
     run dolt table import -c --pk=id test `batshelper bad.xlsx`
     [ "$status" -eq 1 ]
@@ -336,3 +463,101 @@ teardown() {
     [ "${lines[0]}" = "diff --dolt a/test b/test" ]
     [ "${lines[1]}" = "added table" ]
 }
+
+@test "import a table from stdin and export it to stdout" {
+    run bash -c "cat `batshelper 1pk5col-ints.csv` | dolt table import -c --pk=pk test --file-type csv"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt table export test --file-type csv
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "pk,c1,c2,c3,c4,c5" ]
+    [ "${#lines[@]}" -eq 3 ]
+    [[ ! "$output" =~ "Successfully exported data." ]] || false
+    run bash -c "dolt table export test --file-type psv | dolt table import -u test --file-type psv"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+}
+
+@test "import and export a csv file in another dialect" {
+    run dolt table import -c --pk=id --file-type csv --delim tab --no-header --columns id,name,age --quote "'" --null '\N' test `batshelper vendor-dialect.tsv`
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt sql -q "select name from test where age is null"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Smith, John" ]] || false
+    run dolt sql -q "select name from test where age = 42"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "O'Brien" ]] || false
+    run dolt table export test --file-type csv --delim ";" --null NULL --line-terminator crlf
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1;Smith, John;NULL" ]] || false
+    run dolt table export test --file-type csv --quote "'"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,'Smith, John'," ]] || false
+    [[ "$output" =~ "2,'O''Brien',42" ]] || false
+}
+
+@test "csv dialect parameters are only used with csv and psv files" {
+    run dolt table import -c --pk=id --delim tab test `batshelper employees-tbl.json`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "The --delim parameter can only be used with csv and psv files." ]] || false
+    run dolt table import -c --pk=id --no-header test `batshelper 1pk5col-ints.csv`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "The --columns parameter is required" ]] || false
+}
+
+@test "import from stdin requires a file type" {
+    run bash -c "cat `batshelper 1pk5col-ints.csv` | dolt table import -c --pk=pk test"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "The --file-type parameter is required when importing from stdin." ]] || false
+    run dolt table export test
+    [ "$status" -eq 1 ]
+}
+
+@test "import and export an ndjson file" {
+    run dolt table import -c --pk=id test `batshelper events.ndjson`
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt sql -q "select tags from test where id = 1"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '["a","b"]' ]] || false
+    run dolt table export test --file-type ndjson
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '{"id":2,"name":"jane","address":{"city":"Seattle"}}' ]] || false
+}
+
+@test "import nested ndjson fields with a mapping file" {
+    dolt sql -q "create table cities (id int not null, name varchar, city varchar, primary key (id))"
+    run dolt table import -u --map `batshelper events-mapping.json` cities `batshelper events.ndjson`
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt sql -q "select name from cities where city = 'Seattle'"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "jane" ]] || false
+    run dolt table export cities out.jsonl
+    [ "$status" -eq 0 ]
+    grep -F '{"id":1,"name":"bill","city":"Los Angeles"}' out.jsonl
+}
+
+@test "import keeps the auto_increment counter of the table" {
+    dolt sql -q "create table things (id int auto_increment primary key, name varchar)"
+    dolt sql -q "insert into things (name) values ('a'), ('b'), ('c')"
+    dolt sql -q "delete from things where id > 1"
+    echo "id,name" > things.csv
+    echo "1,z" >> things.csv
+    run dolt table import -r things things.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    dolt sql -q "insert into things (name) values ('d')"
+    run dolt sql -q "select id from things where name = 'd'"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "4" ]] || false
+    echo "name" > more.csv
+    echo "e" >> more.csv
+    run dolt table import -u things more.csv
+    [ "$status" -eq 0 ]
+    dolt sql -q "insert into things (name) values ('f')"
+    run dolt sql -q "select id from things where name = 'f'"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "6" ]] || false
+}
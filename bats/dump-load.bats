@@ -0,0 +1,127 @@
+#!/usr/bin/env bats
+
+setup() {
+    load $BATS_TEST_DIRNAME/helper/common.bash
+    export PATH=$PATH:~/go/bin
+    export NOMS_VERSION_NEXT=1
+    cd $BATS_TMPDIR
+    mkdir "dolt-repo-$$"
+    cd "dolt-repo-$$"
+    dolt init
+    dolt sql -q "create table people (id int not null, name varchar, primary key (id))"
+    dolt sql -q "create table pets (id int not null, owner int, name varchar, primary key (id))"
+    dolt sql -q "alter table pets add constraint pets_owner foreign key (owner) references people (id)"
+    dolt sql -q "insert into people (id, name) values (1, 'bill'), (2, 'jane')"
+    dolt sql -q "insert into pets (id, owner, name) values (1, 2, 'fido')"
+}
+
+teardown() {
+    rm -rf "$BATS_TMPDIR/dolt-repo-$$"
+}
+
+@test "dump and load a sql file" {
+    run dolt dump
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully dumped 2 tables to doltdump.sql." ]] || false
+    grep -F 'CREATE TABLE `people`' doltdump.sql
+    grep -F 'ALTER TABLE `pets` ADD constraint `pets_owner`' doltdump.sql
+    run dolt dump
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Data already exists in doltdump.sql." ]] || false
+    run dolt load
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Data already exists in people, pets." ]] || false
+    dolt table rm pets people
+    run dolt load
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully loaded doltdump.sql." ]] || false
+    run dolt sql -q "select name from pets where owner = 2"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "fido" ]] || false
+    run dolt sql -q "delete from people where id = 2"
+    [ "$status" -eq 1 ]
+}
+
+@test "dump and load a directory of csv files" {
+    run dolt dump --file-type csv
+    [ "$status" -eq 0 ]
+    [ -f doltdump/people.csv ]
+    [ -f doltdump/people.schema.json ]
+    [ -f doltdump/foreign_keys.sql ]
+    dolt sql -q "update people set name = 'william' where id = 1"
+    run dolt load -f
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully loaded doltdump." ]] || false
+    run dolt sql -q "select name from people where id = 1"
+    [[ "$output" =~ "bill" ]] || false
+    run dolt schema show people
+    [[ "$output" =~ "tag:0" ]] || false
+    run dolt sql -q "delete from people where id = 2"
+    [ "$status" -eq 1 ]
+}
+
+@test "dump the tables of a commit" {
+    dolt add .
+    dolt commit -m "added tables"
+    dolt table rm pets
+    run dolt dump --commit HEAD --file-type json dump
+    [ "$status" -eq 0 ]
+    [ -f dump/pets.json ]
+    run dolt load dump
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Data already exists in people." ]] || false
+    run dolt ls
+    [[ ! "$output" =~ "pets" ]] || false
+}
+
+@test "tables referenced by foreign keys can't be removed or overwritten" {
+    run dolt table rm people
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "referenced by a foreign key" ]] || false
+    run dolt table mv -f pets people
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "referenced by a foreign key" ]] || false
+    run dolt sql -q "select * from people"
+    [[ "$output" =~ "jane" ]] || false
+    echo -e "id,name\n1,bill" > people.csv
+    run dolt table import -c -f --pk id people people.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "referenced by a foreign key" ]] || false
+    dolt table mv people owners
+    dolt table mv pets animals
+    run dolt sql -q "show create table animals"
+    [[ "$output" =~ 'references `owners`' ]] || false
+    run dolt sql -q "delete from owners where id = 2"
+    [ "$status" -eq 1 ]
+    dolt table rm animals owners
+    run dolt ls
+    [[ ! "$output" =~ "owners" ]] || false
+}
+
+@test "put-row and rm-row check foreign keys" {
+    run dolt table put-row pets id:2 owner:3 name:rex
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "violates a foreign key" ]] || false
+    run dolt table rm-row people 2
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "referenced by a foreign key" ]] || false
+    run dolt table put-row pets id:2 owner:1 name:rex
+    [ "$status" -eq 0 ]
+    run dolt table rm-row people 1
+    [ "$status" -eq 1 ]
+    dolt table rm-row pets 2
+    run dolt table rm-row people 1
+    [ "$status" -eq 0 ]
+    run dolt sql -q "select * from people"
+    [[ ! "$output" =~ "bill" ]] || false
+    [[ "$output" =~ "jane" ]] || false
+}
+
+@test "dump to an unsupported file type" {
+    run dolt dump --file-type xlsx
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Tables can only be dumped to sql, csv, json or parquet files." ]] || false
+    run dolt load
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Give the path of a dump written by dolt dump." ]] || false
+}
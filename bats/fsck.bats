@@ -0,0 +1,42 @@
+#!/usr/bin/env bats
+
+setup() {
+    load $BATS_TEST_DIRNAME/helper/common.bash
+    export PATH=$PATH:~/go/bin
+    export NOMS_VERSION_NEXT=1
+    cd $BATS_TMPDIR
+    mkdir "dolt-repo-$$"
+    cd "dolt-repo-$$"
+    dolt init
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added a row"
+}
+
+teardown() {
+    rm -rf "$BATS_TMPDIR/dolt-repo-$$"
+}
+
+@test "dolt fsck finds no problems in an intact repository" {
+    dolt table put-row test pk:1 c1:11 c2:12 c3:13 c4:14 c5:15
+    run dolt fsck
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "No problems found" ]] || false
+}
+
+@test "dolt fsck reports a table file that was removed" {
+    for file in .dolt/noms/*; do
+        name=`basename $file`
+        if [ "$name" != "manifest" ] && [ "$name" != "LOCK" ]; then
+            rm "$file"
+        fi
+    done
+    run dolt fsck
+    [ "$status" -ne 0 ]
+}
+
+@test "dolt fsck takes no arguments" {
+    run dolt fsck test
+    [ "$status" -ne 0 ]
+}
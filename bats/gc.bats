@@ -0,0 +1,52 @@
+#!/usr/bin/env bats
+
+setup() {
+    load $BATS_TEST_DIRNAME/helper/common.bash
+    export PATH=$PATH:~/go/bin
+    export NOMS_VERSION_NEXT=1
+    cd $BATS_TMPDIR
+    mkdir "dolt-repo-$$"
+    cd "dolt-repo-$$"
+    dolt init
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added a row"
+}
+
+teardown() {
+    rm -rf "$BATS_TMPDIR/dolt-repo-$$"
+}
+
+@test "dolt gc removes unreferenced data and keeps committed data" {
+    dolt table put-row test pk:1 c1:11 c2:12 c3:13 c4:14 c5:15
+    dolt reset --hard
+    run dolt gc
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Removed" ]] || false
+    run dolt table select test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "5" ]] || false
+    [[ ! "$output" =~ "15" ]] || false
+    run dolt log
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "added a row" ]] || false
+}
+
+@test "dolt gc keeps uncommitted changes to the working set" {
+    dolt table put-row test pk:1 c1:11 c2:12 c3:13 c4:14 c5:15
+    run dolt gc
+    [ "$status" -eq 0 ]
+    run dolt table select test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "5" ]] || false
+    [[ "$output" =~ "15" ]] || false
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "modified" ]] || false
+}
+
+@test "dolt gc takes no arguments" {
+    run dolt gc test
+    [ "$status" -ne 0 ]
+}
@@ -0,0 +1,97 @@
+#!/usr/bin/env bats
+
+# creates credentials in their own root, copies them into the test's root, and sets <name>_PUB and <name>_KID
+make_creds() {
+    local root=$BATS_TMPDIR/remotes-auth-$$/$1
+    mkdir -p $root
+    local pub=`DOLT_ROOT_PATH=$root dolt creds new | sed -n 's/^pub key: //p'`
+    local jwk=`ls $root/.dolt/creds`
+    mkdir -p $DOLT_ROOT_PATH/.dolt/creds
+    cp $root/.dolt/creds/$jwk $DOLT_ROOT_PATH/.dolt/creds/
+    eval "$1_PUB=$pub"
+    eval "$1_KID=${jwk%.jwk}"
+}
+
+setup() {
+    load $BATS_TEST_DIRNAME/helper/common.bash
+    export PATH=$PATH:~/go/bin
+    export NOMS_VERSION_NEXT=1
+    cd $BATS_TMPDIR
+    mkdir remotes-auth-$$
+    export DOLT_ROOT_PATH=$BATS_TMPDIR/remotes-auth-$$/home
+    mkdir $DOLT_ROOT_PATH
+    dolt config --global --add user.name "Bats Tests"
+    dolt config --global --add user.email "bats@email.fake"
+    make_creds WRITER
+    make_creds READER
+    make_creds STRANGER
+    cat > remotes-auth-$$/acl.json <<JSON
+{
+  "keys": {
+    "$WRITER_PUB": {"test-org/*": "write"},
+    "$READER_PUB": {"test-org/*": "read", "test-org/private": "none"}
+  }
+}
+JSON
+    echo remotesrv log available here $BATS_TMPDIR/remotes-auth-$$/remotesrv.log
+    remotesrv --http-port 1235 --grpc-port 50052 --dir ./remotes-auth-$$/data --acl ./remotes-auth-$$/acl.json &> ./remotes-auth-$$/remotesrv.log 3>&- &
+    sleep 1
+    mkdir dolt-repo-$$
+    cd dolt-repo-$$
+    dolt init
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt add test
+    dolt commit -m "test commit"
+    dolt remote add test-remote http://localhost:50052/test-org/test-repo
+    mkdir "dolt-repo-clones"
+}
+
+teardown() {
+    rm -rf $BATS_TMPDIR/dolt-repo-$$
+    pgrep remotesrv | xargs kill
+    rm -rf $BATS_TMPDIR/remotes-auth-$$
+}
+
+@test "push without credentials is rejected by a remote with an acl" {
+    run dolt push test-remote master
+    [ "$status" -ne 0 ]
+    [ ! -d "$BATS_TMPDIR/remotes-auth-$$/data/test-org/test-repo" ]
+}
+
+@test "push with credentials the acl doesn't know is rejected" {
+    dolt config --local --add user.creds $STRANGER_KID
+    run dolt push test-remote master
+    [ "$status" -ne 0 ]
+    [ ! -d "$BATS_TMPDIR/remotes-auth-$$/data/test-org/test-repo" ]
+}
+
+@test "a key with write access can push and a key with read access can only clone" {
+    dolt config --local --add user.creds $WRITER_KID
+    run dolt push test-remote master
+    [ "$status" -eq 0 ]
+    dolt config --global --add user.creds $READER_KID
+    cd "dolt-repo-clones"
+    run dolt clone http://localhost:50052/test-org/test-repo
+    [ "$status" -eq 0 ]
+    cd test-repo
+    run dolt log
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "test commit" ]] || false
+    dolt checkout -b reader-branch
+    run dolt push origin reader-branch
+    [ "$status" -ne 0 ]
+}
+
+@test "a key can't read repositories it has no access to" {
+    dolt config --local --add user.creds $WRITER_KID
+    dolt remote add private http://localhost:50052/test-org/private
+    dolt remote add other-org http://localhost:50052/other-org/test-repo
+    run dolt push private master
+    [ "$status" -eq 0 ]
+    run dolt push other-org master
+    [ "$status" -ne 0 ]
+    dolt config --global --add user.creds $READER_KID
+    cd "dolt-repo-clones"
+    run dolt clone http://localhost:50052/test-org/private
+    [ "$status" -ne 0 ]
+}
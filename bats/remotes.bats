@@ -141,6 +141,72 @@ teardown() {
     [[ "$output" =~ "test commit" ]] || false
 }
 
+@test "shallow clone only the last commits of a branch" {
+    dolt remote add test-remote http://localhost:50051/test-org/test-repo
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt add test
+    dolt commit -m "first commit"
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "second commit"
+    dolt table put-row test pk:1 c1:11 c2:12 c3:13 c4:14 c5:15
+    dolt add test
+    dolt commit -m "third commit"
+    dolt push test-remote master
+    cd "dolt-repo-clones"
+    run dolt clone --depth 2 http://localhost:50051/test-org/test-repo
+    [ "$status" -eq 0 ]
+    cd test-repo
+    run dolt log
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "third commit" ]] || false
+    [[ "$output" =~ "second commit" ]] || false
+    [[ ! "$output" =~ "first commit" ]] || false
+    run dolt table select test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "15" ]] || false
+    run dolt fsck
+    [ "$status" -eq 0 ]
+}
+
+@test "push from a shallow clone" {
+    dolt remote add test-remote http://localhost:50051/test-org/test-repo
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt add test
+    dolt commit -m "first commit"
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "second commit"
+    dolt push test-remote master
+    cd "dolt-repo-clones"
+    dolt clone --depth 1 http://localhost:50051/test-org/test-repo
+    cd test-repo
+    dolt table put-row test pk:1 c1:11 c2:12 c3:13 c4:14 c5:15
+    dolt add test
+    dolt commit -m "third commit"
+    mkdir ../shallow-remote
+    dolt remote add other file://../shallow-remote
+    run dolt push other master
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "shallow" ]] || false
+    run dolt push origin master
+    [ "$status" -eq 0 ]
+    cd ..
+    dolt clone http://localhost:50051/test-org/test-repo full-clone
+    cd full-clone
+    run dolt log
+    [[ "$output" =~ "third commit" ]] || false
+    [[ "$output" =~ "first commit" ]] || false
+    run dolt fsck
+    [ "$status" -eq 0 ]
+}
+
+@test "shallow clone with a depth less than 1" {
+    cd "dolt-repo-clones"
+    run dolt clone --depth 0 http://localhost:50051/test-org/test-repo
+    [ "$status" -ne 0 ]
+}
+
 @test "call a clone's remote something other than origin" {
     dolt remote add test-remote http://localhost:50051/test-org/test-repo
     dolt table create -s=`batshelper 1pk5col-ints.schema` test
@@ -371,6 +437,38 @@ teardown() {
     [[ "$output" = "$master_state1" ]] || false
 }
 
+@test "zstd compressed repositories and file based remotes" {
+    dolt config --local --add storage.compression zstd
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "test commit"
+
+    mkdir remotedir
+    run dolt remote add --compression gzip origin file://remotedir
+    [ "$status" -ne 0 ]
+    run dolt remote add --compression zstd origin http://localhost:50051/test-org/test-repo
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "only valid for file, aws and gs remotes" ]] || false
+    dolt remote add --compression zstd origin file://remotedir
+    dolt push origin master
+
+    cd dolt-repo-clones
+    dolt clone file://../remotedir test-repo
+    cd test-repo
+    run dolt table select test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "5" ]] || false
+
+    cd ../..
+    dolt config --local --add storage.compression gzip
+    run dolt status
+    [ "$status" -ne 0 ]
+    dolt config --local --unset storage.compression
+    run dolt status
+    [ "$status" -eq 0 ]
+}
+
 @test "multiple remotes" {
     # seed with some data
     dolt table create -s=`batshelper 1pk5col-ints.schema` test
@@ -15,7 +15,7 @@ teardown() {
 }
 
 @test "changing column types should not produce a data diff error" {
-    dolt table import -c --pk=pk test `batshelper 1pk5col-ints.csv`
+    dolt table import -c -s=`batshelper 1pk5col-strings.schema` test `batshelper 1pk5col-ints.csv`
     run dolt schema
     [[ "$output" =~ "varchar" ]] || false
     dolt add test
@@ -145,8 +145,12 @@ func ParseKeyValues(nbf *types.NomsBinFormat, sch schema.Schema, args []string)
 	convFuncs := make(map[uint64]doltcore.ConvFunc)
 	err := sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		convFunc := doltcore.GetConvFunc(types.StringKind, col.Kind)
+		if col.IsJSON() {
+			convFunc = doltcore.ConvStringToJSON
+		}
+
 		if convFunc == nil {
-			return false, ColumnError{col.Name, "Conversion from string to " + col.KindString() + "is not defined."}
+			return false, ColumnError{col.Name, "Conversion from string to " + col.TypeString() + "is not defined."}
 		}
 
 		convFuncs[tag] = convFunc
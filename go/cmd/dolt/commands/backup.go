@@ -0,0 +1,302 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/store/datas"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+var Backup = cli.GenSubCommandHandler([]*cli.Command{
+	{Name: "add", Desc: "Add a backup of the repository.", Func: backupAdd, ReqRepo: true},
+	{Name: "rm", Desc: "Remove a backup of the repository.", Func: backupRm, ReqRepo: true},
+	{Name: "ls", Desc: "List the backups of the repository.", Func: backupLs, ReqRepo: true},
+	{Name: "sync", Desc: "Update a backup with the current state of the repository.", Func: backupSync, ReqRepo: true},
+	{Name: "restore", Desc: "Restore a repository from a backup.", Func: backupRestore, ReqRepo: false},
+})
+
+var backupAddShortDesc = "Add a backup of the repository"
+var backupAddLongDesc = "Adds a backup named <name> of the repository at <url>. The backup isn't written until " +
+	"<b>dolt backup sync</b> <name> is run.\n" +
+	"\n" +
+	"A backup holds every ref of the repository, including remote-tracking branches, along with its working set, its " +
+	"staged tables, a merge in progress, and its remotes and branch configuration.  Unlike a remote, refs which are " +
+	"deleted or moved in the repository are deleted or moved in the backup.\n" +
+	"\n" +
+	"The <url> parameter supports the same url schemes and aws parameters as <b>dolt remote add</b>."
+var backupAddSynopsis = []string{
+	"[--aws-region <region>] [--aws-creds-type <creds-type>] [--aws-creds-file <file>] [--aws-creds-profile <profile>] [--aws-endpoint <url>] [--aws-s3-path-style <true|false>] [--aws-manifest <manifest>] [--aws-manifest-lock-file <file>] [--compression <compression>] <name> <url>",
+}
+
+func backupAdd(commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	supportsRemoteParams(ap)
+	help, usage := cli.HelpAndUsagePrinters(commandStr, backupAddShortDesc, backupAddLongDesc, backupAddSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 2 {
+		return HandleVErrAndExitCode(errhand.BuildDError("").SetPrintUsage().Build(), usage)
+	}
+
+	backupName := strings.TrimSpace(apr.Arg(0))
+
+	if strings.IndexAny(backupName, " \t\n\r./\\!@#$%^&*(){}[],.<>'\"?=+|") != -1 {
+		return HandleVErrAndExitCode(errhand.BuildDError("invalid backup name: "+backupName).Build(), usage)
+	}
+
+	if _, ok := dEnv.RepoState.Backups[backupName]; ok {
+		verr := errhand.BuildDError("error: A backup named '%s' already exists.", backupName).AddDetails("remove it before running this command again").Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	backupUrl := apr.Arg(1)
+	scheme, backupUrl, err := getAbsRemoteUrl(dEnv.FS, dEnv.Config, backupUrl)
+
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: '%s' is not valid.", backupUrl).Build(), usage)
+	}
+
+	params, verr := parseRemoteArgs(apr, scheme, backupUrl)
+
+	if verr != nil {
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	dEnv.RepoState.AddBackup(env.Remote{Name: backupName, Url: backupUrl, Params: params})
+	err = dEnv.RepoState.Save()
+
+	if err != nil {
+		verr = errhand.BuildDError("error: Unable to save changes.").AddCause(err).Build()
+	}
+
+	return HandleVErrAndExitCode(verr, usage)
+}
+
+var backupRmShortDesc = "Remove a backup of the repository"
+var backupRmLongDesc = "Removes the backup named <name> from the repository.  The data already written to the backup " +
+	"is left as is."
+var backupRmSynopsis = []string{
+	"<name>",
+}
+
+func backupRm(commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	help, usage := cli.HelpAndUsagePrinters(commandStr, backupRmShortDesc, backupRmLongDesc, backupRmSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 1 {
+		return HandleVErrAndExitCode(errhand.BuildDError("").SetPrintUsage().Build(), usage)
+	}
+
+	backupName := strings.TrimSpace(apr.Arg(0))
+
+	var verr errhand.VerboseError
+	if _, ok := dEnv.RepoState.Backups[backupName]; !ok {
+		verr = errhand.BuildDError("error: unknown backup " + backupName).Build()
+	} else {
+		delete(dEnv.RepoState.Backups, backupName)
+		err := dEnv.RepoState.Save()
+
+		if err != nil {
+			verr = errhand.BuildDError("error: unable to save changes.").AddCause(err).Build()
+		}
+	}
+
+	return HandleVErrAndExitCode(verr, usage)
+}
+
+var backupLsShortDesc = "List the backups of the repository"
+var backupLsLongDesc = "Lists the backups of the repository.  With <b>-v</b> their urls and parameters are listed as well."
+var backupLsSynopsis = []string{
+	"[-v | --verbose]",
+}
+
+func backupLs(commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(verboseFlag, "v", "Adds the url and parameters of each backup.")
+	help, _ := cli.HelpAndUsagePrinters(commandStr, backupLsShortDesc, backupLsLongDesc, backupLsSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	backups, err := dEnv.GetBackups()
+
+	if err != nil {
+		cli.PrintErrln("error: failed to read backups from config.")
+		return 1
+	}
+
+	for _, b := range backups {
+		if apr.Contains(verboseFlag) {
+			paramStr := make([]byte, 0)
+			if len(b.Params) > 0 {
+				paramStr, _ = json.Marshal(b.Params)
+			}
+
+			cli.Printf("%s %s %s\n", b.Name, b.Url, paramStr)
+		} else {
+			cli.Println(b.Name)
+		}
+	}
+
+	return 0
+}
+
+var backupSyncShortDesc = "Update a backup with the current state of the repository"
+var backupSyncLongDesc = "Copies every ref of the repository to the backup named <name>, moving refs whether or not " +
+	"they fast-forward and deleting refs the repository no longer has, then replaces the working set, staged tables, " +
+	"merge state, and configuration held by the backup with those of the repository."
+var backupSyncSynopsis = []string{
+	"<name>",
+}
+
+func backupSync(commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	help, usage := cli.HelpAndUsagePrinters(commandStr, backupSyncShortDesc, backupSyncLongDesc, backupSyncSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 1 {
+		return HandleVErrAndExitCode(errhand.BuildDError("").SetPrintUsage().Build(), usage)
+	}
+
+	backupName := strings.TrimSpace(apr.Arg(0))
+	b, ok := dEnv.RepoState.Backups[backupName]
+
+	if !ok {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: unknown backup "+backupName).Build(), usage)
+	}
+
+	return HandleVErrAndExitCode(syncBackup(context.Background(), dEnv, b), usage)
+}
+
+func syncBackup(ctx context.Context, dEnv *env.DoltEnv, b env.Remote) errhand.VerboseError {
+	state, err := dEnv.BackupState()
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to read the repo state").AddCause(err).Build()
+	}
+
+	backupDB, err := b.GetRemoteDB(ctx, dEnv.DoltDB.ValueReadWriter().Format())
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to get backup db").AddCause(err).Build()
+	}
+
+	progChan := make(chan datas.PullProgress)
+	stopChan := make(chan struct{})
+	go progFunc(progChan, stopChan)
+
+	err = actions.SyncBackup(ctx, dEnv.DoltDB, backupDB, state, progChan)
+
+	close(progChan)
+	<-stopChan
+
+	if err != nil {
+		return errhand.BuildDError("error: sync failed").AddCause(err).Build()
+	}
+
+	return nil
+}
+
+var backupRestoreShortDesc = "Restore a repository from a backup"
+var backupRestoreLongDesc = "Restores the repository backed up at <url> into the newly created directory <new-dir>. " +
+	"Every ref of the backup is restored, along with the working set, staged tables, merge state, remotes, backups, " +
+	"and branch configuration of the repository at the time of its last <b>dolt backup sync</b>.\n" +
+	"\n" +
+	"The <url> parameter supports the same url schemes and aws parameters as <b>dolt remote add</b>."
+var backupRestoreSynopsis = []string{
+	"[--aws-region <region>] [--aws-creds-type <creds-type>] [--aws-creds-file <file>] [--aws-creds-profile <profile>] [--aws-endpoint <url>] [--aws-s3-path-style <true|false>] [--aws-manifest <manifest>] [--aws-manifest-lock-file <file>] [--compression <compression>] <url> <new-dir>",
+}
+
+func backupRestore(commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	supportsRemoteParams(ap)
+	help, usage := cli.HelpAndUsagePrinters(commandStr, backupRestoreShortDesc, backupRestoreLongDesc, backupRestoreSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 2 {
+		return HandleVErrAndExitCode(errhand.BuildDError("").SetPrintUsage().Build(), usage)
+	}
+
+	urlStr, dir := apr.Arg(0), apr.Arg(1)
+	scheme, backupUrl, err := getAbsRemoteUrl(dEnv.FS, dEnv.Config, urlStr)
+
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: '%s' is not valid.", urlStr).Build(), usage)
+	}
+
+	params, verr := parseRemoteArgs(apr, scheme, backupUrl)
+
+	if verr != nil {
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	cli.Printf("restoring %s\n", backupUrl)
+
+	b := env.Remote{Name: "backup", Url: backupUrl, Params: params}
+	backupDB, err := b.GetRemoteDB(context.TODO(), types.Format_Default)
+
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: failed to get backup db").AddCause(err).Build(), usage)
+	}
+
+	dEnv, verr = envForClone(backupDB.ValueReadWriter().Format(), b, dir, dEnv.FS)
+
+	if verr == nil {
+		verr = restoreBackup(context.Background(), backupDB, dEnv)
+
+		// Make best effort to delete the directory we created.
+		if verr != nil {
+			_ = os.Chdir("../")
+			_ = dEnv.FS.Delete(dir, true)
+		}
+	}
+
+	return HandleVErrAndExitCode(verr, usage)
+}
+
+func restoreBackup(ctx context.Context, backupDB *doltdb.DoltDB, dEnv *env.DoltEnv) errhand.VerboseError {
+	progChan := make(chan datas.PullProgress)
+	stopChan := make(chan struct{})
+	go progFunc(progChan, stopChan)
+
+	state, err := actions.RestoreBackup(ctx, backupDB, dEnv.DoltDB, progChan)
+
+	close(progChan)
+	<-stopChan
+
+	if err == doltdb.ErrNotABackup {
+		return errhand.BuildDError("error: there is no backup at this url").AddDetails("run dolt backup sync to write it").Build()
+	} else if err != nil {
+		return errhand.BuildDError("error: restore failed").AddCause(err).Build()
+	}
+
+	dEnv.RepoState, err = env.RestoreRepoState(dEnv.FS, state.RepoState)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to write repo state").AddCause(err).Build()
+	}
+
+	return nil
+}
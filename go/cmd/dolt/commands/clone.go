@@ -39,6 +39,7 @@ import (
 const (
 	remoteParam = "remote"
 	branchParam = "branch"
+	depthParam  = "depth"
 )
 
 var cloneShortDesc = "Clone a data repository into a new directory"
@@ -50,26 +51,32 @@ var cloneLongDesc = "Clones a repository into a newly created directory, creates
 	"pull</b> without arguments will in addition merge the remote branch into the current branch\n" +
 	"\n" +
 	"This default configuration is achieved by creating references to the remote branch heads under refs/remotes/origin " +
-	"and by creating a remote named 'origin'."
+	"and by creating a remote named 'origin'.\n" +
+	"\n" +
+	"With <b>--depth</b> only the last <depth> commits of the history of each branch are cloned.  The commits before them " +
+	"are left out of the repository, and <b>dolt log</b> and merges stop at them."
 var cloneSynopsis = []string{
-	"[-remote <remote>] [-branch <branch>]  [--aws-region <region>] [--aws-creds-type <creds-type>] [--aws-creds-file <file>] [--aws-creds-profile <profile>] <remote-url> <new-dir>",
+	"[-remote <remote>] [-branch <branch>] [--depth <depth>]  [--aws-region <region>] [--aws-creds-type <creds-type>] [--aws-creds-file <file>] [--aws-creds-profile <profile>] [--aws-endpoint <url>] [--aws-s3-path-style <true|false>] [--aws-manifest <manifest>] [--aws-manifest-lock-file <file>] [--compression <compression>] <remote-url> <new-dir>",
 }
 
 func Clone(commandStr string, args []string, dEnv *env.DoltEnv) int {
 	ap := argparser.NewArgParser()
 	ap.SupportsString(remoteParam, "", "name", "Name of the remote to be added. Default will be 'origin'.")
 	ap.SupportsString(branchParam, "b", "branch", "The branch to be cloned.  If not specified all branches will be cloned.")
-	ap.SupportsString(dbfactory.AWSRegionParam, "", "region", "")
-	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, credTypes))
-	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file.")
-	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use.")
+	ap.SupportsInt(depthParam, "", "depth", "Clone only the last <depth> commits of the history of each branch.")
+	supportsRemoteParams(ap)
 	help, usage := cli.HelpAndUsagePrinters(commandStr, cloneShortDesc, cloneLongDesc, cloneSynopsis, ap)
 	apr := cli.ParseArgs(ap, args, help)
 
 	remoteName := apr.GetValueOrDefault(remoteParam, "origin")
 	branch := apr.GetValueOrDefault(branchParam, "")
+	depth := apr.GetIntOrDefault(depthParam, 0)
 	dir, urlStr, verr := parseArgs(apr)
 
+	if verr == nil && apr.Contains(depthParam) && depth < 1 {
+		verr = errhand.BuildDError("error: --%s must be at least 1", depthParam).Build()
+	}
+
 	scheme, remoteUrl, err := getAbsRemoteUrl(dEnv.FS, dEnv.Config, urlStr)
 
 	if err != nil {
@@ -89,7 +96,7 @@ func Clone(commandStr string, args []string, dEnv *env.DoltEnv) int {
 				dEnv, verr = envForClone(srcDB.ValueReadWriter().Format(), r, dir, dEnv.FS)
 
 				if verr == nil {
-					verr = cloneRemote(context.Background(), srcDB, remoteName, branch, dEnv)
+					verr = cloneRemote(context.Background(), srcDB, remoteName, branch, depth, dEnv)
 
 					// Make best effort to delete the directory we created.
 					if verr != nil {
@@ -188,7 +195,9 @@ func createRemote(remoteName, remoteUrl string, params map[string]string) (env.R
 	return r, ddb, nil
 }
 
-func cloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, dEnv *env.DoltEnv) errhand.VerboseError {
+// cloneRemote clones the branch given, or every branch if none is given, from srcDB. Only the last |depth| commits of
+// each branch are cloned unless |depth| is 0.
+func cloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, depth int, dEnv *env.DoltEnv) errhand.VerboseError {
 	var branches []ref.DoltRef
 	if len(branch) > 0 {
 		branches = []ref.DoltRef{ref.NewBranchRef(branch)}
@@ -201,10 +210,10 @@ func cloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch s
 		}
 	}
 
-	return cloneAllBranchRefs(branches, srcDB, ctx, remoteName, dEnv)
+	return cloneAllBranchRefs(branches, srcDB, ctx, remoteName, depth, dEnv)
 }
 
-func cloneAllBranchRefs(branches []ref.DoltRef, srcDB *doltdb.DoltDB, ctx context.Context, remoteName string, dEnv *env.DoltEnv) errhand.VerboseError {
+func cloneAllBranchRefs(branches []ref.DoltRef, srcDB *doltdb.DoltDB, ctx context.Context, remoteName string, depth int, dEnv *env.DoltEnv) errhand.VerboseError {
 	var dref ref.DoltRef
 	var masterHash hash.Hash
 	var h hash.Hash
@@ -234,7 +243,12 @@ func cloneAllBranchRefs(branches []ref.DoltRef, srcDB *doltdb.DoltDB, ctx contex
 		go progFunc(progChan, doneChan)
 
 		remoteBranch := ref.NewRemoteRef(remoteName, branch)
-		err = actions.Fetch(ctx, remoteBranch, srcDB, dEnv.DoltDB, cm, progChan)
+		if depth > 0 {
+			err = actions.FetchShallow(ctx, remoteBranch, srcDB, dEnv.DoltDB, cm, depth, progChan)
+		} else {
+			err = actions.Fetch(ctx, remoteBranch, srcDB, dEnv.DoltDB, cm, nil, progChan)
+		}
+
 		close(progChan)
 		<-doneChan
 
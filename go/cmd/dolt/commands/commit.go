@@ -24,6 +24,7 @@ import (
 
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
@@ -88,6 +89,13 @@ func handleCommitErr(err error, usage cli.UsagePrinter) int {
 		return HandleVErrAndExitCode(bdr.Build(), usage)
 	}
 
+	if doltdb.IsForeignKeyViolation(err) {
+		bdr := errhand.BuildDError("Aborting commit, staged rows violate foreign key constraints.")
+		bdr.AddDetails(err.Error())
+		bdr.AddDetails("Fix the referencing rows, or stage the referenced rows, before committing.")
+		return HandleVErrAndExitCode(bdr.Build(), usage)
+	}
+
 	if actions.IsNothingStaged(err) {
 		notStaged := actions.NothingStagedDiffs(err)
 		n := printDiffsNotStaged(cli.CliOut, notStaged, false, 0, []string{})
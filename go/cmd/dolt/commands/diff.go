@@ -344,8 +344,8 @@ func diffSchemas(tableName string, sch1 schema.Schema, sch2 schema.Schema) errha
 			cli.Println(color.RedString("- " + sql.FmtCol(2, 0, 0, *dff.Old)))
 		case diff.SchDiffColModified:
 			// changed in sch2
-			n0, t0 := dff.Old.Name, sql.DoltToSQLType[dff.Old.Kind]
-			n1, t1 := dff.New.Name, sql.DoltToSQLType[dff.New.Kind]
+			n0, t0 := dff.Old.Name, sql.ColumnSQLType(*dff.Old)
+			n1, t1 := dff.New.Name, sql.ColumnSQLType(*dff.New)
 
 			nameLen := 0
 			typeLen := 0
@@ -367,6 +367,11 @@ func diffSchemas(tableName string, sch1 schema.Schema, sch2 schema.Schema) errha
 		}
 	}
 
+	if _, _, changed := diff.DiffPrimaryKeys(sch1, sch2); changed {
+		cli.Println(color.RedString("- " + sql.FmtPrimaryKey(2, sch1)))
+		cli.Println(color.GreenString("+ " + sql.FmtPrimaryKey(2, sch2)))
+	}
+
 	cli.Println("  );")
 	cli.Println()
 
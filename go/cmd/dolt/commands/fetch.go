@@ -34,7 +34,10 @@ var fetchLongDesc = "Fetch refs, along with the objects necessary to complete th
 	"\n By default dolt will attempt to fetch from a remote named 'origin'.  The <remote> parameter allows you to " +
 	"specify the name of a different remote you wish to pull from by the remote's name." +
 	"\n" +
-	"\nWhen no refspec(s) are specified on the command line, the fetch_specs for the default remote are used."
+	"\nWhen no refspec(s) are specified on the command line, the fetch_specs for the default remote are used." +
+	"\n" +
+	"\nThe progress of a fetch is saved as it goes, so a fetch that is interrupted picks up where it left off when it is " +
+	"run again."
 var fetchSynopsis = []string{
 	"[<remote>] [<refspec> ...]",
 }
@@ -147,7 +150,7 @@ func fetchRefSpecs(dEnv *env.DoltEnv, rem env.Remote, refSpecs []ref.RemoteRefSp
 			remoteTrackRef := rs.DestRef(branchRef)
 
 			if remoteTrackRef != nil {
-				verr := fetchRemoteBranch(rem, srcDB, dEnv.DoltDB, branchRef, remoteTrackRef)
+				verr := fetchRemoteBranch(rem, srcDB, dEnv.DoltDB, branchRef, remoteTrackRef, dEnv.PullCheckpoint(""))
 
 				if verr != nil {
 					return verr
@@ -159,7 +162,7 @@ func fetchRefSpecs(dEnv *env.DoltEnv, rem env.Remote, refSpecs []ref.RemoteRefSp
 	return nil
 }
 
-func fetchRemoteBranch(rem env.Remote, srcDB, destDB *doltdb.DoltDB, srcRef, destRef ref.DoltRef) errhand.VerboseError {
+func fetchRemoteBranch(rem env.Remote, srcDB, destDB *doltdb.DoltDB, srcRef, destRef ref.DoltRef, checkpoint datas.PullCheckpoint) errhand.VerboseError {
 	cs, _ := doltdb.NewCommitSpec("HEAD", srcRef.String())
 	cm, err := srcDB.Resolve(context.TODO(), cs)
 
@@ -170,7 +173,7 @@ func fetchRemoteBranch(rem env.Remote, srcDB, destDB *doltdb.DoltDB, srcRef, des
 		stopChan := make(chan struct{})
 		go progFunc(progChan, stopChan)
 
-		err = actions.Fetch(context.TODO(), destRef, srcDB, destDB, cm, progChan)
+		err = actions.Fetch(context.TODO(), destRef, srcDB, destDB, cm, checkpoint, progChan)
 
 		close(progChan)
 		<-stopChan
@@ -0,0 +1,94 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"sort"
+
+	"github.com/fatih/color"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+var fsckShortDesc = "Verifies the integrity of the repository."
+var fsckLongDesc = `Checks that the data of the repository can be read and hasn't been damaged.  Every chunk of data reachable from
+a branch, remote branch or tag, or from the working set, the staged tables or a merge in progress must be stored in the
+repository and hash to its address.  The manifest and the index of every table file are validated, and every row of every
+table of every commit is decoded against the schema of its table.
+
+The chunks that are missing or corrupt are listed, along with the commits and tables they affect.  The exit code is 1
+if any problems are found.`
+
+var fsckSynopsis = []string{
+	"",
+}
+
+func Fsck(commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	help, usage := cli.HelpAndUsagePrinters(commandStr, fsckShortDesc, fsckLongDesc, fsckSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 0 {
+		usage()
+		return 1
+	}
+
+	report, err := dEnv.Fsck(context.Background())
+
+	if err != nil {
+		verr := errhand.BuildDError("An error occurred checking the repository.").AddCause(err).Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	if report.OK() {
+		cli.Println(color.GreenString("No problems found."))
+		return 0
+	}
+
+	for _, problem := range report.StoreProblems {
+		cli.Println(color.RedString(problem))
+	}
+
+	for _, h := range sortedHashes(report.Missing) {
+		cli.Println(color.RedString("chunk %s is missing", h.String()))
+	}
+
+	for _, h := range sortedHashes(report.Corrupt) {
+		cli.Println(color.RedString("chunk %s is corrupt", h.String()))
+	}
+
+	for _, problem := range report.Problems {
+		cli.Println(color.RedString(problem.String()))
+	}
+
+	cli.PrintErrln(color.RedString("Found %d missing chunks and %d corrupt chunks, affecting %d tables and commits.",
+		len(report.Missing), len(report.Corrupt), len(report.Problems)))
+	return 1
+}
+
+func sortedHashes(hs hash.HashSet) hash.HashSlice {
+	sorted := make(hash.HashSlice, 0, len(hs))
+	for h := range hs {
+		sorted = append(sorted, h)
+	}
+
+	sort.Sort(sorted)
+	return sorted
+}
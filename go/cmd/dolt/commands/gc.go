@@ -0,0 +1,79 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/store/datas"
+	"github.com/liquidata-inc/dolt/go/store/nbs"
+)
+
+var gcShortDesc = "Removes data that is no longer referenced."
+var gcLongDesc = `Removes the data of the repository that can't be reached from any branch, remote branch or tag, or from the working 
+set, the staged tables or a merge in progress.  Data is left behind by commands like <b>dolt reset --hard</b>, 
+<b>dolt branch -d</b> and <b>dolt merge --abort</b>, and by every change to the working set that is overwritten.
+
+The data that is kept is written to new table files before the old table files are removed, so the repository is left 
+as it was if the collection fails.  If the repository is written to while it's being collected, nothing is removed and 
+<b>dolt gc</b> fails, and should be run again.
+
+The data pulled by a <b>dolt fetch</b> or <b>dolt pull</b> that was interrupted isn't reachable yet, so it's removed, 
+and the next fetch starts over instead of resuming.  So does the next push of an interrupted <b>dolt push</b>.
+
+The old table files aren't deleted until the next time <b>dolt gc</b> is run, so that a process that was reading the 
+repository when it was collected, like a running <b>dolt sql-server</b>, can keep reading them.  Such processes should be 
+restarted before <b>dolt gc</b> is run again.`
+
+var gcSynopsis = []string{
+	"",
+}
+
+func GC(commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	help, usage := cli.HelpAndUsagePrinters(commandStr, gcShortDesc, gcLongDesc, gcSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 0 {
+		usage()
+		return 1
+	}
+
+	stats, err := dEnv.CollectGarbage(context.Background())
+
+	var verr errhand.VerboseError
+	switch err {
+	case nil:
+	case datas.ErrGCUnsupported:
+		verr = errhand.BuildDError("This repository's storage does not support garbage collection.").Build()
+	case nbs.ErrGCStoreChanged, nbs.ErrGCUncommittedChunks:
+		verr = errhand.BuildDError("The repository was written to while it was being collected.  Run dolt gc again.").AddCause(err).Build()
+	default:
+		verr = errhand.BuildDError("An error occurred collecting garbage.").AddCause(err).Build()
+	}
+
+	if verr != nil {
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	cli.Println(color.CyanString("Removed %d chunks and kept %d.", stats.Removed, stats.Kept))
+	return 0
+}
@@ -290,6 +290,12 @@ func printConflicts(tblToStats map[string]*merge.MergeStats) bool {
 
 			hasConflicts = true
 		}
+
+		if stats.ForeignKeyViolations > 0 {
+			cli.Println(fmt.Sprintf("CONFLICT (foreign key): %d rows in %s violate foreign key constraints", stats.ForeignKeyViolations, tblName))
+
+			hasConflicts = true
+		}
 	}
 
 	return hasConflicts
@@ -29,7 +29,8 @@ var pullLongDesc = "Incorporates changes from a remote repository into the curre
 	"<b>dolt pull</b> is shorthand for <b>dolt fetch</b> followed by <b>dolt merge <remote>/<branch></b>." +
 	"\n" +
 	"\nMore precisely, dolt pull runs dolt fetch with the given parameters and calls dolt merge to merge the retrieved " +
-	"branch heads into the current branch."
+	"branch heads into the current branch.  Like dolt fetch, an interrupted pull picks up where it left off when it is " +
+	"run again."
 var pullSynopsis = []string{
 	"<remote>",
 }
@@ -81,7 +82,7 @@ func pullRemoteBranch(dEnv *env.DoltEnv, r env.Remote, srcRef, destRef ref.DoltR
 		return errhand.BuildDError("error: failed to get remote db").AddCause(err).Build()
 	}
 
-	verr := fetchRemoteBranch(r, srcDB, dEnv.DoltDB, srcRef, destRef)
+	verr := fetchRemoteBranch(r, srcDB, dEnv.DoltDB, srcRef, destRef, dEnv.PullCheckpoint(""))
 
 	if verr != nil {
 		return verr
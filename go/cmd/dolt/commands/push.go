@@ -50,7 +50,10 @@ var pushLongDesc = "Updates remote refs using local refs, while sending objects
 	"\n" +
 	"\nWhen neither the command-line does not specify what to push, the default behavior is used, which corresponds to the " +
 	"current branch being pushed to the corresponding upstream branch, but as a safety measure, the push is aborted if " +
-	"the upstream branch does not have the same name as the local one."
+	"the upstream branch does not have the same name as the local one." +
+	"\n" +
+	"\nThe progress of a push is saved as it goes, so a push that is interrupted picks up where it left off when it is " +
+	"run again."
 
 var pushSynopsis = []string{
 	"[-u | --set-upstream] [<remote>] [<refspec>]",
@@ -176,7 +179,7 @@ func Push(commandStr string, args []string, dEnv *env.DoltEnv) int {
 				} else if src == ref.EmptyBranchRef {
 					verr = deleteRemoteBranch(ctx, dest, remoteRef, dEnv.DoltDB, destDB, remote)
 				} else {
-					verr = pushToRemoteBranch(ctx, src, dest, remoteRef, dEnv.DoltDB, destDB, remote)
+					verr = pushToRemoteBranch(ctx, src, dest, remoteRef, dEnv.DoltDB, destDB, remote, dEnv.PullCheckpoint(remote.Name))
 				}
 			}
 
@@ -230,7 +233,7 @@ func deleteRemoteBranch(ctx context.Context, toDelete, remoteRef ref.DoltRef, lo
 	return nil
 }
 
-func pushToRemoteBranch(ctx context.Context, srcRef, destRef, remoteRef ref.DoltRef, localDB, remoteDB *doltdb.DoltDB, remote env.Remote) errhand.VerboseError {
+func pushToRemoteBranch(ctx context.Context, srcRef, destRef, remoteRef ref.DoltRef, localDB, remoteDB *doltdb.DoltDB, remote env.Remote, checkpoint datas.PullCheckpoint) errhand.VerboseError {
 	cs, _ := doltdb.NewCommitSpec("HEAD", srcRef.GetPath())
 	cm, err := localDB.Resolve(ctx, cs)
 
@@ -241,7 +244,7 @@ func pushToRemoteBranch(ctx context.Context, srcRef, destRef, remoteRef ref.Dolt
 		stopChan := make(chan struct{})
 		go progFunc(progChan, stopChan)
 
-		err = actions.Push(ctx, destRef.(ref.BranchRef), remoteRef.(ref.RemoteRef), localDB, remoteDB, cm, progChan)
+		err = actions.Push(ctx, destRef.(ref.BranchRef), remoteRef.(ref.RemoteRef), localDB, remoteDB, cm, checkpoint, progChan)
 
 		close(progChan)
 		<-stopChan
@@ -33,6 +33,7 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/config"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/earl"
+	"github.com/liquidata-inc/dolt/go/store/nbs"
 )
 
 var ErrInvalidPort = errors.New("invalid port")
@@ -59,9 +60,24 @@ var remoteLongDesc = "With no arguments, shows a list of existing remotes. Sever
 	"\tenv: Looks for environment variables AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY\n" +
 	"\tfile: Uses the credentials file specified by the parameter aws-creds-file\n" +
 	"\n" +
+	"S3-compatible object stores without DynamoDB, such as MinIO or Ceph, can be used with the optional parameters " +
+	"aws-endpoint, which sets the url of the object store, aws-s3-path-style, which addresses buckets in the path of " +
+	"requests rather than the host name, and aws-manifest, which sets where the manifest of the database is stored. " +
+	"Valid values of aws-manifest are 'dynamodb', 's3', or 'lockfile'. Remotes whose manifest isn't in DynamoDB may " +
+	"use urls of the form aws://s3-bucket/database.\n" +
+	"\n" +
+	"\tdynamodb: Stores the manifest in the dynamo table (This is the default)\n" +
+	"\ts3: Stores the manifest in the s3 bucket and updates it with S3 conditional writes. The object store must support " +
+	"If-Match and If-None-Match on PutObject, which is checked when the remote is used, or else lockfile must be used.\n" +
+	"\tlockfile: Stores the manifest in the s3 bucket and updates it while holding a lock on the local file specified " +
+	"by the parameter aws-manifest-lock-file. Every writer of the remote must share the lock file.\n" +
+	"\n" +
 	"GCP remote urls should be of the form gs://gcs-bucket/database and will use the credentials setup using the gcloud " +
 	"command line available from Google" +
 	"\n" +
+	"\n" +
+	"Tables pushed to file, aws and gs remotes are compressed with snappy unless the optional parameter compression is " +
+	"set to 'zstd'. Every version of dolt which reads the remote must support the compression.\n" +
 	"The local filesystem can be used as a remote by providing a repository url in the format file://absolute path. See" +
 	"https://en.wikipedia.org/wiki/File_URI_scheme for details." +
 	"\n" +
@@ -71,7 +87,7 @@ var remoteLongDesc = "With no arguments, shows a list of existing remotes. Sever
 
 var remoteSynopsis = []string{
 	"[-v | --verbose]",
-	"add [--aws-region <region>] [--aws-creds-type <creds-type>] [--aws-creds-file <file>] [--aws-creds-profile <profile>] <name> <url>",
+	"add [--aws-region <region>] [--aws-creds-type <creds-type>] [--aws-creds-file <file>] [--aws-creds-profile <profile>] [--aws-endpoint <url>] [--aws-s3-path-style <true|false>] [--aws-manifest <manifest>] [--aws-manifest-lock-file <file>] [--compression <compression>] <name> <url>",
 	"remove <name>",
 }
 
@@ -82,8 +98,24 @@ const (
 	DolthubHostName = "dolthub.com"
 )
 
-var awsParams = []string{dbfactory.AWSRegionParam, dbfactory.AWSCredsTypeParam, dbfactory.AWSCredsFileParam, dbfactory.AWSCredsProfile}
+var awsParams = []string{dbfactory.AWSRegionParam, dbfactory.AWSCredsTypeParam, dbfactory.AWSCredsFileParam, dbfactory.AWSCredsProfile,
+	dbfactory.AWSEndpointParam, dbfactory.AWSS3PathStyleParam, dbfactory.AWSManifestParam, dbfactory.AWSManifestLockFileParam}
 var credTypes = []string{dbfactory.RoleCS.String(), dbfactory.EnvCS.String(), dbfactory.FileCS.String()}
+var compressionTypes = []string{nbs.SnappyCompression.String(), nbs.ZstdCompression.String()}
+var manifestTypes = []string{dbfactory.DynamoDBManifest.String(), dbfactory.S3Manifest.String(), dbfactory.LockFileManifest.String()}
+
+// supportsRemoteParams adds the parameters of remotes to |ap|
+func supportsRemoteParams(ap *argparser.ArgParser) {
+	ap.SupportsValidatedString(dbfactory.CompressionParam, "", "compression", "Compression of the tables pushed to the remote.  Valid options are snappy and zstd.", argparser.ValidatorFromStrList(dbfactory.CompressionParam, compressionTypes))
+	ap.SupportsString(dbfactory.AWSRegionParam, "", "region", "")
+	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, credTypes))
+	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file.")
+	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use.")
+	ap.SupportsString(dbfactory.AWSEndpointParam, "", "url", "url of an S3-compatible object store to use instead of S3.")
+	ap.SupportsValidatedString(dbfactory.AWSS3PathStyleParam, "", "true|false", "Address buckets in the path of requests rather than the host name.", argparser.ValidatorFromStrList(dbfactory.AWSS3PathStyleParam, []string{"true", "false"}))
+	ap.SupportsValidatedString(dbfactory.AWSManifestParam, "", "manifest", "Where the manifest is stored.  Valid options are dynamodb, s3, and lockfile.", argparser.ValidatorFromStrList(dbfactory.AWSManifestParam, manifestTypes))
+	ap.SupportsString(dbfactory.AWSManifestLockFileParam, "", "file", "Local lock file used by the lockfile manifest.")
+}
 
 func Remote(commandStr string, args []string, dEnv *env.DoltEnv) int {
 	ctx := context.Background()
@@ -93,10 +125,7 @@ func Remote(commandStr string, args []string, dEnv *env.DoltEnv) int {
 	ap.ArgListHelp["creds-type"] = "credential type.  Valid options are role, env, and file.  See the help section for additional details."
 	ap.ArgListHelp["profile"] = "AWS profile to use."
 	ap.SupportsFlag(verboseFlag, "v", "When printing the list of remotes adds additional details.")
-	ap.SupportsString(dbfactory.AWSRegionParam, "", "region", "")
-	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, credTypes))
-	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file")
-	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use")
+	supportsRemoteParams(ap)
 	help, usage := cli.HelpAndUsagePrinters(commandStr, remoteShortDesc, remoteLongDesc, remoteSynopsis, ap)
 	apr := cli.ParseArgs(ap, args, help)
 
@@ -272,7 +301,19 @@ func parseRemoteArgs(apr *argparser.ArgParseResults, scheme, remoteUrl string) (
 		verr = verifyNoAwsParams(apr)
 	}
 
-	return params, verr
+	if verr != nil {
+		return nil, verr
+	}
+
+	if compression, ok := apr.GetValue(dbfactory.CompressionParam); ok {
+		if scheme != dbfactory.FileScheme && scheme != dbfactory.AWSScheme && scheme != dbfactory.GSScheme {
+			return nil, errhand.BuildDError("The parameter %s is only valid for file, aws and gs remotes", dbfactory.CompressionParam).SetPrintUsage().Build()
+		}
+
+		params[dbfactory.CompressionParam] = compression
+	}
+
+	return params, nil
 }
 
 func addAWSParams(remoteUrl string, apr *argparser.ArgParseResults, params map[string]string) errhand.VerboseError {
@@ -281,7 +322,7 @@ func addAWSParams(remoteUrl string, apr *argparser.ArgParseResults, params map[s
 	if !isAWS {
 		for _, p := range awsParams {
 			if _, ok := apr.GetValue(p); ok {
-				return errhand.BuildDError(p + " param is only valid for aws cloud remotes in the format aws://dynamo-table:s3-bucket/database").Build()
+				return errhand.BuildDError(p + " param is only valid for aws cloud remotes in the format aws://dynamo-table:s3-bucket/database or aws://s3-bucket/database").Build()
 			}
 		}
 	}
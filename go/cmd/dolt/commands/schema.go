@@ -74,7 +74,7 @@ func Schema(commandStr string, args []string, dEnv *env.DoltEnv) int {
 	ap.ArgListHelp["table"] = "table(s) whose schema is being displayed."
 	ap.ArgListHelp["commit"] = "commit at which point the schema will be displayed."
 	ap.SupportsFlag(exportFlag, "", "exports schema into file.")
-	ap.SupportsString(defaultParam, "", "default-value", "If provided all existing rows will be given this value as their default.")
+	ap.SupportsString(defaultParam, "", "default-value", "If provided all existing rows will be given this value, and it will be used as the default for rows that don't supply one.")
 	ap.SupportsUint(tagParam, "", "tag-number", "The numeric tag for the new column.")
 	ap.SupportsFlag(notNullFlag, "", "If provided rows without a value in this column will be considered invalid.  If rows already exist and not-null is specified then a default value must be provided.")
 	ap.SupportsFlag(addFieldFlag, "", "add columm to table schema.")
@@ -260,16 +260,35 @@ func addField(apr *argparser.ArgParseResults, root *doltdb.RootValue, dEnv *env.
 
 	newFieldType := strings.ToLower(apr.Arg(2))
 	newFieldKind, ok := schema.LwrStrToKind[newFieldType]
+
+	// JSON columns hold strings, with the type recorded by name in the schema
+	var newFieldTypeName string
+	if newFieldType == schema.JSONTypeName {
+		newFieldKind, newFieldTypeName, ok = types.StringKind, schema.JSONTypeName, true
+	}
+
 	if !ok {
 		return errhand.BuildDError(newFieldType + " is not a valid type for this new column.").SetPrintUsage().Build()
 	}
 
 	var defaultVal types.Value
+	var defaultExpr string
 	if val, ok := apr.GetValue(defaultParam); ok {
-		if nomsVal, err := doltcore.StringToValue(val, newFieldKind); err != nil {
+		var nomsVal types.Value
+		var err error
+		if newFieldTypeName == schema.JSONTypeName {
+			nomsVal, err = doltcore.StringToJSON(val)
+		} else {
+			nomsVal, err = doltcore.StringToValue(val, newFieldKind)
+		}
+
+		if err != nil {
 			return errhand.VerboseErrorFromError(err)
 		} else {
 			defaultVal = nomsVal
+			if !types.IsNull(nomsVal) {
+				defaultExpr = sql.FmtValue(nomsVal)
+			}
 		}
 	}
 
@@ -278,7 +297,7 @@ func addField(apr *argparser.ArgParseResults, root *doltdb.RootValue, dEnv *env.
 		nullable = alterschema.NotNull
 	}
 
-	newTable, err := alterschema.AddColumnToTable(context.TODO(), dEnv.DoltDB, tbl, tag, newFieldName, newFieldKind, nullable, defaultVal)
+	newTable, err := alterschema.AddColumnToTable(context.TODO(), dEnv.DoltDB, tbl, tag, newFieldName, newFieldKind, newFieldTypeName, nullable, defaultVal, defaultExpr)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
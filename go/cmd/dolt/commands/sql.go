@@ -15,8 +15,6 @@
 package commands
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -63,7 +61,11 @@ dolt-interest@liquidata.co
 Reasonably well supported functionality:
 * SELECT statements, including most kinds of joins
 * CREATE TABLE statements
-* ALTER TABLE / DROP TABLE statements
+* ALTER TABLE / DROP TABLE statements, including ADD PRIMARY KEY to redefine the primary key
+* Foreign keys referencing primary keys (RESTRICT only)
+* Column DEFAULT values and AUTO_INCREMENT integer primary keys
+* JSON columns, with JSON_EXTRACT in SELECT statements
+* Running scripts and mysqldump files piped to stdin, e.g. dolt sql < dump.sql
 * UPDATE and DELETE statements
 * Table and column aliases
 * Column functions, e.g. CONCAT
@@ -75,7 +77,6 @@ Known limitations:
 * Some expressions in SELECT statements
 * Subqueries
 * Non-primary indexes
-* Foreign keys
 * Column constraints besides NOT NULL
 * VARCHAR columns are unlimited length; FLOAT, INTEGER columns are 64 bit
 * Performance is very bad for many SELECT statements, especially JOINs
@@ -137,49 +138,58 @@ func Sql(commandStr string, args []string, dEnv *env.DoltEnv) int {
 	return 0
 }
 
-// ScanStatements is a split function for a Scanner that returns each SQL statement in the input as a token. It doesn't
-// work for strings that contain semi-colons. Supporting that requires implementing a state machine.
-func scanStatements(data []byte, atEOF bool) (advance int, token []byte, err error) {
-	if atEOF && len(data) == 0 {
-		return 0, nil, nil
-	}
-	if i := bytes.IndexByte(data, ';'); i >= 0 {
-		// We have a full ;-terminated line.
-		return i + 1, data[0:i], nil
-	}
-	// If we're at EOF, we have a final, non-terminated line. Return it.
-	if atEOF {
-		return len(data), data, nil
+// runBatchMode processes queries until EOF and returns the resulting root value. Session statements in scripts like
+// mysqldump files, e.g. SET and LOCK TABLES, are skipped.
+func runBatchMode(dEnv *env.DoltEnv, root *doltdb.RootValue) *doltdb.RootValue {
+	root, err := RunSqlScript(dEnv, root, os.Stdin, true)
+
+	if err != nil {
+		cli.Println(err.Error())
 	}
-	// Request more data.
-	return 0, nil, nil
-}
 
-// runBatchMode processes queries until EOF and returns the resulting root value
-func runBatchMode(dEnv *env.DoltEnv, root *doltdb.RootValue) *doltdb.RootValue {
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Split(scanStatements)
+	return root
+}
 
+// RunSqlScript runs the statements of a SQL script, like a mysqldump file, and returns the resulting root value. When
+// contOnErr is true the statements that fail are printed and skipped, and the root value is returned along with any
+// error reading the script. Otherwise the script stops at the first statement that fails, and a nil root value is
+// returned with the error.
+func RunSqlScript(dEnv *env.DoltEnv, root *doltdb.RootValue, rd io.Reader, contOnErr bool) (*doltdb.RootValue, error) {
+	scanner := dsql.NewStatementScanner(rd)
 	batcher := dsql.NewSqlBatcher(dEnv.DoltDB, root)
 
 	for scanner.Scan() {
 		query := scanner.Text()
+		if dsql.IsSessionStatement(query) {
+			continue
+		}
+
 		if newRoot, err := processBatchQuery(query, dEnv, root, batcher); newRoot != nil {
 			root = newRoot
 		} else if err != nil {
+			if !contOnErr {
+				return nil, fmt.Errorf("Error processing query '%s': %s", query, err.Error())
+			}
+
 			_, _ = fmt.Fprintf(cli.CliErr, "Error processing query '%s': %s\n", query, err.Error())
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		cli.Println(err.Error())
+	err := scanner.Err()
+
+	if err != nil && !contOnErr {
+		return nil, err
 	}
 
-	if newRoot, _ := batcher.Commit(context.Background()); newRoot != nil {
+	newRoot, cmErr := batcher.Commit(context.Background())
+
+	if newRoot != nil {
 		root = newRoot
+	} else if cmErr != nil && !contOnErr {
+		return nil, cmErr
 	}
 
-	return root
+	return root, err
 }
 
 // runShell starts a SQL shell. Returns when the user exits the shell with the root value resulting from any queries.
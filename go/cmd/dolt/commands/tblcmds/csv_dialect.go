@@ -0,0 +1,166 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tblcmds
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/mvdata"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+const (
+	delimParam          = "delim"
+	noHeaderParam       = "no-header"
+	columnsParam        = "columns"
+	quoteParam          = "quote"
+	escapeParam         = "escape"
+	nullParam           = "null"
+	encodingParam       = "encoding"
+	lineTerminatorParam = "line-terminator"
+)
+
+var csvDialectParams = []string{delimParam, noHeaderParam, columnsParam, quoteParam, escapeParam, nullParam, encodingParam, lineTerminatorParam}
+
+var csvDialectHelp = `The dialect of a csv or psv file can be changed with these parameters:
+	<b>--delim</b> is the field delimiter, e.g. ';'. Use 'tab' or '\t' for tab separated files.
+	<b>--no-header</b> says that the file doesn't have a header line. The names of the columns of an imported file are
+	  then given with <b>--columns</b>, in the order they appear in the file, e.g. --columns id,name,age
+	<b>--quote</b> is the character fields are quoted with, '"' by default.
+	<b>--escape</b> is a character that escapes a quote within a quoted field, like '\'. Quotes are escaped by doubling
+	  them by default.
+	<b>--null</b> is a comma separated list of values that are read as NULL, like 'NULL,\N'. Empty values are always
+	  NULL, and NULLs are exported as the first of these values. Quoted values aren't NULL, so strings equal to one of
+	  these values are exported quoted.
+	<b>--encoding</b> is the character encoding of the file: ` + strings.Join(csv.SupportedEncodings, ", ") + `. utf-8
+	  by default.
+	<b>--line-terminator</b> is what lines end with: lf, crlf or cr. lf by default, which reads crlf as well.
+`
+
+// addCSVDialectArgs adds the parameters that describe the dialect of a csv or psv file. The names of the columns are
+// only given when a file is read.
+func addCSVDialectArgs(ap *argparser.ArgParser, isImport bool) {
+	ap.SupportsString(delimParam, "", "delimiter", "The field delimiter of a csv or psv file.")
+	ap.SupportsFlag(noHeaderParam, "", "The csv or psv file doesn't have a header line with the names of its columns.")
+
+	if isImport {
+		ap.SupportsString(columnsParam, "", "columns", "The comma separated names of the columns of a csv or psv file without a header line.")
+	}
+
+	ap.SupportsString(quoteParam, "", "quote_char", "The character that fields of a csv or psv file are quoted with.")
+	ap.SupportsString(escapeParam, "", "escape_char", "The character that escapes quotes within the quoted fields of a csv or psv file.")
+	ap.SupportsString(nullParam, "", "null_values", "The comma separated values of a csv or psv file that are NULL.")
+	ap.SupportsString(encodingParam, "", "encoding", "The character encoding of a csv or psv file.")
+	ap.SupportsValidatedString(lineTerminatorParam, "", "line_terminator", "The line terminator of a csv or psv file: lf, crlf or cr.",
+		argparser.ValidatorFromStrList(lineTerminatorParam, []string{"lf", "crlf", "cr"}))
+}
+
+// setCSVDialect sets the CSVInfo of a file's DataLocation from the dialect parameters. Returns false if the parameters
+// are invalid, after printing the error.
+func setCSVDialect(apr *argparser.ArgParseResults, fileLoc *mvdata.DataLocation, isImport bool) bool {
+	if !apr.ContainsAny(csvDialectParams...) {
+		return true
+	}
+
+	if fileLoc.Format != mvdata.CsvFile && fileLoc.Format != mvdata.PsvFile {
+		cli.PrintErrln(color.RedString("The --%s parameter can only be used with csv and psv files.", firstContained(apr, csvDialectParams)))
+		return false
+	}
+
+	info := csv.NewCSVInfo()
+	if fileLoc.Format == mvdata.PsvFile {
+		info.SetDelim("|")
+	}
+
+	if delim, ok := apr.GetValue(delimParam); ok {
+		if delim == "tab" || delim == `\t` {
+			delim = "\t"
+		}
+
+		info.SetDelim(delim)
+	}
+
+	if apr.Contains(noHeaderParam) {
+		info.SetHasHeaderLine(false)
+
+		if isImport && !apr.Contains(columnsParam) {
+			cli.PrintErrln(color.RedString("The --%s parameter is required to import a file without a header line.", columnsParam))
+			return false
+		}
+	}
+
+	if columns, ok := apr.GetValue(columnsParam); ok {
+		if !apr.Contains(noHeaderParam) {
+			cli.PrintErrln(color.RedString("The --%s parameter can only be used with --%s.", columnsParam, noHeaderParam))
+			return false
+		}
+
+		info.SetColumns(splitList(columns))
+	}
+
+	if quote, ok := apr.GetValue(quoteParam); ok {
+		info.SetQuote(quote)
+	}
+
+	if escape, ok := apr.GetValue(escapeParam); ok {
+		info.SetEscape(escape)
+	}
+
+	if nulls, ok := apr.GetValue(nullParam); ok {
+		info.SetNullStrings(splitList(nulls))
+	}
+
+	if encoding, ok := apr.GetValue(encodingParam); ok {
+		info.SetEncoding(encoding)
+	}
+
+	switch lt, _ := apr.GetValue(lineTerminatorParam); strings.ToLower(lt) {
+	case "crlf":
+		info.SetLineTerminator(csv.CRLF)
+	case "cr":
+		info.SetLineTerminator(csv.CR)
+	}
+
+	if err := info.Validate(); err != nil {
+		cli.PrintErrln(color.RedString("Invalid csv dialect: %s", err.Error()))
+		return false
+	}
+
+	fileLoc.CSVInfo = info
+	return true
+}
+
+func firstContained(apr *argparser.ArgParseResults, params []string) string {
+	for _, param := range params {
+		if apr.Contains(param) {
+			return param
+		}
+	}
+
+	return ""
+}
+
+func splitList(list string) []string {
+	items := strings.Split(list, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+
+	return items
+}
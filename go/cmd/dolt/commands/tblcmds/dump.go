@@ -0,0 +1,297 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tblcmds
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/fatih/color"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/mvdata"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/sql"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
+)
+
+const (
+	// DefaultDumpFile is the file a sql dump is written to and loaded from when no path is given
+	DefaultDumpFile = "doltdump.sql"
+
+	// DefaultDumpDir is the directory a dump of csv, json or parquet files is written to and loaded from when no path
+	// is given
+	DefaultDumpDir = "doltdump"
+
+	// foreignKeysFile is the sql script of a dump directory that adds the foreign keys of the dumped tables
+	foreignKeysFile = "foreign_keys.sql"
+
+	// schemaFileExt is the extension of the schema files of a dump directory, which are named after their tables
+	schemaFileExt = ".schema.json"
+)
+
+var dumpShortDesc = "Export every table of the working set or a commit."
+var dumpLongDesc = `dolt dump exports every table of the working set, or of a commit with <b>--commit</b>, so that the database can be
+restored with <b>dolt load</b> or moved to another database.
+
+By default the tables are written to a single sql script, doltdump.sql, which drops and creates each table and inserts its
+rows.  The foreign keys between the tables are added at the end of the script, so the script can be run by MySQL, e.g.:
+
+	dolt dump && mysql mydb < doltdump.sql
+
+With <b>--file-type</b> csv, json or parquet the tables are written to a directory, doltdump by default, with a file for
+each table named after the table.  The schema of each table is written next to it in <table>.schema.json, and the
+foreign keys of the tables are written to foreign_keys.sql.
+
+The dump isn't written if <path> already exists, unless <b>--force | -f</b> is given.  Forcing a dump to an existing
+directory overwrites the files of the dumped tables and leaves any other files in place.`
+
+var dumpSynopsis = []string{
+	"[-f] [--commit <commit>] [--file-type <type>] [<path>]",
+}
+
+func Dump(commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["path"] = "The sql file, or the directory for other file types, that the tables are written to."
+	ap.SupportsFlag(forceParam, "f", "Overwrite the dump if <path> already exists.")
+	ap.SupportsString(commitParam, "", "commit", "Dump the tables of a commit rather than the working set.")
+	ap.SupportsString(fileTypeParam, "", "file_type", "The type of the files the tables are written to: sql, csv, json or parquet. sql by default.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, dumpShortDesc, dumpLongDesc, dumpSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() > 1 {
+		usage()
+		return 1
+	}
+
+	format := mvdata.DFFromString(apr.GetValueOrDefault(fileTypeParam, "sql"))
+
+	switch format {
+	case mvdata.SqlFile, mvdata.CsvFile, mvdata.JsonFile, mvdata.ParquetFile:
+	default:
+		cli.PrintErrln(color.RedString("Tables can only be dumped to sql, csv, json or parquet files."))
+		return 1
+	}
+
+	path := DefaultDumpDir
+	if format == mvdata.SqlFile {
+		path = DefaultDumpFile
+	}
+
+	if apr.NArg() == 1 {
+		path = apr.Arg(0)
+	}
+
+	if exists, _ := dEnv.FS.Exists(path); exists && !apr.Contains(forceParam) {
+		cli.PrintErrln(color.RedString("Data already exists in %s.  Use -f to overwrite.", path))
+		return 1
+	}
+
+	root, verr := exportRoot(dEnv, apr)
+
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	tableNames, err := root.GetTableNames(context.TODO())
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to read the tables to dump: %s", err.Error()))
+		return 1
+	} else if len(tableNames) == 0 {
+		cli.PrintErrln(color.RedString("There are no tables to dump."))
+		return 1
+	}
+
+	var res int
+	if format == mvdata.SqlFile {
+		res = dumpToSqlFile(dEnv, root, tableNames, path)
+	} else {
+		res = dumpToDir(dEnv, root, tableNames, path, format)
+	}
+
+	if res == 0 {
+		cli.Println(color.CyanString("Successfully dumped %d tables to %s.", len(tableNames), path))
+	}
+
+	return res
+}
+
+// dumpToSqlFile writes the tables to a single sql script, followed by the statements adding their foreign keys.
+func dumpToSqlFile(dEnv *env.DoltEnv, root *doltdb.RootValue, tableNames []string, path string) int {
+	err := dEnv.FS.MkDirs(filepath.Dir(path))
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to create %s: %s", path, err.Error()))
+		return 1
+	}
+
+	wr, err := dEnv.FS.OpenForWrite(path)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to create %s: %s", path, err.Error()))
+		return 1
+	}
+
+	for _, tableName := range tableNames {
+		mvOpts := &mvdata.MoveOptions{
+			Operation: mvdata.OverwriteOp,
+			TableName: tableName,
+			Src:       &mvdata.DataLocation{Path: tableName, Format: mvdata.DoltDB},
+			Dest:      &mvdata.DataLocation{Path: path, Format: mvdata.SqlFile, OutStream: iohelp.NopWrCloser(wr)},
+		}
+
+		if res := moveFromRoot(dEnv, root, true, mvOpts); res != 0 {
+			wr.Close()
+			return res
+		}
+	}
+
+	err = writeForeignKeys(context.TODO(), root, wr)
+	errCl := wr.Close()
+
+	if err == nil {
+		err = errCl
+	}
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to write %s: %s", path, err.Error()))
+		return 1
+	}
+
+	return 0
+}
+
+// dumpToDir writes each table and its schema to files named after the table in the directory given, and writes the
+// statements adding the foreign keys of the tables to a sql script in the directory.
+func dumpToDir(dEnv *env.DoltEnv, root *doltdb.RootValue, tableNames []string, dir string, format mvdata.DataFormat) int {
+	ctx := context.TODO()
+
+	for _, tableName := range tableNames {
+		tbl, _, err := root.GetTable(ctx, tableName)
+
+		if err != nil {
+			cli.PrintErrln(color.RedString("Unable to read table %s: %s", tableName, err.Error()))
+			return 1
+		}
+
+		sch, err := tbl.GetSchema(ctx)
+
+		if err != nil {
+			cli.PrintErrln(color.RedString("Unable to read the schema of %s: %s", tableName, err.Error()))
+			return 1
+		}
+
+		schJson, err := encoding.MarshalAsJson(sch)
+
+		if err == nil {
+			err = dEnv.FS.MkDirs(dir)
+		}
+
+		if err == nil {
+			err = dEnv.FS.WriteFile(filepath.Join(dir, tableName+schemaFileExt), []byte(schJson))
+		}
+
+		if err != nil {
+			cli.PrintErrln(color.RedString("Unable to write the schema of %s: %s", tableName, err.Error()))
+			return 1
+		}
+
+		mvOpts := &mvdata.MoveOptions{
+			Operation: mvdata.OverwriteOp,
+			TableName: tableName,
+			Src:       &mvdata.DataLocation{Path: tableName, Format: mvdata.DoltDB},
+			Dest:      &mvdata.DataLocation{Path: filepath.Join(dir, tableName+string(format)), Format: format},
+		}
+
+		if res := moveFromRoot(dEnv, root, true, mvOpts); res != 0 {
+			return res
+		}
+	}
+
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to read the foreign keys of the tables: %s", err.Error()))
+		return 1
+	} else if len(fks) == 0 {
+		return 0
+	}
+
+	path := filepath.Join(dir, foreignKeysFile)
+	wr, err := dEnv.FS.OpenForWrite(path)
+
+	if err == nil {
+		err = writeForeignKeys(ctx, root, wr)
+		errCl := wr.Close()
+
+		if err == nil {
+			err = errCl
+		}
+	}
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to write %s: %s", path, err.Error()))
+		return 1
+	}
+
+	return 0
+}
+
+// writeForeignKeys writes an alter table statement for each foreign key of the root value
+func writeForeignKeys(ctx context.Context, root *doltdb.RootValue, wr io.Writer) error {
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, fk := range fks {
+		tbl, _, err := root.GetTable(ctx, fk.TableName)
+
+		if err != nil {
+			return err
+		}
+
+		sch, err := tbl.GetSchema(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		refTbl, _, err := root.GetTable(ctx, fk.ReferencedTableName)
+
+		if err != nil {
+			return err
+		}
+
+		refSch, err := refTbl.GetSchema(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		if err := iohelp.WriteLine(wr, sql.ForeignKeyAsAlterStmt(fk, sch, refSch)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
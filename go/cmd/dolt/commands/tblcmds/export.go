@@ -18,24 +18,39 @@ import (
 	"github.com/fatih/color"
 
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/mvdata"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
 )
 
 var exportShortDesc = `Export the contents of a table to a file.`
 var exportLongDesc = `dolt table export will export the contents of <table> to <file>
 
+If <file> is omitted the data is written to stdout, and <b>--file-type</b> must be given, e.g.:
+
+	dolt table export <table> --file-type csv | gzip > table.csv.gz
+
+Several tables can be exported to an xlsx file, with a sheet for each table named after the table, by listing the tables 
+before the file, or every table with <b>--all</b>.  The sheet written for a single table can be named with <b>--sheet</b>.
+
+The tables are exported from the working set, or from a commit with <b>--commit</b>, e.g.:
+
+	dolt table export --all --commit HEAD~1 tables.xlsx
+
 See the help for <b>dolt table import</b> as the options are the same.`
 var exportSynopsis = []string{
-	"[-f] [-pk <field>] [-schema <file>] [-map <file>] [-continue] [-file-type <type>] <table> <file>",
+	"[-f] [-pk <field>] [-schema <file>] [-map <file>] [-continue] [-file-type <type>] [--commit <commit>] <table> [<file>]",
+	"[-f] [-continue] [--commit <commit>] (--all | <table>...) <file>.xlsx",
 }
 
 // validateExportArgs validates the input from the arg parser, and returns the tuple:
 // (table name to export, data location of table to export, data location to export to)
 func validateExportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter) (string, *mvdata.DataLocation, *mvdata.DataLocation) {
-	if apr.NArg() != 2 {
+	if apr.NArg() != 1 && apr.NArg() != 2 {
 		usage()
 		return "", nil, nil
 	}
@@ -48,35 +63,54 @@ func validateExportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter)
 		return "", nil, nil
 	}
 
-	path := apr.Arg(1)
 	fType, _ := apr.GetValue(fileTypeParam)
-	fileLoc := mvdata.NewDataLocation(path, fType)
+
+	var fileLoc *mvdata.DataLocation
+	if apr.NArg() == 1 {
+		if fType == "" {
+			cli.PrintErrln(color.RedString("The --file-type parameter is required when exporting to stdout."))
+			return "", nil, nil
+		}
+
+		fileLoc = mvdata.NewOutStreamDataLocation(iohelp.NopWrCloser(cli.CliOut), fType)
+	} else {
+		fileLoc = mvdata.NewDataLocation(apr.Arg(1), fType)
+	}
 
 	if fileLoc.Format == mvdata.InvalidDataFormat {
 		cli.PrintErrln(
-			color.RedString("Could not infer type file '%s'\n", path),
+			color.RedString("Could not infer type file '%s'\n", fileLoc.Path),
 			"File extensions should match supported file types, or should be explicitly defined via the file-type parameter")
 		return "", nil, nil
 	}
 
+	if !setCSVDialect(apr, fileLoc, false) || !setXLSXSheet(apr, fileLoc) {
+		return "", nil, nil
+	}
+
 	tableLoc := &mvdata.DataLocation{Path: tableName, Format: mvdata.DoltDB}
 
 	return tableName, tableLoc, fileLoc
 }
 
-func parseExportArgs(commandStr string, args []string) (bool, *mvdata.MoveOptions) {
+func createExportArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
-	ap.ArgListHelp["table"] = "The table being exported."
-	ap.ArgListHelp["file"] = "The file being output to."
+	ap.ArgListHelp["table"] = "The table being exported. Several tables can be exported to an xlsx file."
+	ap.ArgListHelp["file"] = "The file being output to. Omit it to write to stdout."
 	ap.SupportsFlag(forceParam, "f", "If data already exists in the destination, the Force flag will allow the target to be overwritten.")
 	ap.SupportsFlag(contOnErrParam, "", "Continue exporting when row export errors are encountered.")
 	ap.SupportsString(outSchemaParam, "s", "schema_file", "The schema for the output data.")
 	ap.SupportsString(mappingFileParam, "m", "mapping_file", "A file that lays out how fields should be mapped from input data to output data.")
 	ap.SupportsString(primaryKeyParam, "pk", "primary_key", "Explicitly define the name of the field in the schema which should be used as the primary key.")
 	ap.SupportsString(fileTypeParam, "", "file_type", "Explicitly define the type of the file if it can't be inferred from the file extension.")
+	ap.SupportsString(sheetParam, "", "sheet", "The name of the sheet written to an xlsx file. The sheet is named after the table by default.")
+	ap.SupportsFlag(allTablesParam, "", "Export every table to an xlsx file.")
+	ap.SupportsString(commitParam, "", "commit", "Export the tables of a commit rather than the working set.")
+	addCSVDialectArgs(ap, false)
+	return ap
+}
 
-	help, usage := cli.HelpAndUsagePrinters(commandStr, exportShortDesc, exportLongDesc, exportSynopsis, ap)
-	apr := cli.ParseArgs(ap, args, help)
+func parseExportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter) (bool, *mvdata.MoveOptions) {
 	tableName, tableLoc, fileLoc := validateExportArgs(apr, usage)
 
 	if fileLoc == nil || tableLoc == nil {
@@ -100,17 +134,55 @@ func parseExportArgs(commandStr string, args []string) (bool, *mvdata.MoveOption
 }
 
 func Export(commandStr string, args []string, dEnv *env.DoltEnv) int {
-	force, mvOpts := parseExportArgs(commandStr, args)
+	ap := createExportArgParser()
+	help, usage := cli.HelpAndUsagePrinters(commandStr, exportShortDesc, exportLongDesc, exportSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	root, verr := exportRoot(dEnv, apr)
+
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	if apr.Contains(allTablesParam) || apr.NArg() > 2 {
+		return exportTables(dEnv, root, apr, usage)
+	}
+
+	force, mvOpts := parseExportArgs(apr, usage)
 
 	if mvOpts == nil {
 		return 1
 	}
 
-	result := executeMove(dEnv, force, mvOpts)
+	result := moveFromRoot(dEnv, root, force, mvOpts)
 
-	if result == 0 {
+	// only the exported data is written to stdout
+	if result == 0 && !mvOpts.Dest.IsStream() {
 		cli.Println(color.CyanString("Successfully exported data."))
 	}
 
 	return result
 }
+
+// exportRoot returns the root of the commit given with --commit, or the working root
+func exportRoot(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (*doltdb.RootValue, errhand.VerboseError) {
+	cSpecStr, ok := apr.GetValue(commitParam)
+
+	if !ok {
+		return commands.GetWorkingWithVErr(dEnv)
+	}
+
+	cm, verr := commands.ResolveCommitWithVErr(dEnv, cSpecStr, dEnv.RepoState.Head.Ref.String())
+
+	if verr != nil {
+		return nil, verr
+	}
+
+	root, err := cm.GetRootValue()
+
+	if err != nil {
+		return nil, errhand.BuildDError("Unable to get the root value of '%s'", cSpecStr).AddCause(err).Build()
+	}
+
+	return root, nil
+}
@@ -17,6 +17,7 @@ package tblcmds
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/fatih/color"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/mvdata"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/sql"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/pipeline"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/noms"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
@@ -35,6 +37,7 @@ import (
 const (
 	createParam      = "create-table"
 	updateParam      = "update-table"
+	replaceParam     = "replace-table"
 	tableParam       = "table"
 	fileParam        = "file"
 	outSchemaParam   = "schema"
@@ -43,6 +46,13 @@ const (
 	contOnErrParam   = "continue"
 	primaryKeyParam  = "pk"
 	fileTypeParam    = "file-type"
+	dryRunParam      = "dry-run"
+	sheetParam       = "sheet"
+	allSheetsParam   = "all-sheets"
+	allTablesParam   = "all"
+	commitParam      = "commit"
+	rejectsParam     = "rejects"
+	errorReportParam = "error-report"
 )
 
 var schemaFileHelp = "Schema definition files are json files in the format:" + `
@@ -79,83 +89,181 @@ force flag forces the existing table to be overwritten.
 
 The schema for the new table can be specified explicitly by providing a schema definition file, or will be inferred 
 from the imported file.  All schemas, inferred or explicitly defined must define a primary key.  If the file format 
-being imported does not support defining a primary key, then the <b>--pk</b> parameter must supply the name of the 
+being imported does not support defining a primary key, then the <b>--pk</b> parameter can supply the name of the 
 field that should be used as the primary key.
 
+When a csv, psv, xlsx or ndjson file is imported without a schema file, its first 10,000 rows are read to choose the 
+type of each column (bool, int, uint, float, uuid or string, and json for the nested objects and arrays of ndjson 
+files).  Unless <b>--pk</b> is given, the primary key is a column named id, or else the first column whose sampled 
+values are unique and never empty.  The primary key columns are NOT NULL, and so are the columns without empty values 
+if the file has no more than 10,000 rows.  As the rest of a larger file isn't read, a later row with a value that 
+doesn't fit the type of its column, or with a duplicate or empty primary key, fails to import.  Use <b>--dry-run</b> to print the schema that would be used without importing any data, and a schema file or 
+<b>--pk</b> to change it.
+
 ` + schemaFileHelp +
 	`
 If <b>--update-table | -u</b> is given the operation will update <table> with the contents of file. The table's existing 
 schema will be used, and field names will be used to match file fields with table fields unless a mapping file is specified.
 
+If <b>--replace-table | -r</b> is given the operation will replace the rows of <table> with the contents of file, deleting 
+the rows that aren't in the file.  The table's existing schema will be used as with <b>--update-table</b>, so the tags of 
+its columns are kept and <b>dolt diff</b> shows the rows that were added, modified and deleted.
+
 During import, if there is an error importing any row, the import will be aborted by default.  Use the <b>--continue</b>
 flag to continue importing when an error is encountered.
 
+The rows that can't be imported are written to the file given by <b>--rejects</b>, in the format of the file being 
+imported, so that they can be fixed and imported again.  <b>--error-report</b> gives a file that a line of json is 
+written to for each row that can't be imported, with the line of the row, the column that was invalid and the reason it 
+was rejected: a type conversion, a constraint violation, a missing primary key or an invalid row, e.g.:
+
+	dolt table import -u --continue --rejects rejects.csv --error-report errors.json <table> data.csv
+
 A mapping file can be used to map fields between the file being imported and the table being written to.  This can 
-be used when creating a new table, or updating an existing table.
+be used when creating a new table, or updating or replacing an existing table.
 
 ` + mappingFileHelp +
 	`
-In both create and update scenarios the file's extension is used to infer the type of the file.  If a file does not 
+In create, update and replace scenarios the file's extension is used to infer the type of the file.  If a file does not 
 have the expected extension then the <b>--file-type</b> parameter should be used to explicitly define the format of 
-the file in one of the supported formats (csv, psv, nbf, json, xlsx)`
+the file in one of the supported formats (csv, psv, nbf, json, ndjson, xlsx, sql, parquet)
+
+A parquet file exported by dolt keeps the schema of the table it was exported from, including its primary key.  The 
+column types of other parquet files are mapped to the closest dolt types, and <b>--pk</b> is needed to choose the 
+primary key.  Nested and repeated columns, and date and time types, can't be imported.
+
+An ndjson (or jsonl) file has a json object on each line.  The fields of the objects are found by reading the first 
+lines of the file, and nested objects and arrays are imported as json.  A value within a nested object can be imported 
+as a column by using its dotted path, like address.city, as a field name in a mapping file, schema file or the table 
+being updated.
+
+If <file> is omitted the data is read from stdin, and <b>--file-type</b> must be given, e.g.:
+
+	curl https://example.com/data.csv | dolt table import -u <table> --file-type csv
+
+xlsx files can't be read from stdin.
+
+The sheet of an xlsx file that is imported is the one with the same name as <table>, unless another sheet is chosen with 
+<b>--sheet</b>.  With <b>--all-sheets</b> every sheet of the file is imported into the table with the same name as the 
+sheet, and <table> is omitted.  The tables of every sheet are checked before any of them are imported, e.g.:
+
+	dolt table import -c --pk id --all-sheets workbook.xlsx
+
+` + csvDialectHelp + `
+
+A sql file, like one written by mysqldump, is imported by running the CREATE TABLE statement for <table> it contains when 
+creating the table, and its INSERT statements for <table>.  Statements for other tables are ignored.`
 
 var importSynopsis = []string{
-	"-c [-f] [--pk <field>] [--schema <file>] [--map <file>] [--continue] [--file-type <type>] <table> <file>",
-	"-u [--schema <file>] [--map <file>] [--continue] [--file-type <type>] <table> <file>",
+	"-c [-f] [--pk <field>] [--schema <file>] [--map <file>] [--continue] [--rejects <file>] [--error-report <file>] [--file-type <type>] [--dry-run] <table> [<file>]",
+	"-u [--schema <file>] [--map <file>] [--continue] [--rejects <file>] [--error-report <file>] [--file-type <type>] <table> [<file>]",
+	"-r [--map <file>] [--continue] [--rejects <file>] [--error-report <file>] [--file-type <type>] <table> [<file>]",
+	"(-c [-f] [--pk <field>] | -u | -r) [--continue] --all-sheets <file>.xlsx",
 }
 
 func validateImportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter) (mvdata.MoveOperation, *mvdata.DataLocation, *mvdata.DataLocation) {
-	if apr.NArg() != 2 {
+	allSheets := apr.Contains(allSheetsParam)
+	if allSheets && apr.NArg() != 1 || !allSheets && apr.NArg() != 1 && apr.NArg() != 2 {
 		usage()
 		return mvdata.InvalidOp, nil, nil
 	}
 
 	var mvOp mvdata.MoveOperation
-	if !apr.Contains(createParam) && !apr.Contains(updateParam) {
-		cli.PrintErrln("Must include '-c' for initial table import or -u to update existing table.")
-		return mvdata.InvalidOp, nil, nil
-	} else if apr.Contains(createParam) {
-		mvOp = mvdata.OverwriteOp
-	} else {
-		mvOp = mvdata.UpdateOp
-		if apr.Contains(outSchemaParam) {
-			cli.PrintErrln("fatal:", outSchemaParam+"is not supported for update operations")
-			usage()
+	var opParam string
+	for _, op := range []struct {
+		param string
+		mvOp  mvdata.MoveOperation
+	}{{createParam, mvdata.OverwriteOp}, {updateParam, mvdata.UpdateOp}, {replaceParam, mvdata.ReplaceOp}} {
+		if !apr.Contains(op.param) {
+			continue
+		}
+
+		if opParam != "" {
+			cli.PrintErrln(color.RedString("--%s and --%s can't be used together.", opParam, op.param))
 			return mvdata.InvalidOp, nil, nil
 		}
+
+		mvOp, opParam = op.mvOp, op.param
 	}
 
-	tableName := apr.Arg(0)
-	if !doltdb.IsValidTableName(tableName) {
-		cli.PrintErrln(
-			color.RedString("'%s' is not a valid table name\n", tableName),
-			"table names must match the regular expression:", doltdb.TableNameRegexStr)
+	if opParam == "" {
+		cli.PrintErrln("Must include '-c' for initial table import, -u to update existing table, or -r to replace existing table.")
+		return mvdata.InvalidOp, nil, nil
+	} else if mvOp != mvdata.OverwriteOp && apr.Contains(outSchemaParam) {
+		cli.PrintErrln("fatal:", outSchemaParam, "is not supported for", string(mvOp), "operations")
+		usage()
 		return mvdata.InvalidOp, nil, nil
 	}
 
-	path := apr.Arg(1)
+	// every sheet is imported into the table with the same name when --all-sheets is given, so there's no table argument
+	var tableName string
+	fileArgs := apr.Args()
+	if allSheets {
+		for _, param := range []string{outSchemaParam, mappingFileParam, dryRunParam, sheetParam, rejectsParam, errorReportParam} {
+			if apr.Contains(param) {
+				cli.PrintErrln(color.RedString("--%s and --%s can't be used together.", param, allSheetsParam))
+				return mvdata.InvalidOp, nil, nil
+			}
+		}
+	} else {
+		tableName, fileArgs = apr.Arg(0), fileArgs[1:]
+		if !doltdb.IsValidTableName(tableName) {
+			cli.PrintErrln(
+				color.RedString("'%s' is not a valid table name\n", tableName),
+				"table names must match the regular expression:", doltdb.TableNameRegexStr)
+			return mvdata.InvalidOp, nil, nil
+		}
+	}
+
 	fType, _ := apr.GetValue(fileTypeParam)
-	fileLoc := mvdata.NewDataLocation(path, fType)
+
+	var fileLoc *mvdata.DataLocation
+	if len(fileArgs) == 0 {
+		if fType == "" {
+			cli.PrintErrln(color.RedString("The --file-type parameter is required when importing from stdin."))
+			return mvdata.InvalidOp, nil, nil
+		}
+
+		fileLoc = mvdata.NewInStreamDataLocation(os.Stdin, fType)
+	} else {
+		fileLoc = mvdata.NewDataLocation(fileArgs[0], fType)
+	}
 
 	if fileLoc.Format == mvdata.InvalidDataFormat {
 		cli.PrintErrln(
-			color.RedString("Could not infer type file '%s'\n", path),
+			color.RedString("Could not infer type file '%s'\n", fileLoc.Path),
 			"File extensions should match supported file types, or should be explicitly defined via the file-type parameter")
 		return mvdata.InvalidOp, nil, nil
 	}
 
+	if !setCSVDialect(apr, fileLoc, true) || !setXLSXSheet(apr, fileLoc) {
+		return mvdata.InvalidOp, nil, nil
+	}
+
 	tableLoc := &mvdata.DataLocation{Path: tableName, Format: mvdata.DoltDB}
 
 	return mvOp, tableLoc, fileLoc
 }
 
 func Import(commandStr string, args []string, dEnv *env.DoltEnv) int {
-	force, mvOpts := parseCreateArgs(commandStr, args)
+	force, dryRun, allSheets, mvOpts := parseCreateArgs(commandStr, args)
 
 	if mvOpts == nil {
 		return 1
 	}
 
+	if allSheets {
+		return importAllSheets(dEnv, force, mvOpts)
+	}
+
+	if mvOpts.Src.Format == mvdata.SqlFile {
+		return importSqlDump(dEnv, force, dryRun, mvOpts)
+	}
+
+	if dryRun {
+		return printOutSchema(dEnv, mvOpts)
+	}
+
 	res := executeMove(dEnv, force, mvOpts)
 
 	if res == 0 {
@@ -165,7 +273,7 @@ func Import(commandStr string, args []string, dEnv *env.DoltEnv) int {
 	return res
 }
 
-func parseCreateArgs(commandStr string, args []string) (bool, *mvdata.MoveOptions) {
+func parseCreateArgs(commandStr string, args []string) (bool, bool, bool, *mvdata.MoveOptions) {
 	ap := createArgParser()
 
 	help, usage := cli.HelpAndUsagePrinters(commandStr, importShortDesc, importLongDesc, importSynopsis, ap)
@@ -173,36 +281,47 @@ func parseCreateArgs(commandStr string, args []string) (bool, *mvdata.MoveOption
 	moveOp, tableLoc, fileLoc := validateImportArgs(apr, usage)
 
 	if fileLoc == nil || tableLoc == nil {
-		return false, nil
+		return false, false, false, nil
 	}
 
 	schemaFile, _ := apr.GetValue(outSchemaParam)
 	mappingFile, _ := apr.GetValue(mappingFileParam)
 	primaryKey, _ := apr.GetValue(primaryKeyParam)
-
-	return apr.Contains(forceParam), &mvdata.MoveOptions{
-		Operation:   moveOp,
-		ContOnErr:   apr.Contains(contOnErrParam),
-		SchFile:     schemaFile,
-		MappingFile: mappingFile,
-		PrimaryKey:  primaryKey,
-		Src:         fileLoc,
-		Dest:        tableLoc,
+	rejectsFile, _ := apr.GetValue(rejectsParam)
+	errorReportFile, _ := apr.GetValue(errorReportParam)
+
+	return apr.Contains(forceParam), apr.Contains(dryRunParam), apr.Contains(allSheetsParam), &mvdata.MoveOptions{
+		Operation:       moveOp,
+		ContOnErr:       apr.Contains(contOnErrParam),
+		SchFile:         schemaFile,
+		MappingFile:     mappingFile,
+		PrimaryKey:      primaryKey,
+		Src:             fileLoc,
+		Dest:            tableLoc,
+		RejectsFile:     rejectsFile,
+		ErrorReportFile: errorReportFile,
 	}
 }
 
 func createArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.ArgListHelp[tableParam] = "The new or existing table being imported to."
-	ap.ArgListHelp[fileParam] = "The file being imported. Supported file types are csv, psv, and nbf."
+	ap.ArgListHelp[fileParam] = "The file being imported. Supported file types are csv, psv, json, ndjson, xlsx, sql and parquet. Omit it to read from stdin."
 	ap.SupportsFlag(createParam, "c", "Create a new table, or overwrite an existing table (with the -f flag) from the imported data.")
 	ap.SupportsFlag(updateParam, "u", "Update an existing table with the imported data.")
+	ap.SupportsFlag(replaceParam, "r", "Replace the rows of an existing table with the imported data, deleting the rows that aren't imported.")
 	ap.SupportsFlag(forceParam, "f", "If a create operation is being executed, data already exists in the destination, the Force flag will allow the target to be overwritten.")
 	ap.SupportsFlag(contOnErrParam, "", "Continue importing when row import errors are encountered.")
 	ap.SupportsString(outSchemaParam, "s", "schema_file", "The schema for the output data.")
 	ap.SupportsString(mappingFileParam, "m", "mapping_file", "A file that lays out how fields should be mapped from input data to output data.")
 	ap.SupportsString(primaryKeyParam, "pk", "primary_key", "Explicitly define the name of the field in the schema which should be used as the primary key.")
 	ap.SupportsString(fileTypeParam, "", "file_type", "Explicitly define the type of the file if it can't be inferred from the file extension.")
+	ap.SupportsFlag(dryRunParam, "", "Print the schema of the table being imported to without importing any data.")
+	ap.SupportsString(sheetParam, "", "sheet", "The sheet of an xlsx file to import. The sheet named after the table is imported by default.")
+	ap.SupportsFlag(allSheetsParam, "", "Import every sheet of an xlsx file into the table with the same name as the sheet.")
+	ap.SupportsString(rejectsParam, "", "rejects_file", "Write the rows that can't be imported to a file, in the format of the file being imported.")
+	ap.SupportsString(errorReportParam, "", "report_file", "Write a line of json describing each row that can't be imported to a file.")
+	addCSVDialectArgs(ap, true)
 	return ap
 }
 
@@ -223,19 +342,27 @@ func executeMove(dEnv *env.DoltEnv, force bool, mvOpts *mvdata.MoveOptions) int
 		return 1
 	}
 
+	return moveFromRoot(dEnv, root, force, mvOpts)
+}
+
+// moveFromRoot moves data from or to the tables of root. Imported tables are written to the working root.
+func moveFromRoot(dEnv *env.DoltEnv, root *doltdb.RootValue, force bool, mvOpts *mvdata.MoveOptions) int {
 	if mvOpts.Operation == mvdata.OverwriteOp && !force {
 		if exists, err := mvOpts.Dest.Exists(context.TODO(), root, dEnv.FS); err != nil {
-			cli.Println(color.RedString(err.Error()))
+			cli.PrintErrln(color.RedString(err.Error()))
 			return 1
 		} else if exists {
 			cli.PrintErrln(color.RedString("Data already exists in %s.  Use -f to overwrite.", mvOpts.Dest.Path))
 			return 1
 		}
-	}
-
-	if mvOpts.Src.Format == mvdata.SqlFile {
-		cli.Println(color.RedString("For SQL import, please pipe SQL input files to `dolt sql`"))
-		return 1
+	} else if mvOpts.Operation == mvdata.ReplaceOp {
+		if exists, err := mvOpts.Dest.Exists(context.TODO(), root, dEnv.FS); err != nil {
+			cli.PrintErrln(color.RedString(err.Error()))
+			return 1
+		} else if !exists {
+			cli.PrintErrln(color.RedString("Table %s does not exist.", mvOpts.Dest.Path))
+			return 1
+		}
 	}
 
 	if mvOpts.Src.Format == mvdata.JsonFile && mvOpts.SchFile == "" {
@@ -251,10 +378,15 @@ func executeMove(dEnv *env.DoltEnv, force bool, mvOpts *mvdata.MoveOptions) int
 		return 1
 	}
 
-	err = mover.Move(context.TODO())
+	err := mover.Move(context.TODO())
 
 	if err != nil {
-		cli.Println()
+		// only the exported data is written to stdout
+		if mvOpts.Dest.IsStream() {
+			cli.PrintErrln()
+		} else {
+			cli.Println()
+		}
 
 		if pipeline.IsTransformFailure(err) {
 			bdr := errhand.BuildDError("A bad row was encountered while moving data.")
@@ -278,14 +410,159 @@ func executeMove(dEnv *env.DoltEnv, force bool, mvOpts *mvdata.MoveOptions) int
 	}
 
 	if nomsWr, ok := mover.Wr.(noms.NomsMapWriteCloser); ok {
-		err = dEnv.PutTableToWorking(context.Background(), *nomsWr.GetMap(), nomsWr.GetSchema(), mvOpts.Dest.Path)
+		if mover.AutoIncrement != nil {
+			err = dEnv.PutTableToWorkingWithAutoIncrement(context.Background(), *nomsWr.GetMap(), nomsWr.GetSchema(), mvOpts.Dest.Path, mover.AutoIncrement.Next())
+		} else {
+			err = dEnv.PutTableToWorking(context.Background(), *nomsWr.GetMap(), nomsWr.GetSchema(), mvOpts.Dest.Path)
+		}
 
-		if err != nil {
+		if doltdb.IsForeignKeyViolation(err) {
+			cli.PrintErrln(color.RedString("Imported rows violate a foreign key, the working value was not updated."))
+			cli.PrintErrln(err.Error())
+			return 1
+		} else if err != nil {
 			cli.PrintErrln(color.RedString("Failed to update the working value."))
 			return 1
 		}
 	}
 
+	if mover.Rejects != nil && mover.Rejects.Count() > 0 {
+		cli.Println(color.YellowString("\n%d rows could not be imported.", mover.Rejects.Count()))
+	}
+
+	return 0
+}
+
+// importSqlDump imports a table from a SQL dump, like one written by mysqldump. The dump's CREATE TABLE statement for the
+// table defines it, and only its rows are imported.
+func importSqlDump(dEnv *env.DoltEnv, force, dryRun bool, mvOpts *mvdata.MoveOptions) int {
+	if mvOpts.SchFile != "" || mvOpts.MappingFile != "" || mvOpts.PrimaryKey != "" || dryRun || mvOpts.RejectsFile != "" || mvOpts.ErrorReportFile != "" {
+		cli.PrintErrln(color.RedString("The --schema, --map, --pk, --dry-run, --rejects and --error-report parameters are not supported for sql files."))
+		return 1
+	}
+
+	ctx := context.TODO()
+	root, err := dEnv.WorkingRoot(ctx)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to get the working root value for this data repository."))
+		return 1
+	}
+
+	tableName := mvOpts.Dest.Path
+	exists, err := root.HasTable(ctx, tableName)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString(err.Error()))
+		return 1
+	}
+
+	create := mvOpts.Operation == mvdata.OverwriteOp
+	if create && exists {
+		if !force {
+			cli.PrintErrln(color.RedString("Data already exists in %s.  Use -f to overwrite.", tableName))
+			return 1
+		}
+
+		root, err = root.RemoveTables(ctx, tableName)
+
+		if err != nil {
+			cli.PrintErrln(color.RedString("Unable to overwrite %s: %s", tableName, err.Error()))
+			return 1
+		}
+	} else if !create && !exists {
+		cli.PrintErrln(color.RedString("Table %s does not exist.", tableName))
+		return 1
+	} else if mvOpts.Operation == mvdata.ReplaceOp {
+		root, err = clearTableRows(ctx, dEnv, root, tableName)
+
+		if err != nil {
+			cli.PrintErrln(color.RedString("Unable to replace %s: %s", tableName, err.Error()))
+			return 1
+		}
+	}
+
+	rd := mvOpts.Src.InStream
+	if rd == nil {
+		rd, err = dEnv.FS.OpenForRead(mvOpts.Src.Path)
+
+		if err != nil {
+			bdr := errhand.BuildDError("Error opening %s.", mvOpts.Src.Path)
+			cli.PrintErrln(bdr.AddCause(err).Build().Verbose())
+			return 1
+		}
+	}
+
+	defer rd.Close()
+
+	opts := sql.DumpImportOptions{Create: create, ContOnErr: mvOpts.ContOnErr}
+	result, err := sql.ImportDump(ctx, dEnv.DoltDB, root, rd, tableName, opts)
+
+	if doltdb.IsForeignKeyViolation(err) {
+		cli.PrintErrln(color.RedString("Imported rows violate a foreign key, the working value was not updated."))
+		cli.PrintErrln(err.Error())
+		return 1
+	} else if err != nil {
+		cli.PrintErrln("An error occurred importing sql file:\n", err.Error())
+		if !mvOpts.ContOnErr {
+			cli.PrintErrln("Rows that can't be inserted can be ignored using the '--continue'")
+		}
+		return 1
+	}
+
+	cli.Println(fmt.Sprintf("Rows inserted: %d, Updated: %d, Errors ignored: %d", result.NumRowsInserted, result.NumRowsUpdated, result.NumErrorsIgnored))
+
+	err = dEnv.UpdateWorkingRoot(ctx, result.Root)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Failed to update the working value."))
+		return 1
+	}
+
+	return 0
+}
+
+// clearTableRows returns a root with every row of a table deleted, keeping its schema.
+func clearTableRows(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue, tableName string) (*doltdb.RootValue, error) {
+	tbl, _, err := root.GetTable(ctx, tableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	empty, err := types.NewMap(ctx, dEnv.DoltDB.ValueReadWriter())
+
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err = tbl.UpdateRows(ctx, empty)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return root.PutTable(ctx, dEnv.DoltDB, tableName, tbl)
+}
+
+// printOutSchema prints the schema that rows would be imported with as a CREATE TABLE statement.
+func printOutSchema(dEnv *env.DoltEnv, mvOpts *mvdata.MoveOptions) int {
+	root, err := dEnv.WorkingRoot(context.Background())
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to get the working root value for this data repository."))
+		return 1
+	}
+
+	sch, nDMErr := mvdata.OutSchema(context.TODO(), root, dEnv.FS, mvOpts)
+
+	if nDMErr != nil {
+		verr := newDataMoverErrToVerr(mvOpts, nDMErr)
+		cli.PrintErrln(verr.Verbose())
+		return 1
+	}
+
+	cli.Println(sql.SchemaAsCreateStmt(mvOpts.Dest.Path, sch))
 	return 0
 }
 
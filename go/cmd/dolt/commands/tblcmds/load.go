@@ -0,0 +1,302 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tblcmds
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/mvdata"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/sql"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+var loadShortDesc = "Restore the tables of a dump written by dolt dump."
+var loadLongDesc = `dolt load restores the tables of a dump written by <b>dolt dump</b> to the working set.  <path> is the sql file or
+the directory of the dump, and doltdump.sql or doltdump is loaded if it's omitted.
+
+A sql dump is run as a sql script.  The tables of a directory are imported from the files named after them, with the
+schemas in their <table>.schema.json files, and the foreign keys in foreign_keys.sql are added once every table has
+been imported.
+
+The load fails if any of the tables of the dump already exist, unless <b>--force | -f</b> is given, which replaces
+them.  If a table can't be loaded, the working set is left as it was before the load.`
+
+var loadSynopsis = []string{
+	"[-f] [--continue] [<path>]",
+}
+
+func Load(commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["path"] = "The sql file or directory of the dump."
+	ap.SupportsFlag(forceParam, "f", "Replace the tables of the working set that are in the dump.")
+	ap.SupportsFlag(contOnErrParam, "", "Continue loading when rows or statements of the dump can't be loaded.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, loadShortDesc, loadLongDesc, loadSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() > 1 {
+		usage()
+		return 1
+	}
+
+	var path string
+	if apr.NArg() == 1 {
+		path = apr.Arg(0)
+	} else if exists, _ := dEnv.FS.Exists(DefaultDumpFile); exists {
+		path = DefaultDumpFile
+	} else if exists, _ := dEnv.FS.Exists(DefaultDumpDir); exists {
+		path = DefaultDumpDir
+	} else {
+		cli.PrintErrln(color.RedString("Neither %s nor %s exist.  Give the path of a dump written by dolt dump.", DefaultDumpFile, DefaultDumpDir))
+		return 1
+	}
+
+	exists, isDir := dEnv.FS.Exists(path)
+
+	if !exists {
+		cli.PrintErrln(color.RedString("%s does not exist.", path))
+		return 1
+	} else if !isDir && mvdata.NewDataLocation(path, "").Format != mvdata.SqlFile {
+		cli.PrintErrln(color.RedString("%s is not a dump.  A dump is a sql file or a directory written by dolt dump.", path))
+		return 1
+	}
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	var res int
+	if isDir {
+		res = loadDir(dEnv, root, path, apr.Contains(forceParam), apr.Contains(contOnErrParam))
+	} else {
+		res = loadSqlFile(dEnv, root, path, apr.Contains(forceParam), apr.Contains(contOnErrParam))
+	}
+
+	if res == 0 {
+		cli.Println(color.CyanString("\nSuccessfully loaded %s.", path))
+	}
+
+	return res
+}
+
+// loadSqlFile runs a sql dump, after checking that the tables it creates don't exist or removing them when forced
+func loadSqlFile(dEnv *env.DoltEnv, root *doltdb.RootValue, path string, force, contOnErr bool) int {
+	rd, err := dEnv.FS.OpenForRead(path)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to read %s: %s", path, err.Error()))
+		return 1
+	}
+
+	tableNames, err := sql.CreatedTableNames(rd)
+	rd.Close()
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to read %s: %s", path, err.Error()))
+		return 1
+	}
+
+	root, ok := removeLoadedTables(root, tableNames, force)
+
+	if !ok {
+		return 1
+	}
+
+	rd, err = dEnv.FS.OpenForRead(path)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to read %s: %s", path, err.Error()))
+		return 1
+	}
+
+	defer rd.Close()
+
+	root, err = commands.RunSqlScript(dEnv, root, rd, contOnErr)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("\nUnable to load %s: %s", path, err.Error()))
+		return 1
+	}
+
+	if verr := commands.UpdateWorkingWithVErr(dEnv, root); verr != nil {
+		cli.PrintErrln(verr.Verbose())
+		return 1
+	}
+
+	return 0
+}
+
+// loadDir imports the tables of a dump directory with their schema files, and then adds their foreign keys. The working
+// root is restored if any of them can't be loaded.
+func loadDir(dEnv *env.DoltEnv, root *doltdb.RootValue, dir string, force, contOnErr bool) int {
+	tableFiles := make(map[string]string)
+	err := dEnv.FS.Iter(dir, false, func(path string, size int64, isDir bool) (stop bool) {
+		name := filepath.Base(path)
+
+		if isDir || strings.HasSuffix(name, schemaFileExt) {
+			return false
+		}
+
+		switch mvdata.NewDataLocation(path, "").Format {
+		case mvdata.CsvFile, mvdata.JsonFile, mvdata.ParquetFile:
+			tableFiles[strings.TrimSuffix(name, filepath.Ext(name))] = path
+		}
+
+		return false
+	})
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to read %s: %s", dir, err.Error()))
+		return 1
+	} else if len(tableFiles) == 0 {
+		cli.PrintErrln(color.RedString("No tables were found in %s.", dir))
+		return 1
+	}
+
+	var tableNames []string
+	for tableName := range tableFiles {
+		if !doltdb.IsValidTableName(tableName) {
+			cli.PrintErrln(
+				color.RedString("%s can't be loaded because '%s' is not a valid table name\n", tableFiles[tableName], tableName),
+				"table names must match the regular expression:", doltdb.TableNameRegexStr)
+			return 1
+		}
+
+		tableNames = append(tableNames, tableName)
+	}
+
+	sort.Strings(tableNames)
+
+	loadRoot, ok := removeLoadedTables(root, tableNames, force)
+
+	if !ok {
+		return 1
+	}
+
+	if verr := commands.UpdateWorkingWithVErr(dEnv, loadRoot); verr != nil {
+		cli.PrintErrln(verr.Verbose())
+		return 1
+	}
+
+	if res := loadDirTables(dEnv, dir, tableNames, tableFiles, contOnErr); res != 0 {
+		commands.UpdateWorkingWithVErr(dEnv, root)
+		return res
+	}
+
+	return 0
+}
+
+func loadDirTables(dEnv *env.DoltEnv, dir string, tableNames []string, tableFiles map[string]string, contOnErr bool) int {
+	for _, tableName := range tableNames {
+		schFile := filepath.Join(dir, tableName+schemaFileExt)
+		if exists, _ := dEnv.FS.Exists(schFile); !exists {
+			schFile = ""
+		}
+
+		mvOpts := &mvdata.MoveOptions{
+			Operation: mvdata.OverwriteOp,
+			ContOnErr: contOnErr,
+			SchFile:   schFile,
+			Src:       mvdata.NewDataLocation(tableFiles[tableName], ""),
+			Dest:      &mvdata.DataLocation{Path: tableName, Format: mvdata.DoltDB},
+		}
+
+		if res := executeMove(dEnv, false, mvOpts); res != 0 {
+			return res
+		}
+
+		cli.Println(color.CyanString("\nLoaded %s.", tableName))
+		displayStrLen = 0
+	}
+
+	fkFile := filepath.Join(dir, foreignKeysFile)
+	if exists, _ := dEnv.FS.Exists(fkFile); !exists {
+		return 0
+	}
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr != nil {
+		cli.PrintErrln(verr.Verbose())
+		return 1
+	}
+
+	rd, err := dEnv.FS.OpenForRead(fkFile)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to read %s: %s", fkFile, err.Error()))
+		return 1
+	}
+
+	defer rd.Close()
+
+	root, err = commands.RunSqlScript(dEnv, root, rd, false)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to add the foreign keys of the tables: %s", err.Error()))
+		return 1
+	}
+
+	if verr := commands.UpdateWorkingWithVErr(dEnv, root); verr != nil {
+		cli.PrintErrln(verr.Verbose())
+		return 1
+	}
+
+	return 0
+}
+
+// removeLoadedTables returns a root value without the tables of a dump that already exist if force is true, and
+// otherwise checks that none of them exist. Returns false, after printing the error, if they can't be loaded.
+func removeLoadedTables(root *doltdb.RootValue, tableNames []string, force bool) (*doltdb.RootValue, bool) {
+	ctx := context.TODO()
+
+	var existing []string
+	for _, tableName := range tableNames {
+		has, err := root.HasTable(ctx, tableName)
+
+		if err != nil {
+			cli.PrintErrln(color.RedString(err.Error()))
+			return nil, false
+		} else if has {
+			existing = append(existing, tableName)
+		}
+	}
+
+	if len(existing) == 0 {
+		return root, true
+	} else if !force {
+		cli.PrintErrln(color.RedString("Data already exists in %s.  Use -f to overwrite.", strings.Join(existing, ", ")))
+		return nil, false
+	}
+
+	root, err := root.RemoveTables(ctx, existing...)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to replace %s: %s", strings.Join(existing, ", "), err.Error()))
+		return nil, false
+	}
+
+	return root, true
+}
@@ -20,7 +20,9 @@ import (
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/alterschema"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
 )
 
@@ -32,7 +34,10 @@ by the table being renamed.
 
 The result is equivalent of running <b>dolt table cp <old> <new></b> followed by <b>dolt table rm <old></b>, resulting 
 in a new table and a deleted table in the working set. These changes can be staged using <b>dolt add</b> and committed
-using <b>dolt commit</b>.`
+using <b>dolt commit</b>.
+
+Foreign keys declared on or referencing the table are updated to use its new name. A table referenced by the foreign
+key of another table can't be overwritten.`
 
 var tblMvSynopsis = []string{
 	"[-f] <oldtable> <newtable>",
@@ -55,43 +60,44 @@ func Mv(commandStr string, args []string, dEnv *env.DoltEnv) int {
 	working, verr := commands.GetWorkingWithVErr(dEnv)
 
 	if verr == nil {
-		old := apr.Arg(0)
-		new := apr.Arg(1)
-		if verr == nil {
-			tbl, ok, err := working.GetTable(context.TODO(), old)
-
-			if err != nil {
-				verr = errhand.BuildDError("").Build()
-				return commands.HandleVErrAndExitCode(verr, usage)
-			}
-
-			if ok {
-				has, err := working.HasTable(context.TODO(), new)
-
-				if err != nil {
-					verr = errhand.BuildDError("error: failed to read tables from working set").AddCause(err).Build()
-				} else if !force && has {
-					verr = errhand.BuildDError("Data already exists in '%s'.  Use -f to overwrite.", new).Build()
-				} else {
-					working, err = working.PutTable(context.Background(), dEnv.DoltDB, new, tbl)
-
-					if err != nil {
-						verr = errhand.BuildDError("error: failed to write table back to database").AddCause(err).Build()
-					} else {
-						working, err := working.RemoveTables(context.TODO(), old)
-
-						if err != nil {
-							verr = errhand.BuildDError("Unable to remove '%s'", old).Build()
-						} else {
-							verr = commands.UpdateWorkingWithVErr(dEnv, working)
-						}
-					}
-				}
-			} else {
-				verr = errhand.BuildDError("Table '%s' not found.", old).Build()
-			}
-		}
+		verr = moveTable(dEnv, working, apr.Arg(0), apr.Arg(1), force)
 	}
 
 	return commands.HandleVErrAndExitCode(verr, usage)
 }
+
+// moveTable renames the table |old| of the working set to |new|, along with the foreign keys declared on or
+// referencing it. An existing table named |new| is overwritten if |force| is true.
+func moveTable(dEnv *env.DoltEnv, working *doltdb.RootValue, old, new string, force bool) errhand.VerboseError {
+	ctx := context.TODO()
+
+	if has, err := working.HasTable(ctx, old); err != nil {
+		return errhand.BuildDError("error: failed to read tables from working set").AddCause(err).Build()
+	} else if !has {
+		return errhand.BuildDError("Table '%s' not found.", old).Build()
+	}
+
+	has, err := working.HasTable(ctx, new)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to read tables from working set").AddCause(err).Build()
+	} else if has && old != new {
+		if !force {
+			return errhand.BuildDError("Data already exists in '%s'.  Use -f to overwrite.", new).Build()
+		}
+
+		working, err = working.RemoveTables(ctx, new)
+
+		if err != nil {
+			return errhand.BuildDError("Unable to overwrite '%s'", new).AddCause(err).Build()
+		}
+	}
+
+	working, err = alterschema.RenameTable(ctx, dEnv.DoltDB, working, old, new)
+
+	if err != nil {
+		return errhand.BuildDError("Unable to rename '%s'", old).AddCause(err).Build()
+	}
+
+	return commands.UpdateWorkingWithVErr(dEnv, working)
+}
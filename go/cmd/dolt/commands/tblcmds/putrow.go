@@ -23,6 +23,7 @@ import (
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/rowconv"
@@ -35,7 +36,7 @@ import (
 var putRowShortDesc = "Adds or updates a row in a table"
 var putRowLongDesc = "dolt table put-row will put a row in a table.  If a row already exists with a matching primary key" +
 	"it will be overwritten with the new value. All required fields for rows in this table must be supplied or the command" +
-	"will fail.  example usage:\n" +
+	"will fail. Fields that aren't supplied are given their column's default value, if it has one.  example usage:\n" +
 	"\n" +
 	"  dolt table put-row \"field0:value0\" \"field1:value1\" ... \"fieldN:valueN\"\n"
 var putRowSynopsis = []string{
@@ -155,12 +156,16 @@ func PutRow(commandStr string, args []string, dEnv *env.DoltEnv) int {
 				if err != nil {
 					verr = errhand.BuildDError("error: failed to update rows").AddCause(err).Build()
 				} else {
-					root, err = root.PutTable(context.Background(), dEnv.DoltDB, prArgs.TableName, tbl)
+					newRoot, err := root.PutTable(context.Background(), dEnv.DoltDB, prArgs.TableName, tbl)
 
 					if err != nil {
 						verr = errhand.BuildDError("error: failed to write table back to database").AddCause(err).Build()
+					} else if err = newRoot.ValidateForeignKeyChanges(context.Background(), root, prArgs.TableName); doltdb.IsForeignKeyViolation(err) {
+						verr = errhand.BuildDError("The row violates a foreign key, it was not put.").AddDetails(err.Error()).Build()
+					} else if err != nil {
+						verr = errhand.BuildDError("error: failed to check foreign keys").AddCause(err).Build()
 					} else {
-						verr = commands.UpdateWorkingWithVErr(dEnv, root)
+						verr = commands.UpdateWorkingWithVErr(dEnv, newRoot)
 					}
 				}
 			}
@@ -223,6 +228,22 @@ func createRow(nbf *types.NomsBinFormat, sch schema.Schema, prArgs *putRowArgs)
 		return nil, errhand.BuildDError("inserted row does not match schema").AddCause(err).Build()
 	}
 
+	defaults, err := rowconv.ColumnDefaults(sch)
+
+	if err != nil {
+		return nil, errhand.BuildDError("error: failed to get column defaults").AddCause(err).Build()
+	}
+
+	for tag, val := range defaults {
+		if _, supplied := untypedTaggedVals[tag]; !supplied {
+			typedRow, err = typedRow.SetColVal(tag, val, sch)
+
+			if err != nil {
+				return nil, errhand.BuildDError("error: failed to set default value").AddCause(err).Build()
+			}
+		}
+	}
+
 	if col, _ := row.GetInvalidCol(typedRow, sch); col != nil {
 		bdr := errhand.BuildDError("Missing required fields.")
 		bdr.AddDetails("The value for the column %s is not valid", col.Name)
@@ -159,13 +159,21 @@ func updateTableWithRowsRemoved(root *doltdb.RootValue, tbl *doltdb.Table, tblNa
 		return errhand.BuildDError("error: failed to update the table").AddCause(err).Build()
 	}
 
-	root, err = root.PutTable(context.Background(), dEnv.DoltDB, tblName, tbl)
+	newRoot, err := root.PutTable(context.Background(), dEnv.DoltDB, tblName, tbl)
 
 	if err != nil {
 		return errhand.BuildDError("error: failed to update the table").AddCause(err).Build()
 	}
 
-	verr := commands.UpdateWorkingWithVErr(dEnv, root)
+	err = newRoot.ValidateForeignKeyChanges(context.Background(), root, tblName)
+
+	if doltdb.IsForeignKeyViolation(err) {
+		return errhand.BuildDError("The rows are referenced by a foreign key, they were not removed.").AddDetails(err.Error()).Build()
+	} else if err != nil {
+		return errhand.BuildDError("error: failed to check foreign keys").AddCause(err).Build()
+	}
+
+	verr := commands.UpdateWorkingWithVErr(dEnv, newRoot)
 
 	if verr == nil {
 		cli.Printf("Removed %d rows\n", updates)
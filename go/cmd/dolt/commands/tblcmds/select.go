@@ -85,10 +85,14 @@ func parseWhere(sch schema.Schema, whereClause string) (filterFn, error) {
 
 		tag := col.Tag
 		convFunc := doltcore.GetConvFunc(types.StringKind, col.Kind)
+		if col.IsJSON() {
+			convFunc = doltcore.ConvStringToJSON
+		}
+
 		val, err := convFunc(types.String(valStr))
 
 		if err != nil {
-			return nil, errors.New("unable to convert '" + valStr + "' to " + col.KindString())
+			return nil, errors.New("unable to convert '" + valStr + "' to " + col.TypeString())
 		}
 
 		return func(r row.Row) bool {
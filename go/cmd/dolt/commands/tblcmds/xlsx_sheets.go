@@ -0,0 +1,215 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tblcmds
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/mvdata"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/xlsx"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
+)
+
+// setXLSXSheet sets the sheet of an xlsx file's DataLocation that is read or written from the --sheet parameter.
+// Returns false if the parameters are invalid, after printing the error.
+func setXLSXSheet(apr *argparser.ArgParseResults, fileLoc *mvdata.DataLocation) bool {
+	for _, param := range []string{sheetParam, allSheetsParam} {
+		if apr.Contains(param) && fileLoc.Format != mvdata.XlsxFile {
+			cli.PrintErrln(color.RedString("The --%s parameter can only be used with xlsx files.", param))
+			return false
+		}
+	}
+
+	if sheet, ok := apr.GetValue(sheetParam); ok {
+		fileLoc.XLSXInfo = xlsx.NewXLSXInfo().SetSheetName(sheet)
+	}
+
+	return true
+}
+
+// importAllSheets imports each sheet of an xlsx file into the table with the same name as the sheet. The tables of all
+// the sheets are checked before any of them are imported, so that an import isn't stopped part way by a table that
+// can't be imported to.
+func importAllSheets(dEnv *env.DoltEnv, force bool, mvOpts *mvdata.MoveOptions) int {
+	ctx := context.TODO()
+	sheetNames, err := xlsx.SheetNames(mvOpts.Src.Path)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to read the sheets of %s: %s", mvOpts.Src.Path, err.Error()))
+		return 1
+	}
+
+	root, err := dEnv.WorkingRoot(ctx)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to get the working root value for this data repository."))
+		return 1
+	}
+
+	for _, sheetName := range sheetNames {
+		if !doltdb.IsValidTableName(sheetName) {
+			cli.PrintErrln(
+				color.RedString("The sheet '%s' can't be imported because it isn't a valid table name\n", sheetName),
+				"table names must match the regular expression:", doltdb.TableNameRegexStr)
+			return 1
+		}
+
+		exists, err := root.HasTable(ctx, sheetName)
+
+		if err != nil {
+			cli.PrintErrln(color.RedString(err.Error()))
+			return 1
+		}
+
+		if mvOpts.Operation == mvdata.OverwriteOp && exists && !force {
+			cli.PrintErrln(color.RedString("Data already exists in %s.  Use -f to overwrite.", sheetName))
+			return 1
+		} else if mvOpts.Operation != mvdata.OverwriteOp && !exists {
+			cli.PrintErrln(color.RedString("Table %s does not exist.", sheetName))
+			return 1
+		}
+	}
+
+	for _, sheetName := range sheetNames {
+		src := *mvOpts.Src
+		src.XLSXInfo = xlsx.NewXLSXInfo().SetSheetName(sheetName)
+
+		sheetOpts := *mvOpts
+		sheetOpts.Src = &src
+		sheetOpts.Dest = &mvdata.DataLocation{Path: sheetName, Format: mvdata.DoltDB}
+
+		if res := executeMove(dEnv, force, &sheetOpts); res != 0 {
+			return res
+		}
+
+		cli.Println(color.CyanString("\nImported sheet %s.", sheetName))
+		displayStrLen = 0
+	}
+
+	cli.Println(color.CyanString("\nImport completed successfully."))
+	return 0
+}
+
+// exportTables exports the tables listed before the file, or every table with --all, to an xlsx file with a sheet for
+// each table.
+func exportTables(dEnv *env.DoltEnv, root *doltdb.RootValue, apr *argparser.ArgParseResults, usage cli.UsagePrinter) int {
+	ctx := context.TODO()
+
+	for _, param := range append([]string{outSchemaParam, mappingFileParam, primaryKeyParam, sheetParam}, csvDialectParams...) {
+		if apr.Contains(param) {
+			cli.PrintErrln(color.RedString("The --%s parameter can't be used when exporting several tables.", param))
+			return 1
+		}
+	}
+
+	var tableNames []string
+	var path string
+	if apr.Contains(allTablesParam) {
+		if apr.NArg() > 1 {
+			usage()
+			return 1
+		} else if apr.NArg() == 1 {
+			path = apr.Arg(0)
+		}
+
+		var err error
+		tableNames, err = root.GetTableNames(ctx)
+
+		if err != nil {
+			cli.PrintErrln(color.RedString("Unable to read the tables to export: %s", err.Error()))
+			return 1
+		} else if len(tableNames) == 0 {
+			cli.PrintErrln(color.RedString("There are no tables to export."))
+			return 1
+		}
+	} else {
+		tableNames = apr.Args()[:apr.NArg()-1]
+		path = apr.Arg(apr.NArg() - 1)
+
+		for _, tableName := range tableNames {
+			if has, err := root.HasTable(ctx, tableName); err != nil {
+				cli.PrintErrln(color.RedString(err.Error()))
+				return 1
+			} else if !has {
+				cli.PrintErrln(color.RedString("Table %s does not exist.", tableName))
+				return 1
+			}
+		}
+	}
+
+	fType, _ := apr.GetValue(fileTypeParam)
+
+	var fileLoc *mvdata.DataLocation
+	if path == "" {
+		if fType == "" {
+			cli.PrintErrln(color.RedString("The --file-type parameter is required when exporting to stdout."))
+			return 1
+		}
+
+		fileLoc = mvdata.NewOutStreamDataLocation(iohelp.NopWrCloser(cli.CliOut), fType)
+	} else {
+		fileLoc = mvdata.NewDataLocation(path, fType)
+	}
+
+	if fileLoc.Format != mvdata.XlsxFile {
+		cli.PrintErrln(color.RedString("Several tables can only be exported to an xlsx file."))
+		return 1
+	}
+
+	if exists, _ := dEnv.FS.Exists(fileLoc.Path); exists && !fileLoc.IsStream() && !apr.Contains(forceParam) {
+		cli.PrintErrln(color.RedString("Data already exists in %s.  Use -f to overwrite.", fileLoc.Path))
+		return 1
+	}
+
+	wb := xlsx.NewWorkbook()
+	for _, tableName := range tableNames {
+		mvOpts := &mvdata.MoveOptions{
+			Operation: mvdata.OverwriteOp,
+			ContOnErr: apr.Contains(contOnErrParam),
+			TableName: tableName,
+			Src:       &mvdata.DataLocation{Path: tableName, Format: mvdata.DoltDB},
+			Dest:      &mvdata.DataLocation{Path: fileLoc.Path, Format: mvdata.XlsxFile, XLSXInfo: xlsx.NewXLSXInfo().SetWorkbook(wb)},
+		}
+
+		// the workbook is written once every table has been added to it, so nothing is overwritten by the move
+		if res := moveFromRoot(dEnv, root, true, mvOpts); res != 0 {
+			return res
+		}
+	}
+
+	var err error
+	if fileLoc.IsStream() {
+		err = wb.Write(fileLoc.OutStream)
+	} else {
+		err = wb.Save(fileLoc.Path, dEnv.FS)
+	}
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to write %s: %s", fileLoc.Path, err.Error()))
+		return 1
+	}
+
+	if !fileLoc.IsStream() {
+		cli.Println(color.CyanString("Successfully exported data."))
+	}
+
+	return 0
+}
@@ -56,12 +56,17 @@ var doltCommand = cli.GenSubCommandHandler([]*cli.Command{
 	{Name: "pull", Desc: "Fetch from a dolt remote data repository and merge.", Func: commands.Pull, ReqRepo: true},
 	{Name: "fetch", Desc: "Update the database from a remote data repository.", Func: commands.Fetch, ReqRepo: true},
 	{Name: "clone", Desc: "Clone from a remote data repository.", Func: commands.Clone, ReqRepo: false},
+	{Name: "backup", Desc: "Back up and restore a repository, including its working set.", Func: commands.Backup, ReqRepo: false},
 	{Name: "creds", Desc: "Commands for managing credentials.", Func: credcmds.Commands, ReqRepo: false},
 	{Name: "login", Desc: "Login to a dolt remote host.", Func: commands.Login, ReqRepo: false},
 	{Name: "version", Desc: "Displays the current Dolt cli version.", Func: commands.Version(Version), ReqRepo: false},
 	{Name: "config", Desc: "Dolt configuration.", Func: commands.Config, ReqRepo: false},
 	{Name: "ls", Desc: "List tables in the working set.", Func: commands.Ls, ReqRepo: true},
 	{Name: "schema", Desc: "Display the schema for table(s)", Func: commands.Schema, ReqRepo: true},
+	{Name: "gc", Desc: "Removes data that is no longer referenced.", Func: commands.GC, ReqRepo: true},
+	{Name: "fsck", Desc: "Verifies the integrity of the repository.", Func: commands.Fsck, ReqRepo: true},
+	{Name: "dump", Desc: "Export every table of the working set or a commit.", Func: tblcmds.Dump, ReqRepo: true},
+	{Name: "load", Desc: "Restore the tables of a dump written by dolt dump.", Func: tblcmds.Load, ReqRepo: true},
 	{Name: "table", Desc: "Commands for creating, reading, updating, and deleting tables.", Func: tblcmds.Commands, ReqRepo: false},
 	{Name: "conflicts", Desc: "Commands for viewing and resolving merge conflicts.", Func: cnfcmds.Commands, ReqRepo: false},
 })
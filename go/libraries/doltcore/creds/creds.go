@@ -42,6 +42,10 @@ const (
 
 	JWTKIDHeader = "kid"
 	JWTAlgHeader = "alg"
+
+	RemoteAPIAudience   = "dolthub-remote-api.liquidata.co"
+	ClientIssuer        = "dolt-client.liquidata.co"
+	ClientSubjectPrefix = "doltClientCredentials/"
 )
 
 var B32CredsByteSet = set.NewByteSet([]byte(B32CharEncoding))
@@ -133,9 +137,9 @@ func (dc DoltCreds) toBearerToken() (string, error) {
 	// Shouldn't be hard coded
 	jwtBuilder := jwt.Signed(signer)
 	jwtBuilder = jwtBuilder.Claims(jwt.Claims{
-		Audience: []string{"dolthub-remote-api.liquidata.co"},
-		Issuer:   "dolt-client.liquidata.co",
-		Subject:  "doltClientCredentials/" + b32KIDStr,
+		Audience: []string{RemoteAPIAudience},
+		Issuer:   ClientIssuer,
+		Subject:  ClientSubjectPrefix + b32KIDStr,
 		Expiry:   jwt.NewNumericDate(datetime.Now().Add(30 * time.Second)),
 	})
 
@@ -0,0 +1,71 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creds
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// ErrInvalidToken is returned when a bearer token can't be verified
+var ErrInvalidToken = errors.New("invalid bearer token")
+
+// ErrUnknownKey is returned when a bearer token is signed by a key that isn't known
+var ErrUnknownKey = errors.New("bearer token signed by an unknown key")
+
+// VerifyBearerToken verifies a bearer token sent by a client, which is signed by the private key of its DoltCreds
+// and expires shortly after it is made. |getPubKey| returns the public key of a base32 encoded key id, and false if
+// the key isn't known. The base32 encoded key id of the credentials which signed the token is returned.
+func VerifyBearerToken(token string, getPubKey func(kid string) ([]byte, bool), now time.Time) (string, error) {
+	tok, err := jwt.ParseSigned(token)
+
+	if err != nil || len(tok.Headers) != 1 {
+		return "", ErrInvalidToken
+	}
+
+	kid := tok.Headers[0].KeyID
+	pub, ok := getPubKey(kid)
+
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	if len(pub) != pubKeySize || PubKeyToKIDStr(pub) != kid {
+		return "", ErrInvalidToken
+	}
+
+	var claims jwt.Claims
+	err = tok.Claims(ed25519.PublicKey(pub), &claims)
+
+	if err != nil || claims.Expiry == nil {
+		return "", ErrInvalidToken
+	}
+
+	err = claims.Validate(jwt.Expected{
+		Audience: jwt.Audience{RemoteAPIAudience},
+		Issuer:   ClientIssuer,
+		Subject:  ClientSubjectPrefix + kid,
+		Time:     now,
+	})
+
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	return kid, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creds
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBearerToken(t *testing.T) {
+	dc, err := GenerateCredentials()
+	require.NoError(t, err)
+	other, err := GenerateCredentials()
+	require.NoError(t, err)
+
+	md, err := dc.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	token := strings.TrimPrefix(md["authorization"], "Bearer ")
+
+	pubKeys := func(kid string) ([]byte, bool) {
+		switch kid {
+		case dc.KeyIDBase32Str():
+			return dc.PubKey, true
+		case other.KeyIDBase32Str():
+			return other.PubKey, true
+		}
+
+		return nil, false
+	}
+
+	kid, err := VerifyBearerToken(token, pubKeys, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, dc.KeyIDBase32Str(), kid)
+
+	_, err = VerifyBearerToken(token, pubKeys, time.Now().Add(10*time.Minute))
+	assert.Equal(t, ErrInvalidToken, err)
+
+	_, err = VerifyBearerToken(token, func(string) ([]byte, bool) { return nil, false }, time.Now())
+	assert.Equal(t, ErrUnknownKey, err)
+
+	// a token claiming to be from dc but checked against another key
+	_, err = VerifyBearerToken(token, func(string) ([]byte, bool) { return other.PubKey, true }, time.Now())
+	assert.Equal(t, ErrInvalidToken, err)
+
+	_, err = VerifyBearerToken(token[:len(token)-4], pubKeys, time.Now())
+	assert.Equal(t, ErrInvalidToken, err)
+
+	_, err = VerifyBearerToken("not a token", pubKeys, time.Now())
+	assert.Equal(t, ErrInvalidToken, err)
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -47,9 +48,70 @@ const (
 	//AWSCredsProfile is a creation parameter that can be used to specify which AWS profile to use.
 	AWSCredsProfile = "aws-creds-profile"
 
+	// AWSEndpointParam is a creation parameter that can be used to set the url of an S3-compatible object store to
+	// use instead of S3
+	AWSEndpointParam = "aws-endpoint"
+
+	// AWSS3PathStyleParam is a creation parameter that can be set to true to address buckets as part of the path
+	// rather than the host name, as most S3-compatible object stores require
+	AWSS3PathStyleParam = "aws-s3-path-style"
+
+	// AWSManifestParam is a creation parameter that can be used to choose where the manifest of the database is
+	// stored. Valid values are dynamodb, s3 and lockfile.
+	AWSManifestParam = "aws-manifest"
+
+	// AWSManifestLockFileParam is a creation parameter that sets the local lock file used by the lockfile manifest
+	AWSManifestLockFileParam = "aws-manifest-lock-file"
+
 	defaultAWSCredsProfile = "default"
 )
 
+// AWSManifestType is an enum type representing where the manifest of an AWS backed database is stored
+type AWSManifestType int
+
+const (
+	InvalidManifest AWSManifestType = iota - 1
+
+	// DynamoDBManifest stores the manifest in a DynamoDB table (This is the default)
+	DynamoDBManifest
+
+	// S3Manifest stores the manifest in the bucket, and uses S3 conditional writes to update it
+	S3Manifest
+
+	// LockFileManifest stores the manifest in the bucket, and holds a lock on a local file while updating it. Every
+	// process writing to the database must use the same lock file.
+	LockFileManifest
+)
+
+// String returns the string representation of an AWSManifestType
+func (mt AWSManifestType) String() string {
+	switch mt {
+	case DynamoDBManifest:
+		return "dynamodb"
+	case S3Manifest:
+		return "s3"
+	case LockFileManifest:
+		return "lockfile"
+	default:
+		return "invalid"
+	}
+}
+
+// AWSManifestTypeFromStr converts a string to an AWSManifestType
+func AWSManifestTypeFromStr(str string) AWSManifestType {
+	strlwr := strings.TrimSpace(strings.ToLower(str))
+	switch strlwr {
+	case "", "dynamodb":
+		return DynamoDBManifest
+	case "s3":
+		return S3Manifest
+	case "lockfile":
+		return LockFileManifest
+	default:
+		return InvalidManifest
+	}
+}
+
 // AWSCredentialSource is an enum type representing the different credential sources (auto, role, env, file, or invalid)
 type AWSCredentialSource int
 
@@ -121,9 +183,16 @@ func (fact AWSFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, u
 }
 
 func (fact AWSFactory) newChunkStore(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]string) (chunks.ChunkStore, error) {
-	parts := strings.SplitN(urlObj.Host, ":", 2) // [table]:[bucket]
-	if len(parts) != 2 {
-		return nil, errors.New("aws url has an invalid format")
+	manifestType := AWSManifestTypeFromStr(params[AWSManifestParam])
+
+	if manifestType == InvalidManifest {
+		return nil, errors.New("invalid value for aws-manifest")
+	}
+
+	table, bucket, err := parseAWSHost(urlObj.Host, manifestType)
+
+	if err != nil {
+		return nil, err
 	}
 
 	opts, err := awsConfigFromParams(params)
@@ -132,6 +201,12 @@ func (fact AWSFactory) newChunkStore(ctx context.Context, nbf *types.NomsBinForm
 		return nil, err
 	}
 
+	s3Config, err := s3ConfigFromParams(params)
+
+	if err != nil {
+		return nil, err
+	}
+
 	dbName, err := validatePath(urlObj.Path)
 
 	if err != nil {
@@ -139,7 +214,70 @@ func (fact AWSFactory) newChunkStore(ctx context.Context, nbf *types.NomsBinForm
 	}
 
 	sess := session.Must(session.NewSessionWithOptions(opts))
-	return nbs.NewAWSStore(ctx, nbf.VersionString(), parts[0], dbName, parts[1], s3.New(sess), dynamodb.New(sess), defaultMemTableSize)
+	s3Client := s3.New(sess, s3Config)
+
+	var st *nbs.NomsBlockStore
+	switch manifestType {
+	case S3Manifest:
+		st, err = nbs.NewS3Store(ctx, nbf.VersionString(), dbName, bucket, s3Client, "", defaultMemTableSize)
+	case LockFileManifest:
+		lockFile, ok := params[AWSManifestLockFileParam]
+
+		if !ok || lockFile == "" {
+			return nil, errors.New("aws-manifest-lock-file is required by the lockfile manifest")
+		}
+
+		st, err = nbs.NewS3Store(ctx, nbf.VersionString(), dbName, bucket, s3Client, lockFile, defaultMemTableSize)
+	default:
+		st, err = nbs.NewAWSStore(ctx, nbf.VersionString(), table, dbName, bucket, s3Client, dynamodb.New(sess), defaultMemTableSize)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = setCompression(st, params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+// parseAWSHost returns the DynamoDB table and the S3 bucket of the host of an aws url, which is [table]:[bucket]. A
+// database whose manifest isn't stored in DynamoDB doesn't have a table, so its host may be just [bucket].
+func parseAWSHost(host string, manifestType AWSManifestType) (table, bucket string, err error) {
+	parts := strings.SplitN(host, ":", 2)
+
+	if len(parts) == 2 {
+		return parts[0], parts[1], nil
+	} else if manifestType != DynamoDBManifest && host != "" {
+		return "", host, nil
+	}
+
+	return "", "", errors.New("aws url has an invalid format")
+}
+
+// s3ConfigFromParams returns the configuration of the S3 client, which only differs from the session's for
+// S3-compatible object stores
+func s3ConfigFromParams(params map[string]string) (*aws.Config, error) {
+	s3Config := aws.NewConfig()
+	if val, ok := params[AWSEndpointParam]; ok {
+		s3Config = s3Config.WithEndpoint(val)
+	}
+
+	if val, ok := params[AWSS3PathStyleParam]; ok {
+		pathStyle, err := strconv.ParseBool(val)
+
+		if err != nil {
+			return nil, errors.New("invalid value for aws-s3-path-style")
+		}
+
+		s3Config = s3Config.WithS3ForcePathStyle(pathStyle)
+	}
+
+	return s3Config, nil
 }
 
 func validatePath(path string) (string, error) {
@@ -79,3 +79,58 @@ func TestAWSPathValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAWSHost(t *testing.T) {
+	tests := []struct {
+		name         string
+		host         string
+		manifestType AWSManifestType
+		table        string
+		bucket       string
+		expectErr    bool
+	}{
+		{"table and bucket", "table:bucket", DynamoDBManifest, "table", "bucket", false},
+		{"bucket without table", "bucket", DynamoDBManifest, "", "", true},
+		{"s3 manifest with bucket", "bucket", S3Manifest, "", "bucket", false},
+		{"lockfile manifest with bucket", "bucket", LockFileManifest, "", "bucket", false},
+		{"s3 manifest with table and bucket", "table:bucket", S3Manifest, "table", "bucket", false},
+		{"empty host", "", S3Manifest, "", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table, bucket, err := parseAWSHost(test.host, test.manifestType)
+
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.table, table)
+				assert.Equal(t, test.bucket, bucket)
+			}
+		})
+	}
+}
+
+func TestAWSManifestTypeFromStr(t *testing.T) {
+	assert.Equal(t, DynamoDBManifest, AWSManifestTypeFromStr(""))
+	assert.Equal(t, DynamoDBManifest, AWSManifestTypeFromStr("DynamoDB"))
+	assert.Equal(t, S3Manifest, AWSManifestTypeFromStr("s3"))
+	assert.Equal(t, LockFileManifest, AWSManifestTypeFromStr(" lockfile "))
+	assert.Equal(t, InvalidManifest, AWSManifestTypeFromStr("etcd"))
+}
+
+func TestS3ConfigFromParams(t *testing.T) {
+	s3Config, err := s3ConfigFromParams(map[string]string{})
+	assert.NoError(t, err)
+	assert.Nil(t, s3Config.Endpoint)
+	assert.Nil(t, s3Config.S3ForcePathStyle)
+
+	s3Config, err = s3ConfigFromParams(map[string]string{AWSEndpointParam: "http://localhost:9000", AWSS3PathStyleParam: "true"})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:9000", *s3Config.Endpoint)
+	assert.True(t, *s3Config.S3ForcePathStyle)
+
+	_, err = s3ConfigFromParams(map[string]string{AWSS3PathStyleParam: "sometimes"})
+	assert.Error(t, err)
+}
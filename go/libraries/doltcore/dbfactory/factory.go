@@ -22,6 +22,7 @@ import (
 
 	"github.com/liquidata-inc/dolt/go/libraries/utils/earl"
 	"github.com/liquidata-inc/dolt/go/store/datas"
+	"github.com/liquidata-inc/dolt/go/store/nbs"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -48,6 +49,10 @@ const (
 	defaultMemTableSize = 256 * 1024 * 1024
 )
 
+// CompressionParam is a creation parameter that sets the compression of the tables written to file, aws and gs
+// backed databases.  Valid values are snappy, which is the default, and zstd.
+const CompressionParam = "compression"
+
 // DBFactory is an interface for creating concrete datas.Database instances which may have different backing stores.
 type DBFactory interface {
 	CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]string) (datas.Database, error)
@@ -88,3 +93,20 @@ func CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlStr string, para
 
 	return nil, fmt.Errorf("unknown url scheme: '%s'", urlObj.Scheme)
 }
+
+// setCompression sets the compression of |st| to the one given by the CompressionParam of |params|, if there is one
+func setCompression(st *nbs.NomsBlockStore, params map[string]string) error {
+	val, ok := params[CompressionParam]
+
+	if !ok {
+		return nil
+	}
+
+	c, err := nbs.ParseCompression(val)
+
+	if err != nil {
+		return err
+	}
+
+	return st.SetCompression(c)
+}
@@ -16,10 +16,15 @@ package dbfactory
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/liquidata-inc/dolt/go/store/nbs"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -59,3 +64,18 @@ func TestCreateMemDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, db)
 }
+
+func TestCreateFileDBCompression(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "TestCreateFileDBCompression")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	urlStr := "file://" + filepath.ToSlash(dir)
+	_, err = CreateDB(ctx, types.Format_7_18, urlStr, map[string]string{CompressionParam: "gzip"})
+	assert.Equal(t, nbs.ErrUnknownCompression, err)
+
+	db, err := CreateDB(ctx, types.Format_7_18, urlStr, map[string]string{CompressionParam: "zstd"})
+	require.NoError(t, err)
+	assert.NoError(t, db.Close())
+}
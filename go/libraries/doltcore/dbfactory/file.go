@@ -59,6 +59,12 @@ func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat,
 		return nil, err
 	}
 
+	err = setCompression(st, params)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return datas.NewDatabase(st), nil
 
 }
@@ -44,6 +44,12 @@ func (fact GSFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, ur
 		return nil, err
 	}
 
+	err = setCompression(gcsStore, params)
+
+	if err != nil {
+		return nil, err
+	}
+
 	db = datas.NewDatabase(gcsStore)
 
 	return db, err
@@ -147,13 +147,32 @@ func (rdRd *RowDiffSource) NextDiff() (row.Row, pipeline.ImmutableProperties, er
 				oldVal, _ := mappedOld.GetColVal(tag)
 				newVal, _ := mappedNew.GetColVal(tag)
 
-				_, inOld := originalOldSch.GetAllCols().GetByTag(tag)
-				_, inNew := originalNewSch.GetAllCols().GetByTag(tag)
+				oldCol, inOld := originalOldSch.GetAllCols().GetByTag(tag)
+				newCol, inNew := originalNewSch.GetAllCols().GetByTag(tag)
 
 				if inOld && inNew {
 					if !valutil.NilSafeEqCheck(oldVal, newVal) {
 						newColDiffs[col.Name] = DiffModifiedNew
 						oldColDiffs[col.Name] = DiffModifiedOld
+
+						// Whole JSON documents are hard to compare by eye, so only show the values that changed
+						oldDoc, oldIsDoc := oldVal.(types.String)
+						newDoc, newIsDoc := newVal.(types.String)
+						if oldCol.IsJSON() && newCol.IsJSON() && oldIsDoc && newIsDoc {
+							oldSummary, newSummary, err := DiffJSON(oldDoc, newDoc)
+
+							if err != nil {
+								return true, err
+							}
+
+							if mappedOld, err = mappedOld.SetColVal(tag, oldSummary, rdRd.outSch); err != nil {
+								return true, err
+							}
+
+							if mappedNew, err = mappedNew.SetColVal(tag, newSummary, rdRd.outSch); err != nil {
+								return true, err
+							}
+						}
 					}
 				} else if inOld {
 					oldColDiffs[col.Name] = DiffRemoved
@@ -0,0 +1,130 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+var jsonIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// DiffJSON compares two JSON documents, stored as canonical JSON text, and returns a summary of each side of the change.
+// Each summary is a JSON object mapping the path of every value that differs, e.g. $.stats.age, to its value in that
+// document. Paths that only exist in one of the documents only appear in that document's summary.
+func DiffJSON(oldDoc, newDoc types.String) (types.String, types.String, error) {
+	oldVal, err := doltcore.JSONToGo(oldDoc)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	newVal, err := doltcore.JSONToGo(newDoc)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	oldChanges := make(map[string]interface{})
+	newChanges := make(map[string]interface{})
+	diffJSONValues("$", oldVal, newVal, oldChanges, newChanges)
+
+	oldSummary, err := doltcore.JSONFromGo(oldChanges)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	newSummary, err := doltcore.JSONFromGo(newChanges)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return oldSummary, newSummary, nil
+}
+
+// diffJSONValues records the differences between two decoded JSON values at the path given. Objects and arrays are
+// compared member by member, anything else is compared as a whole.
+func diffJSONValues(path string, oldVal, newVal interface{}, oldChanges, newChanges map[string]interface{}) {
+	switch o := oldVal.(type) {
+	case map[string]interface{}:
+		if n, ok := newVal.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(o)+len(n))
+			for k := range o {
+				keys = append(keys, k)
+			}
+			for k := range n {
+				if _, ok := o[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				diffJSONMembers(jsonMemberPath(path, k), o, n, k, oldChanges, newChanges)
+			}
+
+			return
+		}
+	case []interface{}:
+		if n, ok := newVal.([]interface{}); ok {
+			for i := 0; i < len(o) || i < len(n); i++ {
+				elemPath := path + "[" + strconv.Itoa(i) + "]"
+				if i >= len(n) {
+					oldChanges[elemPath] = o[i]
+				} else if i >= len(o) {
+					newChanges[elemPath] = n[i]
+				} else {
+					diffJSONValues(elemPath, o[i], n[i], oldChanges, newChanges)
+				}
+			}
+
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldVal, newVal) {
+		oldChanges[path] = oldVal
+		newChanges[path] = newVal
+	}
+}
+
+func diffJSONMembers(path string, oldObj, newObj map[string]interface{}, key string, oldChanges, newChanges map[string]interface{}) {
+	oldVal, inOld := oldObj[key]
+	newVal, inNew := newObj[key]
+
+	if !inNew {
+		oldChanges[path] = oldVal
+	} else if !inOld {
+		newChanges[path] = newVal
+	} else {
+		diffJSONValues(path, oldVal, newVal, oldChanges, newChanges)
+	}
+}
+
+// jsonMemberPath returns the path of the member of the object at the path given, quoting keys that aren't identifiers.
+func jsonMemberPath(path, key string) string {
+	if jsonIdentifierRegex.MatchString(key) {
+		return path + "." + key
+	}
+
+	return path + "." + strconv.Quote(key)
+}
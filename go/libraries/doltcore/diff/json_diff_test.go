@@ -0,0 +1,92 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestDiffJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldDoc      string
+		newDoc      string
+		expectedOld types.String
+		expectedNew types.String
+	}{
+		{
+			name:        "unchanged",
+			oldDoc:      `{"a": 1}`,
+			newDoc:      `{"a": 1}`,
+			expectedOld: `{}`,
+			expectedNew: `{}`,
+		},
+		{
+			name:        "nested value changed",
+			oldDoc:      `{"name": "bart", "stats": {"age": 10, "grade": 4}}`,
+			newDoc:      `{"name": "bart", "stats": {"age": 11, "grade": 4}}`,
+			expectedOld: `{"$.stats.age":10}`,
+			expectedNew: `{"$.stats.age":11}`,
+		},
+		{
+			name:        "members added and removed",
+			oldDoc:      `{"a": 1, "b c": true}`,
+			newDoc:      `{"a": 1, "d": null}`,
+			expectedOld: `{"$.\"b c\"":true}`,
+			expectedNew: `{"$.d":null}`,
+		},
+		{
+			name:        "array elements",
+			oldDoc:      `{"tags": ["a", "b"]}`,
+			newDoc:      `{"tags": ["a", "c", "d"]}`,
+			expectedOld: `{"$.tags[1]":"b"}`,
+			expectedNew: `{"$.tags[1]":"c","$.tags[2]":"d"}`,
+		},
+		{
+			name:        "type changed",
+			oldDoc:      `{"a": [1]}`,
+			newDoc:      `{"a": {"b": 1}}`,
+			expectedOld: `{"$.a":[1]}`,
+			expectedNew: `{"$.a":{"b":1}}`,
+		},
+		{
+			name:        "scalar documents",
+			oldDoc:      `1`,
+			newDoc:      `"one"`,
+			expectedOld: `{"$":1}`,
+			expectedNew: `{"$":"one"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldDoc, err := doltcore.ParseJSON(tt.oldDoc)
+			require.NoError(t, err)
+			newDoc, err := doltcore.ParseJSON(tt.newDoc)
+			require.NoError(t, err)
+
+			oldSummary, newSummary, err := DiffJSON(oldDoc, newDoc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedOld, oldSummary)
+			assert.Equal(t, tt.expectedNew, newSummary)
+		})
+	}
+}
@@ -63,6 +63,25 @@ func DiffSchemas(sch1, sch2 schema.Schema) (map[uint64]SchemaDifference, error)
 	return diffs, nil
 }
 
+// DiffPrimaryKeys compares the primary keys of two schemas, returning the tags of the primary key columns of each in
+// key order, and whether the keys differ in their columns or column order.
+func DiffPrimaryKeys(sch1, sch2 schema.Schema) (pks1, pks2 []uint64, changed bool) {
+	pks1 = sch1.GetPKCols().Tags
+	pks2 = sch2.GetPKCols().Tags
+
+	if len(pks1) != len(pks2) {
+		return pks1, pks2, true
+	}
+
+	for i := range pks1 {
+		if pks1[i] != pks2[i] {
+			return pks1, pks2, true
+		}
+	}
+
+	return pks1, pks2, false
+}
+
 // pairColumns loops over both sets of columns pairing columns with the same tag.
 func pairColumns(sch1, sch2 schema.Schema) (map[uint64][2]*schema.Column, error) {
 	colPairMap := make(map[uint64][2]*schema.Column)
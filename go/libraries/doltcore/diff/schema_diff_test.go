@@ -15,6 +15,7 @@
 package diff
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -65,3 +66,41 @@ func TestDiffSchemas(t *testing.T) {
 		t.Error(diffs, "!=", expected)
 	}
 }
+
+func TestDiffPrimaryKeys(t *testing.T) {
+	mkSch := func(pkTags ...uint64) schema.Schema {
+		var cols []schema.Column
+		for tag := uint64(0); tag < 3; tag++ {
+			isPK := false
+			for _, pkTag := range pkTags {
+				isPK = isPK || pkTag == tag
+			}
+			cols = append(cols, schema.NewColumn(fmt.Sprint("col", tag), tag, types.StringKind, isPK))
+		}
+
+		colColl, _ := schema.NewColCollection(cols...)
+		return schema.SchemaFromCols(colColl)
+	}
+
+	tests := []struct {
+		name     string
+		sch1     schema.Schema
+		sch2     schema.Schema
+		expected bool
+	}{
+		{"unchanged", mkSch(0), mkSch(0), false},
+		{"unchanged composite", mkSch(0, 1), mkSch(0, 1), false},
+		{"column added", mkSch(0), mkSch(0, 1), true},
+		{"column removed", mkSch(0, 1), mkSch(1), true},
+		{"column replaced", mkSch(0), mkSch(2), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pks1, pks2, changed := DiffPrimaryKeys(tt.sch1, tt.sch2)
+			assert.Equal(t, tt.expected, changed)
+			assert.Equal(t, tt.sch1.GetPKCols().Tags, pks1)
+			assert.Equal(t, tt.sch2.GetPKCols().Tags, pks2)
+		})
+	}
+}
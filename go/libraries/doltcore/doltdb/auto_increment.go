@@ -0,0 +1,94 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// AutoIncrementer assigns values to the AUTO_INCREMENT column of rows being written to a table, and tracks the next
+// value to be assigned so it can be stored with the table using Table.SetAutoIncrementValue.
+type AutoIncrementer struct {
+	sch  schema.Schema
+	col  schema.Column
+	next uint64
+}
+
+// NewAutoIncrementer returns an AutoIncrementer for the schema given, which must have an AUTO_INCREMENT column, that
+// starts counting from the value given.
+func NewAutoIncrementer(sch schema.Schema, next uint64) *AutoIncrementer {
+	col, _ := schema.AutoIncrementCol(sch)
+	return &AutoIncrementer{sch, col, next}
+}
+
+// Next returns the next value that will be assigned.
+func (ai *AutoIncrementer) Next() uint64 {
+	return ai.next
+}
+
+// Fill returns the row given with the next value assigned to its AUTO_INCREMENT column if it has no value or a zero
+// value for it. Rows with an explicit value advance the counter past that value.
+func (ai *AutoIncrementer) Fill(r row.Row) (row.Row, error) {
+	val, ok := r.GetColVal(ai.col.Tag)
+
+	if ok && !isZero(val) {
+		if n, ok := AutoIncrementValueFromNoms(val); ok && n >= ai.next {
+			ai.next = n + 1
+		}
+
+		return r, nil
+	}
+
+	var autoVal types.Value
+	if ai.col.Kind == types.UintKind {
+		autoVal = types.Uint(ai.next)
+	} else {
+		autoVal = types.Int(ai.next)
+	}
+
+	ai.next++
+
+	return r.SetColVal(ai.col.Tag, autoVal, ai.sch)
+}
+
+// AutoIncrementValueFromNoms returns the value given as a counter value for an AUTO_INCREMENT column. Returns false if
+// the value given isn't a positive integer.
+func AutoIncrementValueFromNoms(val types.Value) (uint64, bool) {
+	switch v := val.(type) {
+	case types.Int:
+		if v > 0 {
+			return uint64(v), true
+		}
+	case types.Uint:
+		if v > 0 {
+			return uint64(v), true
+		}
+	}
+
+	return 0, false
+}
+
+func isZero(val types.Value) bool {
+	switch v := val.(type) {
+	case types.Int:
+		return v == 0
+	case types.Uint:
+		return v == 0
+	}
+
+	return types.IsNull(val)
+}
@@ -0,0 +1,246 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/store/datas"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// backupDatasetID is the dataset of a backup holding the state of the backed up repository which isn't in its refs.
+// Its head is a commit without parents, so that only the latest state is kept.
+const backupDatasetID = "backup"
+
+const (
+	backupStructName     = "Backup"
+	backupRepoStateField = "repo_state"
+	backupRootsField     = "roots"
+	backupCommitsField   = "commits"
+)
+
+// BackupState is the state of a repository which isn't in the refs of its database. RepoState is opaque to the
+// database. Roots are the root values the repository references outside of its refs, such as its working and staged
+// roots, by name. Commits are the commits it references outside of its refs, such as the commit being merged, by name.
+type BackupState struct {
+	RepoState string
+	Roots     map[string]hash.Hash
+	Commits   map[string]hash.Hash
+}
+
+// PushBackupState pulls the roots and commits of |state| from srcDB, and replaces the backup state of the database
+// with |state|. Pull progress is communicated over the provided channel.
+func (ddb *DoltDB) PushBackupState(ctx context.Context, srcDB *DoltDB, state BackupState, progChan chan datas.PullProgress) error {
+	roots, err := types.NewMap(ctx, ddb.db)
+
+	if err != nil {
+		return err
+	}
+
+	rootsEditor := roots.Edit()
+	for name, h := range state.Roots {
+		rv, err := srcDB.ReadRootValue(ctx, h)
+
+		if err != nil {
+			return err
+		}
+
+		rf, err := types.NewRef(rv.valueSt, ddb.db.Format())
+
+		if err != nil {
+			return err
+		}
+
+		err = datas.Pull(ctx, srcDB.db, ddb.db, rf, progChan)
+
+		if err != nil {
+			return err
+		}
+
+		rootsEditor.Set(types.String(name), rf)
+	}
+
+	roots, err = rootsEditor.Map(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	commits, err := types.NewMap(ctx, ddb.db)
+
+	if err != nil {
+		return err
+	}
+
+	commitsEditor := commits.Edit()
+	for name, h := range state.Commits {
+		commitSt, err := getCommitStForHash(ctx, srcDB.db, h.String())
+
+		if err != nil {
+			return err
+		}
+
+		err = ddb.PushChunks(ctx, srcDB, &Commit{srcDB.db, commitSt}, nil, progChan)
+
+		if err != nil {
+			return err
+		}
+
+		rf, err := types.NewRef(commitSt, ddb.db.Format())
+
+		if err != nil {
+			return err
+		}
+
+		commitsEditor.Set(types.String(name), rf)
+	}
+
+	commits, err = commitsEditor.Map(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	st, err := types.NewStruct(ddb.db.Format(), backupStructName, types.StructData{
+		backupRepoStateField: types.String(state.RepoState),
+		backupRootsField:     roots,
+		backupCommitsField:   commits,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	parents, err := types.NewSet(ctx, ddb.db)
+
+	if err != nil {
+		return err
+	}
+
+	commitSt, err := datas.NewCommit(st, parents, types.EmptyStruct(ddb.db.Format()))
+
+	if err != nil {
+		return err
+	}
+
+	rf, err := ddb.db.WriteValue(ctx, commitSt)
+
+	if err != nil {
+		return err
+	}
+
+	ds, err := ddb.db.GetDataset(ctx, backupDatasetID)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = ddb.db.SetHead(ctx, ds, rf)
+
+	return err
+}
+
+// PullBackupState pulls the backup state of srcDB, along with its roots, and returns it. Returns ErrNotABackup if srcDB
+// has no backup state. Pull progress is communicated over the provided channel.
+func (ddb *DoltDB) PullBackupState(ctx context.Context, srcDB *DoltDB, progChan chan datas.PullProgress) (BackupState, error) {
+	ds, err := srcDB.db.GetDataset(ctx, backupDatasetID)
+
+	if err != nil {
+		return BackupState{}, err
+	}
+
+	rf, ok, err := ds.MaybeHeadRef()
+
+	if err != nil {
+		return BackupState{}, err
+	} else if !ok {
+		return BackupState{}, ErrNotABackup
+	}
+
+	val, ok, err := ds.MaybeHeadValue()
+
+	if err != nil {
+		return BackupState{}, err
+	} else if !ok {
+		return BackupState{}, ErrNotABackup
+	}
+
+	st, ok := val.(types.Struct)
+
+	if !ok || st.Name() != backupStructName {
+		return BackupState{}, ErrNotABackup
+	}
+
+	repoState, ok, err := st.MaybeGet(backupRepoStateField)
+
+	if err != nil {
+		return BackupState{}, err
+	} else if !ok {
+		return BackupState{}, ErrNotABackup
+	}
+
+	roots, ok, err := st.MaybeGet(backupRootsField)
+
+	if err != nil {
+		return BackupState{}, err
+	} else if !ok {
+		return BackupState{}, ErrNotABackup
+	}
+
+	// pulling the commit holding the state pulls its roots
+	err = datas.PullWithoutBatching(ctx, srcDB.db, ddb.db, rf, progChan)
+
+	if err != nil {
+		return BackupState{}, err
+	}
+
+	state := BackupState{RepoState: string(repoState.(types.String))}
+	state.Roots, err = refTargets(ctx, roots.(types.Map))
+
+	if err != nil {
+		return BackupState{}, err
+	}
+
+	// backups synced before commits were kept don't have any
+	state.Commits = map[string]hash.Hash{}
+	if commits, ok, err := st.MaybeGet(backupCommitsField); err != nil {
+		return BackupState{}, err
+	} else if ok {
+		state.Commits, err = refTargets(ctx, commits.(types.Map))
+
+		if err != nil {
+			return BackupState{}, err
+		}
+	}
+
+	return state, nil
+}
+
+// refTargets returns the target hashes of a map of names to refs, keyed by name.
+func refTargets(ctx context.Context, refs types.Map) (map[string]hash.Hash, error) {
+	targets := make(map[string]hash.Hash)
+	err := refs.IterAll(ctx, func(k, v types.Value) error {
+		targets[string(k.(types.String))] = v.(types.Ref).TargetHash()
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestBackupState(t *testing.T) {
+	ctx := context.Background()
+	srcDB, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+	require.NoError(t, srcDB.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	cs, _ := NewCommitSpec("HEAD", "master")
+	commit, err := srcDB.Resolve(ctx, cs)
+	require.NoError(t, err)
+	root, err := commit.GetRootValue()
+	require.NoError(t, err)
+
+	// a working root which isn't in any commit
+	sch := createTestSchema()
+	rowData, _ := createTestRowData(t, srcDB.db, sch)
+	tbl, err := createTestTable(srcDB.db, sch, rowData)
+	require.NoError(t, err)
+	working, err := root.PutTable(ctx, srcDB, "test", tbl)
+	require.NoError(t, err)
+	workingHash, err := srcDB.WriteRootValue(ctx, working)
+	require.NoError(t, err)
+	stagedHash, err := srcDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+
+	backupDB, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+	destDB, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+
+	_, err = destDB.PullBackupState(ctx, backupDB, nil)
+	assert.Equal(t, ErrNotABackup, err)
+
+	// a commit which isn't the head of any ref, like the commit being merged
+	meta, err := NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "A dangling commit")
+	require.NoError(t, err)
+	dangling, err := srcDB.Commit(ctx, workingHash, ref.NewBranchRef("master"), meta)
+	require.NoError(t, err)
+	require.NoError(t, srcDB.SetHeadToCommit(ctx, ref.NewBranchRef("master"), commit))
+	danglingHash, err := dangling.HashOf()
+	require.NoError(t, err)
+
+	state := BackupState{
+		RepoState: `{"head": "refs/heads/master"}`,
+		Roots:     map[string]hash.Hash{"working": workingHash, "staged": stagedHash},
+		Commits:   map[string]hash.Hash{"merge": danglingHash},
+	}
+	require.NoError(t, backupDB.PushBackupState(ctx, srcDB, state, nil))
+
+	// the backup state isn't a ref
+	refs, err := backupDB.GetRefs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+
+	restored, err := destDB.PullBackupState(ctx, backupDB, nil)
+	require.NoError(t, err)
+	assert.Equal(t, state, restored)
+
+	restoredWorking, err := destDB.ReadRootValue(ctx, workingHash)
+	require.NoError(t, err)
+	has, err := restoredWorking.HasTable(ctx, "test")
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	dcs, err := NewCommitSpec(danglingHash.String(), "")
+	require.NoError(t, err)
+	_, err = destDB.Resolve(ctx, dcs)
+	require.NoError(t, err)
+
+	// a newer state replaces the old one
+	state = BackupState{RepoState: `{}`, Roots: map[string]hash.Hash{"working": stagedHash}, Commits: map[string]hash.Hash{}}
+	require.NoError(t, backupDB.PushBackupState(ctx, srcDB, state, nil))
+	restored, err = destDB.PullBackupState(ctx, backupDB, nil)
+	require.NoError(t, err)
+	assert.Equal(t, state, restored)
+}
+
+func TestSetHeadToCommit(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	cs, _ := NewCommitSpec("HEAD", "master")
+	initial, err := ddb.Resolve(ctx, cs)
+	require.NoError(t, err)
+	root, err := initial.GetRootValue()
+	require.NoError(t, err)
+	valHash, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "Another commit")
+	require.NoError(t, err)
+	next, err := ddb.Commit(ctx, valHash, ref.NewBranchRef("master"), meta)
+	require.NoError(t, err)
+
+	// a remote ref can be set, and moved back to a commit which doesn't descend from its head
+	remoteRef := ref.NewRemoteRef("origin", "master")
+	for _, cm := range []*Commit{next, initial} {
+		require.NoError(t, ddb.SetHeadToCommit(ctx, remoteRef, cm))
+
+		rcs, _ := NewCommitSpec("HEAD", remoteRef.String())
+		resolved, err := ddb.Resolve(ctx, rcs)
+		require.NoError(t, err)
+
+		expected, err := cm.HashOf()
+		require.NoError(t, err)
+		actual, err := resolved.HashOf()
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+}
@@ -142,12 +142,30 @@ func (c *Commit) getParent(ctx context.Context, idx int) (*types.Struct, error)
 
 	if err != nil {
 		return nil, err
+	} else if targVal == nil {
+		return nil, missingCommitErr(ctx, c.vrw, parentRef.TargetHash())
 	}
 
 	parentSt := targVal.(types.Struct)
 	return &parentSt, nil
 }
 
+// missingCommitErr returns the error for a commit with the hash given that isn't in the database. It's
+// ErrPastShallowBoundary if the commit is part of the database's shallow boundary.
+func missingCommitErr(ctx context.Context, vrw types.ValueReadWriter, h hash.Hash) error {
+	if db, ok := vrw.(datas.Database); ok {
+		shallow, err := datas.ShallowCommits(ctx, db)
+
+		if err != nil {
+			return err
+		} else if shallow.Has(h) {
+			return ErrPastShallowBoundary
+		}
+	}
+
+	return ErrHashNotFound
+}
+
 // GetRootValue gets the RootValue of the commit.
 func (c *Commit) GetRootValue() (*RootValue, error) {
 	rootVal, _, err := c.commitSt.MaybeGet(rootValueField)
@@ -165,6 +183,33 @@ func (c *Commit) GetRootValue() (*RootValue, error) {
 	return nil, errHasNoRootValue
 }
 
+// ValidateCommit returns an error if the value in |vrw| with the hash |h| isn't a commit, or if its root value has a
+// table that can't be written to a dolt database
+func ValidateCommit(ctx context.Context, vrw types.ValueReadWriter, h hash.Hash) error {
+	val, err := vrw.ReadValue(ctx, h)
+
+	if err != nil {
+		return err
+	} else if val == nil {
+		return ErrHashNotFound
+	}
+
+	if isCommit, err := datas.IsCommit(val); err != nil {
+		return err
+	} else if !isCommit {
+		return ErrFoundHashNotACommit
+	}
+
+	cm := &Commit{vrw, val.(types.Struct)}
+	root, err := cm.GetRootValue()
+
+	if err != nil {
+		return err
+	}
+
+	return root.ValidateSchemas(ctx)
+}
+
 var ErrNoCommonAnscestor = errors.New("no common anscestor")
 
 func GetCommitAnscestor(ctx context.Context, cm1, cm2 *Commit) (*Commit, error) {
@@ -89,6 +89,16 @@ func LoadDoltDBWithParams(ctx context.Context, nbf *types.NomsBinFormat, urlStr
 		return nil, err
 	}
 
+	shallow, err := datas.ShallowCommits(ctx, db)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(shallow) > 0 {
+		datas.AllowShallow(db)
+	}
+
 	return &DoltDB{db}, nil
 }
 
@@ -522,6 +532,8 @@ func (ddb *DoltDB) ResolveParent(ctx context.Context, commit *Commit, parentIdx
 
 	if err != nil {
 		return nil, err
+	} else if parentVal == nil {
+		return nil, missingCommitErr(ctx, ddb.db, parentCommRef.(types.Ref).TargetHash())
 	}
 
 	parentCommitSt = parentVal.(types.Struct)
@@ -604,6 +616,26 @@ func (ddb *DoltDB) NewBranchAtCommit(ctx context.Context, dref ref.DoltRef, comm
 	return err
 }
 
+// SetHeadToCommit sets the ref given to the commit given, whatever its type and whether or not the commit descends from
+// its current head.
+func (ddb *DoltDB) SetHeadToCommit(ctx context.Context, dref ref.DoltRef, commit *Commit) error {
+	ds, err := ddb.db.GetDataset(ctx, dref.String())
+
+	if err != nil {
+		return err
+	}
+
+	rf, err := types.NewRef(commit.commitSt, ddb.db.Format())
+
+	if err != nil {
+		return err
+	}
+
+	_, err = ddb.db.SetHead(ctx, ds, rf)
+
+	return err
+}
+
 // DeleteBranch deletes the branch given, returning an error if it doesn't exist.
 func (ddb *DoltDB) DeleteBranch(ctx context.Context, dref ref.DoltRef) error {
 	ds, err := ddb.db.GetDataset(ctx, dref.String())
@@ -621,25 +653,70 @@ func (ddb *DoltDB) DeleteBranch(ctx context.Context, dref ref.DoltRef) error {
 }
 
 // PushChunks initiates a push into a database from the source database given, at the commit given. Pull progress is
-// communicated over the provided channel.
-func (ddb *DoltDB) PushChunks(ctx context.Context, srcDB *DoltDB, cm *Commit, progChan chan datas.PullProgress) error {
+// communicated over the provided channel. If |checkpoint| isn't nil the progress of the push is saved to it, and an
+// interrupted push of the same commit picks up where it left off. Returns ErrShallowPush if the source database is a
+// shallow clone and the database doesn't have the commits at its shallow boundary, as the push would leave the
+// database without the history behind them.
+func (ddb *DoltDB) PushChunks(ctx context.Context, srcDB *DoltDB, cm *Commit, checkpoint datas.PullCheckpoint, progChan chan datas.PullProgress) error {
+	missing, err := datas.MissingShallowCommits(ctx, srcDB.db, ddb.db)
+
+	if err != nil {
+		return err
+	} else if len(missing) > 0 {
+		return ErrShallowPush
+	}
+
 	rf, err := types.NewRef(cm.commitSt, ddb.db.Format())
 
 	if err != nil {
 		return err
 	}
 
+	if checkpoint != nil {
+		return datas.PullWithCheckpoint(ctx, srcDB.db, ddb.db, rf, progChan, checkpoint)
+	}
+
 	return datas.Pull(ctx, srcDB.db, ddb.db, rf, progChan)
 }
 
+// CollectGarbage removes the chunks of the database that aren't reachable from any of its refs or from the values of
+// the hashes given, which are values held on to outside of the database like working and staged roots.
+func (ddb *DoltDB) CollectGarbage(ctx context.Context, roots ...hash.Hash) (datas.GCStats, error) {
+	return datas.CollectGarbage(ctx, ddb.db, roots...)
+}
+
+// PullChunksShallow pulls the commit given from the source database given along with only the last |depth| commits
+// of its history. The parents of the oldest commits pulled are recorded as the shallow boundary of the database.
+// Progress is communicated over the provided channel.
+func (ddb *DoltDB) PullChunksShallow(ctx context.Context, srcDB *DoltDB, cm *Commit, depth int, progChan chan datas.PullProgress) error {
+	rf, err := types.NewRef(cm.commitSt, ddb.db.Format())
+
+	if err != nil {
+		return err
+	}
+
+	return datas.PullShallow(ctx, srcDB.db, ddb.db, rf, depth, progChan)
+}
+
+// ShallowCommits returns the hashes of the commits at the boundary of a shallow clone. They are parents of commits in
+// the database but aren't in it themselves. The set is empty if the database holds its complete history.
+func (ddb *DoltDB) ShallowCommits(ctx context.Context) (hash.HashSet, error) {
+	return datas.ShallowCommits(ctx, ddb.db)
+}
+
 // PullChunks initiates a pull into a database from the source database given, at the commit given. Progress is
-// communicated over the provided channel.
-func (ddb *DoltDB) PullChunks(ctx context.Context, srcDB *DoltDB, cm *Commit, progChan chan datas.PullProgress) error {
+// communicated over the provided channel. If |checkpoint| isn't nil the progress of the pull is saved to it, and an
+// interrupted pull of the same commit picks up where it left off.
+func (ddb *DoltDB) PullChunks(ctx context.Context, srcDB *DoltDB, cm *Commit, checkpoint datas.PullCheckpoint, progChan chan datas.PullProgress) error {
 	rf, err := types.NewRef(cm.commitSt, ddb.db.Format())
 
 	if err != nil {
 		return err
 	}
 
+	if checkpoint != nil {
+		return datas.PullWithCheckpoint(ctx, srcDB.db, ddb.db, rf, progChan, checkpoint)
+	}
+
 	return datas.PullWithoutBatching(ctx, srcDB.db, ddb.db, rf, progChan)
 }
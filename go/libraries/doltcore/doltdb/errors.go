@@ -25,13 +25,22 @@ var ErrInvalidBranchOrHash = errors.New("string is not a valid branch or hash")
 var ErrFoundHashNotACommit = errors.New("the value retrieved for this hash is not a commit")
 
 var ErrHashNotFound = errors.New("could not find a value for this hash")
+var ErrPastShallowBoundary = errors.New("the commit is past the boundary of a shallow clone")
+var ErrShallowPush = errors.New("a shallow clone can only be pushed to a database that has the commits at its shallow boundary")
 var ErrBranchNotFound = errors.New("branch not found")
 var ErrTableNotFound = errors.New("table not found")
+var ErrNotABackup = errors.New("the database isn't a backup")
 var ErrTableExists = errors.New("table already exists")
 var ErrAlreadyOnBranch = errors.New("Already on branch")
 
 var ErrNomsIO = errors.New("error reading from or writing to noms")
 
+var ErrForeignKeyExists = errors.New("a foreign key with this name already exists")
+var ErrForeignKeyNotFound = errors.New("foreign key not found")
+var ErrForeignKeyRefNotPK = errors.New("foreign keys must reference the primary key columns of the referenced table")
+var ErrForeignKeyColumnMismatch = errors.New("foreign key columns must match the referenced columns in number and type")
+var ErrTableReferenced = errors.New("table is referenced by a foreign key on another table")
+
 var ErrNoConflicts = errors.New("no conflicts")
 var ErrUpToDate = errors.New("up to date")
 var ErrIsAhead = errors.New("current fast forward from a to b. a is ahead of b already")
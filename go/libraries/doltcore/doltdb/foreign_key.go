@@ -0,0 +1,677 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/atomicerr"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/marshal"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// ForeignKey is a relationship between the columns of a table and the primary key columns of another (or the same)
+// table.  Every row of the child table with non-null values for all of TableColumns must have a matching row in
+// ReferencedTableName.  Columns are identified by tag, so renaming a column doesn't affect the foreign key.
+type ForeignKey struct {
+	// Name is the unique name of the foreign key within a RootValue
+	Name string `noms:"name"`
+
+	// TableName is the name of the child table that holds the referencing columns
+	TableName string `noms:"table_name"`
+
+	// TableColumns are the tags of the referencing columns in the child table
+	TableColumns []uint64 `noms:"table_cols"`
+
+	// ReferencedTableName is the name of the parent table
+	ReferencedTableName string `noms:"ref_table_name"`
+
+	// ReferencedTableColumns are the tags of the referenced columns in the parent table. They must be the parent
+	// table's primary key columns, and are in the same order as TableColumns.
+	ReferencedTableColumns []uint64 `noms:"ref_table_cols"`
+}
+
+// Equals tests equality between two foreign keys.
+func (fk ForeignKey) Equals(other ForeignKey) bool {
+	return fk.Name == other.Name &&
+		fk.TableName == other.TableName &&
+		fk.ReferencedTableName == other.ReferencedTableName &&
+		tagsAreEqual(fk.TableColumns, other.TableColumns) &&
+		tagsAreEqual(fk.ReferencedTableColumns, other.ReferencedTableColumns)
+}
+
+// involvesTable returns whether the foreign key is declared on, or references the table given.
+func (fk ForeignKey) involvesTable(tblName string) bool {
+	return fk.TableName == tblName || fk.ReferencedTableName == tblName
+}
+
+func tagsAreEqual(tags1, tags2 []uint64) bool {
+	if len(tags1) != len(tags2) {
+		return false
+	}
+
+	for i := range tags1 {
+		if tags1[i] != tags2[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ForeignKeyViolation is a row of a child table whose referencing values don't exist in the referenced table.
+type ForeignKeyViolation struct {
+	// ForeignKey is the foreign key that is violated
+	ForeignKey ForeignKey
+
+	// Row is the child row that references a missing parent row
+	Row row.Row
+
+	// Sch is the schema of the child table
+	Sch schema.Schema
+}
+
+// ForeignKeyViolationError is returned when the rows of a root value don't satisfy its foreign keys.
+type ForeignKeyViolationError struct {
+	Violations []ForeignKeyViolation
+}
+
+// Error returns a summary of the violated foreign keys.
+func (e ForeignKeyViolationError) Error() string {
+	counts := make(map[string]int)
+	var names []string
+	for _, v := range e.Violations {
+		if _, ok := counts[v.ForeignKey.Name]; !ok {
+			names = append(names, v.ForeignKey.Name)
+		}
+		counts[v.ForeignKey.Name]++
+	}
+
+	strs := make([]string, len(names))
+	for i, name := range names {
+		strs[i] = fmt.Sprintf("%s (%d rows)", name, counts[name])
+	}
+
+	return "foreign key constraint violated: " + strings.Join(strs, ", ")
+}
+
+// IsForeignKeyViolation returns whether the error given is a ForeignKeyViolationError
+func IsForeignKeyViolation(err error) bool {
+	_, ok := err.(ForeignKeyViolationError)
+	return ok
+}
+
+type foreignKeyCollection struct {
+	ForeignKeys []ForeignKey `noms:"foreign_keys"`
+}
+
+// GetForeignKeys returns all the foreign keys defined in this root value sorted by name.
+func (root *RootValue) GetForeignKeys(ctx context.Context) ([]ForeignKey, error) {
+	val, found, err := root.valueSt.MaybeGet(foreignKeysKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found || val == nil {
+		return nil, nil
+	}
+
+	var fkc foreignKeyCollection
+	err = marshal.Unmarshal(ctx, root.vrw.Format(), val, &fkc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return fkc.ForeignKeys, nil
+}
+
+// GetForeignKeysForTable returns the foreign keys that are declared on, or reference the table given.
+func (root *RootValue) GetForeignKeysForTable(ctx context.Context, tblName string) ([]ForeignKey, error) {
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tblFks []ForeignKey
+	for _, fk := range fks {
+		if fk.involvesTable(tblName) {
+			tblFks = append(tblFks, fk)
+		}
+	}
+
+	return tblFks, nil
+}
+
+// PutForeignKeys replaces the foreign keys of this root value with those given. No validation is done on the keys.
+func (root *RootValue) PutForeignKeys(ctx context.Context, fks []ForeignKey) (*RootValue, error) {
+	if len(fks) == 0 {
+		if _, found, err := root.valueSt.MaybeGet(foreignKeysKey); err != nil {
+			return nil, err
+		} else if !found {
+			return root, nil
+		}
+
+		rootValSt, err := root.valueSt.Delete(foreignKeysKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return newRootValue(root.vrw, rootValSt), nil
+	}
+
+	sorted := make([]ForeignKey, len(fks))
+	copy(sorted, fks)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	val, err := marshal.Marshal(ctx, root.vrw, foreignKeyCollection{sorted})
+
+	if err != nil {
+		return nil, err
+	}
+
+	rootValSt, err := root.valueSt.Set(foreignKeysKey, val)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newRootValue(root.vrw, rootValSt), nil
+}
+
+// AddForeignKey validates the foreign key given against the tables of this root value and adds it. The referenced
+// columns must be the primary key of the referenced table, and the kinds of the referencing columns must match.
+// Existing rows are not checked, see ValidateForeignKeys.
+func (root *RootValue) AddForeignKey(ctx context.Context, fk ForeignKey) (*RootValue, error) {
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, existing := range fks {
+		if existing.Name == fk.Name {
+			return nil, ErrForeignKeyExists
+		}
+	}
+
+	sch, err := root.getSchemaForTable(ctx, fk.TableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	refSch, err := root.getSchemaForTable(ctx, fk.ReferencedTableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fk.TableColumns) == 0 || len(fk.TableColumns) != len(fk.ReferencedTableColumns) {
+		return nil, ErrForeignKeyColumnMismatch
+	}
+
+	refPKTags := refSch.GetPKCols().Tags
+	if len(refPKTags) != len(fk.ReferencedTableColumns) {
+		return nil, ErrForeignKeyRefNotPK
+	}
+
+	for i, tag := range fk.TableColumns {
+		col, ok := sch.GetAllCols().GetByTag(tag)
+
+		if !ok {
+			return nil, schema.ErrColNotFound
+		}
+
+		refCol, ok := refSch.GetAllCols().GetByTag(fk.ReferencedTableColumns[i])
+
+		if !ok {
+			return nil, schema.ErrColNotFound
+		} else if !refCol.IsPartOfPK {
+			return nil, ErrForeignKeyRefNotPK
+		}
+
+		if col.Kind != refCol.Kind {
+			return nil, ErrForeignKeyColumnMismatch
+		}
+	}
+
+	return root.PutForeignKeys(ctx, append(fks, fk))
+}
+
+// DropForeignKey removes the foreign key with the name given.
+func (root *RootValue) DropForeignKey(ctx context.Context, name string) (*RootValue, error) {
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	var remaining []ForeignKey
+	for _, fk := range fks {
+		if fk.Name == name {
+			found = true
+		} else {
+			remaining = append(remaining, fk)
+		}
+	}
+
+	if !found {
+		return nil, ErrForeignKeyNotFound
+	}
+
+	return root.PutForeignKeys(ctx, remaining)
+}
+
+func (root *RootValue) getSchemaForTable(ctx context.Context, tblName string) (schema.Schema, error) {
+	tbl, ok, err := root.GetTable(ctx, tblName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrTableNotFound
+	}
+
+	return tbl.GetSchema(ctx)
+}
+
+// GetForeignKeyViolations returns every row that violates a foreign key of this root value. If table names are given,
+// only the foreign keys that are declared on or reference one of those tables are checked.
+func (root *RootValue) GetForeignKeyViolations(ctx context.Context, tblNames ...string) ([]ForeignKeyViolation, error) {
+	return root.getForeignKeyViolations(ctx, nil, tblNames)
+}
+
+// ValidateForeignKeys returns a ForeignKeyViolationError if any rows violate the foreign keys of this root value. If
+// table names are given, only the foreign keys that are declared on or reference one of those tables are checked.
+func (root *RootValue) ValidateForeignKeys(ctx context.Context, tblNames ...string) error {
+	return root.ValidateForeignKeyChanges(ctx, nil, tblNames...)
+}
+
+// ValidateForeignKeyChanges is ValidateForeignKeys for a root value made from |prev|, whose rows satisfy its foreign
+// keys. Only the rows added to or changed in a referencing table, and the rows referencing keys removed from a
+// referenced table, are checked, so the referencing table is only read in full when keys are removed. Foreign keys that
+// |prev| doesn't have, or whose tables were created or had their schemas changed since, are checked in full, as are
+// all of them if |prev| is nil.
+func (root *RootValue) ValidateForeignKeyChanges(ctx context.Context, prev *RootValue, tblNames ...string) error {
+	violations, err := root.getForeignKeyViolations(ctx, prev, tblNames)
+
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		return ForeignKeyViolationError{violations}
+	}
+
+	return nil
+}
+
+func (root *RootValue) getForeignKeyViolations(ctx context.Context, prev *RootValue, tblNames []string) ([]ForeignKeyViolation, error) {
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var prevFks []ForeignKey
+	if prev != nil {
+		prevFks, err = prev.GetForeignKeys(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var violations []ForeignKeyViolation
+	for _, fk := range fks {
+		if len(tblNames) > 0 {
+			involved := false
+			for _, tblName := range tblNames {
+				if fk.involvesTable(tblName) {
+					involved = true
+					break
+				}
+			}
+
+			if !involved {
+				continue
+			}
+		}
+
+		var prevChk *fkCheck
+		for _, prevFk := range prevFks {
+			if prevFk.Equals(fk) {
+				prevChk, err = prev.newFKCheck(ctx, fk)
+
+				if err != nil {
+					return nil, err
+				}
+
+				break
+			}
+		}
+
+		chk, err := root.newFKCheck(ctx, fk)
+
+		if err != nil {
+			return nil, err
+		} else if chk == nil {
+			continue
+		}
+
+		var fkViolations []ForeignKeyViolation
+		if chk.sameTables(prevChk) {
+			fkViolations, err = chk.changedViolations(ctx, prevChk)
+		} else {
+			fkViolations, err = chk.scan(ctx, nil)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		violations = append(violations, fkViolations...)
+	}
+
+	return violations, nil
+}
+
+// fkCheck finds the rows of the referencing table of a foreign key that violate it
+type fkCheck struct {
+	fk      ForeignKey
+	sch     schema.Schema
+	schRef  types.Ref
+	rowData types.Map
+
+	refOk      bool
+	refSchRef  types.Ref
+	refRowData types.Map
+	refPKTags  []uint64
+}
+
+// newFKCheck returns the check of |fk| against the tables of this root value, or nil if its referencing table doesn't
+// exist.
+func (root *RootValue) newFKCheck(ctx context.Context, fk ForeignKey) (*fkCheck, error) {
+	tbl, ok, err := root.GetTable(ctx, fk.TableName)
+
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	chk := &fkCheck{fk: fk, refRowData: types.EmptyMap}
+	chk.sch, err = tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	chk.schRef, err = tbl.GetSchemaRef()
+
+	if err != nil {
+		return nil, err
+	}
+
+	chk.rowData, err = tbl.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	refTbl, refOk, err := root.GetTable(ctx, fk.ReferencedTableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if refOk {
+		chk.refOk = true
+		refSch, err := refTbl.GetSchema(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		chk.refSchRef, err = refTbl.GetSchemaRef()
+
+		if err != nil {
+			return nil, err
+		}
+
+		chk.refRowData, err = refTbl.GetRowData(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		chk.refPKTags = refSch.GetPKCols().Tags
+	}
+
+	return chk, nil
+}
+
+// sameTables returns whether both tables of the foreign key exist in |prev| with the same schemas, so that only the rows
+// that changed since need to be checked
+func (chk *fkCheck) sameTables(prev *fkCheck) bool {
+	return prev != nil && chk.refOk && prev.refOk &&
+		chk.schRef.TargetHash() == prev.schRef.TargetHash() &&
+		chk.refSchRef.TargetHash() == prev.refSchRef.TargetHash()
+}
+
+// refKey returns the key of the referenced row for the referencing row |r|, or false if one of its referencing values
+// is null, as such rows aren't checked
+func (chk *fkCheck) refKey(ctx context.Context, nbf *types.NomsBinFormat, r row.Row) (types.Value, bool, error) {
+	refVals := make(row.TaggedValues)
+	for i, tag := range chk.fk.TableColumns {
+		val, ok := r.GetColVal(tag)
+
+		if !ok || types.IsNull(val) {
+			return nil, false, nil
+		}
+
+		refVals[chk.fk.ReferencedTableColumns[i]] = val
+	}
+
+	refKey, err := refVals.NomsTupleForTags(nbf, chk.refPKTags, true).Value(ctx)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return refKey, true, nil
+}
+
+// check returns whether the row with the key and value given violates the foreign key. If |removed| isn't nil, rows
+// that don't reference one of its keys are assumed not to.
+func (chk *fkCheck) check(ctx context.Context, nbf *types.NomsBinFormat, key, value types.Value, removed map[hash.Hash]bool) (row.Row, bool, error) {
+	r, err := row.FromNoms(chk.sch, key.(types.Tuple), value.(types.Tuple))
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	refKey, ok, err := chk.refKey(ctx, nbf, r)
+
+	if err != nil || !ok {
+		return r, false, err
+	} else if !chk.refOk {
+		return r, true, nil
+	}
+
+	if removed != nil {
+		h, err := refKey.Hash(nbf)
+
+		if err != nil {
+			return r, false, err
+		} else if !removed[h] {
+			return r, false, nil
+		}
+	}
+
+	has, err := chk.refRowData.Has(ctx, refKey)
+
+	if err != nil {
+		return r, false, err
+	}
+
+	return r, !has, nil
+}
+
+// scan checks every row of the referencing table. If |removed| isn't nil, only the rows referencing one of its keys can
+// violate the foreign key.
+func (chk *fkCheck) scan(ctx context.Context, removed map[hash.Hash]bool) ([]ForeignKeyViolation, error) {
+	nbf := chk.rowData.Format()
+
+	var violations []ForeignKeyViolation
+	err := chk.rowData.Iter(ctx, func(key, value types.Value) (stop bool, err error) {
+		r, violates, err := chk.check(ctx, nbf, key, value, removed)
+
+		if err != nil {
+			return false, err
+		} else if violates {
+			violations = append(violations, ForeignKeyViolation{chk.fk, r, chk.sch})
+		}
+
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return violations, nil
+}
+
+// changedViolations returns the rows that violate the foreign key because of the changes to its tables since |prev|:
+// the rows of the referencing table that were added or changed, and the rows that reference a removed key of the
+// referenced table.
+func (chk *fkCheck) changedViolations(ctx context.Context, prev *fkCheck) ([]ForeignKeyViolation, error) {
+	nbf := chk.rowData.Format()
+
+	var violations []ForeignKeyViolation
+	found := make(map[hash.Hash]bool)
+	err := diffRows(ctx, chk.rowData, prev.rowData, func(change types.ValueChanged) error {
+		if change.ChangeType == types.DiffChangeRemoved {
+			return nil
+		}
+
+		r, violates, err := chk.check(ctx, nbf, change.Key, change.NewValue, nil)
+
+		if err != nil || !violates {
+			return err
+		}
+
+		h, err := change.Key.Hash(nbf)
+
+		if err != nil {
+			return err
+		}
+
+		found[h] = true
+		violations = append(violations, ForeignKeyViolation{chk.fk, r, chk.sch})
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make(map[hash.Hash]bool)
+	err = diffRows(ctx, chk.refRowData, prev.refRowData, func(change types.ValueChanged) error {
+		if change.ChangeType != types.DiffChangeRemoved {
+			return nil
+		}
+
+		h, err := change.Key.Hash(nbf)
+
+		if err != nil {
+			return err
+		}
+
+		removed[h] = true
+		return nil
+	})
+
+	if err != nil || len(removed) == 0 {
+		return violations, err
+	}
+
+	removedViolations, err := chk.scan(ctx, removed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range removedViolations {
+		key, err := v.Row.NomsMapKey(v.Sch).Value(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		h, err := key.Hash(nbf)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !found[h] {
+			violations = append(violations, v)
+		}
+	}
+
+	return violations, nil
+}
+
+// diffRows calls |cb| with each change from the rows |last| to the rows |m|
+func diffRows(ctx context.Context, m, last types.Map, cb func(change types.ValueChanged) error) error {
+	ae := atomicerr.New()
+	changes := make(chan types.ValueChanged, 32)
+	stop := make(chan struct{}, 1)
+
+	go func() {
+		defer close(changes)
+		m.Diff(ctx, last, ae, changes, stop)
+	}()
+
+	for change := range changes {
+		err := cb(change)
+
+		if err != nil {
+			close(stop)
+			for range changes {
+			}
+
+			return err
+		}
+	}
+
+	return ae.Get()
+}
@@ -0,0 +1,463 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/datas"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const commitStructName = "Commit"
+
+// FsckProblem is damage found by Fsck, and the data it affects
+type FsckProblem struct {
+	// Root is where the damaged data was found from, a ref or one of the named roots given to Fsck
+	Root string
+
+	// Commit is the hash of the damaged commit, if the data was found from a commit
+	Commit string
+
+	// Table is the name of the damaged table, if the damage is in a table
+	Table string
+
+	Details string
+}
+
+// String returns a description of the problem on a single line
+func (p FsckProblem) String() string {
+	var where []string
+	if p.Commit != "" {
+		where = append(where, "commit "+p.Commit)
+	}
+
+	where = append(where, p.Root)
+
+	if p.Table != "" {
+		where = append(where, "table "+p.Table)
+	}
+
+	return strings.Join(where, ", ") + ": " + p.Details
+}
+
+// FsckReport is the result of checking a DoltDB. It holds the chunks that are missing or corrupt and the problems
+// found in the files of the store, along with the commits, roots and tables that are damaged.
+type FsckReport struct {
+	*datas.FsckReport
+	Problems []FsckProblem
+}
+
+// OK returns whether no problems were found
+func (r *FsckReport) OK() bool {
+	return r.FsckReport.OK() && len(r.Problems) == 0
+}
+
+// Fsck checks the integrity of the database. Every chunk reachable from a ref or from the |roots| given, which are
+// root values held on to outside of the database like the working set keyed by a name for them, must be in the store
+// and hash to its address. Every commit is then read, and every row of every table of every commit and root is decoded
+// against the schema of its table.
+func (ddb *DoltDB) Fsck(ctx context.Context, roots map[string]hash.Hash) (*FsckReport, error) {
+	rootNames := make([]string, 0, len(roots))
+	rootHashes := make([]hash.Hash, 0, len(roots))
+	for name, h := range roots {
+		rootNames = append(rootNames, name)
+		rootHashes = append(rootHashes, h)
+	}
+
+	sort.Strings(rootNames)
+
+	chunkReport, err := datas.Fsck(ctx, ddb.db, rootHashes...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fc := &fsckChecker{
+		ddb:    ddb,
+		report: &FsckReport{FsckReport: chunkReport},
+		tables: make(map[hash.Hash]string),
+	}
+
+	err = fc.checkCommits(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range rootNames {
+		err = fc.checkRootHash(ctx, name, roots[name])
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fc.report, nil
+}
+
+type fsckChecker struct {
+	ddb    *DoltDB
+	report *FsckReport
+
+	// tables holds the details of the problem with each table that has been checked, or "" if it's intact
+	tables map[hash.Hash]string
+}
+
+func (fc *fsckChecker) addProblem(root, commit, table, details string) {
+	fc.report.Problems = append(fc.report.Problems, FsckProblem{Root: root, Commit: commit, Table: table, Details: details})
+}
+
+// intact returns whether the chunks that have to be read to get the values of the struct types named by |stopAt| out
+// of |v| are all there and valid. Damage within those values isn't counted, as they're checked on their own.
+func (fc *fsckChecker) intact(ctx context.Context, v types.Value, stopAt ...string) (bool, error) {
+	bad := false
+	var damaged []hash.Hash
+	err := v.WalkRefs(fc.ddb.db.Format(), func(r types.Ref) error {
+		h := r.TargetHash()
+
+		if bad || !fc.report.IsDamaged(h) {
+			return nil
+		}
+
+		targetType, err := r.TargetType()
+
+		if err != nil {
+			return err
+		}
+
+		if desc, ok := targetType.Desc.(types.StructDesc); ok {
+			for _, name := range stopAt {
+				if desc.Name == name {
+					return nil
+				}
+			}
+		}
+
+		if fc.report.IsBad(h) {
+			bad = true
+		} else {
+			damaged = append(damaged, h)
+		}
+
+		return nil
+	})
+
+	if err != nil || bad {
+		return false, err
+	}
+
+	for _, h := range damaged {
+		val, err := fc.ddb.db.ReadValue(ctx, h)
+
+		if err != nil {
+			return false, err
+		}
+
+		if ok, err := fc.intact(ctx, val, stopAt...); err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// checkCommits checks every commit reachable from the refs of the database
+func (fc *fsckChecker) checkCommits(ctx context.Context) error {
+	dbRoot := fc.report.Root
+
+	if dbRoot.IsEmpty() {
+		return nil
+	} else if fc.report.IsBad(dbRoot) {
+		fc.addProblem("refs", "", "", "the map of refs is missing or corrupt")
+		return nil
+	}
+
+	dss, err := fc.ddb.db.Datasets(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if ok, err := fc.intact(ctx, dss, commitStructName); err != nil {
+		return err
+	} else if !ok {
+		fc.addProblem("refs", "", "", "the map of refs is damaged")
+		return nil
+	}
+
+	refs, err := fc.ddb.GetRefs(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	visited := hash.HashSet{}
+	for _, dref := range refs {
+		ds, err := fc.ddb.db.GetDataset(ctx, dref.String())
+
+		if err != nil {
+			return err
+		}
+
+		headRef, ok, err := ds.MaybeHeadRef()
+
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		pending := []hash.Hash{headRef.TargetHash()}
+		for len(pending) > 0 {
+			h := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+
+			if visited.Has(h) || fc.report.Shallow.Has(h) {
+				continue
+			}
+
+			visited.Insert(h)
+			parents, err := fc.checkCommit(ctx, dref.String(), h)
+
+			if err != nil {
+				return err
+			}
+
+			pending = append(pending, parents...)
+		}
+	}
+
+	return nil
+}
+
+// checkCommit checks the commit with the hash given and returns the hashes of its parents
+func (fc *fsckChecker) checkCommit(ctx context.Context, refName string, h hash.Hash) ([]hash.Hash, error) {
+	if fc.report.IsBad(h) {
+		fc.addProblem(refName, h.String(), "", "the commit is missing or corrupt")
+		return nil, nil
+	}
+
+	val, err := fc.ddb.db.ReadValue(ctx, h)
+
+	if err != nil {
+		return nil, err
+	}
+
+	commitSt, ok := val.(types.Struct)
+
+	if !ok {
+		fc.addProblem(refName, h.String(), "", "the value of the commit isn't a commit")
+		return nil, nil
+	}
+
+	if ok, err := fc.intact(ctx, commitSt, commitStructName, tableStructName); err != nil {
+		return nil, err
+	} else if !ok {
+		fc.addProblem(refName, h.String(), "", "the commit is damaged")
+		return nil, nil
+	}
+
+	cm := &Commit{fc.ddb.db, commitSt}
+	parents, err := cm.ParentHashes(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := cm.GetRootValue()
+
+	if err != nil {
+		fc.addProblem(refName, h.String(), "", err.Error())
+		return parents, nil
+	}
+
+	return parents, fc.checkRoot(ctx, refName, h.String(), root)
+}
+
+// checkRootHash checks the root value with the hash given
+func (fc *fsckChecker) checkRootHash(ctx context.Context, name string, h hash.Hash) error {
+	if fc.report.IsBad(h) {
+		fc.addProblem(name, "", "", "the root value is missing or corrupt")
+		return nil
+	}
+
+	root, err := fc.ddb.ReadRootValue(ctx, h)
+
+	if err != nil {
+		fc.addProblem(name, "", "", err.Error())
+		return nil
+	}
+
+	return fc.checkRoot(ctx, name, "", root)
+}
+
+// checkRoot checks every table of the root value given
+func (fc *fsckChecker) checkRoot(ctx context.Context, rootName, commit string, root *RootValue) error {
+	if ok, err := fc.intact(ctx, root.valueSt, tableStructName); err != nil {
+		return err
+	} else if !ok {
+		fc.addProblem(rootName, commit, "", "the list of tables is damaged")
+		return nil
+	}
+
+	tblNames, err := root.GetTableNames(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, tblName := range tblNames {
+		h, _, err := root.GetTableHash(ctx, tblName)
+
+		if err != nil {
+			return err
+		}
+
+		details, err := fc.checkTable(ctx, h)
+
+		if err != nil {
+			return err
+		}
+
+		if details != "" {
+			fc.addProblem(rootName, commit, tblName, details)
+		}
+	}
+
+	return nil
+}
+
+// checkTable checks the table with the hash given, decoding every one of its rows against its schema, and returns the
+// details of the problem found, or "" if the table is intact. Tables are only checked once, however many roots they're in.
+func (fc *fsckChecker) checkTable(ctx context.Context, h hash.Hash) (string, error) {
+	if details, ok := fc.tables[h]; ok {
+		return details, nil
+	}
+
+	details, err := fc.tableProblem(ctx, h)
+
+	if err != nil {
+		return "", err
+	}
+
+	fc.tables[h] = details
+	return details, nil
+}
+
+func (fc *fsckChecker) tableProblem(ctx context.Context, h hash.Hash) (string, error) {
+	if fc.report.IsDamaged(h) {
+		return "the data of the table is missing or corrupt", nil
+	}
+
+	val, err := fc.ddb.db.ReadValue(ctx, h)
+
+	if err != nil {
+		return "", err
+	}
+
+	tblSt, ok := val.(types.Struct)
+
+	if !ok {
+		return "the value of the table isn't a table", nil
+	}
+
+	tbl := &Table{fc.ddb.db, tblSt}
+	schemaRef, err := tbl.GetSchemaRef()
+
+	if err != nil {
+		return "", err
+	}
+
+	sch, err := refToSchema(ctx, fc.ddb.db, schemaRef)
+
+	if err != nil {
+		return fmt.Sprintf("the schema can't be decoded: %v", err), nil
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	var badRows int
+	var firstBad string
+	err = rowData.IterAll(ctx, func(key, value types.Value) error {
+		details := rowProblem(sch, key, value)
+
+		if details != "" {
+			if badRows == 0 {
+				firstBad = details
+			}
+
+			badRows++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	switch badRows {
+	case 0:
+		return "", nil
+	case 1:
+		return "1 row doesn't match the schema: " + firstBad, nil
+	default:
+		return fmt.Sprintf("%d rows don't match the schema, the first: %s", badRows, firstBad), nil
+	}
+}
+
+// rowProblem returns the reason the key and value of a row can't be decoded against |sch|, or "" if they can
+func rowProblem(sch schema.Schema, key, value types.Value) string {
+	keyTpl, ok := key.(types.Tuple)
+
+	if !ok {
+		return "a row has a key that isn't a tuple"
+	}
+
+	valTpl, ok := value.(types.Tuple)
+
+	if !ok {
+		return "a row has a value that isn't a tuple"
+	}
+
+	r, err := row.FromNoms(sch, keyTpl, valTpl)
+
+	if err != nil {
+		return err.Error()
+	}
+
+	col, cnst, err := row.GetInvalidConstraint(r, sch)
+
+	if err != nil {
+		return err.Error()
+	} else if col == nil {
+		return ""
+	} else if cnst != nil {
+		return fmt.Sprintf("the value of column %s violates the constraint %s", col.Name, cnst.String())
+	}
+
+	return fmt.Sprintf("the value of column %s isn't of its type", col.Name)
+}
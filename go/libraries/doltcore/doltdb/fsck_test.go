@@ -0,0 +1,81 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestFsck(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	cs, _ := NewCommitSpec("HEAD", "master")
+	commit, err := ddb.Resolve(ctx, cs)
+	require.NoError(t, err)
+	root, err := commit.GetRootValue()
+	require.NoError(t, err)
+
+	sch := createTestSchema()
+	rowData, rows := createTestRowData(t, ddb.db, sch)
+	tbl, err := createTestTable(ddb.db, sch, rowData)
+	require.NoError(t, err)
+	root, err = root.PutTable(ctx, ddb, "test", tbl)
+	require.NoError(t, err)
+	valHash, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+
+	meta, err := NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "Sample data")
+	require.NoError(t, err)
+	_, err = ddb.Commit(ctx, valHash, ref.NewBranchRef("master"), meta)
+	require.NoError(t, err)
+
+	report, err := ddb.Fsck(ctx, map[string]hash.Hash{"working": valHash})
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+
+	// a row whose first name is an int
+	key, err := rows[0].NomsMapKey(sch).Value(ctx)
+	require.NoError(t, err)
+	badVal, err := types.NewTuple(types.Format_7_18, types.Uint(firstTag), types.Int(7), types.Uint(lastTag), types.String("billerson"))
+	require.NoError(t, err)
+	badRowData, err := rowData.Edit().Set(key, badVal).Map(ctx)
+	require.NoError(t, err)
+	badTbl, err := tbl.UpdateRows(ctx, badRowData)
+	require.NoError(t, err)
+	badRoot, err := root.PutTable(ctx, ddb, "test", badTbl)
+	require.NoError(t, err)
+	badHash, err := ddb.WriteRootValue(ctx, badRoot)
+	require.NoError(t, err)
+
+	report, err = ddb.Fsck(ctx, map[string]hash.Hash{"working": badHash, "staged": valHash})
+	require.NoError(t, err)
+	assert.False(t, report.OK())
+	require.Len(t, report.Problems, 1)
+	assert.Equal(t, "working", report.Problems[0].Root)
+	assert.Equal(t, "", report.Problems[0].Commit)
+	assert.Equal(t, "test", report.Problems[0].Table)
+	assert.Contains(t, report.Problems[0].Details, "1 row doesn't match the schema")
+}
@@ -16,7 +16,9 @@ package doltdb
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/store/hash"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
@@ -24,7 +26,8 @@ import (
 const (
 	ddbRootStructName = "dolt_db_root"
 
-	tablesKey = "tables"
+	tablesKey      = "tables"
+	foreignKeysKey = "foreign_keys"
 )
 
 // RootValue defines the structure used inside all Liquidata noms dbs
@@ -189,6 +192,42 @@ func (root *RootValue) GetTableNames(ctx context.Context) ([]string, error) {
 	return names, nil
 }
 
+// ValidateSchemas returns an error if any table of the root has an invalid name, or a schema that can't be written to
+// a dolt database
+func (root *RootValue) ValidateSchemas(ctx context.Context) error {
+	tblNames, err := root.GetTableNames(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, tblName := range tblNames {
+		if !IsValidTableName(tblName) {
+			return fmt.Errorf("%s: %v", tblName, ErrInvTableName)
+		}
+
+		tbl, _, err := root.GetTable(ctx, tblName)
+
+		if err != nil {
+			return err
+		}
+
+		sch, err := tbl.GetSchema(ctx)
+
+		if err != nil {
+			return fmt.Errorf("the schema of table %s can't be read: %v", tblName, err)
+		}
+
+		err = schema.ValidateForInsert(sch)
+
+		if err != nil {
+			return fmt.Errorf("the schema of table %s is invalid: %v", tblName, err)
+		}
+	}
+
+	return nil
+}
+
 func (root *RootValue) getTableMap() (types.Map, error) {
 	val, found, err := root.valueSt.MaybeGet(tablesKey)
 
@@ -433,9 +472,42 @@ func (root *RootValue) UpdateTablesFromOther(ctx context.Context, tblNames []str
 		return nil, err
 	}
 
-	return newRootValue(root.vrw, rootValSt), nil
+	// foreign keys belong to the table they are declared on, and are updated along with it
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	otherFks, err := other.GetForeignKeys(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tblNameSet := make(map[string]bool)
+	for _, tblName := range tblNames {
+		tblNameSet[tblName] = true
+	}
+
+	var updatedFks []ForeignKey
+	for _, fk := range fks {
+		if !tblNameSet[fk.TableName] {
+			updatedFks = append(updatedFks, fk)
+		}
+	}
+
+	for _, fk := range otherFks {
+		if tblNameSet[fk.TableName] {
+			updatedFks = append(updatedFks, fk)
+		}
+	}
+
+	return newRootValue(root.vrw, rootValSt).PutForeignKeys(ctx, updatedFks)
 }
 
+// RemoveTables removes the given tables, along with the foreign keys declared on them. Returns ErrTableReferenced if a
+// table is referenced by a foreign key of a table that isn't removed.
 func (root *RootValue) RemoveTables(ctx context.Context, tables ...string) (*RootValue, error) {
 	tableMap, err := root.getTableMap()
 
@@ -444,6 +516,7 @@ func (root *RootValue) RemoveTables(ctx context.Context, tables ...string) (*Roo
 	}
 
 	me := tableMap.Edit()
+	removed := make(map[string]bool)
 	for _, tbl := range tables {
 		key := types.String(tbl)
 
@@ -451,6 +524,7 @@ func (root *RootValue) RemoveTables(ctx context.Context, tables ...string) (*Roo
 			return nil, err
 		} else if has {
 			me = me.Remove(key)
+			removed[tbl] = true
 		} else {
 			return nil, ErrTableNotFound
 		}
@@ -468,5 +542,24 @@ func (root *RootValue) RemoveTables(ctx context.Context, tables ...string) (*Roo
 		return nil, err
 	}
 
-	return newRootValue(root.vrw, rootValSt), nil
+	// foreign keys declared on removed tables are removed with them, and a table referenced by the foreign key of a
+	// table which isn't removed can't be removed
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var remainingFks []ForeignKey
+	for _, fk := range fks {
+		if removed[fk.TableName] {
+			continue
+		} else if removed[fk.ReferencedTableName] {
+			return nil, ErrTableReferenced
+		}
+
+		remainingFks = append(remainingFks, fk)
+	}
+
+	return newRootValue(root.vrw, rootValSt).PutForeignKeys(ctx, remainingFks)
 }
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
@@ -98,3 +99,46 @@ func TestTableDiff(t *testing.T) {
 		t.Error("Bad table diff after adding a second table")
 	}
 }
+
+func TestRemoveTablesForeignKeys(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "billy bob", "bigbillieb@fake.horse"))
+
+	cs, _ := NewCommitSpec("head", "master")
+	cm, err := ddb.Resolve(ctx, cs)
+	require.NoError(t, err)
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+
+	sch := createTestSchema()
+	m, err := types.NewMap(ctx, ddb.ValueReadWriter())
+	require.NoError(t, err)
+	tbl, err := createTestTable(ddb.ValueReadWriter(), sch, m)
+	require.NoError(t, err)
+
+	for _, tblName := range []string{"parent", "child", "other"} {
+		root, err = root.PutTable(ctx, ddb, tblName, tbl)
+		require.NoError(t, err)
+	}
+
+	root, err = root.PutForeignKeys(ctx, []ForeignKey{
+		{Name: "fk_child", TableName: "child", TableColumns: []uint64{0}, ReferencedTableName: "parent", ReferencedTableColumns: []uint64{0}},
+		{Name: "fk_other", TableName: "other", TableColumns: []uint64{0}, ReferencedTableName: "other", ReferencedTableColumns: []uint64{0}},
+	})
+	require.NoError(t, err)
+
+	_, err = root.RemoveTables(ctx, "parent")
+	assert.Equal(t, ErrTableReferenced, err)
+
+	// the foreign keys of removed tables are removed with them, including those referencing the table itself
+	for _, tblNames := range [][]string{{"child"}, {"parent", "child"}} {
+		updated, err := root.RemoveTables(ctx, append(tblNames, "other")...)
+		require.NoError(t, err)
+
+		fks, err := updated.GetForeignKeys(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, fks)
+	}
+}
@@ -33,6 +33,7 @@ const (
 	tableRowsKey       = "rows"
 	conflictsKey       = "conflicts"
 	conflictSchemasKey = "conflict_schemas"
+	autoIncrementKey   = "auto_increment"
 
 	// TableNameRegexStr is the regular expression that valid tables must match.
 	TableNameRegexStr = `^[a-zA-Z]+[-_0-9a-zA-Z]*[0-9a-zA-Z]+$`
@@ -261,9 +262,7 @@ func (t *Table) GetSchema(ctx context.Context) (schema.Schema, error) {
 	}
 
 	schemaRef := schemaRefVal.(types.Ref)
-	sch, _ := refToSchema(ctx, t.vrw, schemaRef)
-
-	return sch, nil
+	return refToSchema(ctx, t.vrw, schemaRef)
 }
 
 func (t *Table) GetSchemaRef() (types.Ref, error) {
@@ -409,6 +408,96 @@ func (t *Table) UpdateRows(ctx context.Context, updatedRows types.Map) (*Table,
 	return &Table{t.vrw, updatedSt}, nil
 }
 
+// GetAutoIncrementValue returns the next value to be assigned to the AUTO_INCREMENT column of the table. Tables that
+// haven't stored a value yet, like those whose schema was just altered, start after the largest value in the column.
+func (t *Table) GetAutoIncrementValue(ctx context.Context) (uint64, error) {
+	val, ok, err := t.tableStruct.MaybeGet(autoIncrementKey)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if ok {
+		return uint64(val.(types.Uint)), nil
+	}
+
+	sch, err := t.GetSchema(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	autoCol, ok := schema.AutoIncrementCol(sch)
+
+	if !ok {
+		return 1, nil
+	}
+
+	rowData, err := t.GetRowData(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var next uint64 = 1
+	err = rowData.Iter(ctx, func(key, value types.Value) (stop bool, err error) {
+		r, err := row.FromNoms(sch, key.(types.Tuple), value.(types.Tuple))
+
+		if err != nil {
+			return true, err
+		}
+
+		if colVal, ok := r.GetColVal(autoCol.Tag); ok {
+			if n, ok := AutoIncrementValueFromNoms(colVal); ok && n >= next {
+				next = n + 1
+			}
+		}
+
+		return false, nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return next, nil
+}
+
+// SetAutoIncrementValue returns a copy of the table with the next value to be assigned to its AUTO_INCREMENT column
+// set to the value given.
+func (t *Table) SetAutoIncrementValue(val uint64) (*Table, error) {
+	updatedSt, err := t.tableStruct.Set(autoIncrementKey, types.Uint(val))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Table{t.vrw, updatedSt}, nil
+}
+
+// CopyAutoIncrementValue returns a copy of the table with the next AUTO_INCREMENT value of the table given, which it was
+// rebuilt from with NewTable. Without it, the next value would be computed from the rows, handing out the values of
+// deleted rows again. Tables whose schema has no AUTO_INCREMENT column are returned as is.
+func (t *Table) CopyAutoIncrementValue(ctx context.Context, from *Table) (*Table, error) {
+	sch, err := t.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := schema.AutoIncrementCol(sch); !ok {
+		return t, nil
+	}
+
+	next, err := from.GetAutoIncrementValue(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.SetAutoIncrementValue(next)
+}
+
 // GetRowData retrieves the underlying map which is a map from a primary key to a list of field values.
 func (t *Table) GetRowData(ctx context.Context) (types.Map, error) {
 	val, _, err := t.tableStruct.MaybeGet(tableRowsKey)
@@ -0,0 +1,106 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/store/datas"
+)
+
+type chunkCopier func(ctx context.Context, srcDB *doltdb.DoltDB, cm *doltdb.Commit, checkpoint datas.PullCheckpoint, progChan chan datas.PullProgress) error
+
+// SyncBackup makes backupDB a mirror of srcDB. Every ref of srcDB is copied to backupDB whether or not it fast-forwards,
+// refs of backupDB which srcDB doesn't have are deleted, and the backup state of backupDB is replaced with |state|.
+func SyncBackup(ctx context.Context, srcDB, backupDB *doltdb.DoltDB, state doltdb.BackupState, progChan chan datas.PullProgress) error {
+	srcRefs, err := copyRefs(ctx, srcDB, backupDB, backupDB.PushChunks, progChan)
+
+	if err != nil {
+		return err
+	}
+
+	backupRefs, err := backupDB.GetRefs(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, dref := range backupRefs {
+		if _, ok := srcRefs[dref.String()]; !ok {
+			err = backupDB.DeleteBranch(ctx, dref)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return backupDB.PushBackupState(ctx, srcDB, state, progChan)
+}
+
+// RestoreBackup copies every ref of backupDB to destDB, along with its backup state, which is returned. Returns
+// doltdb.ErrNotABackup if backupDB was never synced.
+func RestoreBackup(ctx context.Context, backupDB, destDB *doltdb.DoltDB, progChan chan datas.PullProgress) (doltdb.BackupState, error) {
+	_, err := copyRefs(ctx, backupDB, destDB, destDB.PullChunks, progChan)
+
+	if err != nil {
+		return doltdb.BackupState{}, err
+	}
+
+	return destDB.PullBackupState(ctx, backupDB, progChan)
+}
+
+// copyRefs copies the commit of every ref of srcDB into destDB with |copyChunks|, which is destDB's PushChunks or
+// PullChunks, and sets the ref in destDB to it. Returns the refs copied keyed by their string form.
+func copyRefs(ctx context.Context, srcDB, destDB *doltdb.DoltDB, copyChunks chunkCopier, progChan chan datas.PullProgress) (map[string]ref.DoltRef, error) {
+	refs, err := srcDB.GetRefs(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	copied := make(map[string]ref.DoltRef, len(refs))
+	for _, dref := range refs {
+		cs, err := doltdb.NewCommitSpec("HEAD", dref.String())
+
+		if err != nil {
+			return nil, err
+		}
+
+		cm, err := srcDB.Resolve(ctx, cs)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = copyChunks(ctx, srcDB, cm, nil, progChan)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = destDB.SetHeadToCommit(ctx, dref, cm)
+
+		if err != nil {
+			return nil, err
+		}
+
+		copied[dref.String()] = dref
+	}
+
+	return copied, nil
+}
@@ -88,6 +88,16 @@ func CommitStaged(ctx context.Context, dEnv *env.DoltEnv, msg string, allowEmpty
 		return err
 	}
 
+	headRoot, err := dEnv.HeadRoot(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if err = root.ValidateForeignKeyChanges(ctx, headRoot); err != nil {
+		return err
+	}
+
 	h, err := dEnv.UpdateStagedRoot(ctx, root)
 
 	if err != nil {
@@ -170,7 +180,9 @@ func AddCommits(ctx context.Context, ddb *doltdb.DoltDB, commit *doltdb.Commit,
 	for i := 0; i < numParents && len(hashToCommit) != n; i++ {
 		parentCommit, err := ddb.ResolveParent(ctx, commit, i)
 
-		if err != nil {
+		if err == doltdb.ErrPastShallowBoundary {
+			continue
+		} else if err != nil {
 			return err
 		}
 
@@ -0,0 +1,281 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	parentIdTag = iota
+	childIdTag
+	childParentIdTag
+)
+
+var parentSch = dtestutils.CreateSchema(
+	schema.NewColumn("id", parentIdTag, types.IntKind, true, schema.NotNullConstraint{}),
+)
+
+var childSch = dtestutils.CreateSchema(
+	schema.NewColumn("id", childIdTag, types.IntKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn("parent_id", childParentIdTag, types.IntKind, false),
+)
+
+var childFk = doltdb.ForeignKey{
+	Name:                   "fk_child_parent",
+	TableName:              "child",
+	TableColumns:           []uint64{childParentIdTag},
+	ReferencedTableName:    "parent",
+	ReferencedTableColumns: []uint64{parentIdTag},
+}
+
+// putParentRows returns the root given with the rows of the parent table given by id.
+func putParentRows(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue, ids ...int64) *doltdb.RootValue {
+	var rows []row.Row
+	for _, id := range ids {
+		r, err := row.New(types.Format_7_18, parentSch, row.TaggedValues{parentIdTag: types.Int(id)})
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+
+	return putTestRows(t, ddb, root, "parent", parentSch, rows...)
+}
+
+// putChildRows returns the root given with the rows of the child table given, each as an id and the id of the parent
+// row it references.
+func putChildRows(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue, idsToParentIds ...[2]int64) *doltdb.RootValue {
+	var rows []row.Row
+	for _, ids := range idsToParentIds {
+		r, err := row.New(types.Format_7_18, childSch, row.TaggedValues{childIdTag: types.Int(ids[0]), childParentIdTag: types.Int(ids[1])})
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+
+	return putTestRows(t, ddb, root, "child", childSch, rows...)
+}
+
+// putTestRows returns the root given with the rows given added to the table given, which is created if it doesn't
+// exist.
+func putTestRows(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue, tblName string, sch schema.Schema, rows ...row.Row) *doltdb.RootValue {
+	ctx := context.Background()
+	tbl, ok, err := root.GetTable(ctx, tblName)
+	require.NoError(t, err)
+
+	if !ok {
+		schVal, err := encoding.MarshalAsNomsValue(ctx, root.VRW(), sch)
+		require.NoError(t, err)
+		m, err := types.NewMap(ctx, root.VRW())
+		require.NoError(t, err)
+		tbl, err = doltdb.NewTable(ctx, root.VRW(), schVal, m)
+		require.NoError(t, err)
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+
+	me := rowData.Edit()
+	for _, r := range rows {
+		me.Set(r.NomsMapKey(sch), r.NomsMapValue(sch))
+	}
+
+	rowData, err = me.Map(ctx)
+	require.NoError(t, err)
+	tbl, err = tbl.UpdateRows(ctx, rowData)
+	require.NoError(t, err)
+
+	root, err = root.PutTable(ctx, ddb, tblName, tbl)
+	require.NoError(t, err)
+
+	return root
+}
+
+// removeParentRows returns the root given without the rows of the parent table given by id.
+func removeParentRows(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue, ids ...int64) *doltdb.RootValue {
+	ctx := context.Background()
+	tbl, _, err := root.GetTable(ctx, "parent")
+	require.NoError(t, err)
+	rowData, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+
+	me := rowData.Edit()
+	for _, id := range ids {
+		r, err := row.New(types.Format_7_18, parentSch, row.TaggedValues{parentIdTag: types.Int(id)})
+		require.NoError(t, err)
+		me.Remove(r.NomsMapKey(parentSch))
+	}
+
+	rowData, err = me.Map(ctx)
+	require.NoError(t, err)
+	tbl, err = tbl.UpdateRows(ctx, rowData)
+	require.NoError(t, err)
+
+	root, err = root.PutTable(ctx, ddb, "parent", tbl)
+	require.NoError(t, err)
+
+	return root
+}
+
+// newForeignKeyTestEnv returns an environment whose head commit has a child table with a foreign key referencing a
+// parent table with the rows 1 and 2. The child row 1 references the parent row 1.
+func newForeignKeyTestEnv(t *testing.T) (*env.DoltEnv, *doltdb.RootValue) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	ddb := dEnv.DoltDB
+	root, err := dEnv.HeadRoot(ctx)
+	require.NoError(t, err)
+
+	root = putParentRows(t, ddb, root, 1, 2)
+	root = putChildRows(t, ddb, root, [2]int64{1, 1})
+	root, err = root.AddForeignKey(ctx, childFk)
+	require.NoError(t, err)
+
+	_, err = dEnv.UpdateStagedRoot(ctx, root)
+	require.NoError(t, err)
+	err = CommitStaged(ctx, dEnv, "foreign key", false)
+	require.NoError(t, err)
+
+	return dEnv, root
+}
+
+func TestCommitValidatesForeignKeys(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		change    func(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue) *doltdb.RootValue
+		violation bool
+	}{
+		{
+			name: "child row referencing an existing row",
+			change: func(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue) *doltdb.RootValue {
+				return putChildRows(t, ddb, root, [2]int64{2, 2})
+			},
+		},
+		{
+			name: "child row referencing a missing row",
+			change: func(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue) *doltdb.RootValue {
+				return putChildRows(t, ddb, root, [2]int64{2, 3})
+			},
+			violation: true,
+		},
+		{
+			name: "child row changed to reference a missing row",
+			change: func(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue) *doltdb.RootValue {
+				return putChildRows(t, ddb, root, [2]int64{1, 3})
+			},
+			violation: true,
+		},
+		{
+			name: "unreferenced parent row removed",
+			change: func(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue) *doltdb.RootValue {
+				return removeParentRows(t, ddb, root, 2)
+			},
+		},
+		{
+			name: "referenced parent row removed",
+			change: func(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue) *doltdb.RootValue {
+				return removeParentRows(t, ddb, root, 1)
+			},
+			violation: true,
+		},
+		{
+			name: "referenced parent row removed and child row changed to reference another",
+			change: func(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue) *doltdb.RootValue {
+				root = removeParentRows(t, ddb, root, 1)
+				return putChildRows(t, ddb, root, [2]int64{1, 2})
+			},
+		},
+		{
+			name: "parent row added and referenced",
+			change: func(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue) *doltdb.RootValue {
+				root = putParentRows(t, ddb, root, 3)
+				return putChildRows(t, ddb, root, [2]int64{2, 3})
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dEnv, root := newForeignKeyTestEnv(t)
+
+			_, err := dEnv.UpdateStagedRoot(ctx, test.change(t, dEnv.DoltDB, root))
+			require.NoError(t, err)
+			err = CommitStaged(ctx, dEnv, "change", false)
+
+			if test.violation {
+				assert.True(t, doltdb.IsForeignKeyViolation(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMergeCommitsReportsForeignKeyViolations(t *testing.T) {
+	ctx := context.Background()
+	dEnv, root := newForeignKeyTestEnv(t)
+	ddb := dEnv.DoltDB
+	meta, err := doltdb.NewCommitMeta("billy bob", "bigbillieb@fake.horse", "merge test")
+	require.NoError(t, err)
+
+	headSpec, err := doltdb.NewCommitSpec("head", "master")
+	require.NoError(t, err)
+	ancCommit, err := ddb.Resolve(ctx, headSpec)
+	require.NoError(t, err)
+
+	// master removes the parent row 2, while a branch starts referencing it
+	h, err := ddb.WriteRootValue(ctx, removeParentRows(t, ddb, root, 2))
+	require.NoError(t, err)
+	commit, err := ddb.Commit(ctx, h, ref.NewBranchRef("master"), meta)
+	require.NoError(t, err)
+
+	err = ddb.NewBranchAtCommit(ctx, ref.NewBranchRef("to-merge"), ancCommit)
+	require.NoError(t, err)
+	h, err = ddb.WriteRootValue(ctx, putChildRows(t, ddb, root, [2]int64{2, 2}))
+	require.NoError(t, err)
+	mergeCommit, err := ddb.Commit(ctx, h, ref.NewBranchRef("to-merge"), meta)
+	require.NoError(t, err)
+
+	merged, tblToStats, err := MergeCommits(ctx, ddb, commit, mergeCommit)
+	require.NoError(t, err)
+
+	require.Contains(t, tblToStats, "child")
+	assert.Equal(t, 1, tblToStats["child"].ForeignKeyViolations)
+	require.Contains(t, tblToStats, "parent")
+	assert.Equal(t, 0, tblToStats["parent"].ForeignKeyViolations)
+
+	fks, err := merged.GetForeignKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []doltdb.ForeignKey{childFk}, fks)
+
+	// the merged root can't be committed until the violation is fixed
+	commitRoot, err := commit.GetRootValue()
+	require.NoError(t, err)
+	err = merged.ValidateForeignKeyChanges(ctx, commitRoot)
+	assert.True(t, doltdb.IsForeignKeyViolation(err))
+}
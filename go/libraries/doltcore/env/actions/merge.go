@@ -48,6 +48,7 @@ func MergeCommits(ctx context.Context, ddb *doltdb.DoltDB, cm1, cm2 *doltdb.Comm
 	}
 
 	tblToStats := make(map[string]*merge.MergeStats)
+	var removed []string
 
 	// need to validate merges can be done on all tables before starting the actual merges.
 	for _, tblName := range tblNames {
@@ -70,16 +71,52 @@ func MergeCommits(ctx context.Context, ddb *doltdb.DoltDB, cm1, cm2 *doltdb.Comm
 			return nil, nil, err
 		} else if has {
 			tblToStats[tblName] = &merge.MergeStats{Operation: merge.TableRemoved}
-			root, err = root.RemoveTables(ctx, tblName)
-
-			if err != nil {
-				return nil, nil, err
-			}
+			removed = append(removed, tblName)
 		} else {
 			panic("?")
 		}
 	}
 
+	fks, err := merger.MergeForeignKeys(ctx)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root, err = root.PutForeignKeys(ctx, fks)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// tables are removed after the foreign keys are merged, so that only the merged foreign keys must not reference them
+	if len(removed) > 0 {
+		root, err = root.RemoveTables(ctx, removed...)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// rows merged cleanly from both sides may still violate foreign keys, e.g. when one side deletes a row that the
+	// other side starts referencing. These are reported along with the merge stats of the referencing table.
+	violations, err := root.GetForeignKeyViolations(ctx)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, v := range violations {
+		stats, ok := tblToStats[v.ForeignKey.TableName]
+
+		if !ok {
+			stats = &merge.MergeStats{Operation: merge.TableUnmodified}
+			tblToStats[v.ForeignKey.TableName] = stats
+		}
+
+		stats.ForeignKeyViolations++
+	}
+
 	return root, tblToStats, nil
 }
 
@@ -30,8 +30,8 @@ var ErrCantFF = errors.New("can't fast forward merge")
 // This is accomplished first by verifying that the remote tracking reference for the source database can be updated to
 // the given commit via a fast forward merge.  If this is the case, an attempt will be made to update the branch in the
 // destination db to the given commit via fast forward move.  If that succeeds the tracking branch is updated in the
-// source db.
-func Push(ctx context.Context, destRef ref.BranchRef, remoteRef ref.RemoteRef, srcDB, destDB *doltdb.DoltDB, commit *doltdb.Commit, progChan chan datas.PullProgress) error {
+// source db. The progress of the push is saved to |checkpoint| if it isn't nil.
+func Push(ctx context.Context, destRef ref.BranchRef, remoteRef ref.RemoteRef, srcDB, destDB *doltdb.DoltDB, commit *doltdb.Commit, checkpoint datas.PullCheckpoint, progChan chan datas.PullProgress) error {
 	canFF, err := srcDB.CanFastForward(ctx, remoteRef, commit)
 
 	if err != nil {
@@ -40,7 +40,7 @@ func Push(ctx context.Context, destRef ref.BranchRef, remoteRef ref.RemoteRef, s
 		return ErrCantFF
 	}
 
-	err = destDB.PushChunks(ctx, srcDB, commit, progChan)
+	err = destDB.PushChunks(ctx, srcDB, commit, checkpoint, progChan)
 
 	if err != nil {
 		return err
@@ -83,8 +83,21 @@ func DeleteRemoteBranch(ctx context.Context, targetRef ref.BranchRef, remoteRef
 	return nil
 }
 
-func Fetch(ctx context.Context, destRef ref.DoltRef, srcDB, destDB *doltdb.DoltDB, commit *doltdb.Commit, progChan chan datas.PullProgress) error {
-	err := destDB.PullChunks(ctx, srcDB, commit, progChan)
+// Fetch pulls the commit given from srcDB and fast-forwards destRef in destDB to it. The progress of the pull is saved
+// to |checkpoint| if it isn't nil.
+func Fetch(ctx context.Context, destRef ref.DoltRef, srcDB, destDB *doltdb.DoltDB, commit *doltdb.Commit, checkpoint datas.PullCheckpoint, progChan chan datas.PullProgress) error {
+	err := destDB.PullChunks(ctx, srcDB, commit, checkpoint, progChan)
+
+	if err != nil {
+		return err
+	}
+
+	return destDB.FastForward(ctx, destRef, commit)
+}
+
+// FetchShallow is Fetch for a shallow clone. Only the last |depth| commits of the history of the commit given are pulled.
+func FetchShallow(ctx context.Context, destRef ref.DoltRef, srcDB, destDB *doltdb.DoltDB, commit *doltdb.Commit, depth int, progChan chan datas.PullProgress) error {
+	err := destDB.PullChunksShallow(ctx, srcDB, commit, depth, progChan)
 
 	if err != nil {
 		return err
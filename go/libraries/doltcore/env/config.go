@@ -42,6 +42,10 @@ const (
 	RemotesApiHostPortKey = "remotes.default_port"
 
 	AddCredsUrlKey = "creds.add_url"
+
+	// StorageCompressionKey sets the compression of the tables written to the repository's database, which is snappy
+	// unless set to zstd
+	StorageCompressionKey = "storage.compression"
 )
 
 var LocalConfigWhitelist = set.NewStrSet([]string{UserNameKey, UserEmailKey})
@@ -17,6 +17,7 @@ package env
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -33,6 +34,7 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/datas"
 	"github.com/liquidata-inc/dolt/go/store/hash"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
@@ -68,7 +70,7 @@ type DoltEnv struct {
 func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr string) *DoltEnv {
 	config, cfgErr := loadDoltCliConfig(hdp, fs)
 	repoState, rsErr := LoadRepoState(fs)
-	ddb, dbLoadErr := doltdb.LoadDoltDB(ctx, types.Format_Default, urlStr)
+	ddb, dbLoadErr := doltdb.LoadDoltDBWithParams(ctx, types.Format_Default, urlStr, dbParams(config, cfgErr))
 
 	dEnv := &DoltEnv{
 		config,
@@ -87,6 +89,21 @@ func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr s
 	return dEnv
 }
 
+// dbParams returns the creation params of the repository's database which are set in its config
+func dbParams(config *DoltCliConfig, cfgErr error) map[string]string {
+	params := map[string]string{}
+
+	if cfgErr != nil {
+		return params
+	}
+
+	if compression := config.GetStringOrDefault(StorageCompressionKey, ""); *compression != "" {
+		params[dbfactory.CompressionParam] = *compression
+	}
+
+	return params
+}
+
 // HasDoltDir returns true if the .dolt directory exists and is a valid directory
 func (dEnv *DoltEnv) HasDoltDir() bool {
 	return dEnv.hasDoltDir("./")
@@ -302,6 +319,16 @@ func (dEnv *DoltEnv) UpdateStagedRoot(ctx context.Context, newRoot *doltdb.RootV
 }
 
 func (dEnv *DoltEnv) PutTableToWorking(ctx context.Context, rows types.Map, sch schema.Schema, tableName string) error {
+	return dEnv.putTableToWorking(ctx, rows, sch, tableName, nil)
+}
+
+// PutTableToWorkingWithAutoIncrement puts a table with the rows and schema given in the working root, like
+// PutTableToWorking, storing |next| as the next value to be assigned to its AUTO_INCREMENT column.
+func (dEnv *DoltEnv) PutTableToWorkingWithAutoIncrement(ctx context.Context, rows types.Map, sch schema.Schema, tableName string, next uint64) error {
+	return dEnv.putTableToWorking(ctx, rows, sch, tableName, &next)
+}
+
+func (dEnv *DoltEnv) putTableToWorking(ctx context.Context, rows types.Map, sch schema.Schema, tableName string, autoIncVal *uint64) error {
 	root, err := dEnv.WorkingRoot(ctx)
 
 	if err != nil {
@@ -321,12 +348,24 @@ func (dEnv *DoltEnv) PutTableToWorking(ctx context.Context, rows types.Map, sch
 		return err
 	}
 
+	if autoIncVal != nil {
+		tbl, err = tbl.SetAutoIncrementValue(*autoIncVal)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	newRoot, err := root.PutTable(ctx, dEnv.DoltDB, tableName, tbl)
 
 	if err != nil {
 		return err
 	}
 
+	if err = newRoot.ValidateForeignKeyChanges(ctx, root, tableName); err != nil {
+		return err
+	}
+
 	rootHash, err := root.HashOf()
 
 	if err != nil {
@@ -346,6 +385,111 @@ func (dEnv *DoltEnv) IsMergeActive() bool {
 	return dEnv.RepoState.Merge != nil
 }
 
+// CollectGarbage removes the chunks of the repository's database that aren't reachable from its refs, its working and
+// staged roots, or the state of a merge in progress. The saved progress of interrupted fetches and pushes is discarded
+// first. The chunks a fetch has already pulled aren't reachable yet, so resuming it after they're removed would leave
+// the commit it pulls incomplete. Interrupted pushes start over too.
+func (dEnv *DoltEnv) CollectGarbage(ctx context.Context) (datas.GCStats, error) {
+	for _, dir := range []string{fetchTransfersDir, pushTransfersDir} {
+		checkpointsDir := filepath.Join(getTransfersDir(), dir)
+		if exists, _ := dEnv.FS.Exists(checkpointsDir); exists {
+			err := dEnv.FS.Delete(checkpointsDir, true)
+
+			if err != nil {
+				return datas.GCStats{}, err
+			}
+		}
+	}
+
+	hashStrs := []string{dEnv.RepoState.Working, dEnv.RepoState.Staged}
+	if dEnv.RepoState.Merge != nil {
+		hashStrs = append(hashStrs, dEnv.RepoState.Merge.Commit, dEnv.RepoState.Merge.PreMergeWorking)
+	}
+
+	var roots []hash.Hash
+	for _, hashStr := range hashStrs {
+		h, ok := hash.MaybeParse(hashStr)
+
+		if !ok {
+			return datas.GCStats{}, fmt.Errorf("the repo state has an invalid hash '%s'", hashStr)
+		}
+
+		roots = append(roots, h)
+	}
+
+	return dEnv.DoltDB.CollectGarbage(ctx, roots...)
+}
+
+// Fsck checks the integrity of the repository's database, along with its working and staged roots and the working
+// set from before a merge in progress.
+func (dEnv *DoltEnv) Fsck(ctx context.Context) (*doltdb.FsckReport, error) {
+	hashStrs := map[string]string{"working set": dEnv.RepoState.Working, "staged": dEnv.RepoState.Staged}
+	if dEnv.RepoState.Merge != nil {
+		hashStrs["pre-merge working set"] = dEnv.RepoState.Merge.PreMergeWorking
+	}
+
+	roots := make(map[string]hash.Hash, len(hashStrs))
+	for name, hashStr := range hashStrs {
+		h, ok := hash.MaybeParse(hashStr)
+
+		if !ok {
+			return nil, fmt.Errorf("the repo state has an invalid hash '%s'", hashStr)
+		}
+
+		roots[name] = h
+	}
+
+	return dEnv.DoltDB.Fsck(ctx, roots)
+}
+
+// BackupState returns the state of the repository which a backup holds on to along with its refs: its serialized repo
+// state, its working and staged roots, and the commit being merged along with the working set from before a merge in
+// progress.
+func (dEnv *DoltEnv) BackupState() (doltdb.BackupState, error) {
+	data, err := json.Marshal(dEnv.RepoState)
+
+	if err != nil {
+		return doltdb.BackupState{}, err
+	}
+
+	rootStrs := map[string]string{"working": dEnv.RepoState.Working, "staged": dEnv.RepoState.Staged}
+	commitStrs := map[string]string{}
+	if dEnv.RepoState.Merge != nil {
+		rootStrs["working_pre_merge"] = dEnv.RepoState.Merge.PreMergeWorking
+		commitStrs["merge"] = dEnv.RepoState.Merge.Commit
+	}
+
+	roots, err := parseStateHashes(rootStrs)
+
+	if err != nil {
+		return doltdb.BackupState{}, err
+	}
+
+	commits, err := parseStateHashes(commitStrs)
+
+	if err != nil {
+		return doltdb.BackupState{}, err
+	}
+
+	return doltdb.BackupState{RepoState: string(data), Roots: roots, Commits: commits}, nil
+}
+
+// parseStateHashes parses the hashes of the repo state given, keyed by name.
+func parseStateHashes(hashStrs map[string]string) (map[string]hash.Hash, error) {
+	hashes := make(map[string]hash.Hash, len(hashStrs))
+	for name, hashStr := range hashStrs {
+		h, ok := hash.MaybeParse(hashStr)
+
+		if !ok {
+			return nil, fmt.Errorf("the repo state has an invalid hash '%s'", hashStr)
+		}
+
+		hashes[name] = h
+	}
+
+	return hashes, nil
+}
+
 func (dEnv *DoltEnv) GetTablesWithConflicts(ctx context.Context) ([]string, error) {
 	root, err := dEnv.WorkingRoot(ctx)
 
@@ -455,6 +599,18 @@ func (dEnv *DoltEnv) GetRemotes() (map[string]Remote, error) {
 	return dEnv.RepoState.Remotes, nil
 }
 
+func (dEnv *DoltEnv) GetBackups() (map[string]Remote, error) {
+	if dEnv.RSLoadErr != nil {
+		return nil, dEnv.RSLoadErr
+	}
+
+	if dEnv.RepoState.Backups == nil {
+		return map[string]Remote{}, nil
+	}
+
+	return dEnv.RepoState.Backups, nil
+}
+
 var ErrNotACred = errors.New("not a valid credential key id or public key")
 
 func (dEnv *DoltEnv) FindCreds(credsDir, pubKeyOrId string) (string, error) {
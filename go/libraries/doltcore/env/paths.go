@@ -30,6 +30,10 @@ const (
 	globalConfig = "config_global.json"
 
 	repoStateFile = "repo_state.json"
+
+	transfersDir      = "transfers"
+	fetchTransfersDir = "fetch"
+	pushTransfersDir  = "push"
 )
 
 // HomeDirProvider is a function that returns the users home directory.  This is where global dolt state is stored for
@@ -76,3 +80,7 @@ func getLocalConfigPath() string {
 func getRepoStateFile() string {
 	return filepath.Join(dbfactory.DoltDir, repoStateFile)
 }
+
+func getTransfersDir() string {
+	return filepath.Join(dbfactory.DoltDir, transfersDir)
+}
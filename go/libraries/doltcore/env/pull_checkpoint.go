@@ -0,0 +1,143 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/datas"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+// pullState is the json form of a datas.PullState
+type pullState struct {
+	Absent []string `json:"absent"`
+	Next   []string `json:"next"`
+}
+
+// fileCheckpoint is a datas.PullCheckpoint that saves the state of each pull to a file in a directory named for the
+// hash being pulled
+type fileCheckpoint struct {
+	fs  filesys.ReadWriteFS
+	dir string
+}
+
+// PullCheckpoint returns the checkpoint used to save the progress of fetches into the repository when |remote| is "",
+// or of pushes to the remote named |remote| otherwise.
+func (dEnv *DoltEnv) PullCheckpoint(remote string) datas.PullCheckpoint {
+	dir := filepath.Join(getTransfersDir(), fetchTransfersDir)
+	if remote != "" {
+		dir = filepath.Join(getTransfersDir(), pushTransfersDir, remote)
+	}
+
+	return fileCheckpoint{dEnv.FS, dir}
+}
+
+func (fc fileCheckpoint) path(h hash.Hash) string {
+	return filepath.Join(fc.dir, h.String()+".json")
+}
+
+// Load returns the saved state of the pull of |h|. A state file that can't be read back, because the pull was
+// interrupted while it was being written, resumes the pull from the start.
+func (fc fileCheckpoint) Load(ctx context.Context, h hash.Hash) (datas.PullState, bool, error) {
+	path := fc.path(h)
+
+	if exists, _ := fc.fs.Exists(path); !exists {
+		return datas.PullState{}, false, nil
+	}
+
+	restart := datas.PullState{Absent: hash.HashSlice{h}}
+	data, err := fc.fs.ReadFile(path)
+
+	if err != nil {
+		return datas.PullState{}, false, err
+	}
+
+	var ps pullState
+	err = json.Unmarshal(data, &ps)
+
+	if err != nil {
+		return restart, true, nil
+	}
+
+	absent, ok := parseHashes(ps.Absent)
+
+	if !ok {
+		return restart, true, nil
+	}
+
+	next, ok := parseHashes(ps.Next)
+
+	if !ok {
+		return restart, true, nil
+	}
+
+	return datas.PullState{Absent: absent, Next: next}, true, nil
+}
+
+// Save writes the state of the pull of |h| to its file
+func (fc fileCheckpoint) Save(ctx context.Context, h hash.Hash, state datas.PullState) error {
+	err := fc.fs.MkDirs(fc.dir)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(pullState{hashStrings(state.Absent), hashStrings(state.Next)})
+
+	if err != nil {
+		return err
+	}
+
+	return fc.fs.WriteFile(fc.path(h), data)
+}
+
+// Clear deletes the file of the pull of |h|
+func (fc fileCheckpoint) Clear(ctx context.Context, h hash.Hash) error {
+	path := fc.path(h)
+
+	if exists, _ := fc.fs.Exists(path); !exists {
+		return nil
+	}
+
+	return fc.fs.DeleteFile(path)
+}
+
+func hashStrings(hashes hash.HashSlice) []string {
+	strs := make([]string, len(hashes))
+	for i, h := range hashes {
+		strs[i] = h.String()
+	}
+
+	return strs
+}
+
+func parseHashes(strs []string) (hash.HashSlice, bool) {
+	hashes := make(hash.HashSlice, len(strs))
+	for i, str := range strs {
+		h, ok := hash.MaybeParse(str)
+
+		if !ok {
+			return nil, false
+		}
+
+		hashes[i] = h
+	}
+
+	return hashes, true
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/test"
+	"github.com/liquidata-inc/dolt/go/store/datas"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/nbs"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestPullCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createTestEnv(true, true)
+	fetches := dEnv.PullCheckpoint("")
+	pushes := dEnv.PullCheckpoint("origin")
+
+	target := hash.Of([]byte("target"))
+	state := datas.PullState{
+		Absent: hash.HashSlice{hash.Of([]byte("a")), hash.Of([]byte("b"))},
+		Next:   hash.HashSlice{hash.Of([]byte("c"))},
+	}
+
+	_, ok, err := fetches.Load(ctx, target)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, fetches.Save(ctx, target, state))
+
+	loaded, ok, err := fetches.Load(ctx, target)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, state, loaded)
+
+	_, ok, err = pushes.Load(ctx, target)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// a state file cut short restarts the pull from the target
+	require.NoError(t, dEnv.FS.WriteFile(fetches.(fileCheckpoint).path(target), []byte(`{"absent":["`)))
+	loaded, ok, err = fetches.Load(ctx, target)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, hash.HashSlice{target}, loaded.Absent)
+
+	require.NoError(t, fetches.Clear(ctx, target))
+	_, ok, err = fetches.Load(ctx, target)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// createSourceCommit commits a table to a new database in |dir| and returns the database and the commit
+func createSourceCommit(t *testing.T, ctx context.Context, dir string) (*doltdb.DoltDB, *doltdb.Commit) {
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_Default, "file://"+dir)
+	require.NoError(t, err)
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	cs, err := doltdb.NewCommitSpec("master", "")
+	require.NoError(t, err)
+	head, err := ddb.Resolve(ctx, cs)
+	require.NoError(t, err)
+	root, err := head.GetRootValue()
+	require.NoError(t, err)
+
+	colColl, err := schema.NewColCollection(schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}))
+	require.NoError(t, err)
+	schVal, err := encoding.MarshalAsNomsValue(ctx, ddb.ValueReadWriter(), schema.SchemaFromCols(colColl))
+	require.NoError(t, err)
+	rows, err := types.NewMap(ctx, ddb.ValueReadWriter())
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, ddb.ValueReadWriter(), schVal, rows)
+	require.NoError(t, err)
+	root, err = root.PutTable(ctx, ddb, "fetched", tbl)
+	require.NoError(t, err)
+	valHash, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+
+	meta, err := doltdb.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "add a table")
+	require.NoError(t, err)
+	cm, err := ddb.Commit(ctx, valHash, ref.NewBranchRef("master"), meta)
+	require.NoError(t, err)
+
+	return ddb, cm
+}
+
+// TestCollectGarbageDiscardsCheckpoints interrupts a fetch after it has persisted the commit being fetched, collects
+// the garbage of the repository, which removes that commit, and then checks that the resumed fetch pulls all of it.
+func TestCollectGarbageDiscardsCheckpoints(t *testing.T) {
+	ctx := context.Background()
+	testDir, err := test.ChangeToTestDir("TestCollectGarbageDiscardsCheckpoints")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+
+	dEnv := Load(ctx, testHomeDirFunc, filesys.LocalFS, doltdb.LocalDirDoltDB)
+	require.NoError(t, dEnv.InitRepo(ctx, types.Format_Default, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	srcDir := filepath.Join(testDir, "source")
+	srcDB, cm := createSourceCommit(t, ctx, srcDir)
+	cmHash, err := cm.HashOf()
+	require.NoError(t, err)
+
+	// the state of a fetch interrupted after its first checkpoint, which persisted the commit but none of the chunks
+	// it references
+	srcCS, err := nbs.NewLocalStore(ctx, types.Format_Default.VersionString(), srcDir, 1<<20)
+	require.NoError(t, err)
+	c, err := srcCS.Get(ctx, cmHash)
+	require.NoError(t, err)
+
+	var next hash.HashSlice
+	err = types.WalkRefs(c, types.Format_Default, func(r types.Ref) error {
+		next = append(next, r.TargetHash())
+		return nil
+	})
+	require.NoError(t, err)
+
+	sinkCS, err := nbs.NewLocalStore(ctx, types.Format_Default.VersionString(), dbfactory.DoltDataDir, 1<<20)
+	require.NoError(t, err)
+	require.NoError(t, sinkCS.Put(ctx, c))
+	last, err := sinkCS.Root(ctx)
+	require.NoError(t, err)
+	ok, err := sinkCS.Commit(ctx, last, last)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	state := datas.PullState{Absent: next}
+	require.NoError(t, dEnv.PullCheckpoint("").Save(ctx, cmHash, state))
+	require.NoError(t, dEnv.PullCheckpoint("origin").Save(ctx, cmHash, state))
+
+	dEnv = Load(ctx, testHomeDirFunc, filesys.LocalFS, doltdb.LocalDirDoltDB)
+	_, err = dEnv.CollectGarbage(ctx)
+	require.NoError(t, err)
+
+	for _, remote := range []string{"", "origin"} {
+		_, ok, err := dEnv.PullCheckpoint(remote).Load(ctx, cmHash)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+
+	require.NoError(t, dEnv.DoltDB.PullChunks(ctx, srcDB, cm, dEnv.PullCheckpoint(""), nil))
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("fetched"), cm))
+
+	report, err := dEnv.Fsck(ctx)
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+}
@@ -41,6 +41,7 @@ type RepoState struct {
 	Merge    *MergeState             `json:"merge"`
 	Remotes  map[string]Remote       `json:"remotes"`
 	Branches map[string]BranchConfig `json:"branches"`
+	Backups  map[string]Remote       `json:"backups,omitempty"`
 
 	fs filesys.ReadWriteFS
 }
@@ -68,7 +69,7 @@ func LoadRepoState(fs filesys.ReadWriteFS) (*RepoState, error) {
 func CloneRepoState(fs filesys.ReadWriteFS, r Remote) (*RepoState, error) {
 	h := hash.Hash{}
 	hashStr := h.String()
-	rs := &RepoState{ref.MarshalableRef{Ref: ref.NewBranchRef("master")}, hashStr, hashStr, nil, map[string]Remote{r.Name: r}, nil, fs}
+	rs := &RepoState{ref.MarshalableRef{Ref: ref.NewBranchRef("master")}, hashStr, hashStr, nil, map[string]Remote{r.Name: r}, nil, nil, fs}
 
 	err := rs.Save()
 
@@ -87,7 +88,7 @@ func CreateRepoState(fs filesys.ReadWriteFS, br string, rootHash hash.Hash) (*Re
 		return nil, err
 	}
 
-	rs := &RepoState{ref.MarshalableRef{Ref: headRef}, hashStr, hashStr, nil, nil, nil, fs}
+	rs := &RepoState{ref.MarshalableRef{Ref: headRef}, hashStr, hashStr, nil, nil, nil, nil, fs}
 
 	err = rs.Save()
 
@@ -98,6 +99,25 @@ func CreateRepoState(fs filesys.ReadWriteFS, br string, rootHash hash.Hash) (*Re
 	return rs, nil
 }
 
+// RestoreRepoState writes the repo state serialized in |data|, as is done by Save, to the repo state file of |fs|
+func RestoreRepoState(fs filesys.ReadWriteFS, data string) (*RepoState, error) {
+	var repoState RepoState
+	err := json.Unmarshal([]byte(data), &repoState)
+
+	if err != nil {
+		return nil, err
+	}
+
+	repoState.fs = fs
+	err = repoState.Save()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &repoState, nil
+}
+
 func (rs *RepoState) Save() error {
 	data, err := json.MarshalIndent(rs, "", "  ")
 
@@ -138,3 +158,11 @@ func (rs *RepoState) AddRemote(r Remote) {
 
 	rs.Remotes[r.Name] = r
 }
+
+func (rs *RepoState) AddBackup(r Remote) {
+	if rs.Backups == nil {
+		rs.Backups = make(map[string]Remote)
+	}
+
+	rs.Backups[r.Name] = r
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltcore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// ErrTrailingJSON is returned when parsing text that has data after the end of its JSON document.
+var ErrTrailingJSON = errors.New("invalid JSON: unexpected data after the end of the document")
+
+// ParseJSON parses the JSON document given and returns its canonical form: compact, with object keys sorted, so that
+// equal documents have equal values. JSON columns store documents in this form.
+func ParseJSON(doc string) (types.String, error) {
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+
+	if dec.More() {
+		return "", ErrTrailingJSON
+	}
+
+	return JSONFromGo(v)
+}
+
+// JSONFromGo returns the canonical JSON document for a value decoded by encoding/json.
+func JSONFromGo(v interface{}) (types.String, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+
+	return types.String(bytes.TrimRight(buf.Bytes(), "\n")), nil
+}
+
+// JSONToGo decodes the document given into the generic values used by encoding/json. Numbers are decoded as
+// json.Number.
+func JSONToGo(doc types.String) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(string(doc)))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// StringToJSON parses the string given as a JSON document, like StringToValue does for other types. Empty strings are
+// null.
+func StringToJSON(s string) (types.Value, error) {
+	if len(s) == 0 {
+		return types.NullValue, nil
+	}
+
+	doc, err := ParseJSON(s)
+
+	if err != nil {
+		return types.String(""), ConversionError{types.StringKind, types.StringKind, err}
+	}
+
+	return doc, nil
+}
+
+// ConvStringToJSON is a ConvFunc that parses strings as JSON documents.
+func ConvStringToJSON(val types.Value) (types.Value, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	return StringToJSON(string(val.(types.String)))
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltcore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		doc         string
+		expected    types.String
+		expectedErr bool
+	}{
+		{`{"b": 1, "a": [true, null, "x"]}`, `{"a":[true,null,"x"],"b":1}`, false},
+		{` 12345678901234567890 `, `12345678901234567890`, false},
+		{`"<tag>&"`, `"<tag>&"`, false},
+		{`{"a": 1.50}`, `{"a":1.50}`, false},
+		{`{"a": 1`, "", true},
+		{`{} {}`, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.doc, func(t *testing.T) {
+			doc, err := ParseJSON(test.doc)
+
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, doc)
+			}
+		})
+	}
+}
+
+func TestJSONToGo(t *testing.T) {
+	doc, err := ParseJSON(`{"name": "bart", "tags": ["a", "b"], "age": 10}`)
+	require.NoError(t, err)
+
+	v, err := JSONToGo(doc)
+	require.NoError(t, err)
+
+	roundTripped, err := JSONFromGo(v)
+	require.NoError(t, err)
+	assert.Equal(t, doc, roundTripped)
+}
+
+func TestConvStringToJSON(t *testing.T) {
+	val, err := ConvStringToJSON(types.String(`{"a": 1}`))
+	require.NoError(t, err)
+	assert.Equal(t, types.String(`{"a":1}`), val)
+
+	_, err = ConvStringToJSON(types.String(`{"a": `))
+	assert.Error(t, err)
+}
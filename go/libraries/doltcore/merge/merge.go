@@ -21,9 +21,11 @@ import (
 	"github.com/liquidata-inc/dolt/go/store/atomicerr"
 	"github.com/liquidata-inc/dolt/go/store/hash"
 
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/diff"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/alterschema"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/valutil"
@@ -32,6 +34,9 @@ import (
 
 var ErrFastForward = errors.New("fast forward")
 var ErrSameTblAddedTwice = errors.New("table with same name added in 2 commits can't be merged")
+var ErrForeignKeyConflict = errors.New("foreign key with same name modified differently in 2 commits can't be merged")
+var ErrPrimaryKeyConflict = errors.New("primary key of table changed differently in 2 commits can't be merged")
+var ErrPrimaryKeyColumnMissing = errors.New("primary key of table changed to columns missing from the other commit or the common ancestor can't be merged")
 
 type Merger struct {
 	commit      *doltdb.Commit
@@ -56,6 +61,96 @@ func NewMerger(ctx context.Context, commit, mergeCommit *doltdb.Commit, vrw type
 	return &Merger{commit, mergeCommit, ancestor, vrw}, nil
 }
 
+// mergeAutoIncrementValues sets the next AUTO_INCREMENT value of the merged table given to the larger of the next values
+// of the two tables merged, so that neither side's values are handed out again.
+func mergeAutoIncrementValues(ctx context.Context, mergedTbl, tbl, mergeTbl *doltdb.Table) (*doltdb.Table, error) {
+	next, err := tbl.GetAutoIncrementValue(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mergeNext, err := mergeTbl.GetAutoIncrementValue(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if mergeNext > next {
+		next = mergeNext
+	}
+
+	return mergedTbl.SetAutoIncrementValue(next)
+}
+
+// alignPrimaryKeys rekeys the tables being merged so that they all share the same primary key. When only one side of
+// the merge changed the primary key of the table, the other side and the ancestor are rebuilt with the new key so that
+// their rows can be compared. Returns ErrPrimaryKeyConflict if both sides changed the key differently, and
+// ErrPrimaryKeyColumnMissing if the other side or the ancestor doesn't have a column of the new key, such as one added
+// along with the key change, as their rows can't be given the new key.
+func (merger *Merger) alignPrimaryKeys(ctx context.Context, tbl, mergeTbl, ancTbl *doltdb.Table) (*doltdb.Table, *doltdb.Table, *doltdb.Table, error) {
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	mergeSch, err := mergeTbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ancSch, err := ancTbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pks, mergePKs, changed := diff.DiffPrimaryKeys(sch, mergeSch)
+
+	if !changed {
+		return tbl, mergeTbl, ancTbl, nil
+	}
+
+	_, _, ourChange := diff.DiffPrimaryKeys(ancSch, sch)
+	_, _, theirChange := diff.DiffPrimaryKeys(ancSch, mergeSch)
+
+	if ourChange && theirChange {
+		return nil, nil, nil, ErrPrimaryKeyConflict
+	}
+
+	if ourChange {
+		mergeTbl, err = alterschema.RekeyTable(ctx, merger.vrw, mergeTbl, pks)
+
+		if err == schema.ErrColNotFound {
+			return nil, nil, nil, ErrPrimaryKeyColumnMissing
+		} else if err != nil {
+			return nil, nil, nil, err
+		}
+
+		ancTbl, err = alterschema.RekeyTable(ctx, merger.vrw, ancTbl, pks)
+	} else {
+		tbl, err = alterschema.RekeyTable(ctx, merger.vrw, tbl, mergePKs)
+
+		if err == schema.ErrColNotFound {
+			return nil, nil, nil, ErrPrimaryKeyColumnMissing
+		} else if err != nil {
+			return nil, nil, nil, err
+		}
+
+		ancTbl, err = alterschema.RekeyTable(ctx, merger.vrw, ancTbl, mergePKs)
+	}
+
+	if err == schema.ErrColNotFound {
+		return nil, nil, nil, ErrPrimaryKeyColumnMissing
+	} else if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return tbl, mergeTbl, ancTbl, nil
+}
+
 func (merger *Merger) MergeTable(ctx context.Context, tblName string) (*doltdb.Table, *MergeStats, error) {
 	root, err := merger.commit.GetRootValue()
 
@@ -140,6 +235,12 @@ func (merger *Merger) MergeTable(ctx context.Context, tblName string) (*doltdb.T
 		return tbl, &MergeStats{Operation: TableUnmodified}, nil
 	}
 
+	tbl, mergeTbl, ancTbl, err = merger.alignPrimaryKeys(ctx, tbl, mergeTbl, ancTbl)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
 	tblSchema, err := tbl.GetSchema(ctx)
 
 	if err != nil {
@@ -194,6 +295,14 @@ func (merger *Merger) MergeTable(ctx context.Context, tblName string) (*doltdb.T
 		return nil, nil, err
 	}
 
+	if _, ok := schema.AutoIncrementCol(schemaUnion); ok {
+		mergedTable, err = mergeAutoIncrementValues(ctx, mergedTable, tbl, mergeTbl)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if conflicts.Len() > 0 {
 
 		if err != nil {
@@ -233,6 +342,90 @@ func (merger *Merger) MergeTable(ctx context.Context, tblName string) (*doltdb.T
 	return mergedTable, stats, nil
 }
 
+// MergeForeignKeys merges the foreign keys of the two commits being merged, using the ancestor commit to determine
+// which side added, removed or changed each foreign key. Foreign keys with the same name that were changed in different
+// ways on each side result in ErrForeignKeyConflict.
+func (merger *Merger) MergeForeignKeys(ctx context.Context) ([]doltdb.ForeignKey, error) {
+	fks, err := foreignKeysByName(ctx, merger.commit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mergeFks, err := foreignKeysByName(ctx, merger.mergeCommit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ancFks, err := foreignKeysByName(ctx, merger.ancestor)
+
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for name := range fks {
+		names[name] = true
+	}
+	for name := range mergeFks {
+		names[name] = true
+	}
+
+	var merged []doltdb.ForeignKey
+	for name := range names {
+		fk, ok := fks[name]
+		mergeFk, mergeOk := mergeFks[name]
+		ancFk, ancOk := ancFks[name]
+
+		switch {
+		case ok && mergeOk:
+			if fk.Equals(mergeFk) || (ancOk && ancFk.Equals(mergeFk)) {
+				merged = append(merged, fk)
+			} else if ancOk && ancFk.Equals(fk) {
+				merged = append(merged, mergeFk)
+			} else {
+				return nil, ErrForeignKeyConflict
+			}
+		case ok:
+			if !ancOk {
+				merged = append(merged, fk)
+			} else if !ancFk.Equals(fk) {
+				return nil, ErrForeignKeyConflict
+			}
+		case mergeOk:
+			if !ancOk {
+				merged = append(merged, mergeFk)
+			} else if !ancFk.Equals(mergeFk) {
+				return nil, ErrForeignKeyConflict
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+func foreignKeysByName(ctx context.Context, cm *doltdb.Commit) (map[string]doltdb.ForeignKey, error) {
+	root, err := cm.GetRootValue()
+
+	if err != nil {
+		return nil, err
+	}
+
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nameToFk := make(map[string]doltdb.ForeignKey, len(fks))
+	for _, fk := range fks {
+		nameToFk[fk.Name] = fk
+	}
+
+	return nameToFk, nil
+}
+
 func stopAndDrain(stop chan<- struct{}, drain <-chan types.ValueChanged) {
 	close(stop)
 	for range drain {
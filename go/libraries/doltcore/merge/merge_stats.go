@@ -29,4 +29,7 @@ type MergeStats struct {
 	Deletes       int
 	Modifications int
 	Conflicts     int
+
+	// ForeignKeyViolations is the number of rows of the table which violate a foreign key after the merge
+	ForeignKeyViolations int
 }
@@ -21,10 +21,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/alterschema"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
@@ -456,3 +459,398 @@ func TestMergeCommits(t *testing.T) {
 		}
 	}
 }
+
+// newTestTable returns a table with the schema and rows given.
+func newTestTable(t *testing.T, vrw types.ValueReadWriter, sch schema.Schema, rows ...row.Row) *doltdb.Table {
+	ctx := context.Background()
+	m, err := types.NewMap(ctx, vrw)
+	require.NoError(t, err)
+
+	me := m.Edit()
+	for _, r := range rows {
+		me.Set(r.NomsMapKey(sch), r.NomsMapValue(sch))
+	}
+
+	m, err = me.Map(ctx)
+	require.NoError(t, err)
+
+	schVal, err := encoding.MarshalAsNomsValue(ctx, vrw, sch)
+	require.NoError(t, err)
+
+	tbl, err := doltdb.NewTable(ctx, vrw, schVal, m)
+	require.NoError(t, err)
+
+	return tbl
+}
+
+// putTestRows returns the table given with the rows given added or updated. The rows are rebuilt with the table's
+// schema, so they can be given with a schema that has another primary key.
+func putTestRows(t *testing.T, tbl *doltdb.Table, rows ...row.Row) *doltdb.Table {
+	ctx := context.Background()
+	sch, err := tbl.GetSchema(ctx)
+	require.NoError(t, err)
+	rowData, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+
+	me := rowData.Edit()
+	for _, r := range rows {
+		taggedVals, err := row.GetTaggedVals(r)
+		require.NoError(t, err)
+		r, err = row.New(types.Format_7_18, sch, taggedVals)
+		require.NoError(t, err)
+
+		me.Set(r.NomsMapKey(sch), r.NomsMapValue(sch))
+	}
+
+	rowData, err = me.Map(ctx)
+	require.NoError(t, err)
+
+	tbl, err = tbl.UpdateRows(ctx, rowData)
+	require.NoError(t, err)
+
+	return tbl
+}
+
+// commitMergeTest commits the ancestor root given to master, then commits the root given on top of it and the merge
+// root given on a branch from it, returning the two commits to merge.
+func commitMergeTest(t *testing.T, ddb *doltdb.DoltDB, ancRoot, root, mergeRoot *doltdb.RootValue) (*doltdb.Commit, *doltdb.Commit) {
+	ctx := context.Background()
+	meta, err := doltdb.NewCommitMeta(name, email, "fake")
+	require.NoError(t, err)
+
+	ancHash, err := ddb.WriteRootValue(ctx, ancRoot)
+	require.NoError(t, err)
+	ancCommit, err := ddb.Commit(ctx, ancHash, ref.NewBranchRef("master"), meta)
+	require.NoError(t, err)
+
+	h, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	commit, err := ddb.Commit(ctx, h, ref.NewBranchRef("master"), meta)
+	require.NoError(t, err)
+
+	err = ddb.NewBranchAtCommit(ctx, ref.NewBranchRef("to-merge"), ancCommit)
+	require.NoError(t, err)
+	mergeHash, err := ddb.WriteRootValue(ctx, mergeRoot)
+	require.NoError(t, err)
+	mergeCommit, err := ddb.Commit(ctx, mergeHash, ref.NewBranchRef("to-merge"), meta)
+	require.NoError(t, err)
+
+	return commit, mergeCommit
+}
+
+// newMergeTestDB returns an empty in memory database and its initial root value.
+func newMergeTestDB(t *testing.T) (*doltdb.DoltDB, *doltdb.RootValue) {
+	ctx := context.Background()
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_7_18, doltdb.InMemDoltDB)
+	require.NoError(t, err)
+	err = ddb.WriteEmptyRepo(ctx, name, email)
+	require.NoError(t, err)
+
+	masterHeadSpec, err := doltdb.NewCommitSpec("head", "master")
+	require.NoError(t, err)
+	masterHead, err := ddb.Resolve(ctx, masterHeadSpec)
+	require.NoError(t, err)
+	root, err := masterHead.GetRootValue()
+	require.NoError(t, err)
+
+	return ddb, root
+}
+
+func newPersonRow(t *testing.T, sch schema.Schema, i int, title string) row.Row {
+	taggedVals := row.TaggedValues{
+		idTag:   uuids[i],
+		nameTag: types.String("person " + strconv.Itoa(i)),
+	}
+
+	if title != "" {
+		taggedVals[titleTag] = types.String(title)
+	}
+
+	r, err := row.New(types.Format_7_18, sch, taggedVals)
+	require.NoError(t, err)
+
+	return r
+}
+
+func TestMergePrimaryKeyChanges(t *testing.T) {
+	const codeTag = 2
+
+	changeKey := func(colNames ...string) func(t *testing.T, ddb *doltdb.DoltDB, tbl *doltdb.Table) *doltdb.Table {
+		return func(t *testing.T, ddb *doltdb.DoltDB, tbl *doltdb.Table) *doltdb.Table {
+			tbl, err := alterschema.ChangePrimaryKey(context.Background(), ddb, tbl, colNames...)
+			require.NoError(t, err)
+			return tbl
+		}
+	}
+
+	updateTitle := func(t *testing.T, ddb *doltdb.DoltDB, tbl *doltdb.Table) *doltdb.Table {
+		return putTestRows(t, tbl, newPersonRow(t, sch, 1, "dr"), newPersonRow(t, sch, 3, "new"))
+	}
+
+	changeKeyAndUpdateTitle := func(t *testing.T, ddb *doltdb.DoltDB, tbl *doltdb.Table) *doltdb.Table {
+		return updateTitle(t, ddb, changeKey("name")(t, ddb, tbl))
+	}
+
+	// adds a code column and makes it the primary key, so the other side has no values for the new key
+	keyOnNewColumn := func(t *testing.T, ddb *doltdb.DoltDB, tbl *doltdb.Table) *doltdb.Table {
+		colColl, err := schema.NewColCollection(
+			schema.NewColumn("id", idTag, types.UUIDKind, false, schema.NotNullConstraint{}),
+			schema.NewColumn("name", nameTag, types.StringKind, false, schema.NotNullConstraint{}),
+			schema.NewColumn("title", titleTag, types.StringKind, false),
+			schema.NewColumn("code", codeTag, types.IntKind, true, schema.NotNullConstraint{}))
+		require.NoError(t, err)
+		codeSch := schema.SchemaFromCols(colColl)
+
+		var rows []row.Row
+		for i := 0; i < 3; i++ {
+			r, err := row.New(types.Format_7_18, codeSch, row.TaggedValues{
+				idTag:   uuids[i],
+				nameTag: types.String("person " + strconv.Itoa(i)),
+				codeTag: types.Int(i),
+			})
+			require.NoError(t, err)
+			rows = append(rows, r)
+		}
+
+		return newTestTable(t, ddb.ValueReadWriter(), codeSch, rows...)
+	}
+
+	tests := []struct {
+		name        string
+		ours        func(t *testing.T, ddb *doltdb.DoltDB, tbl *doltdb.Table) *doltdb.Table
+		theirs      func(t *testing.T, ddb *doltdb.DoltDB, tbl *doltdb.Table) *doltdb.Table
+		expectedPKs []uint64
+		expectedErr error
+	}{
+		{
+			name:        "ours rekeys",
+			ours:        changeKey("name"),
+			theirs:      updateTitle,
+			expectedPKs: []uint64{nameTag},
+		},
+		{
+			name:        "theirs rekeys",
+			ours:        updateTitle,
+			theirs:      changeKey("name", "id"),
+			expectedPKs: []uint64{nameTag, idTag},
+		},
+		{
+			name:        "both rekey the same way",
+			ours:        changeKey("name"),
+			theirs:      changeKeyAndUpdateTitle,
+			expectedPKs: []uint64{nameTag},
+		},
+		{
+			name:        "both rekey differently",
+			ours:        changeKey("name"),
+			theirs:      changeKey("name", "id"),
+			expectedErr: ErrPrimaryKeyConflict,
+		},
+		{
+			name:        "new key column missing from theirs",
+			ours:        keyOnNewColumn,
+			theirs:      updateTitle,
+			expectedErr: ErrPrimaryKeyColumnMissing,
+		},
+		{
+			name:        "new key column missing from ours",
+			ours:        updateTitle,
+			theirs:      keyOnNewColumn,
+			expectedErr: ErrPrimaryKeyColumnMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ddb, root := newMergeTestDB(t)
+
+			ancTbl := newTestTable(t, ddb.ValueReadWriter(), sch,
+				newPersonRow(t, sch, 0, "dufus"), newPersonRow(t, sch, 1, ""), newPersonRow(t, sch, 2, "madam"))
+			ancRoot, err := root.PutTable(ctx, ddb, tableName, ancTbl)
+			require.NoError(t, err)
+
+			ourRoot, err := ancRoot.PutTable(ctx, ddb, tableName, tt.ours(t, ddb, ancTbl))
+			require.NoError(t, err)
+			theirRoot, err := ancRoot.PutTable(ctx, ddb, tableName, tt.theirs(t, ddb, ancTbl))
+			require.NoError(t, err)
+
+			commit, mergeCommit := commitMergeTest(t, ddb, ancRoot, ourRoot, theirRoot)
+			merger, err := NewMerger(ctx, commit, mergeCommit, ddb.ValueReadWriter())
+			require.NoError(t, err)
+
+			merged, stats, err := merger.MergeTable(ctx, tableName)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, 0, stats.Conflicts)
+
+			mergedSch, err := merged.GetSchema(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPKs, mergedSch.GetPKCols().Tags)
+
+			// every row of the ancestor is kept, with the titles updated on one side and the row added
+			expectedTitles := map[int]string{0: "dufus", 1: "dr", 2: "madam", 3: "new"}
+			rowData, err := merged.GetRowData(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(len(expectedTitles)), rowData.Len())
+
+			for i, title := range expectedTitles {
+				key, err := newPersonRow(t, mergedSch, i, title).NomsMapKey(mergedSch).Value(ctx)
+				require.NoError(t, err)
+				r, ok, err := merged.GetRow(ctx, key.(types.Tuple), mergedSch)
+				require.NoError(t, err)
+				require.True(t, ok, "row %d not found", i)
+
+				val, _ := r.GetColVal(titleTag)
+				assert.Equal(t, types.String(title), val)
+			}
+		})
+	}
+}
+
+func TestMergeAutoIncrementValues(t *testing.T) {
+	const autoIdTag, autoNameTag = 0, 1
+	ctx := context.Background()
+	ddb, root := newMergeTestDB(t)
+
+	idCol := schema.NewColumn("id", autoIdTag, types.IntKind, true, schema.NotNullConstraint{})
+	idCol.AutoIncrement = true
+	colColl, err := schema.NewColCollection(idCol, schema.NewColumn("name", autoNameTag, types.StringKind, false))
+	require.NoError(t, err)
+	autoSch := schema.SchemaFromCols(colColl)
+
+	newRow := func(id int, name string) row.Row {
+		r, err := row.New(types.Format_7_18, autoSch, row.TaggedValues{autoIdTag: types.Int(id), autoNameTag: types.String(name)})
+		require.NoError(t, err)
+		return r
+	}
+
+	withNext := func(tbl *doltdb.Table, next uint64) *doltdb.Table {
+		tbl, err := tbl.SetAutoIncrementValue(next)
+		require.NoError(t, err)
+		return tbl
+	}
+
+	ancTbl := withNext(newTestTable(t, ddb.ValueReadWriter(), autoSch, newRow(1, "a"), newRow(2, "b")), 3)
+
+	// both sides insert a row with id 3, and theirs also inserted rows 4 and 5 and deleted row 5
+	ourTbl := withNext(putTestRows(t, ancTbl, newRow(3, "c")), 4)
+	theirTbl := withNext(putTestRows(t, ancTbl, newRow(3, "x"), newRow(4, "d")), 6)
+
+	ancRoot, err := root.PutTable(ctx, ddb, tableName, ancTbl)
+	require.NoError(t, err)
+	ourRoot, err := ancRoot.PutTable(ctx, ddb, tableName, ourTbl)
+	require.NoError(t, err)
+	theirRoot, err := ancRoot.PutTable(ctx, ddb, tableName, theirTbl)
+	require.NoError(t, err)
+
+	commit, mergeCommit := commitMergeTest(t, ddb, ancRoot, ourRoot, theirRoot)
+	merger, err := NewMerger(ctx, commit, mergeCommit, ddb.ValueReadWriter())
+	require.NoError(t, err)
+
+	merged, stats, err := merger.MergeTable(ctx, tableName)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Conflicts)
+
+	next, err := merged.GetAutoIncrementValue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), next)
+
+	resolved, err := ResolveTable(ctx, ddb.ValueReadWriter(), merged, Ours)
+	require.NoError(t, err)
+
+	next, err = resolved.GetAutoIncrementValue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), next)
+}
+
+func TestMergeForeignKeys(t *testing.T) {
+	ctx := context.Background()
+	unchanged := doltdb.ForeignKey{Name: "unchanged", TableName: "child", TableColumns: []uint64{1}, ReferencedTableName: "parent", ReferencedTableColumns: []uint64{0}}
+	removed := doltdb.ForeignKey{Name: "removed", TableName: "child", TableColumns: []uint64{2}, ReferencedTableName: "parent", ReferencedTableColumns: []uint64{0}}
+	changed := doltdb.ForeignKey{Name: "changed", TableName: "child", TableColumns: []uint64{3}, ReferencedTableName: "parent", ReferencedTableColumns: []uint64{0}}
+	changedTo := doltdb.ForeignKey{Name: "changed", TableName: "child", TableColumns: []uint64{4}, ReferencedTableName: "parent", ReferencedTableColumns: []uint64{0}}
+	added := doltdb.ForeignKey{Name: "added", TableName: "child", TableColumns: []uint64{5}, ReferencedTableName: "parent", ReferencedTableColumns: []uint64{0}}
+	mergeAdded := doltdb.ForeignKey{Name: "merge_added", TableName: "child", TableColumns: []uint64{6}, ReferencedTableName: "parent", ReferencedTableColumns: []uint64{0}}
+	addedDifferently := doltdb.ForeignKey{Name: "added", TableName: "child", TableColumns: []uint64{7}, ReferencedTableName: "parent", ReferencedTableColumns: []uint64{0}}
+
+	tests := []struct {
+		name     string
+		anc      []doltdb.ForeignKey
+		fks      []doltdb.ForeignKey
+		mergeFks []doltdb.ForeignKey
+		expected []doltdb.ForeignKey
+		err      error
+	}{
+		{
+			name:     "added, removed and changed on either side",
+			anc:      []doltdb.ForeignKey{unchanged, removed, changed},
+			fks:      []doltdb.ForeignKey{unchanged, changed, added},
+			mergeFks: []doltdb.ForeignKey{unchanged, removed, changedTo, mergeAdded},
+			expected: []doltdb.ForeignKey{added, changedTo, mergeAdded, unchanged},
+		},
+		{
+			name:     "added the same on both sides",
+			anc:      []doltdb.ForeignKey{unchanged},
+			fks:      []doltdb.ForeignKey{added, unchanged},
+			mergeFks: []doltdb.ForeignKey{added, mergeAdded, unchanged},
+			expected: []doltdb.ForeignKey{added, mergeAdded, unchanged},
+		},
+		{
+			name:     "added differently on both sides",
+			anc:      []doltdb.ForeignKey{unchanged},
+			fks:      []doltdb.ForeignKey{added},
+			mergeFks: []doltdb.ForeignKey{addedDifferently},
+			err:      ErrForeignKeyConflict,
+		},
+		{
+			name:     "changed differently on both sides",
+			anc:      []doltdb.ForeignKey{changed},
+			fks:      []doltdb.ForeignKey{changedTo},
+			mergeFks: []doltdb.ForeignKey{{Name: "changed", TableName: "child", TableColumns: []uint64{7}, ReferencedTableName: "parent", ReferencedTableColumns: []uint64{0}}},
+			err:      ErrForeignKeyConflict,
+		},
+		{
+			name:     "removed on one side and changed on the other",
+			anc:      []doltdb.ForeignKey{changed},
+			mergeFks: []doltdb.ForeignKey{changedTo},
+			err:      ErrForeignKeyConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ddb, root := newMergeTestDB(t)
+
+			ancRoot, err := root.PutForeignKeys(ctx, test.anc)
+			require.NoError(t, err)
+			fkRoot, err := root.PutForeignKeys(ctx, test.fks)
+			require.NoError(t, err)
+			mergeRoot, err := root.PutForeignKeys(ctx, test.mergeFks)
+			require.NoError(t, err)
+
+			commit, mergeCommit := commitMergeTest(t, ddb, ancRoot, fkRoot, mergeRoot)
+			merger, err := NewMerger(ctx, commit, mergeCommit, ddb.ValueReadWriter())
+			require.NoError(t, err)
+
+			merged, err := merger.MergeForeignKeys(ctx)
+
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			mergedRoot, err := root.PutForeignKeys(ctx, merged)
+			require.NoError(t, err)
+			fks, err := mergedRoot.GetForeignKeys(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, fks)
+		})
+	}
+}
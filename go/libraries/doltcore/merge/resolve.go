@@ -122,6 +122,12 @@ func ResolveTable(ctx context.Context, vrw types.ValueReadWriter, tbl *doltdb.Ta
 		return nil, err
 	}
 
+	newTbl, err = newTbl.CopyAutoIncrementValue(ctx, tbl)
+
+	if err != nil {
+		return nil, err
+	}
+
 	m, err = types.NewMap(ctx, vrw)
 
 	if err != nil {
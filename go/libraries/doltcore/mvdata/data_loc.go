@@ -15,7 +15,11 @@
 package mvdata
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -27,6 +31,7 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/json"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/noms"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/parquet"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/csv"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/sqlexport"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/xlsx"
@@ -44,6 +49,8 @@ const (
 	XlsxFile          DataFormat = ".xlsx"
 	JsonFile          DataFormat = ".json"
 	SqlFile           DataFormat = ".sql"
+	ParquetFile       DataFormat = ".parquet"
+	NdjsonFile        DataFormat = ".ndjson"
 )
 
 func (df DataFormat) ReadableStr() string {
@@ -60,11 +67,25 @@ func (df DataFormat) ReadableStr() string {
 		return "json file"
 	case SqlFile:
 		return "sql file"
+	case ParquetFile:
+		return "parquet file"
+	case NdjsonFile:
+		return "ndjson file"
 	default:
 		return "invalid"
 	}
 }
 
+// IsUntyped returns whether the format has no column types, so that every value read from it is a string.
+func (df DataFormat) IsUntyped() bool {
+	switch df {
+	case CsvFile, PsvFile, XlsxFile, NdjsonFile:
+		return true
+	default:
+		return false
+	}
+}
+
 func DFFromString(dfStr string) DataFormat {
 	switch strings.ToLower(dfStr) {
 	case "csv", ".csv":
@@ -77,14 +98,69 @@ func DFFromString(dfStr string) DataFormat {
 		return JsonFile
 	case "sql", ".sql":
 		return SqlFile
+	case "parquet", ".parquet":
+		return ParquetFile
+	case "ndjson", ".ndjson", "jsonl", ".jsonl":
+		return NdjsonFile
 	default:
 		return InvalidDataFormat
 	}
 }
 
+// Paths of the DataLocations for stdin and stdout, used in messages
+const (
+	StdInPath  = "<stdin>"
+	StdOutPath = "<stdout>"
+)
+
 type DataLocation struct {
 	Path   string
 	Format DataFormat
+
+	// InStream and OutStream are read from and written to instead of the file at Path when they are set, so that data
+	// can be piped through stdin and stdout.
+	InStream  io.ReadCloser
+	OutStream io.WriteCloser
+
+	// CSVInfo describes the dialect of a csv or psv file. The standard dialect of the format is used when it's nil.
+	CSVInfo *csv.CSVFileInfo
+
+	// JSONPaths are the dotted paths to nested values, like address.city, that are read as columns of an ndjson file.
+	JSONPaths []string
+
+	// XLSXInfo names the sheet of an xlsx file that is read or written, and the workbook shared by the tables written
+	// to the file. The sheet named after the table is read or written when it's nil.
+	XLSXInfo *xlsx.XLSXFileInfo
+}
+
+// csvInfo returns the CSVFileInfo used to read and write a csv or psv file
+func (dl *DataLocation) csvInfo() *csv.CSVFileInfo {
+	if dl.CSVInfo != nil {
+		return dl.CSVInfo
+	} else if dl.Format == PsvFile {
+		return csv.NewCSVInfo().SetDelim("|")
+	}
+
+	return csv.NewCSVInfo()
+}
+
+// xlsxInfo returns the XLSXFileInfo used to read or write the sheet of an xlsx file for a table. Readers keep their rows
+// in the info, so each one gets its own copy.
+func (dl *DataLocation) xlsxInfo(tblName string) *xlsx.XLSXFileInfo {
+	info := xlsx.NewXLSXInfo()
+	if dl.XLSXInfo != nil {
+		*info = *dl.XLSXInfo
+	}
+
+	if info.SheetName == "" {
+		info.SetSheetName(tblName)
+	}
+
+	return info
+}
+
+func (dl *DataLocation) ndjsonInfo() *json.NDJSONFileInfo {
+	return json.NewNDJSONInfo().SetPaths(dl.JSONPaths)
 }
 
 func (dl *DataLocation) String() string {
@@ -109,13 +185,32 @@ func NewDataLocation(path, fileFmtStr string) *DataLocation {
 				dataFmt = JsonFile
 			case string(SqlFile):
 				dataFmt = SqlFile
+			case string(ParquetFile):
+				dataFmt = ParquetFile
+			case string(NdjsonFile), ".jsonl":
+				dataFmt = NdjsonFile
 			}
 		}
 	} else {
 		dataFmt = DFFromString(fileFmtStr)
 	}
 
-	return &DataLocation{path, dataFmt}
+	return &DataLocation{Path: path, Format: dataFmt}
+}
+
+// NewInStreamDataLocation returns a DataLocation that reads data of the format given from stdin.
+func NewInStreamDataLocation(rd io.ReadCloser, fileFmtStr string) *DataLocation {
+	return &DataLocation{Path: StdInPath, Format: DFFromString(fileFmtStr), InStream: rd}
+}
+
+// NewOutStreamDataLocation returns a DataLocation that writes data of the format given to stdout.
+func NewOutStreamDataLocation(wr io.WriteCloser, fileFmtStr string) *DataLocation {
+	return &DataLocation{Path: StdOutPath, Format: DFFromString(fileFmtStr), OutStream: wr}
+}
+
+// IsStream returns whether this DataLocation is read from or written to a stream rather than a file or table
+func (dl *DataLocation) IsStream() bool {
+	return dl.InStream != nil || dl.OutStream != nil
 }
 
 func (dl *DataLocation) IsFileType() bool {
@@ -160,6 +255,9 @@ func (dl *DataLocation) CreateReader(ctx context.Context, root *doltdb.RootValue
 		}
 
 		return rd, true, nil
+	} else if dl.InStream != nil {
+		rd, err := dl.createStreamReader(root.VRW().Format(), fs, schPath)
+		return rd, false, err
 	} else {
 		exists, isDir := fs.Exists(dl.Path)
 
@@ -170,28 +268,71 @@ func (dl *DataLocation) CreateReader(ctx context.Context, root *doltdb.RootValue
 		}
 
 		switch dl.Format {
-		case CsvFile:
-			rd, err := csv.OpenCSVReader(root.VRW().Format(), dl.Path, fs, csv.NewCSVInfo())
-			return rd, false, err
-
-		case PsvFile:
-			rd, err := csv.OpenCSVReader(root.VRW().Format(), dl.Path, fs, csv.NewCSVInfo().SetDelim("|"))
+		case CsvFile, PsvFile:
+			rd, err := csv.OpenCSVReader(root.VRW().Format(), dl.Path, fs, dl.csvInfo())
 			return rd, false, err
 
 		case XlsxFile:
-			rd, err := xlsx.OpenXLSXReader(root.VRW().Format(), dl.Path, fs, xlsx.NewXLSXInfo(), tblName)
+			rd, err := xlsx.OpenXLSXReader(root.VRW().Format(), dl.Path, fs, dl.xlsxInfo(tblName), tblName)
 			return rd, false, err
 
 		case JsonFile:
 			rd, err := json.OpenJSONReader(root.VRW().Format(), dl.Path, fs, json.NewJSONInfo(), schPath)
 			return rd, false, err
+
+		case ParquetFile:
+			rd, err := parquet.OpenParquetReader(root.VRW().Format(), dl.Path, fs, parquet.NewParquetInfo())
+			return rd, false, err
+
+		case NdjsonFile:
+			rd, err := json.OpenNDJSONReader(root.VRW().Format(), dl.Path, fs, dl.ndjsonInfo())
+			return rd, false, err
 		}
 	}
 
 	panic("Unsupported table format should have failed before reaching here. ")
 }
 
+func (dl *DataLocation) createStreamReader(nbf *types.NomsBinFormat, fs filesys.ReadableFS, schPath string) (table.TableReadCloser, error) {
+	switch dl.Format {
+	case CsvFile, PsvFile:
+		return csv.NewCSVReader(nbf, dl.InStream, dl.csvInfo())
+	case JsonFile:
+		return json.NewJSONReader(nbf, dl.InStream, json.NewJSONInfo(), fs, schPath)
+	case ParquetFile:
+		return parquet.NewParquetReader(nbf, dl.InStream, parquet.NewParquetInfo())
+	case NdjsonFile:
+		return json.NewNDJSONReader(nbf, dl.InStream, dl.ndjsonInfo())
+	}
+
+	return nil, fmt.Errorf("%ss can't be read from %s", dl.Format.ReadableStr(), dl.Path)
+}
+
+// teeStream returns a copy of a DataLocation that reads from a stream, which records the data read by its readers. The
+// returned function rewinds the stream of the original DataLocation, so that the next reader created for it reads the
+// recorded data again before the rest of the stream.
+func (dl *DataLocation) teeStream() (*DataLocation, func()) {
+	var recorded bytes.Buffer
+	in := dl.InStream
+
+	tee := *dl
+	tee.InStream = ioutil.NopCloser(io.TeeReader(in, &recorded))
+
+	return &tee, func() {
+		dl.InStream = readCloser{io.MultiReader(&recorded, in), in}
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
 func (dl *DataLocation) Exists(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS) (bool, error) {
+	if dl.IsStream() {
+		return false, nil
+	}
+
 	if dl.IsFileType() {
 		exists, _ := fs.Exists(dl.Path)
 		return exists, nil
@@ -211,6 +352,10 @@ func (dl *DataLocation) CreateOverwritingDataWriter(ctx context.Context, mvOpts
 		return nil, ErrNoPK
 	}
 
+	if dl.OutStream != nil {
+		return dl.createStreamWriter(mvOpts, outSch)
+	}
+
 	switch dl.Format {
 	case DoltDB:
 		if sortedInput {
@@ -225,14 +370,16 @@ func (dl *DataLocation) CreateOverwritingDataWriter(ctx context.Context, mvOpts
 			return noms.NewNomsMapUpdater(ctx, root.VRW(), m, outSch, statsCB), nil
 		}
 
-	case CsvFile:
-		return csv.OpenCSVWriter(dl.Path, fs, outSch, csv.NewCSVInfo())
-	case PsvFile:
-		return csv.OpenCSVWriter(dl.Path, fs, outSch, csv.NewCSVInfo().SetDelim("|"))
+	case CsvFile, PsvFile:
+		return csv.OpenCSVWriter(dl.Path, fs, outSch, dl.csvInfo())
 	case XlsxFile:
-		return xlsx.OpenXLSXWriter(dl.Path, fs, outSch, xlsx.NewXLSXInfo())
+		return xlsx.OpenXLSXWriter(dl.Path, fs, outSch, dl.xlsxInfo(mvOpts.TableName))
 	case JsonFile:
 		return json.OpenJSONWriter(dl.Path, fs, outSch, json.NewJSONInfo())
+	case ParquetFile:
+		return parquet.OpenParquetWriter(dl.Path, fs, outSch, parquet.NewParquetInfo())
+	case NdjsonFile:
+		return json.OpenNDJSONWriter(dl.Path, fs, outSch)
 	case SqlFile:
 		return sqlexport.OpenSQLExportWriter(dl.Path, mvOpts.TableName, fs, outSch)
 	}
@@ -240,6 +387,25 @@ func (dl *DataLocation) CreateOverwritingDataWriter(ctx context.Context, mvOpts
 	panic("Invalid Data Format." + string(dl.Format))
 }
 
+func (dl *DataLocation) createStreamWriter(mvOpts *MoveOptions, outSch schema.Schema) (table.TableWriteCloser, error) {
+	switch dl.Format {
+	case CsvFile, PsvFile:
+		return csv.NewCSVWriter(dl.OutStream, outSch, dl.csvInfo())
+	case XlsxFile:
+		return xlsx.NewXLSXWriter(dl.OutStream, outSch, dl.xlsxInfo(mvOpts.TableName))
+	case JsonFile:
+		return json.NewJSONWriter(dl.OutStream, outSch, json.NewJSONInfo())
+	case ParquetFile:
+		return parquet.NewParquetWriter(dl.OutStream, outSch, parquet.NewParquetInfo())
+	case NdjsonFile:
+		return json.NewNDJSONWriter(dl.OutStream, outSch), nil
+	case SqlFile:
+		return sqlexport.NewSQLExportWriter(dl.OutStream, mvOpts.TableName, outSch), nil
+	}
+
+	return nil, fmt.Errorf("%ss can't be written to %s", dl.Format.ReadableStr(), dl.Path)
+}
+
 // CreateUpdatingDataWriter will create a TableWriteCloser for a DataLocation that will update and append rows based
 // on their primary key.
 func (dl *DataLocation) CreateUpdatingDataWriter(ctx context.Context, mvOpts *MoveOptions, root *doltdb.RootValue, fs filesys.WritableFS, srcIsSorted bool, outSch schema.Schema, statsCB noms.StatsCB) (table.TableWriteCloser, error) {
@@ -264,7 +430,7 @@ func (dl *DataLocation) CreateUpdatingDataWriter(ctx context.Context, mvOpts *Mo
 
 		return noms.NewNomsMapUpdater(ctx, root.VRW(), m, outSch, statsCB), nil
 
-	case CsvFile, PsvFile, JsonFile, XlsxFile, SqlFile:
+	case CsvFile, PsvFile, JsonFile, XlsxFile, SqlFile, ParquetFile, NdjsonFile:
 		panic("Update not supported for this file type.")
 	}
 
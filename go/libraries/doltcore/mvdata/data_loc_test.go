@@ -15,11 +15,15 @@
 package mvdata
 
 import (
+	"bytes"
 	"context"
+	"io/ioutil"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
@@ -28,8 +32,10 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/json"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/noms"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/parquet"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/csv"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -96,6 +102,7 @@ func TestBasics(t *testing.T) {
 		{NewDataLocation("file.csv", ""), CsvFile, "file.csv", true, false, false},
 		{NewDataLocation("file.psv", ""), PsvFile, "file.psv", true, false, false},
 		{NewDataLocation("file.json", ""), JsonFile, "file.json", true, false, false},
+		{NewDataLocation("file.parquet", ""), ParquetFile, "file.parquet", true, false, false},
 		//{NewDataLocation("file.nbf", ""), NbfFile, "file.nbf", true, true, true},
 	}
 
@@ -201,6 +208,7 @@ func TestCreateRdWr(t *testing.T) {
 		{NewDataLocation("file.psv", ""), reflect.TypeOf((*csv.CSVReader)(nil)).Elem(), reflect.TypeOf((*csv.CSVWriter)(nil)).Elem()},
 		// TODO (oo): uncomment and fix this for json path test
 		{NewDataLocation("file.json", ""), reflect.TypeOf((*json.JSONReader)(nil)).Elem(), reflect.TypeOf((*json.JSONWriter)(nil)).Elem()},
+		{NewDataLocation("file.parquet", ""), reflect.TypeOf((*parquet.ParquetReader)(nil)).Elem(), reflect.TypeOf((*parquet.ParquetWriter)(nil)).Elem()},
 		//{NewDataLocation("file.nbf", ""), reflect.TypeOf((*nbf.NBFReader)(nil)).Elem(), reflect.TypeOf((*nbf.NBFWriter)(nil)).Elem()},
 	}
 
@@ -264,3 +272,33 @@ func TestCreateRdWr(t *testing.T) {
 		rd.Close(context.Background())
 	}
 }
+
+func TestStreams(t *testing.T) {
+	const csvData = "id,name\n1,bill\n2,jane\n3,rick\n"
+
+	_, root, fs := createRootAndFS()
+
+	var out bytes.Buffer
+	mvOpts := &MoveOptions{
+		Operation: OverwriteOp,
+		TableName: "people",
+		Src:       NewInStreamDataLocation(ioutil.NopCloser(strings.NewReader(csvData)), "csv"),
+		Dest:      NewOutStreamDataLocation(iohelp.NopWrCloser(&out), "psv"),
+	}
+
+	exists, err := mvOpts.Dest.Exists(context.Background(), root, fs)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	mover, dmce := NewDataMover(context.Background(), root, fs, mvOpts, nil)
+	require.Nil(t, dmce)
+
+	// the rows read to infer the schema are read again when they are moved
+	err = mover.Move(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(csvData, ",", "|", -1), out.String())
+
+	unreadable := NewInStreamDataLocation(ioutil.NopCloser(strings.NewReader("")), "xlsx")
+	_, _, err = unreadable.CreateReader(context.Background(), root, fs, "", "people")
+	assert.EqualError(t, err, "xlsx files can't be read from <stdin>")
+}
@@ -16,7 +16,9 @@ package mvdata
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"sort"
 	"strings"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/noms"
@@ -37,9 +39,15 @@ type MoveOperation string
 const (
 	OverwriteOp MoveOperation = "overwrite"
 	UpdateOp    MoveOperation = "update"
+	ReplaceOp   MoveOperation = "replace"
 	InvalidOp   MoveOperation = "invalid"
 )
 
+// keepsSchema returns whether the operation moves data into an existing table, keeping that table's schema.
+func (op MoveOperation) keepsSchema() bool {
+	return op == UpdateOp || op == ReplaceOp
+}
+
 type MoveOptions struct {
 	Operation   MoveOperation
 	ContOnErr   bool
@@ -49,6 +57,11 @@ type MoveOptions struct {
 	PrimaryKey  string
 	Src         *DataLocation
 	Dest        *DataLocation
+
+	// RejectsFile is the file the rows that fail to be moved are written to, in the format of the source, and
+	// ErrorReportFile is the file a line of json describing each failure is written to.
+	RejectsFile     string
+	ErrorReportFile string
 }
 
 type DataMover struct {
@@ -56,6 +69,13 @@ type DataMover struct {
 	Transforms *pipeline.TransformCollection
 	Wr         table.TableWriteCloser
 	ContOnErr  bool
+
+	// Rejects keeps the rows that fail to be moved. It's nil when they aren't kept.
+	Rejects *RejectsWriter
+
+	// AutoIncrement assigns the values of the AUTO_INCREMENT column of the rows moved, and holds the next value to be
+	// stored with the table written. It's nil when the rows have no AUTO_INCREMENT column.
+	AutoIncrement *doltdb.AutoIncrementer
 }
 
 type DataMoverCreationErrType string
@@ -68,6 +88,7 @@ const (
 	CreateMapperErr   DataMoverCreationErrType = "Mapper creation error"
 	CreateWriterErr   DataMoverCreationErrType = "Create writer error"
 	CreateSorterErr   DataMoverCreationErrType = "Create sorter error"
+	CreateRejectsErr  DataMoverCreationErrType = "Create rejects file error"
 )
 
 type DataMoverCreationError struct {
@@ -84,10 +105,10 @@ func NewDataMover(ctx context.Context, root *doltdb.RootValue, fs filesys.Filesy
 	var err error
 	transforms := pipeline.NewTransformCollection()
 
-	rd, srcIsSorted, err := mvOpts.Src.CreateReader(ctx, root, fs, mvOpts.SchFile, mvOpts.Dest.Path)
+	rd, srcIsSorted, outSch, dmce := createReaderAndOutSchema(ctx, root, fs, mvOpts)
 
-	if err != nil {
-		return nil, &DataMoverCreationError{CreateReaderErr, err}
+	if dmce != nil {
+		return nil, dmce
 	}
 
 	defer func() {
@@ -96,15 +117,6 @@ func NewDataMover(ctx context.Context, root *doltdb.RootValue, fs filesys.Filesy
 		}
 	}()
 
-	outSch, err := getOutSchema(ctx, rd.GetSchema(), root, fs, mvOpts)
-
-	if err != nil {
-		if strings.Contains(err.Error(), "invalid noms kind") {
-			return nil, &DataMoverCreationError{NomsKindSchemaErr, err}
-		}
-		return nil, &DataMoverCreationError{SchemaErr, err}
-	}
-
 	var mapping *rowconv.FieldMapping
 	if mvOpts.MappingFile != "" {
 		mapping, err = rowconv.MappingFromFile(mvOpts.MappingFile, fs, rd.GetSchema(), outSch)
@@ -118,17 +130,24 @@ func NewDataMover(ctx context.Context, root *doltdb.RootValue, fs filesys.Filesy
 		return nil, &DataMoverCreationError{MappingErr, err}
 	}
 
-	err = maybeMapFields(transforms, mapping)
+	fillers, autoInc, err := getRowFillers(ctx, root, outSch, mvOpts)
+
+	if err != nil {
+		return nil, &DataMoverCreationError{SchemaErr, err}
+	}
+
+	err = maybeMapFields(transforms, mapping, fillers)
 
 	if err != nil {
 		return nil, &DataMoverCreationError{CreateMapperErr, err}
 	}
 
 	var wr table.TableWriteCloser
-	if mvOpts.Operation == OverwriteOp {
-		wr, err = mvOpts.Dest.CreateOverwritingDataWriter(ctx, mvOpts, root, fs, srcIsSorted, outSch, statsCB)
-	} else {
+	if mvOpts.Operation == UpdateOp {
 		wr, err = mvOpts.Dest.CreateUpdatingDataWriter(ctx, mvOpts, root, fs, srcIsSorted, outSch, statsCB)
+	} else {
+		// a replaced table is written from scratch, with the schema of the existing table
+		wr, err = mvOpts.Dest.CreateOverwritingDataWriter(ctx, mvOpts, root, fs, srcIsSorted, outSch, statsCB)
 	}
 
 	if err != nil {
@@ -141,18 +160,161 @@ func NewDataMover(ctx context.Context, root *doltdb.RootValue, fs filesys.Filesy
 		return nil, &DataMoverCreationError{CreateSorterErr, err}
 	}
 
-	imp := &DataMover{rd, transforms, wr, mvOpts.ContOnErr}
+	rejects, err := newRejectsWriter(ctx, root, fs, mvOpts, rd.GetSchema())
+
+	if err != nil {
+		wr.Close(ctx)
+		return nil, &DataMoverCreationError{CreateRejectsErr, err}
+	}
+
+	imp := &DataMover{rd, transforms, wr, mvOpts.ContOnErr, rejects, autoInc}
 	rd = nil
 
 	return imp, nil
 }
 
+// OutSchema returns the schema of the rows that a DataMover created with the options given would write, without moving
+// any data.
+func OutSchema(ctx context.Context, root *doltdb.RootValue, fs filesys.Filesys, mvOpts *MoveOptions) (schema.Schema, *DataMoverCreationError) {
+	rd, _, outSch, dmce := createReaderAndOutSchema(ctx, root, fs, mvOpts)
+
+	if dmce != nil {
+		return nil, dmce
+	}
+
+	rd.Close(ctx)
+
+	return outSch, nil
+}
+
+// createReaderAndOutSchema creates the reader for the source of a move, and gets the schema of the rows written to its
+// destination. The schema is inferred before the reader is created, as inferring it reads a sample of the source.
+func createReaderAndOutSchema(ctx context.Context, root *doltdb.RootValue, fs filesys.Filesys, mvOpts *MoveOptions) (table.TableReadCloser, bool, schema.Schema, *DataMoverCreationError) {
+	if mvOpts.Src.Format == NdjsonFile {
+		paths, err := nestedJSONPaths(ctx, root, fs, mvOpts)
+
+		if err != nil {
+			return nil, false, nil, &DataMoverCreationError{MappingErr, err}
+		}
+
+		mvOpts.Src.JSONPaths = paths
+	}
+
+	outSch, dmce := inferOutSchema(ctx, root, fs, mvOpts)
+
+	if dmce != nil {
+		return nil, false, nil, dmce
+	}
+
+	rd, srcIsSorted, err := mvOpts.Src.CreateReader(ctx, root, fs, mvOpts.SchFile, mvOpts.Dest.Path)
+
+	if err != nil {
+		return nil, false, nil, &DataMoverCreationError{CreateReaderErr, err}
+	}
+
+	if outSch == nil {
+		outSch, err = getOutSchema(ctx, rd.GetSchema(), root, fs, mvOpts)
+
+		if err != nil {
+			rd.Close(ctx)
+			return nil, false, nil, outSchemaErr(err)
+		}
+	}
+
+	return rd, srcIsSorted, outSch, nil
+}
+
+// nestedJSONPaths returns the dotted paths to nested values, like address.city, that are read as columns of an ndjson
+// file. These are the fields of a mapping file, and the columns of a schema file or of the table being updated, whose
+// names contain a dot.
+func nestedJSONPaths(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS, mvOpts *MoveOptions) ([]string, error) {
+	var names []string
+	if mvOpts.MappingFile != "" {
+		data, err := fs.ReadFile(mvOpts.MappingFile)
+
+		if err != nil {
+			return nil, rowconv.ErrMappingFileRead
+		}
+
+		var inNameToOutName map[string]string
+		err = json.Unmarshal(data, &inNameToOutName)
+
+		if err != nil {
+			return nil, rowconv.ErrUnmarshallingMapping
+		}
+
+		for inName := range inNameToOutName {
+			names = append(names, inName)
+		}
+	}
+
+	var sch schema.Schema
+	if mvOpts.SchFile != "" {
+		var err error
+		sch, err = schFromFileOrDefault(mvOpts.SchFile, fs, nil)
+
+		if err != nil {
+			return nil, err
+		}
+	} else if mvOpts.Operation.keepsSchema() {
+		tbl, ok, err := root.GetTable(ctx, mvOpts.Dest.Path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			sch, err = tbl.GetSchema(ctx)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if sch != nil {
+		err := sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+			names = append(names, col.Name)
+			return false, nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var paths []string
+	for _, name := range names {
+		if strings.Contains(name, ".") {
+			paths = append(paths, name)
+		}
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func outSchemaErr(err error) *DataMoverCreationError {
+	if strings.Contains(err.Error(), "invalid noms kind") {
+		return &DataMoverCreationError{NomsKindSchemaErr, err}
+	}
+
+	return &DataMoverCreationError{SchemaErr, err}
+}
+
 func (imp *DataMover) Move(ctx context.Context) error {
 	defer imp.Rd.Close(ctx)
 	defer imp.Wr.Close(ctx)
 
 	var rowErr error
 	badRowCB := func(trf *pipeline.TransformRowFailure) (quit bool) {
+		if imp.Rejects != nil {
+			if err := imp.Rejects.Reject(ctx, trf); err != nil {
+				rowErr = err
+				return true
+			}
+		}
+
 		if !imp.ContOnErr {
 			rowErr = trf
 			return true
@@ -161,8 +323,13 @@ func (imp *DataMover) Move(ctx context.Context) error {
 		return false
 	}
 
+	inFunc := pipeline.ProcFuncForReader(ctx, imp.Rd)
+	if imp.Rejects != nil {
+		inFunc = procFuncForRejects(ctx, imp.Rd)
+	}
+
 	p := pipeline.NewAsyncPipeline(
-		pipeline.ProcFuncForReader(ctx, imp.Rd),
+		inFunc,
 		pipeline.ProcFuncForWriter(ctx, imp.Wr),
 		imp.Transforms,
 		badRowCB)
@@ -170,6 +337,12 @@ func (imp *DataMover) Move(ctx context.Context) error {
 
 	err := p.Wait()
 
+	if imp.Rejects != nil {
+		if errCl := imp.Rejects.Close(ctx); err == nil && rowErr == nil {
+			err = errCl
+		}
+	}
+
 	if err != nil {
 		return err
 	}
@@ -177,15 +350,59 @@ func (imp *DataMover) Move(ctx context.Context) error {
 	return rowErr
 }
 
-func maybeMapFields(transforms *pipeline.TransformCollection, mapping *rowconv.FieldMapping) error {
+// getRowFillers returns the fillers for values that rows being moved may be missing: column defaults for rows added to
+// an existing table, and generated values for AUTO_INCREMENT columns. The AutoIncrementer generating the values is
+// returned as well, or nil if the rows have no AUTO_INCREMENT column.
+func getRowFillers(ctx context.Context, root *doltdb.RootValue, outSch schema.Schema, mvOpts *MoveOptions) ([]rowconv.RowFiller, *doltdb.AutoIncrementer, error) {
+	var fillers []rowconv.RowFiller
+
+	if mvOpts.Operation.keepsSchema() {
+		defaults, err := rowconv.ColumnDefaults(outSch)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(defaults) > 0 {
+			fillers = append(fillers, rowconv.DefaultsFiller(outSch, defaults))
+		}
+	}
+
+	var autoInc *doltdb.AutoIncrementer
+	if _, ok := schema.AutoIncrementCol(outSch); ok {
+		var next uint64 = 1
+		if mvOpts.Operation.keepsSchema() {
+			tbl, ok, err := root.GetTable(ctx, mvOpts.TableName)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if ok {
+				next, err = tbl.GetAutoIncrementValue(ctx)
+
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+
+		autoInc = doltdb.NewAutoIncrementer(outSch, next)
+		fillers = append(fillers, autoInc.Fill)
+	}
+
+	return fillers, autoInc, nil
+}
+
+func maybeMapFields(transforms *pipeline.TransformCollection, mapping *rowconv.FieldMapping, fillers []rowconv.RowFiller) error {
 	rconv, err := rowconv.NewRowConverter(mapping)
 
 	if err != nil {
 		return err
 	}
 
-	if !rconv.IdentityConverter {
-		nt := pipeline.NewNamedTransform("Mapping transform", rowconv.GetRowConvTransformFunc(rconv))
+	if len(fillers) > 0 || !rconv.IdentityConverter {
+		nt := pipeline.NewNamedErrTransform("Mapping transform", rowconv.GetRowConvTransformErrFunc(rconv, fillers...))
 		transforms.AppendTransforms(nt)
 	}
 
@@ -203,7 +420,7 @@ func maybeSort(wr table.TableWriteCloser, outSch schema.Schema, srcIsSorted bool
 }
 
 func getOutSchema(ctx context.Context, inSch schema.Schema, root *doltdb.RootValue, fs filesys.ReadableFS, mvOpts *MoveOptions) (schema.Schema, error) {
-	if mvOpts.Operation == UpdateOp {
+	if mvOpts.Operation.keepsSchema() {
 		// Get schema from target
 
 		rd, _, err := mvOpts.Dest.CreateReader(ctx, root, fs, mvOpts.SchFile, mvOpts.Dest.Path)
@@ -232,6 +449,38 @@ func getOutSchema(ctx context.Context, inSch schema.Schema, root *doltdb.RootVal
 
 }
 
+// inferOutSchema infers the column types of an untyped file imported without a schema file, rather than importing every
+// value as a string. Returns nil if the schema isn't inferred. A sample of the file is read by its own reader, and a
+// stream is rewound afterward so that the rows sampled are read again when they are moved.
+func inferOutSchema(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS, mvOpts *MoveOptions) (schema.Schema, *DataMoverCreationError) {
+	if mvOpts.Operation.keepsSchema() || mvOpts.SchFile != "" || !mvOpts.Src.Format.IsUntyped() {
+		return nil, nil
+	}
+
+	src := mvOpts.Src
+	if src.InStream != nil {
+		var rewind func()
+		src, rewind = src.teeStream()
+		defer rewind()
+	}
+
+	rd, _, err := src.CreateReader(ctx, root, fs, mvOpts.SchFile, mvOpts.Dest.Path)
+
+	if err != nil {
+		return nil, &DataMoverCreationError{CreateReaderErr, err}
+	}
+
+	defer rd.Close(ctx)
+
+	sch, err := InferSchema(ctx, rd, primaryKeyColNames(mvOpts.PrimaryKey), InferenceSampleSize, src.Format == NdjsonFile)
+
+	if err != nil {
+		return nil, outSchemaErr(err)
+	}
+
+	return sch, nil
+}
+
 func schFromFileOrDefault(path string, fs filesys.ReadableFS, defSch schema.Schema) (schema.Schema, error) {
 	if path != "" {
 		data, err := fs.ReadFile(path)
@@ -248,9 +497,7 @@ func schFromFileOrDefault(path string, fs filesys.ReadableFS, defSch schema.Sche
 
 func addPrimaryKey(sch schema.Schema, explicitKey string) (schema.Schema, error) {
 	if explicitKey != "" {
-		keyCols := strings.Split(explicitKey, ",")
-		trimmedCols := funcitr.MapStrings(keyCols, func(s string) string { return strings.TrimSpace(s) })
-		keyColSet := set.NewStrSet(trimmedCols)
+		keyColSet := set.NewStrSet(primaryKeyColNames(explicitKey))
 
 		foundPKCols := 0
 		var updatedCols []schema.Column
@@ -288,3 +535,13 @@ func addPrimaryKey(sch schema.Schema, explicitKey string) (schema.Schema, error)
 
 	return sch, nil
 }
+
+// primaryKeyColNames returns the column names in a comma separated primary key parameter.
+func primaryKeyColNames(explicitKey string) []string {
+	if explicitKey == "" {
+		return nil
+	}
+
+	keyCols := strings.Split(explicitKey, ",")
+	return funcitr.MapStrings(keyCols, func(s string) string { return strings.TrimSpace(s) })
+}
@@ -0,0 +1,277 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// InferenceSampleSize is the number of rows read from an untyped file to infer the schema of the table created from it.
+const InferenceSampleSize = 10000
+
+// ErrInferNonStringValue is returned when inferring a schema from a reader whose rows aren't untyped.
+var ErrInferNonStringValue = errors.New("schema inference requires rows with string values")
+
+// InferSchema reads up to sampleSize rows from an untyped reader, one whose values are all strings, and returns a schema
+// with the narrowest kind that can hold every value sampled for each column: bool, int, uint, float, uuid or, if
+// inferJSON is true, json for json objects and arrays, falling back to string. JSON is only inferred for sources whose
+// nested values are json, as a string of another format that happens to look like json should stay a string.
+//
+// If pkColNames is empty, the primary key is the column named "id" if its values are unique, otherwise the first column
+// whose values are unique and present in every row, otherwise the first column. The primary key columns are NOT NULL,
+// and so are the columns without missing values if every row of the reader was sampled. Otherwise the rows after the
+// sample may be missing values of those columns.
+func InferSchema(ctx context.Context, rd table.TableReader, pkColNames []string, sampleSize int, inferJSON bool) (schema.Schema, error) {
+	var stats []*colStats
+	err := rd.GetSchema().GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		stats = append(stats, &colStats{name: col.Name, tag: tag, notJSON: !inferJSON, vals: make(map[string]bool)})
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sampledAll := false
+	for i := 0; i <= sampleSize; i++ {
+		r, err := rd.ReadRow(ctx)
+
+		if err == io.EOF {
+			sampledAll = true
+			break
+		} else if i == sampleSize {
+			// the row after the sample was only read to find out whether there is one
+			break
+		} else if table.IsBadRow(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, cs := range stats {
+			val, ok := r.GetColVal(cs.tag)
+
+			if !ok || types.IsNull(val) {
+				cs.hasNull = true
+				continue
+			}
+
+			str, ok := val.(types.String)
+
+			if !ok {
+				return nil, ErrInferNonStringValue
+			} else if len(str) == 0 {
+				cs.hasNull = true
+			} else {
+				cs.observe(string(str))
+			}
+		}
+	}
+
+	isPK, err := inferredPrimaryKey(stats, pkColNames)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]schema.Column, len(stats))
+	for i, cs := range stats {
+		var constraints []schema.ColConstraint
+		if isPK[i] || sampledAll && !cs.hasNull {
+			constraints = append(constraints, schema.NotNullConstraint{})
+		}
+
+		cols[i] = schema.NewColumn(cs.name, cs.tag, cs.kind(), isPK[i], constraints...)
+
+		if cs.isJSON() {
+			cols[i].TypeName = schema.JSONTypeName
+		}
+	}
+
+	colColl, err := schema.NewColCollection(cols...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.SchemaFromCols(colColl), nil
+}
+
+// inferredPrimaryKey returns which of the columns given are part of the primary key of an inferred schema.
+func inferredPrimaryKey(stats []*colStats, pkColNames []string) ([]bool, error) {
+	isPK := make([]bool, len(stats))
+
+	if len(pkColNames) > 0 {
+		found := 0
+		for i, cs := range stats {
+			for _, name := range pkColNames {
+				if cs.name == name {
+					isPK[i] = true
+					found++
+					break
+				}
+			}
+		}
+
+		if found != len(pkColNames) {
+			return nil, errors.New("could not find all pks: " + strings.Join(pkColNames, ","))
+		}
+
+		return isPK, nil
+	}
+
+	if len(stats) == 0 {
+		return isPK, nil
+	}
+
+	suggested := -1
+	for i, cs := range stats {
+		if cs.isKeyCandidate() {
+			if strings.EqualFold(cs.name, "id") {
+				suggested = i
+				break
+			} else if suggested == -1 {
+				suggested = i
+			}
+		}
+	}
+
+	// without a better candidate, key on the first column like the untyped schema does
+	if suggested == -1 {
+		suggested = 0
+	}
+
+	isPK[suggested] = true
+	return isPK, nil
+}
+
+// colStats tracks which kinds can hold every value sampled for a column.
+type colStats struct {
+	name    string
+	tag     uint64
+	numVals int
+	hasNull bool
+
+	notBool  bool
+	notInt   bool
+	notUint  bool
+	notFloat bool
+	notUUID  bool
+	notJSON  bool
+
+	// vals holds the distinct values sampled, and is nil once a value is repeated
+	vals map[string]bool
+}
+
+func (cs *colStats) observe(s string) {
+	cs.numVals++
+
+	if cs.vals != nil {
+		if cs.vals[s] {
+			cs.vals = nil
+		} else {
+			cs.vals[s] = true
+		}
+	}
+
+	if !cs.notBool && !strings.EqualFold(s, "true") && !strings.EqualFold(s, "false") {
+		cs.notBool = true
+	}
+
+	// numbers with leading zeros, like zip codes, would lose them if converted
+	numeric := !hasLeadingZero(s)
+
+	if !cs.notInt {
+		if _, err := strconv.ParseInt(s, 10, 64); err != nil || !numeric {
+			cs.notInt = true
+		}
+	}
+
+	if !cs.notUint {
+		if _, err := strconv.ParseUint(s, 10, 64); err != nil || !numeric {
+			cs.notUint = true
+		}
+	}
+
+	if !cs.notFloat {
+		if f, err := strconv.ParseFloat(s, 64); err != nil || !numeric || math.IsNaN(f) || math.IsInf(f, 0) {
+			cs.notFloat = true
+		}
+	}
+
+	if !cs.notUUID {
+		if _, err := uuid.Parse(s); err != nil {
+			cs.notUUID = true
+		}
+	}
+
+	if !cs.notJSON {
+		if (s[0] != '{' && s[0] != '[') || !json.Valid([]byte(s)) {
+			cs.notJSON = true
+		}
+	}
+}
+
+func (cs *colStats) kind() types.NomsKind {
+	switch {
+	case cs.numVals == 0:
+		return types.StringKind
+	case !cs.notBool:
+		return types.BoolKind
+	case !cs.notInt:
+		return types.IntKind
+	case !cs.notUint:
+		return types.UintKind
+	case !cs.notFloat:
+		return types.FloatKind
+	case !cs.notUUID:
+		return types.UUIDKind
+	}
+
+	return types.StringKind
+}
+
+// isJSON returns whether the column holds json documents, which are stored as strings.
+func (cs *colStats) isJSON() bool {
+	return cs.kind() == types.StringKind && cs.numVals > 0 && !cs.notJSON
+}
+
+// isKeyCandidate returns whether the column could be a primary key based on the values sampled. Floats, bools and json
+// are never suggested as keys.
+func (cs *colStats) isKeyCandidate() bool {
+	if cs.hasNull || cs.numVals == 0 || cs.vals == nil {
+		return false
+	}
+
+	kind := cs.kind()
+	return kind != types.FloatKind && kind != types.BoolKind && !cs.isJSON()
+}
+
+func hasLeadingZero(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	return len(s) > 1 && s[0] == '0' && s[1] >= '0' && s[1] <= '9'
+}
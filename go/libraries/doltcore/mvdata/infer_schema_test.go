@@ -0,0 +1,186 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestInferSchema(t *testing.T) {
+	tests := []struct {
+		name          string
+		csv           string
+		pkColNames    []string
+		sampleSize    int
+		inferJSON     bool
+		expectedKinds map[string]types.NomsKind
+		expectedPKs   []string
+		nullable      []string
+		jsonCols      []string
+		expectedErr   string
+	}{
+		{
+			name: "kinds",
+			csv: "name,age,big,score,married,uuid\n" +
+				"bill,32,18446744073709551615,3.5,true,00000000-0000-0000-0000-000000000001\n" +
+				"jane,-7,1,1e3,FALSE,00000000-0000-0000-0000-000000000002\n",
+			expectedKinds: map[string]types.NomsKind{
+				"name":    types.StringKind,
+				"age":     types.IntKind,
+				"big":     types.UintKind,
+				"score":   types.FloatKind,
+				"married": types.BoolKind,
+				"uuid":    types.UUIDKind,
+			},
+			expectedPKs: []string{"name"},
+		},
+		{
+			name:          "json",
+			csv:           "id,doc,text\n1,\"{\"\"a\"\": 1}\",{x\n2,[1],[\n",
+			inferJSON:     true,
+			expectedKinds: map[string]types.NomsKind{"id": types.IntKind, "doc": types.StringKind, "text": types.StringKind},
+			expectedPKs:   []string{"id"},
+			jsonCols:      []string{"doc"},
+		},
+		{
+			name:          "json strings",
+			csv:           "id,doc\n1,\"{\"\"a\"\": 1}\"\n2,[1]\n",
+			expectedKinds: map[string]types.NomsKind{"id": types.IntKind, "doc": types.StringKind},
+			expectedPKs:   []string{"id"},
+		},
+		{
+			name:          "leading zeros stay strings",
+			csv:           "zip,n\n02134,1\n90210,2\n",
+			expectedKinds: map[string]types.NomsKind{"zip": types.StringKind, "n": types.IntKind},
+			expectedPKs:   []string{"zip"},
+		},
+		{
+			name:          "nullable columns",
+			csv:           "a,b,c\n1,,x\n2,3,\n",
+			expectedKinds: map[string]types.NomsKind{"a": types.IntKind, "b": types.IntKind, "c": types.StringKind},
+			expectedPKs:   []string{"a"},
+			nullable:      []string{"b", "c"},
+		},
+		{
+			name:          "empty column",
+			csv:           "a,b\n1,\n2,\n",
+			expectedKinds: map[string]types.NomsKind{"a": types.IntKind, "b": types.StringKind},
+			expectedPKs:   []string{"a"},
+			nullable:      []string{"b"},
+		},
+		{
+			name:          "prefers id",
+			csv:           "name,id\nbill,1\njane,2\n",
+			expectedKinds: map[string]types.NomsKind{"name": types.StringKind, "id": types.IntKind},
+			expectedPKs:   []string{"id"},
+		},
+		{
+			name:          "skips duplicates, nulls and floats",
+			csv:           "a,b,c,d\n1,x,1.5,k1\n1,,2.5,k2\n",
+			expectedKinds: map[string]types.NomsKind{"a": types.IntKind, "b": types.StringKind, "c": types.FloatKind, "d": types.StringKind},
+			expectedPKs:   []string{"d"},
+			nullable:      []string{"b"},
+		},
+		{
+			name:          "no unique column",
+			csv:           "a,b\n1,x\n1,x\n",
+			expectedKinds: map[string]types.NomsKind{"a": types.IntKind, "b": types.StringKind},
+			expectedPKs:   []string{"a"},
+		},
+		{
+			name:          "explicit key",
+			csv:           "a,b,c\n1,x,\n1,y,\n",
+			pkColNames:    []string{"b", "c"},
+			expectedKinds: map[string]types.NomsKind{"a": types.IntKind, "b": types.StringKind, "c": types.StringKind},
+			expectedPKs:   []string{"b", "c"},
+		},
+		{
+			name:        "explicit key not found",
+			csv:         "a,b\n1,x\n",
+			pkColNames:  []string{"z"},
+			expectedErr: "could not find all pks: z",
+		},
+		{
+			name:          "sample size",
+			csv:           "a,b\n1,x\n2,y\nnot a number,\n",
+			sampleSize:    2,
+			expectedKinds: map[string]types.NomsKind{"a": types.IntKind, "b": types.StringKind},
+			expectedPKs:   []string{"a"},
+			nullable:      []string{"b"},
+		},
+		{
+			name:          "sample size of the whole file",
+			csv:           "a,b\n1,x\n2,y\n",
+			sampleSize:    2,
+			expectedKinds: map[string]types.NomsKind{"a": types.IntKind, "b": types.StringKind},
+			expectedPKs:   []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd, err := csv.NewCSVReader(types.Format_7_18, ioutil.NopCloser(strings.NewReader(tt.csv)), csv.NewCSVInfo())
+			require.NoError(t, err)
+			defer rd.Close(context.Background())
+
+			sampleSize := tt.sampleSize
+			if sampleSize == 0 {
+				sampleSize = InferenceSampleSize
+			}
+
+			sch, err := InferSchema(context.Background(), rd, tt.pkColNames, sampleSize, tt.inferJSON)
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+
+			kinds := make(map[string]types.NomsKind)
+			var pks, nullable, jsonCols []string
+			err = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+				kinds[col.Name] = col.Kind
+				if col.IsPartOfPK {
+					pks = append(pks, col.Name)
+				}
+				if col.IsNullable() {
+					nullable = append(nullable, col.Name)
+				}
+				if col.IsJSON() {
+					jsonCols = append(jsonCols, col.Name)
+				}
+				return false, nil
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedKinds, kinds)
+			assert.Equal(t, tt.expectedPKs, pks)
+			assert.Equal(t, tt.nullable, nullable)
+			assert.Equal(t, tt.jsonCols, jsonCols)
+		})
+	}
+}
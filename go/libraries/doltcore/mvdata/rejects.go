@@ -0,0 +1,202 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/rowconv"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+)
+
+// Reasons given in an error report for rows that fail for reasons other than a failed conversion
+const (
+	ReadFailure  = "invalid row"
+	WriteFailure = "write failure"
+)
+
+// Properties of the rows read by a DataMover that keeps the rows that fail
+const (
+	lineNumProp = "line_num"
+	srcRowProp  = "src_row"
+)
+
+// RowRejection is a line of the error report of a move, describing a row that failed to be moved
+type RowRejection struct {
+	// Line is the line of the source the row was read from. It's the number of the row for sources that aren't read a
+	// line at a time, and blank lines or values spanning several lines in a csv file will throw it off.
+	Line    int64  `json:"line"`
+	Column  string `json:"column,omitempty"`
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+}
+
+// RejectsWriter keeps the rows that fail to be moved, by writing them to a rejects file in the format of the source and
+// writing a RowRejection describing each of them to an error report, as a line of json. Rows are written in the order
+// they fail, which isn't always the order they were read in.
+type RejectsWriter struct {
+	rowWr      table.TableWriteCloser
+	reportWr   io.WriteCloser
+	lineOffset int64
+	count      int64
+}
+
+// newRejectsWriter returns the RejectsWriter for the rejects file and error report of the move options given, or nil if
+// neither of them were given.
+func newRejectsWriter(ctx context.Context, root *doltdb.RootValue, fs filesys.WritableFS, mvOpts *MoveOptions, srcSch schema.Schema) (*RejectsWriter, error) {
+	if mvOpts.RejectsFile == "" && mvOpts.ErrorReportFile == "" {
+		return nil, nil
+	}
+
+	rw := &RejectsWriter{lineOffset: headerLines(mvOpts.Src)}
+
+	if mvOpts.RejectsFile != "" {
+		rejectsLoc := &DataLocation{
+			Path:      mvOpts.RejectsFile,
+			Format:    mvOpts.Src.Format,
+			CSVInfo:   mvOpts.Src.CSVInfo,
+			JSONPaths: mvOpts.Src.JSONPaths,
+			XLSXInfo:  mvOpts.Src.XLSXInfo,
+		}
+
+		var err error
+		rw.rowWr, err = rejectsLoc.CreateOverwritingDataWriter(ctx, mvOpts, root, fs, false, srcSch, nil)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if mvOpts.ErrorReportFile != "" {
+		err := fs.MkDirs(filepath.Dir(mvOpts.ErrorReportFile))
+
+		if err == nil {
+			rw.reportWr, err = fs.OpenForWrite(mvOpts.ErrorReportFile)
+		}
+
+		if err != nil {
+			if rw.rowWr != nil {
+				rw.rowWr.Close(ctx)
+			}
+
+			return nil, err
+		}
+	}
+
+	return rw, nil
+}
+
+// headerLines returns the number of lines at the start of a source that are read before its first row
+func headerLines(src *DataLocation) int64 {
+	switch src.Format {
+	case CsvFile, PsvFile:
+		if src.csvInfo().HasHeaderLine {
+			return 1
+		}
+	case XlsxFile:
+		return 1
+	}
+
+	return 0
+}
+
+// Count returns the number of rows that have been rejected
+func (rw *RejectsWriter) Count() int64 {
+	return rw.count
+}
+
+// Reject writes the row of a failure to the rejects file, if it was read, and writes the failure to the error report.
+func (rw *RejectsWriter) Reject(ctx context.Context, trf *pipeline.TransformRowFailure) error {
+	rw.count++
+
+	if srcRow, ok := trf.Props.Get(srcRowProp); ok && rw.rowWr != nil {
+		err := rw.rowWr.WriteRow(ctx, srcRow.(row.Row))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if rw.reportWr == nil {
+		return nil
+	}
+
+	rejection := RowRejection{Reason: WriteFailure, Details: trf.Details}
+	if lineNum, ok := trf.Props.Get(lineNumProp); ok {
+		rejection.Line = lineNum.(int64) + rw.lineOffset
+	}
+
+	if rce, ok := trf.Cause.(*rowconv.RowConvError); ok {
+		rejection.Column = rce.Column
+		rejection.Reason = string(rce.Failure)
+	} else if trf.TransformName == "reader" {
+		rejection.Reason = ReadFailure
+	}
+
+	data, err := json.Marshal(rejection)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = rw.reportWr.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the rejects file and the error report
+func (rw *RejectsWriter) Close(ctx context.Context) error {
+	var err error
+	if rw.rowWr != nil {
+		err = rw.rowWr.Close(ctx)
+	}
+
+	if rw.reportWr != nil {
+		if errCl := rw.reportWr.Close(); err == nil {
+			err = errCl
+		}
+	}
+
+	return err
+}
+
+// procFuncForRejects returns an InFunc for a reader whose rows carry their line number, and the row as it was read, so
+// that the rows that fail can be written to the rejects file and error report.
+func procFuncForRejects(ctx context.Context, rd table.TableReader) pipeline.InFunc {
+	var lineNum int64
+	return pipeline.ProcFuncForSourceFunc(func() (row.Row, pipeline.ImmutableProperties, error) {
+		r, err := rd.ReadRow(ctx)
+
+		if err == io.EOF && r == nil {
+			return nil, pipeline.NoProps, err
+		}
+
+		lineNum++
+		props := map[string]interface{}{lineNumProp: lineNum}
+
+		if r != nil {
+			props[srcRowProp] = r
+		}
+
+		return r, pipeline.NoProps.Set(props), err
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/rowconv"
+)
+
+const rejectsSchemaJSON = `{
+	"columns": [
+		{"name": "key", "kind": "string", "tag": 0, "is_part_of_pk": true, "col_constraints":[{"constraint_type": "not_null"}]},
+		{"name": "value", "kind": "int", "tag": 1}
+	]
+}`
+
+const rejectsCSV = `key,value
+a,1
+b,x
+,2
+d
+e,5
+`
+
+func TestMoveRejects(t *testing.T) {
+	ctx := context.Background()
+	_, root, fs := createRootAndFS()
+
+	require.NoError(t, fs.WriteFile(schemaFile, []byte(rejectsSchemaJSON)))
+	require.NoError(t, fs.WriteFile("data.csv", []byte(rejectsCSV)))
+
+	mvOpts := &MoveOptions{
+		Operation:       OverwriteOp,
+		ContOnErr:       true,
+		SchFile:         schemaFile,
+		Src:             NewDataLocation("data.csv", ""),
+		Dest:            NewDataLocation("table-name", ""),
+		RejectsFile:     "rejects.csv",
+		ErrorReportFile: "errors.json",
+	}
+
+	dm, dmce := NewDataMover(ctx, root, fs, mvOpts, nil)
+	require.Nil(t, dmce)
+	require.NoError(t, dm.Move(ctx))
+	assert.Equal(t, int64(3), dm.Rejects.Count())
+
+	data, err := fs.ReadFile("rejects.csv")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key,value", "b,x", ",2"}, strings.Split(strings.TrimSpace(string(data)), "\n"))
+
+	data, err = fs.ReadFile("errors.json")
+	require.NoError(t, err)
+
+	var rejections []RowRejection
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var rejection RowRejection
+		require.NoError(t, json.Unmarshal([]byte(line), &rejection))
+		assert.NotEmpty(t, rejection.Details)
+
+		rejection.Details = ""
+		rejections = append(rejections, rejection)
+	}
+
+	// rows that fail to be read are reported as they're read, ahead of rows that fail later in the pipeline
+	sort.Slice(rejections, func(i, j int) bool {
+		return rejections[i].Line < rejections[j].Line
+	})
+
+	expected := []RowRejection{
+		{Line: 3, Column: "value", Reason: string(rowconv.TypeConversionFailure)},
+		{Line: 4, Column: "key", Reason: string(rowconv.MissingPKFailure)},
+		{Line: 5, Reason: ReadFailure},
+	}
+	assert.Equal(t, expected, rejections)
+}
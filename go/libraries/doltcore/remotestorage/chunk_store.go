@@ -27,7 +27,6 @@ import (
 	"time"
 
 	"github.com/cenkalti/backoff"
-	"github.com/golang/snappy"
 
 	remotesapi "github.com/liquidata-inc/dolt/go/gen/proto/dolt/services/remotesapi_v1alpha1"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
@@ -678,7 +677,7 @@ func (dcs *DoltChunkStore) getRangeDownloadFunc(ctx context.Context, urlStr stri
 		for _, r := range ranges {
 			chunkStart := r.Offset - offset
 			chunkEnd := chunkStart + uint64(r.Length) - 4
-			chunkBytes, err := snappy.Decode(nil, comprData[chunkStart:chunkEnd])
+			chunkBytes, err := nbs.DecompressChunk(comprData[chunkStart:chunkEnd])
 
 			if err != nil {
 				return err
@@ -0,0 +1,137 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rowconv
+
+import (
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// ColumnDefault returns the value of the default persisted for the column given, or nil if the column has no default.
+// Defaults are persisted as SQL literals, like 'abc', -1.5 or true.
+func ColumnDefault(col schema.Column) (types.Value, error) {
+	if !col.HasDefault() {
+		return nil, nil
+	}
+
+	// The parser has no entry point for a lone expression, so parse it as the only expression of a select statement.
+	stmt, err := sqlparser.Parse("select " + col.Default)
+
+	if err != nil {
+		return nil, invalidDefaultErr(col)
+	}
+
+	sel, ok := stmt.(*sqlparser.Select)
+
+	if !ok || len(sel.SelectExprs) != 1 {
+		return nil, invalidDefaultErr(col)
+	}
+
+	aliased, ok := sel.SelectExprs[0].(*sqlparser.AliasedExpr)
+
+	if !ok {
+		return nil, invalidDefaultErr(col)
+	}
+
+	str, ok := literalString(aliased.Expr)
+
+	if !ok {
+		return nil, invalidDefaultErr(col)
+	}
+
+	var val types.Value
+	if col.IsJSON() {
+		val, err = doltcore.StringToJSON(str)
+	} else {
+		val, err = doltcore.StringToValue(str, col.Kind)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Type mismatch for default value of column %v: '%v'", col.Name, col.Default)
+	}
+
+	return val, nil
+}
+
+// ColumnDefaults returns the default values of all the columns in the schema given that have one, keyed by tag.
+func ColumnDefaults(sch schema.Schema) (row.TaggedValues, error) {
+	defaults := make(row.TaggedValues)
+	err := sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		val, err := ColumnDefault(col)
+
+		if err != nil {
+			return true, err
+		}
+
+		if !types.IsNull(val) {
+			defaults[tag] = val
+		}
+
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return defaults, nil
+}
+
+// literalString returns the text of the literal value given, which is converted to the kind of its column. Returns
+// false if the expression isn't a literal.
+func literalString(expr sqlparser.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *sqlparser.SQLVal:
+		switch e.Type {
+		case sqlparser.StrVal, sqlparser.IntVal, sqlparser.FloatVal:
+			return string(e.Val), true
+		}
+	case sqlparser.BoolVal:
+		if e {
+			return "true", true
+		}
+		return "false", true
+	case *sqlparser.ParenExpr:
+		return literalString(e.Expr)
+	case *sqlparser.UnaryExpr:
+		if e.Operator != sqlparser.UMinusStr {
+			return "", false
+		}
+
+		if val, ok := e.Expr.(*sqlparser.SQLVal); ok && (val.Type == sqlparser.IntVal || val.Type == sqlparser.FloatVal) {
+			str := string(val.Val)
+
+			// a double negative
+			if strings.HasPrefix(str, "-") {
+				return str[1:], true
+			}
+
+			return "-" + str, true
+		}
+	}
+
+	return "", false
+}
+
+func invalidDefaultErr(col schema.Column) error {
+	return fmt.Errorf("Invalid default expression for column %v: '%v'", col.Name, col.Default)
+}
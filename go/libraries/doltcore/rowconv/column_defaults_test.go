@@ -0,0 +1,74 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rowconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestColumnDefault(t *testing.T) {
+	tests := []struct {
+		kind     types.NomsKind
+		def      string
+		expected types.Value
+	}{
+		{types.StringKind, "'it''s'", types.String("it's")},
+		{types.StringKind, "''", types.String("")},
+		{types.IntKind, "3", types.Int(3)},
+		{types.IntKind, "-3", types.Int(-3)},
+		{types.FloatKind, "-1.1", types.Float(-1.1)},
+		{types.FloatKind, "(0.0)", types.Float(0)},
+		{types.BoolKind, "true", types.Bool(true)},
+		{types.StringKind, "", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.def, func(t *testing.T) {
+			col := schema.NewColumn("col", 0, test.kind, false)
+			col.Default = test.def
+
+			val, err := ColumnDefault(col)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, val)
+		})
+	}
+
+	for _, def := range []string{"2 + 2", "concat('a', 'b')", "'abc'"} {
+		col := schema.NewColumn("col", 0, types.IntKind, false)
+		col.Default = def
+
+		_, err := ColumnDefault(col)
+		assert.Error(t, err, def)
+	}
+}
+
+func TestJSONColumnDefault(t *testing.T) {
+	col := schema.NewJSONColumn("col", 0, false)
+	col.Default = `'{"tags": []}'`
+
+	val, err := ColumnDefault(col)
+	require.NoError(t, err)
+	assert.Equal(t, types.String(`{"tags":[]}`), val)
+
+	col.Default = `'{"tags": '`
+	_, err = ColumnDefault(col)
+	assert.Error(t, err)
+}
@@ -26,6 +26,27 @@ import (
 
 var IdentityConverter = &RowConverter{nil, true, nil}
 
+// RowConvFailure is the reason a row failed to be converted
+type RowConvFailure string
+
+const (
+	TypeConversionFailure RowConvFailure = "type conversion"
+	ConstraintFailure     RowConvFailure = "constraint violation"
+	MissingPKFailure      RowConvFailure = "missing primary key"
+)
+
+// RowConvError is the error for a row that failed to be converted, with the column it failed on and the reason it failed
+type RowConvError struct {
+	Column  string
+	Failure RowConvFailure
+	Details string
+}
+
+// Error returns the details of the failure
+func (rce *RowConvError) Error() string {
+	return rce.Details
+}
+
 // RowConverter converts rows from one schema to another
 type RowConverter struct {
 	// FieldMapping is a mapping from source column to destination column
@@ -56,16 +77,32 @@ func NewRowConverter(mapping *FieldMapping) (*RowConverter, error) {
 			return nil, fmt.Errorf("Could not find column being mapped. src tag: %d, dest tag: %d", srcTag, destTag)
 		}
 
-		convFuncs[srcTag] = doltcore.GetConvFunc(srcCol.Kind, destCol.Kind)
+		convFuncs[srcTag] = getColConvFunc(srcCol, destCol)
 
 		if convFuncs[srcTag] == nil {
-			return nil, fmt.Errorf("Unsupported conversion from type %s to %s", srcCol.KindString(), destCol.KindString())
+			return nil, fmt.Errorf("Unsupported conversion from type %s to %s", srcCol.TypeString(), destCol.TypeString())
 		}
 	}
 
 	return &RowConverter{mapping, false, convFuncs}, nil
 }
 
+// getColConvFunc returns the ConvFunc for values of the source column given to values of the destination column, or
+// nil if there is no conversion. JSON documents are strings, but only strings that parse as JSON can become documents,
+// and documents can only become strings.
+func getColConvFunc(srcCol, destCol schema.Column) doltcore.ConvFunc {
+	switch {
+	case srcCol.IsJSON() == destCol.IsJSON(), srcCol.Kind == types.NullKind:
+		return doltcore.GetConvFunc(srcCol.Kind, destCol.Kind)
+	case destCol.IsJSON() && srcCol.Kind == types.StringKind:
+		return doltcore.ConvStringToJSON
+	case srcCol.IsJSON() && destCol.Kind == types.StringKind:
+		return doltcore.GetConvFunc(srcCol.Kind, destCol.Kind)
+	}
+
+	return nil
+}
+
 // Convert takes a row maps its columns to their destination columns, and performs any type conversion needed to create
 // a row of the expected destination schema.
 func (rc *RowConverter) Convert(inRow row.Row) (row.Row, error) {
@@ -82,7 +119,8 @@ func (rc *RowConverter) Convert(inRow row.Row) (row.Row, error) {
 			outVal, err := convFunc(val)
 
 			if err != nil {
-				return false, err
+				col, _ := rc.SrcSch.GetAllCols().GetByTag(tag)
+				return false, &RowConvError{col.Name, TypeConversionFailure, err.Error()}
 			}
 
 			outTaggedVals[outTag] = outVal
@@ -122,7 +160,7 @@ func isNecessary(srcSch, destSch schema.Schema, destToSrc map[uint64]uint64) (bo
 			return true, nil
 		}
 
-		if srcCol.Kind != destCol.Kind {
+		if srcCol.Kind != destCol.Kind || srcCol.TypeName != destCol.TypeName {
 			return true, nil
 		}
 	}
@@ -160,26 +198,85 @@ func GetRowConvTransformFunc(rc *RowConverter) func(row.Row, pipeline.ReadableMa
 			return []*pipeline.TransformedRowResult{{RowData: inRow, PropertyUpdates: nil}}, ""
 		}
 	} else {
-		return func(inRow row.Row, props pipeline.ReadableMap) (outRows []*pipeline.TransformedRowResult, badRowDetails string) {
-			outRow, err := rc.Convert(inRow)
+		return GetRowConvTransformFuncWithFillers(rc)
+	}
+}
 
-			if err != nil {
-				return nil, err.Error()
-			}
+// RowFiller sets values on a converted row that it is missing, and returns the updated row.
+type RowFiller func(r row.Row) (row.Row, error)
 
-			if isv, err := row.IsValid(outRow, rc.DestSch); err != nil {
-				return nil, err.Error()
-			} else if !isv {
-				col, err := row.GetInvalidCol(outRow, rc.DestSch)
+// DefaultsFiller returns a RowFiller which sets the default values given, keyed by tag, on any columns of the schema
+// given that a row has no value for.
+func DefaultsFiller(sch schema.Schema, defaults row.TaggedValues) RowFiller {
+	return func(r row.Row) (row.Row, error) {
+		var err error
+		for tag, val := range defaults {
+			if _, ok := r.GetColVal(tag); !ok {
+				r, err = r.SetColVal(tag, val, sch)
 
 				if err != nil {
-					return nil, "invalid column"
-				} else {
-					return nil, "invalid column: " + col.Name
+					return nil, err
 				}
 			}
+		}
+
+		return r, nil
+	}
+}
+
+// GetRowConvTransformFuncWithFillers is like GetRowConvTransformFunc, but converted rows are passed through the fillers
+// given, in order, before they are validated.
+func GetRowConvTransformFuncWithFillers(rc *RowConverter, fillers ...RowFiller) func(row.Row, pipeline.ReadableMap) ([]*pipeline.TransformedRowResult, string) {
+	convAndFill := GetRowConvTransformErrFunc(rc, fillers...)
+	return func(inRow row.Row, props pipeline.ReadableMap) (outRows []*pipeline.TransformedRowResult, badRowDetails string) {
+		outRows, err := convAndFill(inRow, props)
+
+		if err != nil {
+			return nil, err.Error()
+		}
+
+		return outRows, ""
+	}
+}
+
+// GetRowConvTransformErrFunc is like GetRowConvTransformFuncWithFillers, but returns a RowConvError, naming the column
+// and the reason, for rows that fail to be converted or that are invalid once converted.
+func GetRowConvTransformErrFunc(rc *RowConverter, fillers ...RowFiller) pipeline.TransformRowErrFunc {
+	return func(inRow row.Row, props pipeline.ReadableMap) ([]*pipeline.TransformedRowResult, error) {
+		outRow, err := rc.Convert(inRow)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fill := range fillers {
+			outRow, err = fill(outRow)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		col, cnst, err := row.GetInvalidConstraint(outRow, rc.DestSch)
 
-			return []*pipeline.TransformedRowResult{{RowData: outRow, PropertyUpdates: nil}}, ""
+		if err != nil {
+			return nil, err
+		} else if col != nil {
+			return nil, invalidColErr(outRow, *col, cnst)
 		}
+
+		return []*pipeline.TransformedRowResult{{RowData: outRow, PropertyUpdates: nil}}, nil
 	}
 }
+
+// invalidColErr returns the RowConvError for a converted row that is invalid because of the column given
+func invalidColErr(r row.Row, col schema.Column, cnst schema.ColConstraint) *RowConvError {
+	failure := TypeConversionFailure
+	if val, ok := r.GetColVal(col.Tag); col.IsPartOfPK && (!ok || types.IsNull(val)) {
+		failure = MissingPKFailure
+	} else if cnst != nil {
+		failure = ConstraintFailure
+	}
+
+	return &RowConvError{col.Name, failure, "invalid column: " + col.Name}
+}
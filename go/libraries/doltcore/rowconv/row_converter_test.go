@@ -96,3 +96,59 @@ func TestUnneccessaryConversion(t *testing.T) {
 		t.Error("expected identity converter")
 	}
 }
+
+func TestRowConvErrors(t *testing.T) {
+	inCols, _ := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.StringKind, true),
+		schema.NewColumn("age", 1, types.StringKind, false),
+		schema.NewColumn("name", 2, types.StringKind, false),
+	)
+	outCols, _ := schema.NewColCollection(
+		schema.NewColumn("id", 10, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("age", 11, types.IntKind, false),
+		schema.NewColumn("name", 12, types.StringKind, false, schema.NotNullConstraint{}),
+	)
+	inSch := schema.SchemaFromCols(inCols)
+
+	mapping, err := NameMapping(inSch, schema.SchemaFromCols(outCols))
+	assert.NoError(t, err)
+
+	rConv, err := NewRowConverter(mapping)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		vals            row.TaggedValues
+		expectedCol     string
+		expectedFailure RowConvFailure
+	}{
+		{"valid", row.TaggedValues{0: types.String("1"), 1: types.String("30"), 2: types.String("bill")}, "", ""},
+		{"bad int", row.TaggedValues{0: types.String("1"), 1: types.String("thirty"), 2: types.String("bill")}, "age", TypeConversionFailure},
+		{"missing pk", row.TaggedValues{1: types.String("30"), 2: types.String("bill")}, "id", MissingPKFailure},
+		{"null name", row.TaggedValues{0: types.String("1"), 1: types.String("30")}, "name", ConstraintFailure},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inRow, err := row.New(types.Format_7_18, inSch, test.vals)
+			assert.NoError(t, err)
+
+			results, err := GetRowConvTransformErrFunc(rConv)(inRow, pipeline.ImmutableProperties{})
+
+			if test.expectedFailure == "" {
+				assert.NoError(t, err)
+				assert.Len(t, results, 1)
+				return
+			}
+
+			rce, ok := err.(*RowConvError)
+			if assert.True(t, ok, "expected a RowConvError, got %v", err) {
+				assert.Equal(t, test.expectedCol, rce.Column)
+				assert.Equal(t, test.expectedFailure, rce.Failure)
+			}
+
+			_, details := GetRowConvTransformFuncWithFillers(rConv)(inRow, pipeline.ImmutableProperties{})
+			assert.Equal(t, err.Error(), details)
+		})
+	}
+}
@@ -35,9 +35,11 @@ const (
 
 // Adds a new column to the schema given and returns the new table value. Non-null column additions rewrite the entire
 // table, since we must write a value for each row. If the column is not nullable, a default value must be provided.
+// The default expression, if not empty, is persisted in the schema as the column's default for future rows. The type
+// name, if not empty, is the column's more specific type, like schema.JSONTypeName.
 //
 // Returns an error if the column added conflicts with the existing schema in tag or name.
-func AddColumnToTable(ctx context.Context, db *doltdb.DoltDB, tbl *doltdb.Table, tag uint64, newColName string, colKind types.NomsKind, nullable Nullable, defaultVal types.Value) (*doltdb.Table, error) {
+func AddColumnToTable(ctx context.Context, db *doltdb.DoltDB, tbl *doltdb.Table, tag uint64, newColName string, colKind types.NomsKind, typeName string, nullable Nullable, defaultVal types.Value, defaultExpr string) (*doltdb.Table, error) {
 	sch, err := tbl.GetSchema(ctx)
 
 	if err != nil {
@@ -48,12 +50,18 @@ func AddColumnToTable(ctx context.Context, db *doltdb.DoltDB, tbl *doltdb.Table,
 		return nil, err
 	}
 
-	newSchema, err := createNewSchema(sch, tag, newColName, colKind, nullable)
+	newSchema, err := createNewSchema(sch, tag, newColName, colKind, typeName, nullable, defaultExpr)
 	if err != nil {
 		return nil, err
 	}
 
-	return updateTableWithNewSchema(ctx, db, tbl, tag, newSchema, defaultVal)
+	newTbl, err := updateTableWithNewSchema(ctx, db, tbl, tag, newSchema, defaultVal)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newTbl.CopyAutoIncrementValue(ctx, tbl)
 }
 
 // updateTableWithNewSchema updates the existing table with a new schema and new values for the new column as necessary,
@@ -106,13 +114,15 @@ func updateTableWithNewSchema(ctx context.Context, db *doltdb.DoltDB, tbl *doltd
 }
 
 // createNewSchema Creates a new schema with a column as specified by the params.
-func createNewSchema(sch schema.Schema, tag uint64, newColName string, colKind types.NomsKind, nullable Nullable) (schema.Schema, error) {
+func createNewSchema(sch schema.Schema, tag uint64, newColName string, colKind types.NomsKind, typeName string, nullable Nullable, defaultExpr string) (schema.Schema, error) {
 	var col schema.Column
 	if nullable {
 		col = schema.NewColumn(newColName, tag, colKind, false)
 	} else {
 		col = schema.NewColumn(newColName, tag, colKind, false, schema.NotNullConstraint{})
 	}
+	col.Default = defaultExpr
+	col.TypeName = typeName
 
 	updatedCols, err := sch.GetAllCols().Append(col)
 	if err != nil {
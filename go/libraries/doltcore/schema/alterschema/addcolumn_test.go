@@ -22,10 +22,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/noms"
 	"github.com/liquidata-inc/dolt/go/store/types"
@@ -242,7 +244,7 @@ func TestAddColumnToTable(t *testing.T) {
 			tbl, _, err := root.GetTable(ctx, tableName)
 			assert.NoError(t, err)
 
-			updatedTable, err := AddColumnToTable(ctx, dEnv.DoltDB, tbl, tt.tag, tt.newColName, tt.colKind, tt.nullable, tt.defaultVal)
+			updatedTable, err := AddColumnToTable(ctx, dEnv.DoltDB, tbl, tt.tag, tt.newColName, tt.colKind, "", tt.nullable, tt.defaultVal, "")
 			if len(tt.expectedErr) > 0 {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.expectedErr)
@@ -276,6 +278,84 @@ func TestAddColumnToTable(t *testing.T) {
 	}
 }
 
+func TestAlterKeepsAutoIncrementValue(t *testing.T) {
+	const idTag, nameTag = 0, 1
+
+	tests := []struct {
+		name  string
+		alter func(ctx context.Context, dEnv *env.DoltEnv, tbl *doltdb.Table) (*doltdb.Table, error)
+	}{
+		{
+			name: "add column",
+			alter: func(ctx context.Context, dEnv *env.DoltEnv, tbl *doltdb.Table) (*doltdb.Table, error) {
+				return AddColumnToTable(ctx, dEnv.DoltDB, tbl, 2, "age", types.IntKind, "", Null, nil, "")
+			},
+		},
+		{
+			name: "add column with default",
+			alter: func(ctx context.Context, dEnv *env.DoltEnv, tbl *doltdb.Table) (*doltdb.Table, error) {
+				return AddColumnToTable(ctx, dEnv.DoltDB, tbl, 2, "age", types.IntKind, "", NotNull, types.Int(1), "1")
+			},
+		},
+		{
+			name: "drop column",
+			alter: func(ctx context.Context, dEnv *env.DoltEnv, tbl *doltdb.Table) (*doltdb.Table, error) {
+				return DropColumn(ctx, dEnv.DoltDB, tbl, "name")
+			},
+		},
+		{
+			name: "rename column",
+			alter: func(ctx context.Context, dEnv *env.DoltEnv, tbl *doltdb.Table) (*doltdb.Table, error) {
+				return RenameColumn(ctx, dEnv.DoltDB, tbl, "name", "full_name")
+			},
+		},
+		{
+			name: "change primary key",
+			alter: func(ctx context.Context, dEnv *env.DoltEnv, tbl *doltdb.Table) (*doltdb.Table, error) {
+				return AddPrimaryKeyColumns(ctx, dEnv.DoltDB, tbl, "name")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dEnv := dtestutils.CreateTestEnv()
+			vrw := dEnv.DoltDB.ValueReadWriter()
+
+			idCol := schema.NewColumn("id", idTag, types.IntKind, true, schema.NotNullConstraint{})
+			idCol.AutoIncrement = true
+			colColl, err := schema.NewColCollection(idCol, schema.NewColumn("name", nameTag, types.StringKind, false, schema.NotNullConstraint{}))
+			require.NoError(t, err)
+			sch := schema.SchemaFromCols(colColl)
+
+			r, err := row.New(types.Format_7_18, sch, row.TaggedValues{idTag: types.Int(1), nameTag: types.String("bill")})
+			require.NoError(t, err)
+			key, err := r.NomsMapKey(sch).Value(ctx)
+			require.NoError(t, err)
+			val, err := r.NomsMapValue(sch).Value(ctx)
+			require.NoError(t, err)
+			rowData, err := types.NewMap(ctx, vrw, key, val)
+			require.NoError(t, err)
+			schVal, err := encoding.MarshalAsNomsValue(ctx, vrw, sch)
+			require.NoError(t, err)
+			tbl, err := doltdb.NewTable(ctx, vrw, schVal, rowData)
+			require.NoError(t, err)
+
+			// rows with ids up to 9 were inserted and deleted, so their ids must not be handed out again
+			tbl, err = tbl.SetAutoIncrementValue(10)
+			require.NoError(t, err)
+
+			updatedTbl, err := tt.alter(ctx, dEnv, tbl)
+			require.NoError(t, err)
+
+			next, err := updatedTbl.GetAutoIncrementValue(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(10), next)
+		})
+	}
+}
+
 func createEnvWithSeedData(t *testing.T) *env.DoltEnv {
 	dEnv := dtestutils.CreateTestEnv()
 	imt, sch := dtestutils.CreateTestDataTable(true)
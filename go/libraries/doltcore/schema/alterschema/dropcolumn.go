@@ -81,5 +81,5 @@ func DropColumn(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, c
 		return nil, err
 	}
 
-	return newTable, nil
+	return newTable.CopyAutoIncrementValue(ctx, tbl)
 }
@@ -0,0 +1,368 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alterschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// ErrEmptyPrimaryKey is returned when a change would leave a table without any primary key columns.
+var ErrEmptyPrimaryKey = errors.New("a table must have at least one primary key column")
+
+// ErrDuplicatePrimaryKeyColumn is returned when a column is named more than once in a primary key.
+var ErrDuplicatePrimaryKeyColumn = errors.New("a column can only appear in the primary key once")
+
+// ErrPrimaryKeyTableHasConflicts is returned when changing the primary key of a table with unresolved conflicts, since
+// the conflicts are keyed by the old primary key.
+var ErrPrimaryKeyTableHasConflicts = errors.New("cannot change the primary key of a table with unresolved conflicts")
+
+// NullPrimaryKeyError is returned when rows have no value for a column being added to the primary key.
+type NullPrimaryKeyError struct {
+	ColName string
+}
+
+func (e NullPrimaryKeyError) Error() string {
+	return fmt.Sprintf("column '%s' has null values and cannot be part of the primary key", e.ColName)
+}
+
+// DuplicatePrimaryKeyError is returned when the rows of a table don't have unique values for a new primary key.
+type DuplicatePrimaryKeyError struct {
+	// NumDuplicates is the number of rows whose new key is the same as that of another row
+	NumDuplicates int
+
+	// Example describes the first duplicated key found
+	Example string
+}
+
+func (e DuplicatePrimaryKeyError) Error() string {
+	return fmt.Sprintf("%d rows have duplicate values for the new primary key, e.g. (%s)", e.NumDuplicates, e.Example)
+}
+
+// AddPrimaryKeyColumns adds the columns named to the primary key of a table. Every row is rewritten with its new key.
+//
+// Returns a NullPrimaryKeyError if rows have no value for a column added, and a DuplicatePrimaryKeyError if the rows
+// aren't unique under the new key.
+func AddPrimaryKeyColumns(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, colNames ...string) (*doltdb.Table, error) {
+	tblSch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pkTags := append([]uint64(nil), tblSch.GetPKCols().Tags...)
+	addTags, err := tagsForColNames(tblSch, colNames)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return RekeyTable(ctx, doltDB.ValueReadWriter(), tbl, append(pkTags, addTags...))
+}
+
+// DropPrimaryKeyColumns removes the columns named from the primary key of a table. At least one column must remain in
+// the key. Every row is rewritten with its new key.
+//
+// Returns a DuplicatePrimaryKeyError if the rows aren't unique under the new key.
+func DropPrimaryKeyColumns(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, colNames ...string) (*doltdb.Table, error) {
+	tblSch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dropTags, err := tagsForColNames(tblSch, colNames)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var pkTags []uint64
+	err = tblSch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		for _, dropTag := range dropTags {
+			if tag == dropTag {
+				return false, nil
+			}
+		}
+
+		pkTags = append(pkTags, tag)
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return RekeyTable(ctx, doltDB.ValueReadWriter(), tbl, pkTags)
+}
+
+// ChangePrimaryKey redefines the primary key of a table as the columns named. Every row is rewritten with its new key.
+//
+// Returns a NullPrimaryKeyError if rows have no value for a new key column, and a DuplicatePrimaryKeyError if the rows
+// aren't unique under the new key.
+func ChangePrimaryKey(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, colNames ...string) (*doltdb.Table, error) {
+	tblSch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pkTags, err := tagsForColNames(tblSch, colNames)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return RekeyTable(ctx, doltDB.ValueReadWriter(), tbl, pkTags)
+}
+
+// RekeyTable rebuilds a table with a primary key made up of the columns with the tags given, in the order given. A
+// schema orders its key columns the way it orders its columns, so the key columns are reordered among themselves to
+// match the order of the key, while every other column keeps its position. Columns added to the key become NOT NULL,
+// and columns leaving it lose AUTO_INCREMENT. The row data is rebuilt with the new key tuples. Returns
+// schema.ErrColNotFound if the table has no column with one of the tags given.
+func RekeyTable(ctx context.Context, vrw types.ValueReadWriter, tbl *doltdb.Table, pkTags []uint64) (*doltdb.Table, error) {
+	if has, err := tbl.HasConflicts(); err != nil {
+		return nil, err
+	} else if has {
+		return nil, ErrPrimaryKeyTableHasConflicts
+	}
+
+	tblSch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	newSch, err := schemaWithPrimaryKey(tblSch, pkTags)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	newRowData, err := rekeyRows(ctx, vrw, tblSch, newSch, rowData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	schVal, err := encoding.MarshalAsNomsValue(ctx, vrw, newSch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	newTbl, err := doltdb.NewTable(ctx, vrw, schVal, newRowData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newTbl.CopyAutoIncrementValue(ctx, tbl)
+}
+
+// schemaWithPrimaryKey returns a copy of the schema given with a primary key made up of the columns with the tags given.
+// The key columns fill the positions of the columns in the key, in the order of the tags given, so that the schema's
+// key has the order given. Every other column keeps its position.
+func schemaWithPrimaryKey(sch schema.Schema, pkTags []uint64) (schema.Schema, error) {
+	if len(pkTags) == 0 {
+		return nil, ErrEmptyPrimaryKey
+	}
+
+	allCols := sch.GetAllCols()
+	isPK := make(map[uint64]bool, len(pkTags))
+
+	for _, tag := range pkTags {
+		if isPK[tag] {
+			return nil, ErrDuplicatePrimaryKeyColumn
+		} else if _, ok := allCols.GetByTag(tag); !ok {
+			return nil, schema.ErrColNotFound
+		}
+
+		isPK[tag] = true
+	}
+
+	cols := make([]schema.Column, 0, allCols.Size())
+	nextPK := 0
+	err := allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if isPK[tag] {
+			col, _ = allCols.GetByTag(pkTags[nextPK])
+			nextPK++
+
+			if col.IsNullable() {
+				col.Constraints = append(append([]schema.ColConstraint(nil), col.Constraints...), schema.NotNullConstraint{})
+			}
+
+			col.IsPartOfPK = true
+		} else {
+			col.IsPartOfPK = false
+			col.AutoIncrement = false
+		}
+
+		cols = append(cols, col)
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	colColl, err := schema.NewColCollection(cols...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.SchemaFromCols(colColl), nil
+}
+
+// rekeyRows returns the row data given rebuilt with the keys of the new schema given.
+func rekeyRows(ctx context.Context, vrw types.ValueReadWriter, oldSch, newSch schema.Schema, rowData types.Map) (types.Map, error) {
+	newRowData, err := types.NewMap(ctx, vrw)
+
+	if err != nil {
+		return types.EmptyMap, err
+	}
+
+	me := newRowData.Edit()
+	seen := make(map[hash.Hash]bool)
+	dupErr := DuplicatePrimaryKeyError{}
+
+	err = rowData.Iter(ctx, func(key, value types.Value) (stop bool, err error) {
+		oldRow, err := row.FromNoms(oldSch, key.(types.Tuple), value.(types.Tuple))
+
+		if err != nil {
+			return true, err
+		}
+
+		// the key and value tuples of a row are split by schema, so the row is rebuilt for the new one. Values of
+		// columns that were dropped from the schema are left out.
+		taggedVals := make(row.TaggedValues)
+		_, err = oldRow.IterSchema(oldSch, func(tag uint64, val types.Value) (stop bool, err error) {
+			if !types.IsNull(val) {
+				taggedVals[tag] = val
+			}
+			return false, nil
+		})
+
+		if err != nil {
+			return true, err
+		}
+
+		r, err := row.New(vrw.Format(), newSch, taggedVals)
+
+		if err != nil {
+			return true, err
+		}
+
+		if col, ok := missingKeyCol(r, newSch); ok {
+			return true, NullPrimaryKeyError{col.Name}
+		}
+
+		newKey, err := r.NomsMapKey(newSch).Value(ctx)
+
+		if err != nil {
+			return true, err
+		}
+
+		h, err := newKey.Hash(vrw.Format())
+
+		if err != nil {
+			return true, err
+		}
+
+		if seen[h] {
+			if dupErr.NumDuplicates == 0 {
+				dupErr.Example = keyString(r, newSch)
+			}
+			dupErr.NumDuplicates++
+			return false, nil
+		}
+
+		seen[h] = true
+		me.Set(newKey, r.NomsMapValue(newSch))
+		return false, nil
+	})
+
+	if err != nil {
+		return types.EmptyMap, err
+	}
+
+	if dupErr.NumDuplicates > 0 {
+		return types.EmptyMap, dupErr
+	}
+
+	return me.Map(ctx)
+}
+
+// missingKeyCol returns the first primary key column of the schema given that the row has no value for.
+func missingKeyCol(r row.Row, sch schema.Schema) (schema.Column, bool) {
+	var missing schema.Column
+	var found bool
+	_ = sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if val, ok := r.GetColVal(tag); !ok || types.IsNull(val) {
+			missing = col
+			found = true
+			return true, nil
+		}
+
+		return false, nil
+	})
+
+	return missing, found
+}
+
+// keyString returns a description of the primary key values of the row given.
+func keyString(r row.Row, sch schema.Schema) string {
+	var parts []string
+	_ = sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		val, _ := r.GetColVal(tag)
+		parts = append(parts, fmt.Sprintf("%s: %v", col.Name, val))
+		return false, nil
+	})
+
+	return strings.Join(parts, ", ")
+}
+
+// tagsForColNames returns the tags of the columns named, in order.
+func tagsForColNames(sch schema.Schema, colNames []string) ([]uint64, error) {
+	tags := make([]uint64, len(colNames))
+	for i, colName := range colNames {
+		col, ok := sch.GetAllCols().GetByName(colName)
+
+		if !ok {
+			return nil, schema.ErrColNotFound
+		}
+
+		tags[i] = col.Tag
+	}
+
+	return tags, nil
+}
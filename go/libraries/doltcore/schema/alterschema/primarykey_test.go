@@ -0,0 +1,166 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alterschema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestChangePrimaryKey(t *testing.T) {
+	type alterFunc func(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, colNames ...string) (*doltdb.Table, error)
+
+	tests := []struct {
+		name        string
+		alter       alterFunc
+		colNames    []string
+		expectedPKs []uint64
+		// expectedCols is the order of the columns after the change, if it isn't the original order
+		expectedCols []uint64
+		expectedErr  string
+	}{
+		{
+			name:        "redefine",
+			alter:       ChangePrimaryKey,
+			colNames:    []string{"name"},
+			expectedPKs: []uint64{dtestutils.NameTag},
+		},
+		{
+			name:        "redefine composite in column order",
+			alter:       ChangePrimaryKey,
+			colNames:    []string{"id", "name"},
+			expectedPKs: []uint64{dtestutils.IdTag, dtestutils.NameTag},
+		},
+		{
+			name:         "redefine composite in another order",
+			alter:        ChangePrimaryKey,
+			colNames:     []string{"name", "id"},
+			expectedPKs:  []uint64{dtestutils.NameTag, dtestutils.IdTag},
+			expectedCols: []uint64{dtestutils.NameTag, dtestutils.IdTag, dtestutils.AgeTag, dtestutils.IsMarriedTag, dtestutils.TitleTag},
+		},
+		{
+			name:         "key order doesn't move other columns",
+			alter:        ChangePrimaryKey,
+			colNames:     []string{"title", "name"},
+			expectedPKs:  []uint64{dtestutils.TitleTag, dtestutils.NameTag},
+			expectedCols: []uint64{dtestutils.IdTag, dtestutils.TitleTag, dtestutils.AgeTag, dtestutils.IsMarriedTag, dtestutils.NameTag},
+		},
+		{
+			name:        "add column",
+			alter:       AddPrimaryKeyColumns,
+			colNames:    []string{"is_married"},
+			expectedPKs: []uint64{dtestutils.IdTag, dtestutils.IsMarriedTag},
+		},
+		{
+			name:        "duplicate keys",
+			alter:       ChangePrimaryKey,
+			colNames:    []string{"is_married"},
+			expectedErr: "1 rows have duplicate values for the new primary key",
+		},
+		{
+			name:        "column not found",
+			alter:       ChangePrimaryKey,
+			colNames:    []string{"not found"},
+			expectedErr: schema.ErrColNotFound.Error(),
+		},
+		{
+			name:        "column repeated",
+			alter:       ChangePrimaryKey,
+			colNames:    []string{"name", "name"},
+			expectedErr: ErrDuplicatePrimaryKeyColumn.Error(),
+		},
+		{
+			name:        "drop only column",
+			alter:       DropPrimaryKeyColumns,
+			colNames:    []string{"id"},
+			expectedErr: ErrEmptyPrimaryKey.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := createEnvWithSeedData(t)
+			ctx := context.Background()
+
+			root, err := dEnv.WorkingRoot(ctx)
+			require.NoError(t, err)
+			tbl, _, err := root.GetTable(ctx, tableName)
+			require.NoError(t, err)
+
+			updatedTable, err := tt.alter(ctx, dEnv.DoltDB, tbl, tt.colNames...)
+			if len(tt.expectedErr) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			} else {
+				require.NoError(t, err)
+			}
+
+			sch, err := updatedTable.GetSchema(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPKs, sch.GetPKCols().Tags)
+
+			expectedCols := tt.expectedCols
+			if expectedCols == nil {
+				expectedCols = dtestutils.TypedSchema.GetAllCols().Tags
+			}
+			assert.Equal(t, expectedCols, sch.GetAllCols().Tags)
+
+			rowData, err := updatedTable.GetRowData(ctx)
+			require.NoError(t, err)
+
+			for _, typedRow := range dtestutils.TypedRows {
+				taggedVals, err := row.GetTaggedVals(typedRow)
+				require.NoError(t, err)
+				expectedRow, err := row.New(types.Format_7_18, sch, taggedVals)
+				require.NoError(t, err)
+
+				key, err := expectedRow.NomsMapKey(sch).Value(ctx)
+				require.NoError(t, err)
+
+				val, ok, err := rowData.MaybeGet(ctx, key)
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				r, err := row.FromNoms(sch, key.(types.Tuple), val.(types.Tuple))
+				require.NoError(t, err)
+				assert.True(t, row.AreEqual(expectedRow, r, sch))
+			}
+		})
+	}
+}
+
+func TestRekeyTableColumnNotFound(t *testing.T) {
+	dEnv := createEnvWithSeedData(t)
+	ctx := context.Background()
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	tbl, _, err := root.GetTable(ctx, tableName)
+	require.NoError(t, err)
+
+	// e.g. the ancestor of a merge, which doesn't have a column added to the table along with the new key
+	_, err = RekeyTable(ctx, dEnv.DoltDB.ValueReadWriter(), tbl, []uint64{dtestutils.IdTag, dtestutils.NextTag})
+	assert.Equal(t, schema.ErrColNotFound, err)
+}
@@ -85,5 +85,5 @@ func RenameColumn(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table,
 		return nil, err
 	}
 
-	return newTable, nil
+	return newTable.CopyAutoIncrementValue(ctx, tbl)
 }
@@ -44,6 +44,27 @@ func RenameTable(ctx context.Context, doltDb *doltdb.DoltDB, root *doltdb.RootVa
 		return nil, doltdb.ErrTableExists
 	}
 
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range fks {
+		if fks[i].TableName == oldName {
+			fks[i].TableName = newName
+		}
+
+		if fks[i].ReferencedTableName == oldName {
+			fks[i].ReferencedTableName = newName
+		}
+	}
+
+	// the foreign keys are renamed first, so that the old table is no longer referenced when it's removed
+	if root, err = root.PutForeignKeys(ctx, fks); err != nil {
+		return nil, err
+	}
+
 	if root, err = root.RemoveTables(ctx, oldName); err != nil {
 		return nil, err
 	}
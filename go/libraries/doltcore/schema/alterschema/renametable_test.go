@@ -104,3 +104,29 @@ func TestRenameTable(t *testing.T) {
 		})
 	}
 }
+
+func TestRenameTableForeignKeys(t *testing.T) {
+	dEnv := createEnvWithSeedData(t)
+	ctx := context.Background()
+
+	dtestutils.CreateTestTable(t, dEnv, "child", dtestutils.TypedSchema)
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	root, err = root.PutForeignKeys(ctx, []doltdb.ForeignKey{
+		{Name: "fk_child", TableName: "child", TableColumns: []uint64{dtestutils.IdTag}, ReferencedTableName: "people", ReferencedTableColumns: []uint64{dtestutils.IdTag}},
+	})
+	require.NoError(t, err)
+
+	root, err = RenameTable(ctx, dEnv.DoltDB, root, "people", "newPeople")
+	require.NoError(t, err)
+	root, err = RenameTable(ctx, dEnv.DoltDB, root, "child", "newChild")
+	require.NoError(t, err)
+
+	fks, err := root.GetForeignKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, fks, 1)
+	assert.Equal(t, "newChild", fks[0].TableName)
+	assert.Equal(t, "newPeople", fks[0].ReferencedTableName)
+}
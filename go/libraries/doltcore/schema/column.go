@@ -40,6 +40,9 @@ var InvalidTag uint64 = math.MaxUint64
 // ReservedTagMin is the start of a range of tags which the user should not be able to use in their schemas.
 const ReservedTagMin uint64 = 1 << 63
 
+// JSONTypeName is the TypeName of string columns that hold JSON documents, stored as canonical JSON text.
+const JSONTypeName = "json"
+
 // InvalidCol is a Column instance that is returned when there is nothing to return and can be tested against.
 var InvalidCol = NewColumn("invalid", InvalidTag, types.NullKind, false)
 
@@ -59,6 +62,17 @@ type Column struct {
 
 	// Constraints are rules that can be checked on each column to say if the columns value is valid
 	Constraints []ColConstraint
+
+	// Default is the SQL expression giving the value of this column when none is supplied, or empty if the column has
+	// no default value
+	Default string
+
+	// AutoIncrement says whether values for this column are generated from a per-table counter when not supplied
+	AutoIncrement bool
+
+	// TypeName is the name of the column's type when it's more specific than its kind, like JSONTypeName for string
+	// columns holding JSON documents, or empty otherwise
+	TypeName string
 }
 
 // NewColumn creates a Column instance
@@ -75,9 +89,19 @@ func NewColumn(name string, tag uint64, kind types.NomsKind, partOfPK bool, cons
 		kind,
 		partOfPK,
 		constraints,
+		"",
+		false,
+		"",
 	}
 }
 
+// NewJSONColumn creates a Column instance holding JSON documents
+func NewJSONColumn(name string, tag uint64, partOfPK bool, constraints ...ColConstraint) Column {
+	col := NewColumn(name, tag, types.StringKind, partOfPK, constraints...)
+	col.TypeName = JSONTypeName
+	return col
+}
+
 // IsNullable returns whether the column can be set to a null value.
 func (c Column) IsNullable() bool {
 	for _, cnst := range c.Constraints {
@@ -94,10 +118,32 @@ func (c Column) Equals(other Column) bool {
 		c.Tag == other.Tag &&
 		c.Kind == other.Kind &&
 		c.IsPartOfPK == other.IsPartOfPK &&
-		ColConstraintsAreEqual(c.Constraints, other.Constraints)
+		ColConstraintsAreEqual(c.Constraints, other.Constraints) &&
+		c.Default == other.Default &&
+		c.AutoIncrement == other.AutoIncrement &&
+		c.TypeName == other.TypeName
+}
+
+// HasDefault returns whether the column has a default value.
+func (c Column) HasDefault() bool {
+	return c.Default != ""
 }
 
 // KindString returns the string representation of the NomsKind stored in the column.
 func (c Column) KindString() string {
 	return KindToLwrStr[c.Kind]
 }
+
+// IsJSON returns whether the column holds JSON documents.
+func (c Column) IsJSON() bool {
+	return c.Kind == types.StringKind && c.TypeName == JSONTypeName
+}
+
+// TypeString returns the name of the column's type, which is its TypeName if it has one and its kind otherwise.
+func (c Column) TypeString() string {
+	if c.TypeName != "" {
+		return c.TypeName
+	}
+
+	return c.KindString()
+}
@@ -36,6 +36,16 @@ type encodedColumn struct {
 	IsPartOfPK bool `noms:"is_part_of_pk" json:"is_part_of_pk"`
 
 	Constraints []encodedConstraint `noms:"col_constraints" json:"col_constraints"`
+
+	// Default is the SQL expression for the column's default value. Omitted for columns without one, so that the
+	// encoding of those columns is unchanged.
+	Default string `noms:"default,omitempty" json:"default,omitempty"`
+
+	AutoIncrement bool `noms:"auto_increment,omitempty" json:"auto_increment,omitempty"`
+
+	// TypeName narrows the type of the field's kind, like "json" for strings holding JSON documents. Clients that
+	// don't know it read the field as its kind.
+	TypeName string `noms:"type_name,omitempty" json:"type_name,omitempty"`
 }
 
 func encodeAllColConstraints(constraints []schema.ColConstraint) []encodedConstraint {
@@ -69,12 +79,19 @@ func encodeColumn(col schema.Column) encodedColumn {
 		col.Name,
 		col.KindString(),
 		col.IsPartOfPK,
-		encodeAllColConstraints(col.Constraints)}
+		encodeAllColConstraints(col.Constraints),
+		col.Default,
+		col.AutoIncrement,
+		col.TypeName}
 }
 
 func (nfd encodedColumn) decodeColumn() schema.Column {
 	colConstraints := decodeAllColConstraint(nfd.Constraints)
-	return schema.NewColumn(nfd.Name, nfd.Tag, schema.LwrStrToKind[nfd.Kind], nfd.IsPartOfPK, colConstraints...)
+	col := schema.NewColumn(nfd.Name, nfd.Tag, schema.LwrStrToKind[nfd.Kind], nfd.IsPartOfPK, colConstraints...)
+	col.Default = nfd.Default
+	col.AutoIncrement = nfd.AutoIncrement
+	col.TypeName = nfd.TypeName
+	return col
 }
 
 type encodedConstraint struct {
@@ -117,8 +134,10 @@ func (sd schemaData) decodeSchema() (schema.Schema, error) {
 	numCols := len(sd.Columns)
 	cols := make([]schema.Column, numCols)
 
+	hasPK := false
 	for i, col := range sd.Columns {
 		cols[i] = col.decodeColumn()
+		hasPK = hasPK || cols[i].IsPartOfPK
 	}
 
 	colColl, err := schema.NewColCollection(cols...)
@@ -127,6 +146,11 @@ func (sd schemaData) decodeSchema() (schema.Schema, error) {
 		return nil, err
 	}
 
+	// schemas are written with a primary key, but one read from a pushed table may not have one
+	if !hasPK {
+		return nil, schema.ErrNoPrimaryKeyColumns
+	}
+
 	return schema.SchemaFromCols(colColl), nil
 }
 
@@ -33,11 +33,15 @@ const expectedSQL = `CREATE TABLE %s (
 );`
 
 func createTestSchema() schema.Schema {
+	ageCol := schema.NewColumn("age", 3, types.UintKind, false)
+	ageCol.Default = "21"
+
 	columns := []schema.Column{
 		schema.NewColumn("id", 4, types.UUIDKind, true, schema.NotNullConstraint{}),
 		schema.NewColumn("first", 1, types.StringKind, false),
 		schema.NewColumn("last", 2, types.StringKind, false, schema.NotNullConstraint{}),
-		schema.NewColumn("age", 3, types.UintKind, false),
+		ageCol,
+		schema.NewJSONColumn("metadata", 5, false),
 	}
 
 	colColl, _ := schema.NewColCollection(columns...)
@@ -41,6 +41,23 @@ func ColFromName(sch Schema, name string) (Column, bool) {
 	return sch.GetAllCols().GetByName(name)
 }
 
+// AutoIncrementCol returns the AUTO_INCREMENT column of the schema given, if it has one.
+func AutoIncrementCol(sch Schema) (Column, bool) {
+	var autoCol Column
+	var found bool
+	_ = sch.GetPKCols().Iter(func(tag uint64, col Column) (stop bool, err error) {
+		if col.AutoIncrement {
+			autoCol = col
+			found = true
+			return true, nil
+		}
+
+		return false, nil
+	})
+
+	return autoCol, found
+}
+
 // ExtractAllColNames returns a map of tag to column name, with one map entry for every column in the schema.
 func ExtractAllColNames(sch Schema) (map[uint64]string, error) {
 	colNames := make(map[uint64]string)
@@ -46,14 +46,14 @@ var ageVal = types.Uint(53)
 var titleVal = types.NullValue
 
 var pkCols = []Column{
-	{lnColName, lnColTag, types.StringKind, true, nil},
-	{fnColName, fnColTag, types.StringKind, true, nil},
+	{lnColName, lnColTag, types.StringKind, true, nil, "", false, ""},
+	{fnColName, fnColTag, types.StringKind, true, nil, "", false, ""},
 }
 var nonPkCols = []Column{
-	{addrColName, addrColTag, types.StringKind, false, nil},
-	{ageColName, ageColTag, types.UintKind, false, nil},
-	{titleColName, titleColTag, types.StringKind, false, nil},
-	{reservedColName, reservedColTag, types.StringKind, false, nil},
+	{addrColName, addrColTag, types.StringKind, false, nil, "", false, ""},
+	{ageColName, ageColTag, types.UintKind, false, nil, "", false, ""},
+	{titleColName, titleColTag, types.StringKind, false, nil, "", false, ""},
+	{reservedColName, reservedColTag, types.StringKind, false, nil, "", false, ""},
 }
 
 var allCols = append(append([]Column(nil), pkCols...), nonPkCols...)
@@ -98,7 +98,7 @@ func TestValidateForInsert(t *testing.T) {
 	})
 
 	t.Run("Name collision", func(t *testing.T) {
-		cols := append(allCols, Column{titleColName, 100, types.StringKind, false, nil})
+		cols := append(allCols, Column{titleColName, 100, types.StringKind, false, nil, "", false, ""})
 		colColl, err := NewColCollection(cols...)
 		require.NoError(t, err)
 
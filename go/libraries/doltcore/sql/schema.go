@@ -15,15 +15,60 @@
 package sql
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
+const doubleQuot = "\""
+
 // SchemaAsCreateStmt takes a Schema and returns a string representing a SQL create table command that could be used to
 // create this table
 func SchemaAsCreateStmt(tableName string, sch schema.Schema) string {
+	return schemaAsCreateStmt(tableName, sch, nil)
+}
+
+// SchemaAsCreateStmtWithForeignKeys returns the same create table command as SchemaAsCreateStmt, including the foreign
+// keys declared on the table in the root value given.
+func SchemaAsCreateStmtWithForeignKeys(ctx context.Context, root *doltdb.RootValue, tableName string, sch schema.Schema) (string, error) {
+	fks, err := root.GetForeignKeysForTable(ctx, tableName)
+
+	if err != nil {
+		return "", err
+	}
+
+	var fkDefs []string
+	for _, fk := range fks {
+		if fk.TableName != tableName {
+			continue
+		}
+
+		refTable, ok, err := root.GetTable(ctx, fk.ReferencedTableName)
+
+		if err != nil {
+			return "", err
+		} else if !ok {
+			return "", doltdb.ErrTableNotFound
+		}
+
+		refSch, err := refTable.GetSchema(ctx)
+
+		if err != nil {
+			return "", err
+		}
+
+		fkDefs = append(fkDefs, FmtForeignKey(2, fk, sch, refSch))
+	}
+
+	return schemaAsCreateStmt(tableName, sch, fkDefs), nil
+}
+
+func schemaAsCreateStmt(tableName string, sch schema.Schema, constraintDefs []string) string {
 	sb := &strings.Builder{}
 	fmt.Fprintf(sb, "CREATE TABLE %s (\n", QuoteIdentifier(tableName))
 
@@ -41,31 +86,57 @@ func SchemaAsCreateStmt(tableName string, sch schema.Schema) string {
 		return false
 	})
 
-	firstPK := true
-	err := sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
-		if firstPK {
-			sb.WriteString(",\n  primary key (")
-			firstPK = false
-		} else {
-			sb.WriteRune(',')
-		}
-		sb.WriteString(QuoteIdentifier(col.Name))
+	sb.WriteString(",\n")
+	sb.WriteString(FmtPrimaryKey(2, sch))
+
+	for _, def := range constraintDefs {
+		sb.WriteString(",\n")
+		sb.WriteString(def)
+	}
+
+	sb.WriteString("\n);")
+	return sb.String()
+}
+
+// FmtPrimaryKey converts the primary key of a schema to the primary key definition of a create table statement with the
+// given indent space count.
+func FmtPrimaryKey(indent int, sch schema.Schema) string {
+	var names []string
+	_ = sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		names = append(names, QuoteIdentifier(col.Name))
 		return false, nil
 	})
 
-	// TODO: fix panics
-	if err != nil {
-		panic(err)
+	return fmt.Sprintf("%sprimary key (%s)", strings.Repeat(" ", indent), strings.Join(names, ","))
+}
+
+// FmtForeignKey converts a foreign key to the constraint definition of a create table statement with the given indent
+// space count. The schemas of the table and the referenced table are used to look up column names.
+func FmtForeignKey(indent int, fk doltdb.ForeignKey, sch, refSch schema.Schema) string {
+	colNames := func(sch schema.Schema, tags []uint64) string {
+		names := make([]string, len(tags))
+		for i, tag := range tags {
+			col, _ := sch.GetAllCols().GetByTag(tag)
+			names[i] = QuoteIdentifier(col.Name)
+		}
+		return strings.Join(names, ",")
 	}
 
-	sb.WriteString(")\n);")
-	return sb.String()
+	return fmt.Sprintf("%sconstraint %s foreign key (%s) references %s (%s)", strings.Repeat(" ", indent),
+		QuoteIdentifier(fk.Name), colNames(sch, fk.TableColumns),
+		QuoteIdentifier(fk.ReferencedTableName), colNames(refSch, fk.ReferencedTableColumns))
+}
+
+// ForeignKeyAsAlterStmt returns an alter table statement that adds a foreign key to its table. The schemas of the table
+// and the referenced table are used to look up column names.
+func ForeignKeyAsAlterStmt(fk doltdb.ForeignKey, sch, refSch schema.Schema) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", QuoteIdentifier(fk.TableName), FmtForeignKey(0, fk, sch, refSch))
 }
 
 // FmtCol converts a column to a string with a given indent space count, name width, and type width.  If nameWidth or
 // typeWidth are 0 or less than the length of the name or type, then the length of the name or type will be used
 func FmtCol(indent, nameWidth, typeWidth int, col schema.Column) string {
-	return FmtColWithNameAndType(indent, nameWidth, typeWidth, col.Name, DoltToSQLType[col.Kind], col)
+	return FmtColWithNameAndType(indent, nameWidth, typeWidth, col.Name, ColumnSQLType(col), col)
 }
 
 // FmtColWithNameAndType creates a string representing a column within a sql create table statement with a given indent
@@ -85,9 +156,46 @@ func FmtColWithNameAndType(indent, nameWidth, typeWidth int, colName, typeStr st
 		}
 	}
 
+	if col.AutoIncrement {
+		colStr += " auto_increment"
+	}
+
+	if col.HasDefault() {
+		colStr += " default " + col.Default
+	}
+
 	return colStr + fmt.Sprintf(" comment 'tag:%d'", col.Tag)
 }
 
+// FmtValue returns the SQL literal for the value given.
+func FmtValue(value types.Value) string {
+	if types.IsNull(value) {
+		return "NULL"
+	}
+
+	switch value.Kind() {
+	case types.BoolKind:
+		if value.(types.Bool) {
+			return "TRUE"
+		} else {
+			return "FALSE"
+		}
+	case types.UUIDKind:
+		convFn := doltcore.GetConvFunc(value.Kind(), types.StringKind)
+		str, _ := convFn(value)
+		return doubleQuot + string(str.(types.String)) + doubleQuot
+	case types.StringKind:
+		// Backslashes are escape characters in SQL strings, and strings like JSON text escape quotes with them
+		s := strings.ReplaceAll(string(value.(types.String)), "\\", "\\\\")
+		s = strings.ReplaceAll(s, doubleQuot, "\\\"")
+		return doubleQuot + s + doubleQuot
+	default:
+		convFn := doltcore.GetConvFunc(value.Kind(), types.StringKind)
+		str, _ := convFn(value)
+		return string(str.(types.String))
+	}
+}
+
 // Quotes the identifier given with backticks.
 func QuoteIdentifier(s string) string {
 	return "`" + s + "`"
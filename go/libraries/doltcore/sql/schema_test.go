@@ -15,6 +15,7 @@
 package sql
 
 import (
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
 	"github.com/liquidata-inc/dolt/go/store/types"
@@ -43,6 +44,24 @@ func TestSchemaAsCreateStmt(t *testing.T) {
 	assert.Equal(t, expectedSQL, str)
 }
 
+func TestForeignKeyAsAlterStmt(t *testing.T) {
+	colColl, _ := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("person_id", 1, types.IntKind, false),
+	)
+	sch := schema.SchemaFromCols(colColl)
+	fk := doltdb.ForeignKey{
+		Name:                   "fk_person",
+		TableName:              "episodes",
+		TableColumns:           []uint64{1},
+		ReferencedTableName:    "people",
+		ReferencedTableColumns: []uint64{0},
+	}
+
+	str := ForeignKeyAsAlterStmt(fk, sch, sqltestutil.PeopleTestSchema)
+	assert.Equal(t, "ALTER TABLE `episodes` ADD constraint `fk_person` foreign key (`person_id`) references `people` (`id`);", str)
+}
+
 func TestFmtCol(t *testing.T) {
 	tests := []struct {
 		Col       schema.Column
@@ -43,6 +43,8 @@ type SqlBatcher struct {
 	editors map[string]*types.MapEditor
 	// The hashes of primary keys being inserted to the tables
 	hashes map[string]map[hash.Hash]bool
+	// The counters for the AUTO_INCREMENT columns of the tables being edited
+	autoIncrementers map[string]*doltdb.AutoIncrementer
 }
 
 // Returns a new SqlBatcher for the given environment and root value.
@@ -80,6 +82,7 @@ func (b *SqlBatcher) resetState() {
 	b.rowData = make(map[string]types.Map)
 	b.editors = make(map[string]*types.MapEditor)
 	b.hashes = make(map[string]map[hash.Hash]bool)
+	b.autoIncrementers = make(map[string]*doltdb.AutoIncrementer)
 }
 
 type InsertOptions struct {
@@ -148,6 +151,38 @@ func (b *SqlBatcher) Insert(ctx context.Context, tableName string, r row.Row, op
 	return &BatchInsertResult{RowInserted: !rowExists, RowUpdated: rowExists || rowAlreadyTouched}, nil
 }
 
+// AutoIncrement returns the row given with a generated value for the AUTO_INCREMENT column of the table named if the
+// row doesn't supply one. Rows of tables without an AUTO_INCREMENT column are returned unchanged.
+func (b *SqlBatcher) AutoIncrement(ctx context.Context, tableName string, r row.Row) (row.Row, error) {
+	ai, ok := b.autoIncrementers[tableName]
+
+	if !ok {
+		sch, err := b.GetSchema(ctx, tableName)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := schema.AutoIncrementCol(sch); !ok {
+			return r, nil
+		}
+
+		table, err := b.GetTable(ctx, tableName)
+		if err != nil {
+			return nil, err
+		}
+
+		next, err := table.GetAutoIncrementValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ai = doltdb.NewAutoIncrementer(sch, next)
+		b.autoIncrementers[tableName] = ai
+	}
+
+	return ai.Fill(r)
+}
+
 // GetTable returns the table with the name given. This method is offered because reading the table from the root value
 // is relatively expensive, and SqlBatcher caches Tables to avoid the overhead.
 func (b *SqlBatcher) GetTable(ctx context.Context, tableName string) (*doltdb.Table, error) {
@@ -239,11 +274,14 @@ func (b *SqlBatcher) getHashes(ctx context.Context, tableName string) map[hash.H
 }
 
 // Commit writes a new root value for every table under edit and returns the new root value. Tables are written in an
-// arbitrary order.
+// arbitrary order. If the edits violate a foreign key, they are discarded and a doltdb.ForeignKeyViolationError is
+// returned.
 func (b *SqlBatcher) Commit(ctx context.Context) (*doltdb.RootValue, error) {
 	root := b.root
 
+	var tableNames []string
 	for tableName, ed := range b.editors {
+		tableNames = append(tableNames, tableName)
 		newMap, err := ed.Map(ctx)
 
 		if err != nil {
@@ -257,6 +295,14 @@ func (b *SqlBatcher) Commit(ctx context.Context) (*doltdb.RootValue, error) {
 			return nil, err
 		}
 
+		if ai, ok := b.autoIncrementers[tableName]; ok {
+			table, err = table.SetAutoIncrementValue(ai.Next())
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		root, err = root.PutTable(ctx, b.db, tableName, table)
 
 		if err != nil {
@@ -265,6 +311,11 @@ func (b *SqlBatcher) Commit(ctx context.Context) (*doltdb.RootValue, error) {
 
 	}
 
+	if err := root.ValidateForeignKeyChanges(ctx, b.root, tableNames...); err != nil {
+		b.resetState()
+		return nil, err
+	}
+
 	b.root = root
 	b.resetState()
 
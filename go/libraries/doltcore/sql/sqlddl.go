@@ -80,7 +80,23 @@ func ExecuteDrop(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue,
 		}
 	}
 
-	var err error
+	dropped := make(map[string]bool)
+	for _, tableName := range filtered {
+		dropped[tableName] = true
+	}
+
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fk := range fks {
+		if dropped[fk.ReferencedTableName] && !dropped[fk.TableName] {
+			return nil, errFmt("Cannot drop table '%v': it is referenced by foreign key '%v' on table '%v'", fk.ReferencedTableName, fk.Name, fk.TableName)
+		}
+	}
+
 	if root, err = root.RemoveTables(ctx, filtered...); err != nil {
 		return nil, err
 	}
@@ -154,6 +170,14 @@ func ExecuteCreate(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValu
 		return nil, nil, err
 	}
 
+	for _, constraint := range spec.Constraints {
+		root, err = addForeignKey(ctx, root, tableName, constraint)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	return root, sch, nil
 }
 
@@ -223,6 +247,20 @@ func executeAlter(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue
 		return nil, err
 	}
 
+	// Key and constraint clauses are skipped by the parser, leaving a statement without a column action
+	if ddl.ColumnAction == "" && ddl.PartitionSpec == nil {
+		clause, err := parseAlterTableClause(query)
+		if err != nil {
+			return nil, err
+		}
+
+		if clause.primaryKey != nil {
+			return alterPrimaryKey(ctx, db, root, tableName, clause, query)
+		}
+
+		return alterConstraint(ctx, root, tableName, clause, query)
+	}
+
 	switch ddl.ColumnAction {
 	case sqlparser.AddStr:
 		return addColumn(ctx, db, root, tableName, ddl.TableSpec)
@@ -235,6 +273,76 @@ func executeAlter(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue
 	}
 }
 
+// alterTableClause is an ADD or DROP clause of an alter table statement for a primary key or foreign key.
+type alterTableClause struct {
+	// action is sqlparser.AddStr or sqlparser.DropStr
+	action string
+
+	// primaryKey is set for primary key clauses. Its columns are set when adding a primary key.
+	primaryKey *sqlparser.IndexDefinition
+
+	// constraint is set for foreign key clauses. Only its name is set when dropping a foreign key.
+	constraint *sqlparser.ConstraintDefinition
+}
+
+// parseAlterTableClause parses the ADD or DROP clause of the alter table statement given, which must be for a primary
+// key or foreign key. The parser skips over these clauses, so they're parsed here. Keys added have the same syntax as
+// in a create table statement, so they're parsed as part of one.
+func parseAlterTableClause(query string) (*alterTableClause, error) {
+	unsupportedErr := errFmt("Unsupported alter table statement: '%v'", query)
+
+	tkn := sqlparser.NewStringTokenizer(query)
+	typ, _ := tkn.Scan()
+	for typ != sqlparser.ADD && typ != sqlparser.DROP {
+		if typ == 0 || typ == sqlparser.LEX_ERROR {
+			return nil, unsupportedErr
+		}
+		typ, _ = tkn.Scan()
+	}
+
+	if typ == sqlparser.DROP {
+		first, _ := tkn.Scan()
+		second, _ := tkn.Scan()
+		third, name := tkn.Scan()
+		last, _ := tkn.Scan()
+
+		switch {
+		case first == sqlparser.PRIMARY && second == sqlparser.KEY && (third == 0 || third == ';'):
+			return &alterTableClause{action: sqlparser.DropStr, primaryKey: &sqlparser.IndexDefinition{}}, nil
+		case first == sqlparser.FOREIGN && second == sqlparser.KEY && third == sqlparser.ID && (last == 0 || last == ';'):
+			constraint := &sqlparser.ConstraintDefinition{Name: string(name)}
+			return &alterTableClause{action: sqlparser.DropStr, constraint: constraint}, nil
+		default:
+			return nil, unsupportedErr
+		}
+	}
+
+	// the tokenizer has read one character past the ADD keyword
+	definition := strings.TrimRight(strings.TrimSpace(query[tkn.Position-1:]), ";")
+	stmt, err := sqlparser.Parse("create table t (unused int, " + definition + ")")
+	if err != nil {
+		return nil, unsupportedErr
+	}
+
+	// statements the parser can't fully handle are returned without a table spec
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.TableSpec == nil {
+		return nil, unsupportedErr
+	}
+
+	spec := ddl.TableSpec
+	switch {
+	case len(spec.Columns) != 1:
+		return nil, unsupportedErr
+	case len(spec.Indexes) == 1 && len(spec.Constraints) == 0 && spec.Indexes[0].Info.Primary:
+		return &alterTableClause{action: sqlparser.AddStr, primaryKey: spec.Indexes[0]}, nil
+	case len(spec.Indexes) == 0 && len(spec.Constraints) == 1:
+		return &alterTableClause{action: sqlparser.AddStr, constraint: spec.Constraints[0]}, nil
+	default:
+		return nil, unsupportedErr
+	}
+}
+
 // renameColumn renames the column named. Returns the new root value and new schema, or an error if one occurs.
 func renameColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, tableName string, fromCol, toCol sqlparser.ColIdent) (*doltdb.RootValue, error) {
 	table, _, err := root.GetTable(ctx, tableName)
@@ -264,6 +372,26 @@ func dropColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue,
 		return nil, err
 	}
 
+	sch, err := table.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if dropCol, ok := sch.GetAllCols().GetByName(col.String()); ok {
+		fks, err := root.GetForeignKeysForTable(ctx, tableName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fk := range fks {
+			if usesColumn(fk, tableName, dropCol.Tag) {
+				return nil, errFmt("Cannot drop column '%v': it is used by foreign key '%v'", col.String(), fk.Name)
+			}
+		}
+	}
+
 	updatedTable, err := alterschema.DropColumn(ctx, db, table, col.String())
 	if err != nil {
 		if err == schema.ErrColNotFound {
@@ -299,13 +427,16 @@ func addColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, t
 	if col.IsPartOfPK {
 		return nil, errFmt("Adding primary keys is not supported")
 	}
+	if col.AutoIncrement {
+		return nil, errFmt("Adding AUTO_INCREMENT columns is not supported")
+	}
 
 	nullable := alterschema.NotNull
 	if col.IsNullable() {
 		nullable = alterschema.Null
 	}
 
-	updatedTable, err := alterschema.AddColumnToTable(ctx, db, table, col.Tag, col.Name, col.Kind, nullable, defaultVal)
+	updatedTable, err := alterschema.AddColumnToTable(ctx, db, table, col.Tag, col.Name, col.Kind, col.TypeName, nullable, defaultVal, col.Default)
 	if err != nil {
 		return nil, err
 	}
@@ -313,14 +444,244 @@ func addColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, t
 	return root.PutTable(ctx, db, tableName, updatedTable)
 }
 
+// alterPrimaryKey redefines the primary key of the table named as the columns given in an ADD PRIMARY KEY clause and
+// returns the updated root value. Tables always have a primary key, so it can be redefined but not dropped.
+func alterPrimaryKey(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, tableName string, clause *alterTableClause, query string) (*doltdb.RootValue, error) {
+	switch clause.action {
+	case sqlparser.AddStr:
+	case sqlparser.DropStr:
+		return nil, errFmt("Cannot drop the primary key of table '%v': use ADD PRIMARY KEY to redefine it", tableName)
+	default:
+		return nil, errFmt("Unsupported alter table statement: '%v'", query)
+	}
+
+	fks, err := root.GetForeignKeysForTable(ctx, tableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fk := range fks {
+		if fk.ReferencedTableName == tableName {
+			return nil, errFmt("Cannot change the primary key of table '%v': it is referenced by foreign key '%v'", tableName, fk.Name)
+		}
+	}
+
+	table, _, err := root.GetTable(ctx, tableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	colNames := make([]string, len(clause.primaryKey.Columns))
+	for i, indexCol := range clause.primaryKey.Columns {
+		colNames[i] = indexCol.Column.String()
+	}
+
+	updatedTable, err := alterschema.ChangePrimaryKey(ctx, db, table, colNames...)
+	if err != nil {
+		switch err {
+		case schema.ErrColNotFound:
+			return nil, errFmt("Unknown column in primary key: '%v'", strings.Join(colNames, ", "))
+		case alterschema.ErrDuplicatePrimaryKeyColumn:
+			return nil, errFmt("Duplicate column in primary key: '%v'", strings.Join(colNames, ", "))
+		case alterschema.ErrPrimaryKeyTableHasConflicts:
+			return nil, errFmt("Cannot change the primary key of table '%v' while it has unresolved conflicts", tableName)
+		}
+		return nil, err
+	}
+
+	return root.PutTable(ctx, db, tableName, updatedTable)
+}
+
+// alterConstraint adds or drops the foreign key constraint given in an alter table statement and returns the updated
+// root value.
+func alterConstraint(ctx context.Context, root *doltdb.RootValue, tableName string, clause *alterTableClause, query string) (*doltdb.RootValue, error) {
+	constraint := clause.constraint
+
+	switch clause.action {
+	case sqlparser.AddStr:
+		return addForeignKey(ctx, root, tableName, constraint)
+	case sqlparser.DropStr:
+		fks, err := root.GetForeignKeysForTable(ctx, tableName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fk := range fks {
+			if fk.Name == constraint.Name && fk.TableName == tableName {
+				return root.DropForeignKey(ctx, fk.Name)
+			}
+		}
+
+		return nil, errFmt("Unknown foreign key '%v' on table '%v'", constraint.Name, tableName)
+	default:
+		return nil, errFmt("Unsupported alter table statement: '%v'", query)
+	}
+}
+
+// addForeignKey adds the foreign key constraint given to the table named, and validates that the existing rows of the
+// table satisfy it. Returns the updated root value.
+func addForeignKey(ctx context.Context, root *doltdb.RootValue, tableName string, constraint *sqlparser.ConstraintDefinition) (*doltdb.RootValue, error) {
+	fkDef, ok := constraint.Details.(*sqlparser.ForeignKeyDefinition)
+	if !ok {
+		return nil, errFmt("Unsupported constraint: '%v'", nodeToString(constraint.Details))
+	}
+
+	if !isRestrictAction(fkDef.OnDelete) || !isRestrictAction(fkDef.OnUpdate) {
+		return nil, errFmt("Only RESTRICT and NO ACTION are supported as referential actions")
+	}
+
+	refTableName := fkDef.ReferencedTable.Name.String()
+	if err := validateTable(ctx, root, refTableName); err != nil {
+		return nil, err
+	}
+
+	if len(fkDef.Source) != len(fkDef.ReferencedColumns) {
+		return nil, errFmt("Foreign key on table '%v' must reference as many columns as it declares", tableName)
+	}
+
+	table, _, err := root.GetTable(ctx, tableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sch, err := table.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	refTable, _, err := root.GetTable(ctx, refTableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	refSch, err := refTable.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fkName := constraint.Name
+	if fkName == "" {
+		fkName, err = generateForeignKeyName(ctx, root, tableName)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fk := doltdb.ForeignKey{
+		Name:                   fkName,
+		TableName:              tableName,
+		TableColumns:           make([]uint64, len(fkDef.Source)),
+		ReferencedTableName:    refTableName,
+		ReferencedTableColumns: make([]uint64, len(fkDef.ReferencedColumns)),
+	}
+
+	for i, colName := range fkDef.Source {
+		col, ok := sch.GetAllCols().GetByName(colName.String())
+		if !ok {
+			return nil, errFmt(UnknownColumnErrFmt, colName.String())
+		}
+		fk.TableColumns[i] = col.Tag
+	}
+
+	for i, colName := range fkDef.ReferencedColumns {
+		col, ok := refSch.GetAllCols().GetByName(colName.String())
+		if !ok {
+			return nil, errFmt(UnknownColumnErrFmt, colName.String())
+		}
+		fk.ReferencedTableColumns[i] = col.Tag
+	}
+
+	newRoot, err := root.AddForeignKey(ctx, fk)
+
+	if err != nil {
+		switch err {
+		case doltdb.ErrForeignKeyExists:
+			return nil, errFmt("A foreign key with the name '%v' already exists", fkName)
+		case doltdb.ErrForeignKeyRefNotPK:
+			return nil, errFmt("Foreign key '%v' must reference the primary key of table '%v'", fkName, refTableName)
+		case doltdb.ErrForeignKeyColumnMismatch:
+			return nil, errFmt("The columns of foreign key '%v' don't match the types of the referenced columns", fkName)
+		}
+		return nil, err
+	}
+
+	if err := newRoot.ValidateForeignKeys(ctx, tableName); err != nil {
+		return nil, err
+	}
+
+	return newRoot, nil
+}
+
+// generateForeignKeyName returns a name for an unnamed foreign key on the table given, following MySQL's convention
+// of <table>_ibfk_<n>.
+func generateForeignKeyName(ctx context.Context, root *doltdb.RootValue, tableName string) (string, error) {
+	fks, err := root.GetForeignKeys(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	names := make(map[string]bool)
+	for _, fk := range fks {
+		names[fk.Name] = true
+	}
+
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s_ibfk_%d", tableName, i)
+		if !names[name] {
+			return name, nil
+		}
+	}
+}
+
+// isRestrictAction returns whether the referential action given rejects changes that would violate the foreign key,
+// which is the only behavior supported.
+func isRestrictAction(action sqlparser.ReferenceAction) bool {
+	switch action {
+	case sqlparser.DefaultAction, sqlparser.Restrict, sqlparser.NoAction:
+		return true
+	default:
+		return false
+	}
+}
+
+// usesColumn returns whether the foreign key given uses the column with the tag given from the table named.
+func usesColumn(fk doltdb.ForeignKey, tableName string, tag uint64) bool {
+	if fk.TableName == tableName {
+		for _, fkTag := range fk.TableColumns {
+			if fkTag == tag {
+				return true
+			}
+		}
+	}
+
+	if fk.ReferencedTableName == tableName {
+		for _, fkTag := range fk.ReferencedTableColumns {
+			if fkTag == tag {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // getSchema returns the schema corresponding to the TableSpec given
 func getSchema(spec *sqlparser.TableSpec) (schema.Schema, error) {
 	cols := make([]schema.Column, len(spec.Columns))
 
 	var tag uint64
 	var seenPk bool
+	var seenAutoIncrement bool
 	for i, colDef := range spec.Columns {
-		// TODO: support default value
 		col, _, err := getColumn(colDef, spec.Indexes, tag)
 		if err != nil {
 			return nil, err
@@ -328,6 +689,15 @@ func getSchema(spec *sqlparser.TableSpec) (schema.Schema, error) {
 		if col.IsPartOfPK {
 			seenPk = true
 		}
+		if col.AutoIncrement {
+			if !col.IsPartOfPK {
+				return nil, errFmt("Incorrect table definition: AUTO_INCREMENT column '%v' must be part of the primary key", col.Name)
+			}
+			if seenAutoIncrement {
+				return nil, errFmt("Incorrect table definition: there can be only one AUTO_INCREMENT column")
+			}
+			seenAutoIncrement = true
+		}
 		cols[i] = col
 		tag++
 	}
@@ -343,16 +713,6 @@ func getSchema(spec *sqlparser.TableSpec) (schema.Schema, error) {
 	return schema.SchemaFromCols(colColl), nil
 }
 
-// fakeResolver satisfies the TagResolver interface to let us fetch a value from a RowValGetter, without needing an
-// actual row. This only works for literal values.
-type fakeResolver struct {
-	TagResolver
-}
-
-func (fakeResolver) ResolveTag(tableName string, columnName string) (uint64, error) {
-	return schema.InvalidTag, errors.New("Fake ResolveTag called")
-}
-
 // getColumn returns the column given by the definition, indexes, and tag given, as well as its default value if
 // specified by the definition. The tag may be overridden if the column definition includes a tag already.
 func getColumn(colDef *sqlparser.ColumnDefinition, indexes []*sqlparser.IndexDefinition, tag uint64) (schema.Column, types.Value, error) {
@@ -388,6 +748,7 @@ func getColumn(colDef *sqlparser.ColumnDefinition, indexes []*sqlparser.IndexDef
 	}
 
 	var colKind types.NomsKind
+	var typeName string
 	switch columnType.Type {
 
 	// integer-like types
@@ -420,6 +781,11 @@ func getColumn(colDef *sqlparser.ColumnDefinition, indexes []*sqlparser.IndexDef
 	case BIT, BOOLEAN, BOOL:
 		colKind = types.BoolKind
 
+	// JSON documents, stored as canonical JSON text
+	case JSON:
+		colKind = types.StringKind
+		typeName = schema.JSONTypeName
+
 	// time-like types (not yet supported in noms, but should be)
 	case DATE, TIME, DATETIME, TIMESTAMP, YEAR:
 		return errColumn("Date and time types aren't supported")
@@ -429,7 +795,7 @@ func getColumn(colDef *sqlparser.ColumnDefinition, indexes []*sqlparser.IndexDef
 		return errColumn("BINARY and VARBINARY types are not supported")
 
 	// unsupported types
-	case ENUM, SET, JSON, GEOMETRY, POINT, LINESTRING, POLYGON, GEOMETRYCOLLECTION, MULTIPOINT, MULTILINESTRING, MULTIPOLYGON:
+	case ENUM, SET, GEOMETRY, POINT, LINESTRING, POLYGON, GEOMETRYCOLLECTION, MULTIPOINT, MULTILINESTRING, MULTIPOLYGON:
 		return errColumn("Unsupported column type %v", columnType.Type)
 
 	// unrecognized types
@@ -438,41 +804,33 @@ func getColumn(colDef *sqlparser.ColumnDefinition, indexes []*sqlparser.IndexDef
 	}
 
 	column := schema.NewColumn(colDef.Name.String(), tag, colKind, isPkey, constraints...)
+	column.TypeName = typeName
 
-	if colDef.Type.Default == nil {
-		return column, nil, nil
+	if colDef.Type.Autoincrement {
+		if colKind != types.IntKind && colKind != types.UintKind {
+			return errColumn("Incorrect column specifier for column '%v': AUTO_INCREMENT is only supported for integer columns", column.Name)
+		}
+		column.AutoIncrement = true
 	}
 
-	// Get the default value. This can be any expression (usually a literal value). We aren't using the simpler semantics
-	// of extractNomsValueFromSQLVal here, that doesn't cover the full range of expressions permitted by SQL (like -1.0,
-	// 2+2, CONCAT("a", "b")).
-	getter, err := getterFor(colDef.Type.Default, nil, NewAliases())
-	if err != nil {
-		return schema.InvalidCol, nil, err
+	if colDef.Type.Default == nil {
+		return column, nil, nil
 	}
 
-	if getter.NomsKind != colKind {
-		return errColumn("Type mismatch for default value of column %v: '%v'", column.Name, nodeToString(colDef.Type.Default))
+	// DEFAULT NULL is the same as having no default, and is how mysqldump writes every nullable column
+	if _, ok := colDef.Type.Default.(*sqlparser.NullVal); ok {
+		if !column.IsNullable() {
+			return errColumn("Invalid default value for column '%v'", column.Name)
+		}
+		return column, nil, nil
 	}
 
-	if err = getter.Init(fakeResolver{}); err != nil {
-		return errColumn("Unsupported default expression for column %v: '%v'", column.Name, nodeToString(colDef.Type.Default))
+	defaultVal, err := evalDefaultExpr(column, colDef.Type.Default)
+	if err != nil {
+		return schema.InvalidCol, nil, err
 	}
 
-	var defaultVal types.Value
-	// Extracting the default value this way requires us to be prepared to panic, since we're using a nil row to extract
-	// the value. This should work fine for literal expressions, but might panic otherwise.
-	func() {
-		defer func() {
-			rp := recover()
-			if rp != nil {
-				err = errFmt("Unsupported default expression for column %v: '%v'", column.Name, nodeToString(colDef.Type.Default))
-			}
-		}()
-
-		defaultVal = getter.Get(nil)
-	}()
-
+	column.Default, err = persistedDefault(column, colDef.Type.Default, defaultVal)
 	if err != nil {
 		return schema.InvalidCol, nil, err
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"vitess.io/vitess/go/vt/sqlparser"
 
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
@@ -49,6 +50,42 @@ func TestExecuteCreate(t *testing.T) {
 				schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}),
 				schema.NewColumn("age", 1, types.IntKind, false)),
 		},
+		{
+			name:  "Test create json column",
+			query: "create table testTable (id int primary key, metadata json default '{\"tags\": []}')",
+			expectedSchema: dtestutils.CreateSchema(
+				schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}),
+				withDefault(schema.NewJSONColumn("metadata", 1, false), `'{\"tags\": []}'`)),
+		},
+		{
+			name:  "Test create default null",
+			query: "create table testTable (id int primary key, age int default null)",
+			expectedSchema: dtestutils.CreateSchema(
+				schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}),
+				schema.NewColumn("age", 1, types.IntKind, false)),
+		},
+		{
+			name:        "Test create not null default null",
+			query:       "create table testTable (id int primary key, age int not null default null)",
+			expectedErr: "Invalid default value for column 'age'",
+		},
+		{
+			name:  "Test create auto increment primary key",
+			query: "create table testTable (id int auto_increment primary key, age int)",
+			expectedSchema: dtestutils.CreateSchema(
+				withAutoIncrement(schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{})),
+				schema.NewColumn("age", 1, types.IntKind, false)),
+		},
+		{
+			name:        "Test auto increment non-integer column",
+			query:       "create table testTable (id varchar(20) auto_increment primary key, age int)",
+			expectedErr: "AUTO_INCREMENT is only supported for integer columns",
+		},
+		{
+			name:        "Test auto increment non-key column",
+			query:       "create table testTable (id int primary key, age int auto_increment)",
+			expectedErr: "must be part of the primary key",
+		},
 		{
 			name:        "Test syntax error",
 			query:       "create table testTable id int, age int",
@@ -217,13 +254,13 @@ func TestExecuteCreate(t *testing.T) {
   PRIMARY KEY (code)
 );`,
 			expectedSchema: dtestutils.CreateSchema(
-				schema.NewColumn("code", 0, types.StringKind, true, schema.NotNullConstraint{}),
-				schema.NewColumn("iso_code_2", 1, types.StringKind, false, schema.NotNullConstraint{}),
-				schema.NewColumn("iso_code_3", 2, types.StringKind, false),
-				schema.NewColumn("iso_country", 3, types.StringKind, false, schema.NotNullConstraint{}),
-				schema.NewColumn("country", 4, types.StringKind, false, schema.NotNullConstraint{}),
-				schema.NewColumn("lat", 5, types.FloatKind, false, schema.NotNullConstraint{}),
-				schema.NewColumn("lon", 6, types.FloatKind, false, schema.NotNullConstraint{})),
+				withDefault(schema.NewColumn("code", 0, types.StringKind, true, schema.NotNullConstraint{}), "''"),
+				withDefault(schema.NewColumn("iso_code_2", 1, types.StringKind, false, schema.NotNullConstraint{}), "''"),
+				withDefault(schema.NewColumn("iso_code_3", 2, types.StringKind, false), "''"),
+				withDefault(schema.NewColumn("iso_country", 3, types.StringKind, false, schema.NotNullConstraint{}), "''"),
+				withDefault(schema.NewColumn("country", 4, types.StringKind, false, schema.NotNullConstraint{}), "''"),
+				withDefault(schema.NewColumn("lat", 5, types.FloatKind, false, schema.NotNullConstraint{}), "0.0"),
+				withDefault(schema.NewColumn("lon", 6, types.FloatKind, false, schema.NotNullConstraint{}), "0.0")),
 		},
 	}
 
@@ -346,21 +383,21 @@ func TestAddColumn(t *testing.T) {
 			name:  "alter add column not null",
 			query: "alter table people add (newColumn varchar(80) not null default 'default' comment 'tag:100')",
 			expectedSchema: dtestutils.AddColumnToSchema(PeopleTestSchema,
-				schema.NewColumn("newColumn", 100, types.StringKind, false, schema.NotNullConstraint{})),
+				withDefault(schema.NewColumn("newColumn", 100, types.StringKind, false, schema.NotNullConstraint{}), "'default'")),
 			expectedRows: dtestutils.AddColToRows(t, AllPeopleRows, 100, types.String("default")),
 		},
 		{
 			name:  "alter add column not null with expression default",
 			query: "alter table people add (newColumn int not null default 2+2/2 comment 'tag:100')",
 			expectedSchema: dtestutils.AddColumnToSchema(PeopleTestSchema,
-				schema.NewColumn("newColumn", 100, types.IntKind, false, schema.NotNullConstraint{})),
+				withDefault(schema.NewColumn("newColumn", 100, types.IntKind, false, schema.NotNullConstraint{}), "3")),
 			expectedRows: dtestutils.AddColToRows(t, AllPeopleRows, 100, types.Int(3)),
 		},
 		{
 			name:  "alter add column not null with negative expression",
 			query: "alter table people add (newColumn float not null default -1.1 comment 'tag:100')",
 			expectedSchema: dtestutils.AddColumnToSchema(PeopleTestSchema,
-				schema.NewColumn("newColumn", 100, types.FloatKind, false, schema.NotNullConstraint{})),
+				withDefault(schema.NewColumn("newColumn", 100, types.FloatKind, false, schema.NotNullConstraint{}), "-1.1")),
 			expectedRows: dtestutils.AddColToRows(t, AllPeopleRows, 100, types.Float(-1.1)),
 		},
 		{
@@ -474,11 +511,6 @@ func TestUnsupportedAlterStatements(t *testing.T) {
 			query:       "alter table people change id newId (varchar(80) not null)",
 			expectedErr: "Unsupported",
 		},
-		{
-			name:        "alter add foreign key",
-			query:       "alter table appearances add constraint people_id_ref foreign key (id) references people (id)",
-			expectedErr: "Unsupported",
-		},
 	}
 
 	for _, tt := range tests {
@@ -500,6 +532,171 @@ func TestUnsupportedAlterStatements(t *testing.T) {
 	}
 }
 
+func TestForeignKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		queries     []string
+		expectedFks []string
+		expectedErr string
+	}{
+		{
+			name:        "alter add foreign key",
+			queries:     []string{"alter table appearances add constraint people_id_ref foreign key (character_id) references people (id)"},
+			expectedFks: []string{"people_id_ref"},
+		},
+		{
+			name:        "alter add foreign key, generated name",
+			queries:     []string{"alter table appearances add foreign key (episode_id) references episodes (id)"},
+			expectedFks: []string{"appearances_ibfk_1"},
+		},
+		{
+			name: "alter drop foreign key",
+			queries: []string{
+				"alter table appearances add constraint people_id_ref foreign key (character_id) references people (id)",
+				"alter table appearances drop foreign key people_id_ref",
+			},
+			expectedFks: []string{},
+		},
+		{
+			name:        "alter add foreign key, unknown column",
+			queries:     []string{"alter table appearances add constraint people_id_ref foreign key (id) references people (id)"},
+			expectedErr: "Unknown column: 'id'",
+		},
+		{
+			name:        "alter add foreign key, unknown table",
+			queries:     []string{"alter table appearances add constraint people_id_ref foreign key (character_id) references notfound (id)"},
+			expectedErr: "Unknown table: 'notfound'",
+		},
+		{
+			name:        "alter add foreign key, not referencing primary key",
+			queries:     []string{"alter table appearances add constraint people_id_ref foreign key (character_id) references people (age)"},
+			expectedErr: "primary key",
+		},
+		{
+			name:        "alter add foreign key, existing rows violate it",
+			queries:     []string{"alter table people add constraint ep_ref foreign key (age) references episodes (id)"},
+			expectedErr: "foreign key constraint violated",
+		},
+		{
+			name:        "alter add foreign key, cascade",
+			queries:     []string{"alter table appearances add constraint people_id_ref foreign key (character_id) references people (id) on delete cascade"},
+			expectedErr: "Only RESTRICT and NO ACTION",
+		},
+		{
+			name: "drop referenced table",
+			queries: []string{
+				"alter table appearances add constraint people_id_ref foreign key (character_id) references people (id)",
+				"drop table people",
+			},
+			expectedErr: "people_id_ref",
+		},
+		{
+			name: "drop referenced and referencing tables",
+			queries: []string{
+				"alter table appearances add constraint people_id_ref foreign key (character_id) references people (id)",
+				"drop table people, appearances",
+			},
+			expectedFks: []string{},
+		},
+		{
+			name: "drop referencing column",
+			queries: []string{
+				"alter table appearances add constraint people_id_ref foreign key (character_id) references people (id)",
+				"alter table appearances drop column character_id",
+			},
+			expectedErr: "people_id_ref",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			ctx := context.Background()
+			root, _ := dEnv.WorkingRoot(ctx)
+
+			var err error
+			for _, query := range tt.queries {
+				sqlStatement, parseErr := sqlparser.Parse(query)
+				require.NoError(t, parseErr)
+
+				s := sqlStatement.(*sqlparser.DDL)
+
+				var updatedRoot *doltdb.RootValue
+				switch s.Action {
+				case sqlparser.DropStr:
+					updatedRoot, err = ExecuteDrop(ctx, dEnv.DoltDB, root, s, query)
+				default:
+					updatedRoot, err = ExecuteAlter(ctx, dEnv.DoltDB, root, s, query)
+				}
+
+				if err != nil {
+					break
+				}
+
+				root = updatedRoot
+			}
+
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+
+			fks, err := root.GetForeignKeys(ctx)
+			require.NoError(t, err)
+
+			fkNames := make([]string, 0, len(fks))
+			for _, fk := range fks {
+				fkNames = append(fkNames, fk.Name)
+			}
+
+			assert.Equal(t, tt.expectedFks, fkNames)
+		})
+	}
+}
+
+func TestForeignKeyViolations(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	ctx := context.Background()
+	root, _ := dEnv.WorkingRoot(ctx)
+
+	query := "alter table appearances add constraint people_id_ref foreign key (character_id) references people (id)"
+	sqlStatement, err := sqlparser.Parse(query)
+	require.NoError(t, err)
+
+	root, err = ExecuteAlter(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.DDL), query)
+	require.NoError(t, err)
+
+	query = "delete from people where id = 0"
+	sqlStatement, err = sqlparser.Parse(query)
+	require.NoError(t, err)
+
+	_, err = ExecuteDelete(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.Delete), query)
+	require.Error(t, err)
+	assert.True(t, doltdb.IsForeignKeyViolation(err))
+
+	query = "delete from appearances where character_id = 0"
+	sqlStatement, err = sqlparser.Parse(query)
+	require.NoError(t, err)
+
+	result, err := ExecuteDelete(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.Delete), query)
+	require.NoError(t, err)
+
+	sqlStatement, err = sqlparser.Parse("delete from people where id = 0")
+	require.NoError(t, err)
+
+	_, err = ExecuteDelete(ctx, dEnv.DoltDB, result.Root, sqlStatement.(*sqlparser.Delete), "delete from people where id = 0")
+	assert.NoError(t, err)
+
+	violations, err := root.GetForeignKeyViolations(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
 func TestDropColumn(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -588,6 +785,104 @@ func TestDropColumn(t *testing.T) {
 	}
 }
 
+func TestAlterPrimaryKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		expectedPKs []string
+		expectedErr string
+	}{
+		{
+			name:        "redefine primary key",
+			query:       "alter table people add primary key (first)",
+			expectedPKs: []string{"first"},
+		},
+		{
+			name:        "composite primary key in column order",
+			query:       "alter table people add primary key (first, last)",
+			expectedPKs: []string{"first", "last"},
+		},
+		{
+			name:        "composite primary key in another order",
+			query:       "alter table people add primary key (last, first)",
+			expectedPKs: []string{"last", "first"},
+		},
+		{
+			name:        "duplicate keys",
+			query:       "alter table people add primary key (last)",
+			expectedErr: "rows have duplicate values for the new primary key",
+		},
+		{
+			name:        "null keys",
+			query:       "alter table people add primary key (uuid)",
+			expectedErr: "column 'uuid' has null values",
+		},
+		{
+			name:        "column not found",
+			query:       "alter table people add primary key (notFound)",
+			expectedErr: "Unknown column in primary key",
+		},
+		{
+			name:        "drop primary key",
+			query:       "alter table people drop primary key",
+			expectedErr: "Cannot drop the primary key of table 'people'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			ctx := context.Background()
+			root, _ := dEnv.WorkingRoot(ctx)
+
+			sqlStatement, err := sqlparser.Parse(tt.query)
+			require.NoError(t, err)
+
+			updatedRoot, err := ExecuteAlter(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.DDL), tt.query)
+
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+
+			table, ok, err := updatedRoot.GetTable(ctx, PeopleTableName)
+			require.NoError(t, err)
+			require.True(t, ok)
+			sch, err := table.GetSchema(ctx)
+			require.NoError(t, err)
+
+			var pks []string
+			err = sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+				pks = append(pks, col.Name)
+				return false, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPKs, pks)
+
+			rowData, err := table.GetRowData(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(len(AllPeopleRows)), rowData.Len())
+
+			for _, r := range AllPeopleRows {
+				taggedVals, err := row.GetTaggedVals(r)
+				require.NoError(t, err)
+				r, err = row.New(types.Format_7_18, sch, taggedVals)
+				require.NoError(t, err)
+
+				key, err := r.NomsMapKey(sch).Value(ctx)
+				require.NoError(t, err)
+				_, ok, err := rowData.MaybeGet(ctx, key)
+				require.NoError(t, err)
+				assert.True(t, ok)
+			}
+		})
+	}
+}
+
 func TestRenameColumn(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -829,3 +1124,15 @@ func TestRenameTable(t *testing.T) {
 		})
 	}
 }
+
+// withDefault returns the column given with the default expression given.
+func withDefault(col schema.Column, defaultExpr string) schema.Column {
+	col.Default = defaultExpr
+	return col
+}
+
+// withAutoIncrement returns the column given marked as AUTO_INCREMENT.
+func withAutoIncrement(col schema.Column) schema.Column {
+	col.AutoIncrement = true
+	return col
+}
@@ -0,0 +1,135 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"errors"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/rowconv"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// fakeResolver satisfies the TagResolver interface to let us fetch a value from a RowValGetter, without needing an
+// actual row. This only works for literal values.
+type fakeResolver struct {
+	TagResolver
+}
+
+func (fakeResolver) ResolveTag(tableName string, columnName string) (uint64, error) {
+	return schema.InvalidTag, errors.New("Fake ResolveTag called")
+}
+
+// evalDefaultExpr returns the value of the default expression given for the column given. This can be any expression
+// (usually a literal value) that doesn't reference other columns.
+func evalDefaultExpr(column schema.Column, expr sqlparser.Expr) (types.Value, error) {
+	// We aren't using the simpler semantics of extractNomsValueFromSQLVal here, that doesn't cover the full range of
+	// expressions permitted by SQL (like -1.0, 2+2, CONCAT("a", "b")).
+	getter, err := getterFor(expr, nil, NewAliases())
+	if err != nil {
+		return nil, err
+	}
+
+	// UUIDs and JSON documents have no literal syntax, so those columns take string defaults
+	isString := (column.Kind == types.UUIDKind || column.IsJSON()) && getter.NomsKind == types.StringKind
+	if getter.NomsKind != column.Kind && !isString {
+		return nil, errFmt("Type mismatch for default value of column %v: '%v'", column.Name, nodeToString(expr))
+	}
+
+	if err = getter.Init(fakeResolver{}); err != nil {
+		return nil, errFmt("Unsupported default expression for column %v: '%v'", column.Name, nodeToString(expr))
+	}
+
+	var defaultVal types.Value
+	// Extracting the default value this way requires us to be prepared to panic, since we're using a nil row to extract
+	// the value. This should work fine for literal expressions, but might panic otherwise.
+	func() {
+		defer func() {
+			rp := recover()
+			if rp != nil {
+				err = errFmt("Unsupported default expression for column %v: '%v'", column.Name, nodeToString(expr))
+			}
+		}()
+
+		defaultVal = getter.Get(nil)
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if isString {
+		var val types.Value
+		if column.IsJSON() {
+			val, err = doltcore.StringToJSON(string(defaultVal.(types.String)))
+		} else {
+			val, err = doltcore.StringToValue(string(defaultVal.(types.String)), column.Kind)
+		}
+		if err != nil {
+			return nil, errFmt("Type mismatch for default value of column %v: '%v'", column.Name, nodeToString(expr))
+		}
+		return val, nil
+	}
+
+	return defaultVal, nil
+}
+
+// defaultLiteral returns the SQL literal a default value is persisted as, as read by rowconv.ColumnDefault.
+func defaultLiteral(val types.Value) (sqlparser.Expr, error) {
+	convFunc := doltcore.GetConvFunc(val.Kind(), types.StringKind)
+
+	if convFunc == nil {
+		return nil, errFmt("Unsupported default value: %v", val)
+	}
+
+	str, err := convFunc(val)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch val.Kind() {
+	case types.IntKind, types.UintKind:
+		return sqlparser.NewIntVal([]byte(str.(types.String))), nil
+	case types.FloatKind:
+		return sqlparser.NewFloatVal([]byte(str.(types.String))), nil
+	case types.BoolKind:
+		return sqlparser.BoolVal(val.(types.Bool)), nil
+	default:
+		return sqlparser.NewStrVal([]byte(str.(types.String))), nil
+	}
+}
+
+// persistedDefault returns the default expression given as it's persisted for the column given. Literals are
+// persisted as they're written, and other expressions as the literal of their value, as evaluating them needs the
+// full expression evaluator of this package.
+func persistedDefault(column schema.Column, expr sqlparser.Expr, val types.Value) (string, error) {
+	column.Default = nodeToString(expr)
+
+	if persistedVal, err := rowconv.ColumnDefault(column); err == nil && persistedVal != nil && persistedVal.Equals(val) {
+		return column.Default, nil
+	}
+
+	literal, err := defaultLiteral(val)
+
+	if err != nil {
+		return "", err
+	}
+
+	return nodeToString(literal), nil
+}
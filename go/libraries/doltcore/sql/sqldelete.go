@@ -137,6 +137,10 @@ func ExecuteDelete(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValu
 		return nil, err
 	}
 
+	if err = result.Root.ValidateForeignKeyChanges(ctx, root, tableName); err != nil {
+		return nil, err
+	}
+
 	return &result, nil
 }
 
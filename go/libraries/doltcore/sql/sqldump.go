@@ -0,0 +1,315 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+)
+
+// MaxStatementSize is the size of the largest statement that can be read from a SQL script. mysqldump writes the rows of
+// a table as INSERT statements of up to a megabyte each by default.
+const MaxStatementSize = 64 * 1024 * 1024
+
+// NewStatementScanner returns a Scanner that reads the statements of a SQL script.
+func NewStatementScanner(rd io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(nil, MaxStatementSize)
+	scanner.Split(ScanStatements)
+
+	return scanner
+}
+
+// ScanStatements is a split function for a Scanner that returns each statement in a SQL script as a token, without its
+// terminating semicolon. Semicolons in quoted strings and identifiers don't end a statement. Comments are removed, and
+// statements left empty are skipped.
+func ScanStatements(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	for advance < len(data) {
+		n, stmt, ok := nextStatement(data[advance:], atEOF)
+
+		if !ok {
+			return advance, nil, nil
+		}
+
+		advance += n
+
+		if len(stmt) > 0 {
+			return advance, stmt, nil
+		}
+	}
+
+	return advance, nil, nil
+}
+
+// nextStatement returns the length of the first statement in the data given including its semicolon, and the statement
+// with its comments removed. Returns false if more data is needed to find the end of the statement.
+func nextStatement(data []byte, atEOF bool) (int, []byte, bool) {
+	var stmt bytes.Buffer
+
+	i := 0
+	for i < len(data) {
+		c := data[i]
+		rest := data[i:]
+
+		if !atEOF && len(rest) < 3 && (c == '-' || c == '/') {
+			// can't tell whether this starts a comment yet
+			return 0, nil, false
+		}
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			n := quotedLen(rest, atEOF)
+
+			if n < 0 {
+				return 0, nil, false
+			}
+
+			stmt.Write(rest[:n])
+			i += n
+
+		case c == '#' || bytes.HasPrefix(rest, []byte("-- ")) || bytes.HasPrefix(rest, []byte("--\t")) ||
+			bytes.HasPrefix(rest, []byte("--\n")) || bytes.HasPrefix(rest, []byte("--\r")) || (atEOF && string(rest) == "--"):
+			n := bytes.IndexByte(rest, '\n')
+
+			if n < 0 {
+				if !atEOF {
+					return 0, nil, false
+				}
+				n = len(rest) - 1
+			}
+
+			stmt.WriteByte(' ')
+			i += n + 1
+
+		case bytes.HasPrefix(rest, []byte("/*")):
+			n := bytes.Index(rest[2:], []byte("*/"))
+
+			if n < 0 {
+				if !atEOF {
+					return 0, nil, false
+				}
+				n = len(rest) - 4
+			}
+
+			stmt.WriteByte(' ')
+			i += n + 4
+
+		case c == ';':
+			return i + 1, bytes.TrimSpace(stmt.Bytes()), true
+
+		default:
+			stmt.WriteByte(c)
+			i++
+		}
+	}
+
+	if !atEOF {
+		return 0, nil, false
+	}
+
+	return len(data), bytes.TrimSpace(stmt.Bytes()), true
+}
+
+// quotedLen returns the length of the quoted string or identifier at the start of the data given, including its quotes,
+// or -1 if more data is needed to find its end. Strings can escape characters with backslashes, and all quotes can be
+// escaped by doubling them.
+func quotedLen(data []byte, atEOF bool) int {
+	quote := data[0]
+
+	for i := 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			if i+1 == len(data) && !atEOF {
+				return -1
+			} else if i+1 < len(data) && data[i+1] == quote {
+				i++
+			} else {
+				return i + 1
+			}
+		}
+	}
+
+	if atEOF {
+		return len(data)
+	}
+
+	return -1
+}
+
+// IsSessionStatement returns whether the statement given only changes the state of a MySQL session, like the SET, LOCK
+// TABLES and USE statements that mysqldump writes around the data of each table. Dolt has no equivalent state, so these
+// statements can be ignored when running a script.
+func IsSessionStatement(query string) bool {
+	switch firstKeyword(query) {
+	case "set", "lock", "unlock", "use":
+		return true
+	default:
+		return false
+	}
+}
+
+func firstKeyword(query string) string {
+	fields := strings.Fields(query)
+
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.ToLower(fields[0])
+}
+
+// CreatedTableNames returns the names of the tables created by the CREATE TABLE statements of a SQL script, in the
+// order they're created.
+func CreatedTableNames(rd io.Reader) ([]string, error) {
+	scanner := NewStatementScanner(rd)
+
+	var names []string
+	for scanner.Scan() {
+		query := scanner.Text()
+
+		if firstKeyword(query) != "create" {
+			continue
+		}
+
+		sqlStatement, err := sqlparser.Parse(query)
+
+		if err != nil {
+			return nil, errFmt("Error parsing SQL: %v", err.Error())
+		}
+
+		if ddl, ok := sqlStatement.(*sqlparser.DDL); ok && ddl.Action == sqlparser.CreateStr {
+			names = append(names, ddl.Table.Name.String())
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// DumpImportOptions controls how the statements in a SQL dump are applied by ImportDump.
+type DumpImportOptions struct {
+	// Create creates the table from its CREATE TABLE statement in the dump. Otherwise the table must already exist and
+	// its CREATE TABLE statement is ignored.
+	Create bool
+
+	// ContOnErr skips rows that can't be inserted instead of failing.
+	ContOnErr bool
+}
+
+// ImportDump applies the statements for a single table in a SQL dump, like one written by mysqldump, and returns the
+// result with the new root value. The table's CREATE TABLE statement creates it when importing with the Create option,
+// and its INSERT and REPLACE statements are streamed through a SqlBatcher. Statements for other tables, DROP TABLE
+// statements and session statements are ignored.
+func ImportDump(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, rd io.Reader, tableName string, opts DumpImportOptions) (*InsertResult, error) {
+	scanner := NewStatementScanner(rd)
+	batcher := NewSqlBatcher(db, root)
+
+	var result InsertResult
+	created := false
+	for scanner.Scan() {
+		query := scanner.Text()
+
+		switch firstKeyword(query) {
+		case "create", "insert", "replace":
+		default:
+			continue
+		}
+
+		sqlStatement, err := sqlparser.Parse(query)
+
+		if err != nil {
+			return nil, errFmt("Error parsing SQL: %v", err.Error())
+		}
+
+		switch s := sqlStatement.(type) {
+		case *sqlparser.DDL:
+			if s.Action != sqlparser.CreateStr || s.Table.Name.String() != tableName || !opts.Create {
+				continue
+			}
+
+			root, err = batcher.Commit(ctx)
+
+			if err != nil {
+				return nil, err
+			}
+
+			root, _, err = ExecuteCreate(ctx, db, root, s, query)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if err = batcher.UpdateRoot(root); err != nil {
+				return nil, err
+			}
+
+			created = true
+
+		case *sqlparser.Insert:
+			if s.Table.Name.String() != tableName {
+				continue
+			}
+
+			if opts.Create && !created {
+				return nil, errFmt("Rows for table '%v' come before its CREATE TABLE statement", tableName)
+			}
+
+			if opts.ContOnErr {
+				s.Ignore = sqlparser.IgnoreStr
+			}
+
+			insertResult, err := ExecuteBatchInsert(ctx, root, s, batcher)
+
+			if err != nil {
+				return nil, err
+			}
+
+			result.NumRowsInserted += insertResult.NumRowsInserted
+			result.NumRowsUpdated += insertResult.NumRowsUpdated
+			result.NumErrorsIgnored += insertResult.NumErrorsIgnored
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if opts.Create && !created {
+		return nil, errFmt("No CREATE TABLE statement for table '%v' found", tableName)
+	}
+
+	newRoot, err := batcher.Commit(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.Root = newRoot
+	return &result, nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestScanStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected []string
+	}{
+		{
+			name:     "statements",
+			script:   "select 1;\nselect 2;  \n\n select 3",
+			expected: []string{"select 1", "select 2", "select 3"},
+		},
+		{
+			name:     "semicolons in quotes",
+			script:   "insert into t values ('a;b', \"c;d\");select `e;f` from t;",
+			expected: []string{"insert into t values ('a;b', \"c;d\")", "select `e;f` from t"},
+		},
+		{
+			name:     "escaped quotes",
+			script:   `insert into t values ('it\'s;', 'it''s;', "\\");select 1;`,
+			expected: []string{`insert into t values ('it\'s;', 'it''s;', "\\")`, "select 1"},
+		},
+		{
+			name:     "comments",
+			script:   "-- a comment;\nselect 1 # another;\n;/*!40101 SET NAMES utf8 */;\nselect /* inline; */ 2;--\nselect 3 - -1;",
+			expected: []string{"select 1", "select   2", "select 3 - -1"},
+		},
+		{
+			name:     "comment markers in quotes",
+			script:   "insert into t values ('-- not a comment', '/* nor this */', '#');",
+			expected: []string{"insert into t values ('-- not a comment', '/* nor this */', '#')"},
+		},
+		{
+			name:     "only comments",
+			script:   "-- nothing here\n/* at all */",
+			expected: nil,
+		},
+		{
+			name:     "unterminated string",
+			script:   "select 'abc",
+			expected: []string{"select 'abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Reading a byte at a time splits statements across reads
+			for _, rd := range []io.Reader{strings.NewReader(tt.script), iotest.OneByteReader(strings.NewReader(tt.script))} {
+				scanner := NewStatementScanner(rd)
+
+				var actual []string
+				for scanner.Scan() {
+					actual = append(actual, scanner.Text())
+				}
+
+				require.NoError(t, scanner.Err())
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+const testDump = "-- MySQL dump 10.13\n" +
+	"/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;\n" +
+	"SET NAMES utf8mb4;\n" +
+	"DROP TABLE IF EXISTS `characters`;\n" +
+	"CREATE TABLE `characters` (\n" +
+	"  `id` int(11) NOT NULL,\n" +
+	"  `name` varchar(255) DEFAULT NULL,\n" +
+	"  PRIMARY KEY (`id`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+	"LOCK TABLES `characters` WRITE;\n" +
+	"INSERT INTO `characters` VALUES (1,'Homer; Simpson'),(2,'Marge \\'Bouvier\\''),(3,NULL);\n" +
+	"UNLOCK TABLES;\n" +
+	"DROP TABLE IF EXISTS `locations`;\n" +
+	"CREATE TABLE `locations` (`id` int(11) NOT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB;\n" +
+	"INSERT INTO `locations` VALUES (1);\n"
+
+func TestImportDump(t *testing.T) {
+	tests := []struct {
+		name          string
+		dump          string
+		opts          DumpImportOptions
+		existing      string
+		expectedNames map[int64]string
+		expectedErr   string
+	}{
+		{
+			name:          "create",
+			dump:          testDump,
+			opts:          DumpImportOptions{Create: true},
+			expectedNames: map[int64]string{1: "Homer; Simpson", 2: "Marge 'Bouvier'", 3: ""},
+		},
+		{
+			name:          "update",
+			dump:          testDump,
+			existing:      "insert into characters values (4, 'Bart');",
+			expectedNames: map[int64]string{1: "Homer; Simpson", 2: "Marge 'Bouvier'", 3: "", 4: "Bart"},
+		},
+		{
+			name:        "update duplicate key",
+			dump:        testDump,
+			existing:    "insert into characters values (1, 'Bart');",
+			expectedErr: "Duplicate primary key",
+		},
+		{
+			name:          "update continuing on errors",
+			dump:          testDump,
+			opts:          DumpImportOptions{ContOnErr: true},
+			existing:      "insert into characters values (1, 'Bart');",
+			expectedNames: map[int64]string{1: "Bart", 2: "Marge 'Bouvier'", 3: ""},
+		},
+		{
+			name:        "missing create",
+			dump:        "INSERT INTO `locations` VALUES (1);",
+			opts:        DumpImportOptions{Create: true},
+			expectedErr: "No CREATE TABLE statement for table 'characters' found",
+		},
+		{
+			name:        "rows before create",
+			dump:        "INSERT INTO `characters` VALUES (1, 'Homer');\nCREATE TABLE `characters` (`id` int NOT NULL, `name` varchar(255), PRIMARY KEY (`id`));",
+			opts:        DumpImportOptions{Create: true},
+			expectedErr: "Rows for table 'characters' come before its CREATE TABLE statement",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			ctx := context.Background()
+			root, err := dEnv.WorkingRoot(ctx)
+			require.NoError(t, err)
+
+			if tt.existing != "" {
+				existing := "create table characters (id int primary key, name varchar(255));" + tt.existing
+				result, err := ImportDump(ctx, dEnv.DoltDB, root, strings.NewReader(existing), "characters", DumpImportOptions{Create: true})
+				require.NoError(t, err)
+				root = result.Root
+			}
+
+			result, err := ImportDump(ctx, dEnv.DoltDB, root, strings.NewReader(tt.dump), "characters", tt.opts)
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			has, err := result.Root.HasTable(ctx, "locations")
+			require.NoError(t, err)
+			assert.False(t, has)
+
+			rows, err := GetAllRows(result.Root, "characters")
+			require.NoError(t, err)
+
+			names := make(map[int64]string)
+			for _, r := range rows {
+				id, _ := r.GetColVal(0)
+				name, ok := r.GetColVal(1)
+
+				if ok {
+					names[int64(id.(types.Int))] = string(name.(types.String))
+				} else {
+					names[int64(id.(types.Int))] = ""
+				}
+			}
+
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestCreatedTableNames(t *testing.T) {
+	names, err := CreatedTableNames(strings.NewReader(testDump))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"characters", "locations"}, names)
+
+	names, err = CreatedTableNames(strings.NewReader("INSERT INTO `locations` VALUES (1);"))
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
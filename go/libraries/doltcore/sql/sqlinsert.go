@@ -24,6 +24,7 @@ import (
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/rowconv"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
@@ -103,6 +104,11 @@ func ExecuteBatchInsert(
 	var result InsertResult
 	opt := InsertOptions{replace}
 	for _, r := range rows {
+		r, err := batcher.AutoIncrement(ctx, tableName, r)
+		if err != nil {
+			return nil, err
+		}
+
 		if has, err := row.IsValid(r, tableSch); err != nil {
 			return nil, err
 		} else if !has {
@@ -203,6 +209,10 @@ func prepareInsertVals(nbf *types.NomsBinFormat, cols []schema.Column, values *s
 	// Lack of primary keys is its own special kind of failure that we can detect before creating any rows
 	allKeysFound := true
 	err := tableSch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if col.AutoIncrement {
+			return false, nil
+		}
+
 		for _, insertCol := range cols {
 			if insertCol.Tag == tag {
 				return false, nil
@@ -220,10 +230,15 @@ func prepareInsertVals(nbf *types.NomsBinFormat, cols []schema.Column, values *s
 		return nil, ErrMissingPrimaryKeys
 	}
 
+	defaults, err := rowconv.ColumnDefaults(tableSch)
+	if err != nil {
+		return nil, err
+	}
+
 	rows := make([]row.Row, len(*values))
 
 	for i, valTuple := range *values {
-		r, err := makeRow(nbf, cols, tableSch, valTuple)
+		r, err := makeRow(nbf, cols, tableSch, defaults, valTuple)
 		if err != nil {
 			return nil, err
 		}
@@ -233,17 +248,26 @@ func prepareInsertVals(nbf *types.NomsBinFormat, cols []schema.Column, values *s
 	return rows, nil
 }
 
-func makeRow(nbf *types.NomsBinFormat, columns []schema.Column, tableSch schema.Schema, tuple sqlparser.ValTuple) (row.Row, error) {
+// makeRow returns a row for the values given, setting the columns of the table not named to their default values.
+func makeRow(nbf *types.NomsBinFormat, columns []schema.Column, tableSch schema.Schema, defaults row.TaggedValues, tuple sqlparser.ValTuple) (row.Row, error) {
 	if len(columns) != len(tuple) {
 		return errInsertRow("Wrong number of values for tuple %v", nodeToString(tuple))
 	}
 
 	taggedVals := make(row.TaggedValues)
+	for tag, val := range defaults {
+		taggedVals[tag] = val
+	}
+
+	for _, column := range columns {
+		delete(taggedVals, column.Tag)
+	}
+
 	for i, expr := range tuple {
 		column := columns[i]
 		switch val := expr.(type) {
 		case *sqlparser.SQLVal:
-			nomsVal, err := extractNomsValueFromSQLVal(val, column.Kind)
+			nomsVal, err := extractColumnValueFromSQLVal(val, column)
 			if err != nil {
 				return nil, err
 			}
@@ -311,7 +335,9 @@ func makeRow(nbf *types.NomsBinFormat, columns []schema.Column, tableSch schema.
 		case *sqlparser.GroupConcatExpr:
 			return errInsertRow("Group concat expressions not supported in insert values: %v", nodeToString(tuple))
 		case *sqlparser.Default:
-			return errInsertRow("Unrecognized expression: %v", nodeToString(tuple))
+			if val, ok := defaults[column.Tag]; ok {
+				taggedVals[column.Tag] = val
+			}
 		default:
 			return errInsertRow("Unrecognized expression: %v", nodeToString(tuple))
 		}
@@ -354,6 +354,195 @@ func TestExecuteInsert(t *testing.T) {
 	}
 }
 
+func TestExecuteInsertWithDefaults(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		expectedValue types.Value
+	}{
+		{
+			name:          "column omitted",
+			query:         `insert into people (id, first, last) values (7, "Maggie", "Simpson")`,
+			expectedValue: types.Int(7),
+		},
+		{
+			name:          "default keyword",
+			query:         `insert into people (id, first, last, lucky_num) values (7, "Maggie", "Simpson", default)`,
+			expectedValue: types.Int(7),
+		},
+		{
+			name:          "explicit value",
+			query:         `insert into people (id, first, last, lucky_num) values (7, "Maggie", "Simpson", 13)`,
+			expectedValue: types.Int(13),
+		},
+		{
+			name:  "explicit null",
+			query: `insert into people (id, first, last, lucky_num) values (7, "Maggie", "Simpson", null)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			ctx := context.Background()
+
+			CreateTestDatabase(dEnv, t)
+			root, _ := dEnv.WorkingRoot(ctx)
+
+			alter := "alter table people add (lucky_num int default 3+4 comment 'tag:100')"
+			sqlStatement, err := sqlparser.Parse(alter)
+			require.NoError(t, err)
+
+			root, err = ExecuteAlter(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.DDL), alter)
+			require.NoError(t, err)
+
+			sqlStatement, err = sqlparser.Parse(tt.query)
+			require.NoError(t, err)
+
+			result, err := ExecuteInsert(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.Insert))
+			require.NoError(t, err)
+			assert.Equal(t, 1, result.NumRowsInserted)
+
+			table, _, err := result.Root.GetTable(ctx, PeopleTableName)
+			require.NoError(t, err)
+			sch, err := table.GetSchema(ctx)
+			require.NoError(t, err)
+
+			col, ok := sch.GetAllCols().GetByTag(100)
+			require.True(t, ok)
+			// expressions other than literals are persisted as the literal of their value
+			assert.Equal(t, "7", col.Default)
+
+			key, err := NewPeopleRow(7, "Maggie", "Simpson", false, 0, 0).NomsMapKey(sch).Value(ctx)
+			require.NoError(t, err)
+			foundRow, ok, err := table.GetRow(ctx, key.(types.Tuple), sch)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			val, _ := foundRow.GetColVal(100)
+			assert.Equal(t, tt.expectedValue, val)
+		})
+	}
+}
+
+func TestExecuteInsertJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		expectedValue types.Value
+		expectedErr   bool
+	}{
+		{
+			name:          "document",
+			query:         `insert into people (id, first, last, metadata) values (7, "Maggie", "Simpson", '{"pacifier": true, "age": 1}')`,
+			expectedValue: types.String(`{"age":1,"pacifier":true}`),
+		},
+		{
+			name:          "scalar document",
+			query:         `insert into people (id, first, last, metadata) values (7, "Maggie", "Simpson", '"baby"')`,
+			expectedValue: types.String(`"baby"`),
+		},
+		{
+			name:        "invalid document",
+			query:       `insert into people (id, first, last, metadata) values (7, "Maggie", "Simpson", '{"age":')`,
+			expectedErr: true,
+		},
+		{
+			name:        "non-string value",
+			query:       `insert into people (id, first, last, metadata) values (7, "Maggie", "Simpson", 1)`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			ctx := context.Background()
+
+			CreateTestDatabase(dEnv, t)
+			root, _ := dEnv.WorkingRoot(ctx)
+
+			alter := "alter table people add (metadata json comment 'tag:100')"
+			sqlStatement, err := sqlparser.Parse(alter)
+			require.NoError(t, err)
+
+			root, err = ExecuteAlter(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.DDL), alter)
+			require.NoError(t, err)
+
+			sqlStatement, err = sqlparser.Parse(tt.query)
+			require.NoError(t, err)
+
+			result, err := ExecuteInsert(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.Insert))
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			table, _, err := result.Root.GetTable(ctx, PeopleTableName)
+			require.NoError(t, err)
+			sch, err := table.GetSchema(ctx)
+			require.NoError(t, err)
+
+			key, err := NewPeopleRow(7, "Maggie", "Simpson", false, 0, 0).NomsMapKey(sch).Value(ctx)
+			require.NoError(t, err)
+			foundRow, ok, err := table.GetRow(ctx, key.(types.Tuple), sch)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			val, _ := foundRow.GetColVal(100)
+			assert.Equal(t, tt.expectedValue, val)
+		})
+	}
+}
+
+func TestExecuteInsertAutoIncrement(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	ctx := context.Background()
+	root, _ := dEnv.WorkingRoot(ctx)
+
+	create := "create table things (id bigint auto_increment primary key, name varchar(20))"
+	sqlStatement, err := sqlparser.Parse(create)
+	require.NoError(t, err)
+
+	root, _, err = ExecuteCreate(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.DDL), create)
+	require.NoError(t, err)
+
+	queries := []string{
+		`insert into things (name) values ("a"), ("b")`,
+		`insert into things (id, name) values (10, "c")`,
+		`insert into things (id, name) values (null, "d"), (0, "e")`,
+	}
+
+	for _, query := range queries {
+		sqlStatement, err = sqlparser.Parse(query)
+		require.NoError(t, err)
+
+		result, err := ExecuteInsert(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.Insert))
+		require.NoError(t, err)
+		root = result.Root
+	}
+
+	table, _, err := root.GetTable(ctx, "things")
+	require.NoError(t, err)
+	sch, err := table.GetSchema(ctx)
+	require.NoError(t, err)
+
+	expected := map[int64]string{1: "a", 2: "b", 10: "c", 11: "d", 12: "e"}
+	for id, name := range expected {
+		r, ok, err := table.GetRowByPKVals(ctx, row.TaggedValues{0: types.Int(id)}, sch)
+		require.NoError(t, err)
+		require.True(t, ok, "row %d not found", id)
+
+		val, _ := r.GetColVal(1)
+		assert.Equal(t, types.String(name), val)
+	}
+
+	next, err := table.GetAutoIncrementValue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(13), next)
+}
+
 func rowsEqual(expected, actual row.Row) (bool, string) {
 	er, ar := make(map[uint64]types.Value), make(map[uint64]types.Value)
 	_, err := expected.IterCols(func(t uint64, v types.Value) (bool, error) {
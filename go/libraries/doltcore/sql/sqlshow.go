@@ -116,7 +116,11 @@ func BuildShowPipeline(ctx context.Context, root *doltdb.RootValue, show *sqlpar
 			return nil, nil, err
 		}
 
-		schemaStr := SchemaAsCreateStmt(tableName, sch)
+		schemaStr, err := SchemaAsCreateStmtWithForeignKeys(ctx, root, tableName, sch)
+
+		if err != nil {
+			return nil, nil, err
+		}
 
 		resultSch := showCreateTableSchema()
 		rows, err := toRows(root.VRW().Format(), ([][]string{{tableName, schemaStr}}), resultSch)
@@ -216,14 +220,22 @@ func describeColumn(nbf *types.NomsBinFormat, col schema.Column) (row.Row, error
 	if col.IsPartOfPK {
 		keyStr = "PRI"
 	}
+	defaultStr := "NULL"
+	if col.HasDefault() {
+		defaultStr = col.Default
+	}
+	extraStr := ""
+	if col.AutoIncrement {
+		extraStr = "auto_increment"
+	}
 
 	taggedVals := row.TaggedValues{
 		0: types.String(col.Name),
-		1: types.String(DoltToSQLType[col.Kind]),
+		1: types.String(ColumnSQLType(col)),
 		2: types.String(nullStr),
 		3: types.String(keyStr),
-		4: types.String("NULL"), // TODO: when schemas store defaults, use them here
-		5: types.String(""),     // Extra column reserved for future use
+		4: types.String(defaultStr),
+		5: types.String(extraStr),
 	}
 	return row.New(nbf, showColumnsSchema(), taggedVals)
 }
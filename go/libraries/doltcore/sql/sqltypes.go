@@ -15,6 +15,7 @@
 package sql
 
 import (
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -27,6 +28,15 @@ var DoltToSQLType = map[types.NomsKind]string{
 	types.UUIDKind:   UUID,
 }
 
+// ColumnSQLType returns the SQL type of the column given.
+func ColumnSQLType(col schema.Column) string {
+	if col.IsJSON() {
+		return JSON
+	}
+
+	return DoltToSQLType[col.Kind]
+}
+
 // TypeConversionFn is a function that converts one noms value to another of a different type in a guaranteed fashion,
 // i.e. any conversion that could possibly fail (e.g. string -> int) are not allowed. Only SQL-safe conversions are
 // allowed, even if they are guaranteed to be safe, so that e.g. there is no way to convert a numeric type to a string.
@@ -27,6 +27,7 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/noms"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/resultset"
+	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
 type UpdateResult struct {
@@ -101,9 +102,19 @@ func ExecuteUpdate(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValu
 		}
 
 		// TODO: support aliases, multiple table updates
-		getter, err := getterFor(update.Expr, schemas, aliases)
-		if err != nil {
+		var getter *RowValGetter
+		if sqlVal, ok := update.Expr.(*sqlparser.SQLVal); ok && column.IsJSON() {
+			// JSON documents have no literal syntax, so parse string literals as JSON text
+			doc, err := extractColumnValueFromSQLVal(sqlVal, column)
+			if err != nil {
+				return errUpdate(err.Error())
+			}
+			getter = LiteralValueGetter(doc)
+		} else if getter, err = getterFor(update.Expr, schemas, aliases); err != nil {
 			return nil, err
+		} else if column.IsJSON() && getter.NomsKind != types.NullKind {
+			// Other expressions of JSON columns' string kind could produce text that isn't JSON
+			return errUpdate("Type mismatch: only JSON text can be assigned to JSON column '%v'", colName)
 		}
 
 		if getter.NomsKind != column.Kind {
@@ -219,6 +230,10 @@ func ExecuteUpdate(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValu
 		return nil, err
 	}
 
+	if err = result.Root.ValidateForeignKeyChanges(ctx, root, tableName); err != nil {
+		return nil, err
+	}
+
 	return &result, nil
 }
 
@@ -21,6 +21,7 @@ import (
 	"github.com/google/uuid"
 	"vitess.io/vitess/go/vt/sqlparser"
 
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/store/chunks"
@@ -685,6 +686,20 @@ func divineNomsValueFromSQLVal(val *sqlparser.SQLVal) (types.Value, error) {
 	}
 }
 
+// extractColumnValueFromSQLVal extracts a noms value for the column given from the given SQLVal. Like
+// extractNomsValueFromSQLVal, but JSON columns parse string values as JSON text.
+func extractColumnValueFromSQLVal(val *sqlparser.SQLVal, column schema.Column) (types.Value, error) {
+	if column.IsJSON() && val.Type == sqlparser.StrVal {
+		doc, err := doltcore.ParseJSON(string(val.Val))
+		if err != nil {
+			return nil, errFmt("Invalid JSON text: %v", nodeToString(val))
+		}
+		return doc, nil
+	}
+
+	return extractNomsValueFromSQLVal(val, column.Kind)
+}
+
 // extractNomsValueFromSQLVal extracts a noms value from the given SQLVal, using type info in the dolt column given as
 // a hint and for type-checking
 func extractNomsValueFromSQLVal(val *sqlparser.SQLVal, kind types.NomsKind) (types.Value, error) {
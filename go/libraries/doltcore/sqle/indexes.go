@@ -20,8 +20,10 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/google/uuid"
 	"github.com/src-d/go-mysql-server/sql"
 
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/store/types"
@@ -101,7 +103,13 @@ func keyColsToTuple(sch schema.Schema, key []interface{}) (row.TaggedValues, err
 	var i int
 	taggedVals := make(row.TaggedValues)
 	err := sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
-		taggedVals[tag] = keyColToValue(key[i], col)
+		val, err := keyColToValue(key[i], col)
+
+		if err != nil {
+			return true, err
+		}
+
+		taggedVals[tag] = val
 		i++
 		return false, nil
 	})
@@ -113,24 +121,43 @@ func keyColsToTuple(sch schema.Schema, key []interface{}) (row.TaggedValues, err
 	return taggedVals, nil
 }
 
-func keyColToValue(v interface{}, column schema.Column) types.Value {
+func keyColToValue(v interface{}, column schema.Column) (types.Value, error) {
+	// JSON documents are decoded values, stored as their canonical text
+	if column.IsJSON() {
+		return doltcore.JSONFromGo(v)
+	}
+
 	// TODO: type conversion
 	switch column.Kind {
 	case types.BoolKind:
-		return types.Bool(v.(bool))
+		if b, ok := v.(bool); ok {
+			return types.Bool(b), nil
+		}
 	case types.IntKind:
-		return types.Int(v.(int64))
+		if i, ok := v.(int64); ok {
+			return types.Int(i), nil
+		}
 	case types.FloatKind:
-		return types.Float(v.(float64))
+		if f, ok := v.(float64); ok {
+			return types.Float(f), nil
+		}
 	case types.UintKind:
-		return types.Uint(v.(uint64))
+		if u, ok := v.(uint64); ok {
+			return types.Uint(u), nil
+		}
 	case types.UUIDKind:
-		panic("Implement me")
+		if s, ok := v.(string); ok {
+			if id, err := uuid.Parse(s); err == nil {
+				return types.UUID(id), nil
+			}
+		}
 	case types.StringKind:
-		return types.String(v.(string))
-	default:
-		panic("unhandled type")
+		if s, ok := v.(string); ok {
+			return types.String(s), nil
+		}
 	}
+
+	return nil, fmt.Errorf("unsupported value %v of type %T for key column %s of type %s", v, v, column.Name, column.TypeString())
 }
 
 func (*doltIndex) Has(partition sql.Partition, key ...interface{}) (bool, error) {
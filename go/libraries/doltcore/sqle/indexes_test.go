@@ -0,0 +1,69 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestKeyColToValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		val         interface{}
+		col         schema.Column
+		expected    types.Value
+		expectedErr bool
+	}{
+		{"int", int64(1), schema.NewColumn("id", 0, types.IntKind, true), types.Int(1), false},
+		{"string", "a", schema.NewColumn("id", 0, types.StringKind, true), types.String("a"), false},
+		{
+			"uuid",
+			"01234567-89ab-cdef-0123-456789abcdef",
+			schema.NewColumn("id", 0, types.UUIDKind, true),
+			types.UUID([16]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}),
+			false,
+		},
+		{
+			"json",
+			map[string]interface{}{"b": json.Number("1"), "a": "x"},
+			schema.NewJSONColumn("id", 0, true),
+			types.String(`{"a":"x","b":1}`),
+			false,
+		},
+		{"wrong type", "1", schema.NewColumn("id", 0, types.IntKind, true), nil, true},
+		{"invalid uuid", "abc", schema.NewColumn("id", 0, types.UUIDKind, true), nil, true},
+		{"unsupported kind", []byte("abc"), schema.NewColumn("id", 0, types.BlobKind, true), nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			val, err := keyColToValue(test.val, test.col)
+
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, val)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration_tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle/sqletestutil"
+)
+
+const createJSONTable = `
+CREATE TABLE metadata (
+  id int primary key,
+  doc json
+);
+INSERT INTO metadata VALUES (1, '{"name": "bart", "tags": ["a", "b"], "stats": {"age": 10}}');
+INSERT INTO metadata VALUES (2, '{"name": "lisa", "tags": [], "stats": {"age": 8}}');
+`
+
+func TestJSONExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []sql.Row
+	}{
+		{
+			name:     "select document",
+			query:    `select doc from metadata where id = 2`,
+			expected: []sql.Row{{map[string]interface{}{"name": "lisa", "tags": []interface{}{}, "stats": map[string]interface{}{"age": json.Number("8")}}}},
+		},
+		{
+			name:     "extract field",
+			query:    `select id, json_extract(doc, '$.name') from metadata order by id`,
+			expected: []sql.Row{{int64(1), "bart"}, {int64(2), "lisa"}},
+		},
+		{
+			name:     "extract nested field",
+			query:    `select json_extract(doc, '$.stats.age') from metadata where id = 1`,
+			expected: []sql.Row{{float64(10)}},
+		},
+		{
+			name:     "extract array element",
+			query:    `select json_extract(doc, '$.tags[1]') from metadata where id = 1`,
+			expected: []sql.Row{{"b"}},
+		},
+	}
+
+	dEnv := dtestutils.CreateTestEnv()
+	root, _ := dEnv.WorkingRoot(context.Background())
+	root, err := sqletestutil.ExecuteSql(dEnv, root, createJSONTable)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := sqletestutil.ExecuteSelect(root, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rows)
+		})
+	}
+}
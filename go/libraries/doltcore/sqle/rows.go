@@ -19,6 +19,7 @@ import (
 
 	"github.com/src-d/go-mysql-server/sql"
 
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/store/types"
@@ -83,7 +84,13 @@ func doltRowToSqlRow(doltRow row.Row, sch schema.Schema) (sql.Row, error) {
 	i := 0
 	err := sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		value, _ := doltRow.GetColVal(tag)
-		colVals[i] = doltColValToSqlColVal(value)
+		colVal, err := doltColValToSqlColVal(value, col)
+
+		if err != nil {
+			return true, err
+		}
+
+		colVals[i] = colVal
 		i++
 		return false, nil
 	})
@@ -99,7 +106,20 @@ func doltRowToSqlRow(doltRow row.Row, sch schema.Schema) (sql.Row, error) {
 func SqlRowToDoltRow(nbf *types.NomsBinFormat, r sql.Row, doltSchema schema.Schema) (row.Row, error) {
 	taggedVals := make(row.TaggedValues)
 	for i, val := range r {
-		if val != nil {
+		if val == nil {
+			continue
+		}
+
+		// JSON values are decoded documents, which can't be told apart from other values without the schema
+		if col, ok := doltSchema.GetAllCols().GetByTag(uint64(i)); ok && col.IsJSON() {
+			doc, err := doltcore.JSONFromGo(val)
+
+			if err != nil {
+				return nil, err
+			}
+
+			taggedVals[uint64(i)] = doc
+		} else {
 			taggedVals[uint64(i)] = SqlValToNomsVal(val)
 		}
 	}
@@ -107,10 +127,15 @@ func SqlRowToDoltRow(nbf *types.NomsBinFormat, r sql.Row, doltSchema schema.Sche
 	return row.New(nbf, doltSchema, taggedVals)
 }
 
-// Returns the column value for a SQL column
-func doltColValToSqlColVal(val types.Value) interface{} {
+// Returns the column value for a SQL column. JSON documents are decoded into the generic values used by encoding/json,
+// which is how the engine represents JSON values.
+func doltColValToSqlColVal(val types.Value, col schema.Column) (interface{}, error) {
 	if types.IsNull(val) {
-		return nil
+		return nil, nil
+	}
+
+	if col.IsJSON() {
+		return doltcore.JSONToGo(val.(types.String))
 	}
 
 	return nomsValToSqlVal(val)
@@ -52,7 +52,7 @@ func SqlSchemaToDoltSchema(sqlSchema sql.Schema) schema.Schema {
 func doltColToSqlCol(tableName string, col schema.Column) *sql.Column {
 	return &sql.Column{
 		Name:     col.Name,
-		Type:     nomsTypeToSqlType(col.Kind),
+		Type:     doltColToSqlType(col),
 		Default:  nil,
 		Nullable: col.IsNullable(),
 		Source:   tableName,
@@ -62,5 +62,9 @@ func doltColToSqlCol(tableName string, col schema.Column) *sql.Column {
 // doltColToSqlCol returns the dolt column corresponding to the SQL column given
 func SqlColToDoltCol(tag uint64, isPk bool, col *sql.Column) schema.Column {
 	// TODO: nullness constraint
+	if col.Type == sql.JSON {
+		return schema.NewJSONColumn(col.Name, tag, isPk)
+	}
+
 	return schema.NewColumn(col.Name, tag, SqlTypeToNomsKind(col.Type), isPk)
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/src-d/go-mysql-server/sql"
 
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -43,6 +44,15 @@ func nomsTypeToSqlType(kind types.NomsKind) sql.Type {
 	}
 }
 
+// doltColToSqlType returns the SQL type of the column given.
+func doltColToSqlType(col schema.Column) sql.Type {
+	if col.IsJSON() {
+		return sql.JSON
+	}
+
+	return nomsTypeToSqlType(col.Kind)
+}
+
 func SqlTypeToNomsKind(t sql.Type) types.NomsKind {
 	switch t {
 	case sql.Boolean:
@@ -56,25 +66,28 @@ func SqlTypeToNomsKind(t sql.Type) types.NomsKind {
 		return types.IntKind
 	case sql.Uint64:
 		return types.UintKind
+	case sql.JSON:
+		// JSON documents are stored as JSON text
+		return types.StringKind
 	default:
 		panic(fmt.Sprintf("Unexpected type %v", t))
 	}
 }
 
-func nomsValToSqlVal(val types.Value) interface{} {
+func nomsValToSqlVal(val types.Value) (interface{}, error) {
 	switch val.Kind() {
 	case types.BoolKind:
-		return convertBool(val.(types.Bool))
+		return convertBool(val.(types.Bool)), nil
 	case types.FloatKind:
-		return convertFloat(val.(types.Float))
+		return convertFloat(val.(types.Float)), nil
 	case types.StringKind:
-		return convertString(val.(types.String))
+		return convertString(val.(types.String)), nil
 	case types.UUIDKind:
-		return convertUUID(val.(types.UUID))
+		return convertUUID(val.(types.UUID)), nil
 	case types.IntKind:
-		return convertInt(val.(types.Int))
+		return convertInt(val.(types.Int)), nil
 	case types.UintKind:
-		return convertUint(val.(types.Uint))
+		return convertUint(val.(types.Uint)), nil
 	default:
 		panic(fmt.Sprintf("Unexpected kind %v", val.Kind()))
 	}
@@ -24,6 +24,12 @@ type TransformRowFailure struct {
 	Row           row.Row
 	TransformName string
 	Details       string
+
+	// Props are the properties of the row that failed, which are empty for rows that failed to be read
+	Props ImmutableProperties
+
+	// Cause is the error the row failed with, which may describe the failure in more detail than Details
+	Cause error
 }
 
 // Error returns a string containing details of the error that occurred
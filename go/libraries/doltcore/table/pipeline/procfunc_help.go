@@ -50,7 +50,7 @@ func ProcFuncForSourceFunc(sourceFunc SourceFunc) InFunc {
 						return
 					}
 				} else if table.IsBadRow(err) {
-					badRowChan <- &TransformRowFailure{table.GetBadRowRow(err), "reader", err.Error()}
+					badRowChan <- &TransformRowFailure{Row: table.GetBadRowRow(err), TransformName: "reader", Details: err.Error(), Props: props, Cause: err}
 				} else {
 					p.StopWithErr(err)
 					return
@@ -101,7 +101,7 @@ func ProcFuncForSinkFunc(sinkFunc SinkFunc) OutFunc {
 
 					if err != nil {
 						if table.IsBadRow(err) {
-							badRowChan <- &TransformRowFailure{r.Row, "writer", err.Error()}
+							badRowChan <- &TransformRowFailure{Row: r.Row, TransformName: "writer", Details: err.Error(), Props: r.Props, Cause: err}
 						} else {
 							p.StopWithErr(err)
 							return
@@ -15,6 +15,8 @@
 package pipeline
 
 import (
+	"errors"
+
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 )
 
@@ -33,6 +35,20 @@ type NamedTransform struct {
 // will have its Func member set to be a TransformFunc that handles input, output, and stop channel processing, along
 // with error handling and it will call the given TransformRowFunc for every row.
 func NewNamedTransform(name string, transRowFunc TransformRowFunc) NamedTransform {
+	return NewNamedErrTransform(name, func(inRow row.Row, props ReadableMap) ([]*TransformedRowResult, error) {
+		rowData, badRowDetails := transRowFunc(inRow, props)
+
+		if badRowDetails != "" {
+			return rowData, errors.New(badRowDetails)
+		}
+
+		return rowData, nil
+	})
+}
+
+// NewNamedErrTransform is like NewNamedTransform, but takes a TransformRowErrFunc, whose errors are kept as the Cause of
+// the TransformRowFailures for the rows that fail.
+func NewNamedErrTransform(name string, transRowFunc TransformRowErrFunc) NamedTransform {
 	transformer := newRowTransformer(name, transRowFunc)
 	return NamedTransform{name, transformer}
 }
@@ -55,7 +71,10 @@ type TransformFunc func(inChan <-chan RowWithProps, outChan chan<- RowWithProps,
 // the row being processed is bad it should return nil, and a string containing details of the row problem.
 type TransformRowFunc func(inRow row.Row, props ReadableMap) (rowData []*TransformedRowResult, badRowDetails string)
 
-func newRowTransformer(name string, transRowFunc TransformRowFunc) TransformFunc {
+// TransformRowErrFunc is like TransformRowFunc, but returns an error for a bad row rather than a string of its details.
+type TransformRowErrFunc func(inRow row.Row, props ReadableMap) (rowData []*TransformedRowResult, err error)
+
+func newRowTransformer(name string, transRowFunc TransformRowErrFunc) TransformFunc {
 	return func(inChan <-chan RowWithProps, outChan chan<- RowWithProps, badRowChan chan<- *TransformRowFailure, stopChan <-chan struct{}) {
 		for {
 			select {
@@ -67,7 +86,7 @@ func newRowTransformer(name string, transRowFunc TransformRowFunc) TransformFunc
 			select {
 			case r, ok := <-inChan:
 				if ok {
-					outRowData, badRowDetails := transRowFunc(r.Row, r.Props)
+					outRowData, err := transRowFunc(r.Row, r.Props)
 					outSize := len(outRowData)
 
 					for i := 0; i < outSize; i++ {
@@ -82,8 +101,8 @@ func newRowTransformer(name string, transRowFunc TransformRowFunc) TransformFunc
 						outChan <- outRow
 					}
 
-					if badRowDetails != "" {
-						badRowChan <- &TransformRowFailure{r.Row, name, badRowDetails}
+					if err != nil {
+						badRowChan <- &TransformRowFailure{Row: r.Row, TransformName: name, Details: err.Error(), Props: r.Props, Cause: err}
 					}
 				} else {
 					return
@@ -0,0 +1,306 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// DefaultNDJSONSampleSize is the default number of lines read to find the fields of a newline delimited json file
+const DefaultNDJSONSampleSize = 10000
+
+// NDJSONFileInfo describes how a newline delimited json file is read
+type NDJSONFileInfo struct {
+	// Paths are dotted paths to values nested within objects, like address.city, that are read as columns named by
+	// their path in addition to the top level fields of each object.
+	Paths []string
+
+	// SampleSize is the number of lines read to find the top level fields of the objects in the file. A line with a
+	// field that isn't in any of the sampled lines is a bad row.
+	SampleSize int
+}
+
+func NewNDJSONInfo() *NDJSONFileInfo {
+	return &NDJSONFileInfo{nil, DefaultNDJSONSampleSize}
+}
+
+func (info *NDJSONFileInfo) SetPaths(paths []string) *NDJSONFileInfo {
+	info.Paths = paths
+	return info
+}
+
+func (info *NDJSONFileInfo) SetSampleSize(sampleSize int) *NDJSONFileInfo {
+	info.SampleSize = sampleSize
+	return info
+}
+
+// ndjsonLine is a parsed line of a newline delimited json file
+type ndjsonLine struct {
+	obj map[string]interface{}
+	err error
+}
+
+// NDJSONReader reads a newline delimited json file, which has a json object on each line, and returns untyped rows
+// with the string values of the fields of each object. Nested objects and arrays are read as their json text.
+type NDJSONReader struct {
+	closer  io.Closer
+	bRd     *bufio.Reader
+	nbf     *types.NomsBinFormat
+	sch     schema.Schema
+	cols    []string
+	tags    map[string]uint64
+	sampled []ndjsonLine
+	isDone  bool
+}
+
+func OpenNDJSONReader(nbf *types.NomsBinFormat, path string, fs filesys.ReadableFS, info *NDJSONFileInfo) (*NDJSONReader, error) {
+	r, err := fs.OpenForRead(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNDJSONReader(nbf, r, info)
+}
+
+// NewNDJSONReader returns a reader for the newline delimited json read from r. The first lines are read to find the
+// fields of the objects in the file, which are the columns of the rows read.
+func NewNDJSONReader(nbf *types.NomsBinFormat, r io.ReadCloser, info *NDJSONFileInfo) (*NDJSONReader, error) {
+	ndjr := &NDJSONReader{closer: r, bRd: bufio.NewReaderSize(r, ReadBufSize), nbf: nbf}
+
+	seen := make(map[string]bool)
+	for len(ndjr.sampled) < info.SampleSize && !ndjr.isDone {
+		line, err := ndjr.readLine()
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			r.Close()
+			return nil, err
+		}
+
+		keys, obj, err := parseObject(line)
+		ndjr.sampled = append(ndjr.sampled, ndjsonLine{obj, err})
+
+		for _, key := range keys {
+			if !seen[key] {
+				seen[key] = true
+				ndjr.cols = append(ndjr.cols, key)
+			}
+		}
+	}
+
+	for _, path := range info.Paths {
+		if !seen[path] {
+			seen[path] = true
+			ndjr.cols = append(ndjr.cols, path)
+		}
+	}
+
+	if len(ndjr.cols) == 0 {
+		r.Close()
+		return nil, errors.New("no fields were found in the first lines of the file")
+	}
+
+	ndjr.tags, ndjr.sch = untyped.NewUntypedSchema(ndjr.cols...)
+
+	return ndjr, nil
+}
+
+// readLine returns the next line that isn't empty
+func (ndjr *NDJSONReader) readLine() (string, error) {
+	for !ndjr.isDone {
+		line, err := ndjr.bRd.ReadString('\n')
+
+		if err == io.EOF {
+			ndjr.isDone = true
+		} else if err != nil {
+			return "", err
+		}
+
+		if line = strings.TrimSpace(line); line != "" {
+			return line, nil
+		}
+	}
+
+	return "", io.EOF
+}
+
+// parseObject parses a line holding a json object, returning its top level fields in the order they appear.
+func parseObject(line string) ([]string, map[string]interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, table.NewBadRow(nil, "line isn't a json object", "line: "+line)
+	}
+
+	var keys []string
+	obj := make(map[string]interface{})
+	for dec.More() {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return nil, nil, table.NewBadRow(nil, err.Error(), "line: "+line)
+		}
+
+		key := tok.(string)
+
+		var val interface{}
+		if err := dec.Decode(&val); err != nil {
+			return nil, nil, table.NewBadRow(nil, err.Error(), "line: "+line)
+		}
+
+		keys = append(keys, key)
+		obj[key] = val
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, table.NewBadRow(nil, err.Error(), "line: "+line)
+	} else if dec.More() {
+		return nil, nil, table.NewBadRow(nil, "line has more than one json value", "line: "+line)
+	}
+
+	return keys, obj, nil
+}
+
+// GetSchema gets the schema of the rows that this reader will return
+func (ndjr *NDJSONReader) GetSchema() schema.Schema {
+	return ndjr.sch
+}
+
+// ReadRow reads a row from a table. If there is a bad row the returned error will be non nil, and calling IsBadRow(err)
+// will be return true. This is a potentially non-fatal error and callers can decide if they want to continue on a bad row,
+// or fail.
+func (ndjr *NDJSONReader) ReadRow(ctx context.Context) (row.Row, error) {
+	var parsed ndjsonLine
+	if len(ndjr.sampled) > 0 {
+		parsed, ndjr.sampled = ndjr.sampled[0], ndjr.sampled[1:]
+	} else {
+		line, err := ndjr.readLine()
+
+		if err != nil {
+			return nil, err
+		}
+
+		_, parsed.obj, parsed.err = parseObject(line)
+	}
+
+	if parsed.err != nil {
+		return nil, parsed.err
+	}
+
+	return ndjr.objToRow(parsed.obj)
+}
+
+func (ndjr *NDJSONReader) objToRow(obj map[string]interface{}) (row.Row, error) {
+	for key := range obj {
+		if _, ok := ndjr.tags[key]; !ok {
+			return nil, table.NewBadRow(nil, fmt.Sprintf("field '%s' isn't in any of the lines read to find the fields of the file", key))
+		}
+	}
+
+	taggedVals := make(row.TaggedValues)
+	for _, col := range ndjr.cols {
+		val, ok := lookupPath(obj, col)
+
+		if !ok || val == nil {
+			continue
+		}
+
+		str, err := valueString(val)
+
+		if err != nil {
+			return nil, table.NewBadRow(nil, err.Error())
+		}
+
+		taggedVals[ndjr.tags[col]] = types.String(str)
+	}
+
+	return row.New(ndjr.nbf, ndjr.sch, taggedVals)
+}
+
+// lookupPath returns the value of a top level field, or of a dotted path to a value within nested objects.
+func lookupPath(obj map[string]interface{}, path string) (interface{}, bool) {
+	if val, ok := obj[path]; ok {
+		return val, true
+	}
+
+	parts := strings.Split(path, ".")
+	var val interface{} = obj
+	for _, part := range parts {
+		m, ok := val.(map[string]interface{})
+
+		if !ok {
+			return nil, false
+		}
+
+		val, ok = m[part]
+
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return val, true
+}
+
+func valueString(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+
+		return "false", nil
+	default:
+		data, err := json.Marshal(v)
+
+		if err != nil {
+			return "", err
+		}
+
+		return string(data), nil
+	}
+}
+
+// Close should release resources being held
+func (ndjr *NDJSONReader) Close(ctx context.Context) error {
+	if ndjr.closer != nil {
+		err := ndjr.closer.Close()
+		ndjr.closer = nil
+
+		return err
+	}
+
+	return errors.New("already closed")
+}
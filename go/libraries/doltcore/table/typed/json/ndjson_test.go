@@ -0,0 +1,138 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const events = `{"id": 1, "type": "click", "user": {"name": "bill", "address": {"city": "LA"}}, "tags": ["a", "b"]}
+
+{"id": 2, "type": "view", "user": {"name": "jane"}, "ok": true, "score": 1.50}
+not json
+{"id": 3, "late": "field"}
+`
+
+func TestNDJSONReader(t *testing.T) {
+	ctx := context.Background()
+	info := NewNDJSONInfo().SetPaths([]string{"user.address.city", "user.name"}).SetSampleSize(3)
+	rd, err := NewNDJSONReader(types.Format_7_18, ioutil.NopCloser(strings.NewReader(events)), info)
+	require.NoError(t, err)
+	defer rd.Close(ctx)
+
+	var names []string
+	err = rd.GetSchema().GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		names = append(names, col.Name)
+		return false, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "type", "user", "tags", "ok", "score", "user.address.city", "user.name"}, names)
+
+	expected := []map[string]string{
+		{"id": "1", "type": "click", "user": `{"address":{"city":"LA"},"name":"bill"}`, "tags": `["a","b"]`, "user.address.city": "LA", "user.name": "bill"},
+		{"id": "2", "type": "view", "user": `{"name":"jane"}`, "ok": "true", "score": "1.50", "user.name": "jane"},
+	}
+
+	allCols := rd.GetSchema().GetAllCols()
+	for _, vals := range expected {
+		r, err := rd.ReadRow(ctx)
+		require.NoError(t, err)
+
+		for _, name := range names {
+			col, _ := allCols.GetByName(name)
+			val, ok := r.GetColVal(col.Tag)
+
+			if expectedVal, isSet := vals[name]; isSet {
+				assert.Equal(t, types.String(expectedVal), val)
+			} else {
+				assert.False(t, ok, name)
+			}
+		}
+	}
+
+	_, err = rd.ReadRow(ctx)
+	assert.True(t, table.IsBadRow(err))
+
+	_, err = rd.ReadRow(ctx)
+	assert.True(t, table.IsBadRow(err))
+
+	_, err = rd.ReadRow(ctx)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestNDJSONRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("name", 1, types.StringKind, false),
+		schema.NewColumn("uuid", 2, types.UUIDKind, false),
+		schema.NewJSONColumn("doc", 3, false),
+	)
+	require.NoError(t, err)
+	sch := schema.SchemaFromCols(colColl)
+
+	id := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	rows := []row.Row{
+		mustRow(t, sch, row.TaggedValues{0: types.Int(1), 1: types.String(`say "hi"`), 2: types.UUID(id), 3: types.String(`{"a":[1,2]}`)}),
+		mustRow(t, sch, row.TaggedValues{0: types.Int(2)}),
+	}
+
+	fs := filesys.NewInMemFS(nil, nil, "/")
+	wr, err := OpenNDJSONWriter("/data/events.ndjson", fs, sch)
+	require.NoError(t, err)
+
+	for _, r := range rows {
+		require.NoError(t, wr.WriteRow(ctx, r))
+	}
+
+	require.NoError(t, wr.Close(ctx))
+
+	data, err := fs.ReadFile("/data/events.ndjson")
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1,"name":"say \"hi\"","uuid":"00000000-0000-0000-0000-000000000001","doc":{"a":[1,2]}}`+"\n"+`{"id":2}`+"\n", string(data))
+
+	rd, err := OpenNDJSONReader(types.Format_7_18, "/data/events.ndjson", fs, NewNDJSONInfo())
+	require.NoError(t, err)
+	defer rd.Close(ctx)
+
+	r, err := rd.ReadRow(ctx)
+	require.NoError(t, err)
+
+	doc, ok := rd.GetSchema().GetAllCols().GetByName("doc")
+	require.True(t, ok)
+	val, _ := r.GetColVal(doc.Tag)
+	assert.Equal(t, types.String(`{"a":[1,2]}`), val)
+}
+
+func mustRow(t *testing.T, sch schema.Schema, taggedVals row.TaggedValues) row.Row {
+	r, err := row.New(types.Format_7_18, sch, taggedVals)
+	require.NoError(t, err)
+
+	return r
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// NDJSONWriter writes each row as a json object on its own line, with the fields of the object in the order of the
+// columns of the schema. Values of JSON columns are written as nested json.
+type NDJSONWriter struct {
+	closer io.Closer
+	bWr    *bufio.Writer
+	sch    schema.Schema
+}
+
+func OpenNDJSONWriter(path string, fs filesys.WritableFS, outSch schema.Schema) (*NDJSONWriter, error) {
+	err := fs.MkDirs(filepath.Dir(path))
+
+	if err != nil {
+		return nil, err
+	}
+
+	wr, err := fs.OpenForWrite(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNDJSONWriter(wr, outSch), nil
+}
+
+func NewNDJSONWriter(wr io.WriteCloser, outSch schema.Schema) *NDJSONWriter {
+	return &NDJSONWriter{wr, bufio.NewWriterSize(wr, WriteBufSize), outSch}
+}
+
+func (ndjw *NDJSONWriter) GetSchema() schema.Schema {
+	return ndjw.sch
+}
+
+// WriteRow will write a row to a table
+func (ndjw *NDJSONWriter) WriteRow(ctx context.Context, r row.Row) error {
+	var line bytes.Buffer
+	line.WriteByte('{')
+
+	err := ndjw.sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		val, ok := r.GetColVal(tag)
+
+		if !ok || types.IsNull(val) {
+			return false, nil
+		}
+
+		jsonVal, err := jsonValue(ctx, val, col)
+
+		if err != nil {
+			return false, err
+		}
+
+		name, err := json.Marshal(col.Name)
+
+		if err != nil {
+			return false, err
+		}
+
+		if line.Len() > 1 {
+			line.WriteByte(',')
+		}
+
+		line.Write(name)
+		line.WriteByte(':')
+		line.Write(jsonVal)
+
+		return false, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	line.WriteString("}\n")
+	return iohelp.WriteAll(ndjw.bWr, line.Bytes())
+}
+
+// jsonValue returns the json for a value of the column given: a json number or bool for numeric and bool values, the
+// document of a JSON column, and a string otherwise.
+func jsonValue(ctx context.Context, val types.Value, col schema.Column) ([]byte, error) {
+	if col.IsJSON() {
+		return []byte(val.(types.String)), nil
+	}
+
+	switch v := val.(type) {
+	case types.Int, types.Uint, types.Float, types.Bool:
+		return json.Marshal(v)
+	case types.String:
+		return json.Marshal(string(v))
+	}
+
+	str, err := types.EncodedValue(ctx, val)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(str)
+}
+
+// Close should flush all writes, release resources being held
+func (ndjw *NDJSONWriter) Close(ctx context.Context) error {
+	if ndjw.closer != nil {
+		errFl := ndjw.bWr.Flush()
+		errCl := ndjw.closer.Close()
+		ndjw.closer = nil
+
+		if errCl != nil {
+			return errCl
+		}
+
+		return errFl
+	}
+
+	return errors.New("already closed")
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"io/ioutil"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
@@ -44,10 +45,10 @@ func OpenJSONReader(nbf *types.NomsBinFormat, path string, fs filesys.ReadableFS
 		return nil, err
 	}
 
-	return NewJSONReader(nbf, r, info, fs, schPath, path)
+	return NewJSONReader(nbf, r, info, fs, schPath)
 }
 
-func NewJSONReader(nbf *types.NomsBinFormat, r io.ReadCloser, info *JSONFileInfo, fs filesys.ReadableFS, schPath string, tblPath string) (*JSONReader, error) {
+func NewJSONReader(nbf *types.NomsBinFormat, r io.ReadCloser, info *JSONFileInfo, fs filesys.ReadableFS, schPath string) (*JSONReader, error) {
 	br := bufio.NewReaderSize(r, ReadBufSize)
 	if schPath == "" {
 		panic("schema must be provided")
@@ -64,7 +65,7 @@ func NewJSONReader(nbf *types.NomsBinFormat, r io.ReadCloser, info *JSONFileInfo
 		return nil, err
 	}
 
-	tblData, err := fs.ReadFile(tblPath)
+	tblData, err := ioutil.ReadAll(br)
 	if err != nil {
 		return nil, err
 	}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
@@ -121,6 +122,21 @@ func convToRow(nbf *types.NomsBinFormat, sch schema.Schema, rowMap map[string]in
 			return nil, errors.New("column not found in schema")
 		}
 
+		// JSON columns take any value, including nested objects and arrays, as their document
+		if col.IsJSON() {
+			if v != nil {
+				doc, err := doltcore.JSONFromGo(v)
+
+				if err != nil {
+					return nil, err
+				}
+
+				taggedVals[col.Tag] = doc
+			}
+
+			continue
+		}
+
 		switch val := v.(type) {
 		case int:
 			f := doltcore.GetConvFunc(types.IntKind, col.Kind)
@@ -134,6 +150,8 @@ func convToRow(nbf *types.NomsBinFormat, sch schema.Schema, rowMap map[string]in
 		case float64:
 			f := doltcore.GetConvFunc(types.FloatKind, col.Kind)
 			taggedVals[col.Tag], _ = f(types.Float(val))
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("column '%s' has a nested value but isn't a JSON column", col.Name)
 		}
 
 	}
@@ -18,6 +18,12 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
 func TestUnmarshalFromJSON(t *testing.T) {
@@ -53,3 +59,39 @@ func TestUnmarshalFromJSON(t *testing.T) {
 		t.Error("something went wrong")
 	}
 }
+
+func TestConvToRowWithNestedJSON(t *testing.T) {
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("name", 1, types.StringKind, false),
+		schema.NewJSONColumn("metadata", 2, false),
+	)
+	require.NoError(t, err)
+	sch := schema.SchemaFromCols(colColl)
+
+	testJSON := `{
+		"rows": [
+			{"id": 0, "name": "tim", "metadata": {"title": "ceo", "tags": ["a", "b"]}},
+			{"id": 1, "name": "brian", "metadata": "engineer"},
+			{"id": 2, "name": {"first": "aaron"}}
+		]
+	}`
+
+	jsonRows, err := UnmarshalFromJSON([]byte(testJSON))
+	require.NoError(t, err)
+
+	r, err := convToRow(types.Format_7_18, sch, jsonRows.Rows[0])
+	require.NoError(t, err)
+	val, ok := r.GetColVal(2)
+	require.True(t, ok)
+	assert.Equal(t, types.String(`{"tags":["a","b"],"title":"ceo"}`), val)
+
+	r, err = convToRow(types.Format_7_18, sch, jsonRows.Rows[1])
+	require.NoError(t, err)
+	val, ok = r.GetColVal(2)
+	require.True(t, ok)
+	assert.Equal(t, types.String(`"engineer"`), val)
+
+	_, err = convToRow(types.Format_7_18, sch, jsonRows.Rows[2])
+	assert.Error(t, err)
+}
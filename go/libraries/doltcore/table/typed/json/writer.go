@@ -78,7 +78,14 @@ func (jsonw *JSONWriter) WriteRow(ctx context.Context, r row.Row) error {
 	colValMap := make(map[string]interface{}, allCols.Size())
 	err := allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		val, ok := r.GetColVal(tag)
-		if ok && !types.IsNull(val) {
+		if !ok || types.IsNull(val) {
+			return false, nil
+		}
+
+		if col.IsJSON() {
+			// write documents as nested JSON rather than as strings
+			colValMap[col.Name] = json.RawMessage(val.(types.String))
+		} else {
 			colValMap[col.Name] = val
 		}
 
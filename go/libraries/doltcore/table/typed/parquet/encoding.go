@@ -0,0 +1,448 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+var errTruncatedPage = errors.New("invalid parquet file: page data is truncated")
+var errPageSize = errors.New("invalid parquet file: page data doesn't match the size in its header")
+
+// CompressionCodec is the compression applied to the pages of a parquet file
+type CompressionCodec int32
+
+const (
+	Uncompressed CompressionCodec = 0
+	Snappy       CompressionCodec = 1
+	Gzip         CompressionCodec = 2
+	Lzo          CompressionCodec = 3
+	Brotli       CompressionCodec = 4
+	Lz4          CompressionCodec = 5
+	Zstd         CompressionCodec = 6
+	Lz4Raw       CompressionCodec = 7
+)
+
+func (codec CompressionCodec) String() string {
+	switch codec {
+	case Uncompressed:
+		return "UNCOMPRESSED"
+	case Snappy:
+		return "SNAPPY"
+	case Gzip:
+		return "GZIP"
+	case Lzo:
+		return "LZO"
+	case Brotli:
+		return "BROTLI"
+	case Lz4:
+		return "LZ4"
+	case Zstd:
+		return "ZSTD"
+	case Lz4Raw:
+		return "LZ4_RAW"
+	}
+
+	return fmt.Sprintf("codec %d", int32(codec))
+}
+
+// supported returns whether pages compressed with the codec can be read and written.
+func (codec CompressionCodec) supported() bool {
+	switch codec {
+	case Uncompressed, Snappy, Gzip, Zstd:
+		return true
+	}
+
+	return false
+}
+
+// CompressionCodecFromString returns the codec with the name given: none, snappy, gzip or zstd
+func CompressionCodecFromString(str string) (CompressionCodec, error) {
+	switch str {
+	case "none", "uncompressed":
+		return Uncompressed, nil
+	case "snappy":
+		return Snappy, nil
+	case "gzip":
+		return Gzip, nil
+	case "zstd":
+		return Zstd, nil
+	}
+
+	return Uncompressed, fmt.Errorf("unknown parquet compression codec '%s'", str)
+}
+
+func compress(codec CompressionCodec, data []byte) ([]byte, error) {
+	switch codec {
+	case Uncompressed:
+		return data, nil
+
+	case Snappy:
+		return snappy.Encode(nil, data), nil
+
+	case Gzip:
+		var buf bytes.Buffer
+		gzw := gzip.NewWriter(&buf)
+
+		if _, err := gzw.Write(data); err != nil {
+			return nil, err
+		}
+
+		if err := gzw.Close(); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+
+	case Zstd:
+		zw, err := zstd.NewWriter(nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer zw.Close()
+
+		return zw.EncodeAll(data, nil), nil
+	}
+
+	return nil, fmt.Errorf("parquet compression codec %s isn't supported", codec)
+}
+
+// decompress decompresses a page whose uncompressed size is given by its header. The size is only trusted as a limit,
+// so a corrupt header can't make the page take more memory than its data decompresses to.
+func decompress(codec CompressionCodec, data []byte, size int) ([]byte, error) {
+	var decoded []byte
+	switch codec {
+	case Uncompressed:
+		return data, nil
+
+	case Snappy:
+		n, err := snappy.DecodedLen(data)
+
+		if err != nil {
+			return nil, err
+		} else if n != size {
+			return nil, errPageSize
+		}
+
+		return snappy.Decode(nil, data)
+
+	case Gzip:
+		gzr, err := gzip.NewReader(bytes.NewReader(data))
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer gzr.Close()
+
+		decoded, err = ioutil.ReadAll(io.LimitReader(gzr, int64(size)+1))
+
+		if err != nil {
+			return nil, err
+		}
+
+	case Zstd:
+		zr, err := zstd.NewReader(bytes.NewReader(data), zstd.WithDecoderConcurrency(1))
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer zr.Close()
+
+		decoded, err = ioutil.ReadAll(io.LimitReader(zr, int64(size)+1))
+
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("parquet compression codec %s isn't supported", codec)
+	}
+
+	if len(decoded) != size {
+		return nil, errPageSize
+	}
+
+	return decoded, nil
+}
+
+// decodeHybrid decodes n values of the width given from data encoded with the RLE / bit-packing hybrid encoding used for
+// definition levels and dictionary indices.
+func decodeHybrid(data []byte, bitWidth int, n int) ([]uint32, error) {
+	if bitWidth > 32 {
+		return nil, fmt.Errorf("invalid parquet file: bit width %d", bitWidth)
+	}
+
+	// runs can repeat a value any number of times, so n is only trusted as far as bit-packed data could hold
+	byteWidth := (bitWidth + 7) / 8
+	capacity := n
+	if capacity > 8*len(data) {
+		capacity = 8 * len(data)
+	}
+
+	vals := make([]uint32, 0, capacity)
+
+	pos := 0
+	for len(vals) < n {
+		if pos >= len(data) {
+			return nil, errTruncatedPage
+		}
+
+		header, size := binary.Uvarint(data[pos:])
+
+		if size <= 0 {
+			return nil, errTruncatedPage
+		}
+
+		pos += size
+
+		if header&1 == 0 {
+			// a run of a single repeated value
+			if pos+byteWidth > len(data) {
+				return nil, errTruncatedPage
+			}
+
+			var val uint32
+			for i := 0; i < byteWidth; i++ {
+				val |= uint32(data[pos+i]) << uint(8*i)
+			}
+
+			pos += byteWidth
+
+			for count := int(header >> 1); count > 0 && len(vals) < n; count-- {
+				vals = append(vals, val)
+			}
+		} else {
+			// groups of 8 bit-packed values. The last group may be padded past the values encoded.
+			numBytes := int(header>>1) * bitWidth
+			end := pos + numBytes
+
+			if end > len(data) {
+				end = len(data)
+			}
+
+			for i := 0; i < int(header>>1)*8 && len(vals) < n; i++ {
+				var val uint32
+				for b := 0; b < bitWidth; b++ {
+					bit := i*bitWidth + b
+
+					if pos+bit/8 >= end {
+						return nil, errTruncatedPage
+					}
+
+					val |= uint32(data[pos+bit/8]>>uint(bit%8)&1) << uint(b)
+				}
+
+				vals = append(vals, val)
+			}
+
+			pos += numBytes
+		}
+	}
+
+	return vals, nil
+}
+
+// decodeDeltaBinaryPacked decodes n integers encoded with the DELTA_BINARY_PACKED encoding, returning them and the
+// number of bytes they took. The values are deltas from the previous value, stored in blocks of miniblocks that each
+// have their own bit width. Deltas of INT32 columns wrap around as 32 bit integers, which truncating the 64 bit sums
+// accounts for.
+func decodeDeltaBinaryPacked(data []byte, n int) ([]int64, int, error) {
+	r := &thriftReader{buf: data}
+
+	blockSize, err := r.readUvarint()
+
+	if err != nil {
+		return nil, 0, errTruncatedPage
+	}
+
+	numMiniblocks, err := r.readUvarint()
+
+	if err != nil {
+		return nil, 0, errTruncatedPage
+	}
+
+	count, err := r.readUvarint()
+
+	if err != nil {
+		return nil, 0, errTruncatedPage
+	}
+
+	val, err := r.readZigzag()
+
+	if err != nil {
+		return nil, 0, errTruncatedPage
+	}
+
+	if count != uint64(n) {
+		return nil, 0, fmt.Errorf("invalid parquet file: a page has %d delta encoded values, but should have %d", count, n)
+	} else if numMiniblocks == 0 || blockSize == 0 || blockSize%numMiniblocks != 0 || blockSize/numMiniblocks%8 != 0 {
+		return nil, 0, errors.New("invalid parquet file: a page has an invalid delta encoding block size")
+	}
+
+	if n == 0 {
+		return nil, r.pos, nil
+	}
+
+	miniblockSize := int(blockSize / numMiniblocks)
+	vals := make([]int64, 1, n)
+	vals[0] = val
+
+	for len(vals) < n {
+		minDelta, err := r.readZigzag()
+
+		if err != nil {
+			return nil, 0, errTruncatedPage
+		}
+
+		bitWidths, err := r.readBytes(int(numMiniblocks))
+
+		if err != nil {
+			return nil, 0, errTruncatedPage
+		}
+
+		// miniblocks after the last value are left out
+		for _, width := range bitWidths {
+			if len(vals) == n {
+				break
+			} else if width > 64 {
+				return nil, 0, fmt.Errorf("invalid parquet file: bit width %d", width)
+			}
+
+			packed, err := r.readBytes(miniblockSize * int(width) / 8)
+
+			if err != nil {
+				return nil, 0, errTruncatedPage
+			}
+
+			for i := 0; i < miniblockSize && len(vals) < n; i++ {
+				var delta uint64
+				for b := 0; b < int(width); b++ {
+					bit := i*int(width) + b
+					delta |= uint64(packed[bit/8]>>uint(bit%8)&1) << uint(b)
+				}
+
+				val += minDelta + int64(delta)
+				vals = append(vals, val)
+			}
+		}
+	}
+
+	return vals, r.pos, nil
+}
+
+// decodeDeltaLengthByteArray decodes n byte arrays encoded with the DELTA_LENGTH_BYTE_ARRAY encoding, whose lengths are
+// DELTA_BINARY_PACKED ahead of their concatenated data. Returns the byte arrays and the number of bytes they took.
+func decodeDeltaLengthByteArray(data []byte, n int) ([][]byte, int, error) {
+	lengths, pos, err := decodeDeltaBinaryPacked(data, n)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	vals := make([][]byte, n)
+	for i, length := range lengths {
+		if length < 0 || length > int64(len(data)-pos) {
+			return nil, 0, errTruncatedPage
+		}
+
+		vals[i] = data[pos : pos+int(length)]
+		pos += int(length)
+	}
+
+	return vals, pos, nil
+}
+
+// decodeDeltaByteArray decodes n byte arrays encoded with the DELTA_BYTE_ARRAY encoding, where each value is stored as
+// the length of the prefix it shares with the previous value and the rest of the value. The prefix lengths are
+// DELTA_BINARY_PACKED, and the rest of the values are DELTA_LENGTH_BYTE_ARRAY encoded.
+func decodeDeltaByteArray(data []byte, n int) ([][]byte, error) {
+	prefixLengths, pos, err := decodeDeltaBinaryPacked(data, n)
+
+	if err != nil {
+		return nil, err
+	}
+
+	suffixes, _, err := decodeDeltaLengthByteArray(data[pos:], n)
+
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([][]byte, n)
+	var prev []byte
+	for i, prefixLen := range prefixLengths {
+		if prefixLen < 0 || prefixLen > int64(len(prev)) {
+			return nil, errors.New("invalid parquet file: a delta encoded value has a prefix longer than the previous value")
+		}
+
+		val := make([]byte, 0, int(prefixLen)+len(suffixes[i]))
+		val = append(append(val, prev[:prefixLen]...), suffixes[i]...)
+		vals[i] = val
+		prev = val
+	}
+
+	return vals, nil
+}
+
+// encodeLevels encodes the definition levels of an optional column as runs of the RLE / bit-packing hybrid encoding,
+// prefixed with their length as data pages require.
+func encodeLevels(present []bool) []byte {
+	var runs bytes.Buffer
+	for i := 0; i < len(present); {
+		j := i + 1
+		for j < len(present) && present[j] == present[i] {
+			j++
+		}
+
+		writeUvarint(&runs, uint64(j-i)<<1)
+
+		if present[i] {
+			runs.WriteByte(1)
+		} else {
+			runs.WriteByte(0)
+		}
+
+		i = j
+	}
+
+	data := make([]byte, 4, 4+runs.Len())
+	binary.LittleEndian.PutUint32(data, uint32(runs.Len()))
+
+	return append(data, runs.Bytes()...)
+}
+
+// bitWidth returns the number of bits needed to encode values up to max.
+func bitWidth(max int) int {
+	width := 0
+	for max > 0 {
+		width++
+		max >>= 1
+	}
+
+	return width
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+// DefaultRowGroupSize is the default number of rows written to each row group of a parquet file
+const DefaultRowGroupSize = 100000
+
+// ParquetFileInfo describes how a parquet file is written
+type ParquetFileInfo struct {
+	// RowGroupSize is the maximum number of rows in each row group. A row group is buffered in memory while it's written.
+	RowGroupSize int
+
+	// Compression is the codec used to compress the pages of the file
+	Compression CompressionCodec
+}
+
+func NewParquetInfo() *ParquetFileInfo {
+	return &ParquetFileInfo{DefaultRowGroupSize, Snappy}
+}
+
+func (info *ParquetFileInfo) SetRowGroupSize(rowGroupSize int) *ParquetFileInfo {
+	info.RowGroupSize = rowGroupSize
+	return info
+}
+
+func (info *ParquetFileInfo) SetCompression(compression CompressionCodec) *ParquetFileInfo {
+	info.Compression = compression
+	return info
+}
@@ -0,0 +1,459 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// The structs in this file mirror the structs of the same names in parquet.thrift, keeping only the fields dolt uses.
+// Field ids are those of parquet.thrift.
+
+const magic = "PAR1"
+
+// physical types
+const (
+	typeBoolean           int32 = 0
+	typeInt32             int32 = 1
+	typeInt64             int32 = 2
+	typeInt96             int32 = 3
+	typeFloat             int32 = 4
+	typeDouble            int32 = 5
+	typeByteArray         int32 = 6
+	typeFixedLenByteArray int32 = 7
+)
+
+// converted types, the deprecated annotations still written alongside logical types for older readers
+const (
+	convertedUTF8            int32 = 0
+	convertedEnum            int32 = 4
+	convertedDecimal         int32 = 5
+	convertedDate            int32 = 6
+	convertedTimeMillis      int32 = 7
+	convertedTimeMicros      int32 = 8
+	convertedTimestampMillis int32 = 9
+	convertedTimestampMicros int32 = 10
+	convertedUint8           int32 = 11
+	convertedUint16          int32 = 12
+	convertedUint32          int32 = 13
+	convertedUint64          int32 = 14
+	convertedInt64           int32 = 18
+	convertedJSON            int32 = 19
+)
+
+// logical type union field ids
+const (
+	logicalString    int16 = 1
+	logicalEnum      int16 = 4
+	logicalDecimal   int16 = 5
+	logicalDate      int16 = 6
+	logicalTime      int16 = 7
+	logicalTimestamp int16 = 8
+	logicalInteger   int16 = 10
+	logicalJSON      int16 = 12
+	logicalUUID      int16 = 14
+)
+
+// field repetition types
+const (
+	repetitionRequired int32 = 0
+	repetitionOptional int32 = 1
+	repetitionRepeated int32 = 2
+)
+
+// encodings
+const (
+	encodingPlain                int32 = 0
+	encodingPlainDictionary      int32 = 2
+	encodingRLE                  int32 = 3
+	encodingBitPacked            int32 = 4
+	encodingDeltaBinaryPacked    int32 = 5
+	encodingDeltaLengthByteArray int32 = 6
+	encodingDeltaByteArray       int32 = 7
+	encodingRLEDictionary        int32 = 8
+	encodingByteStreamSplit      int32 = 9
+)
+
+func encodingName(encoding int32) string {
+	switch encoding {
+	case encodingPlain:
+		return "PLAIN"
+	case encodingPlainDictionary:
+		return "PLAIN_DICTIONARY"
+	case encodingRLE:
+		return "RLE"
+	case encodingBitPacked:
+		return "BIT_PACKED"
+	case encodingDeltaBinaryPacked:
+		return "DELTA_BINARY_PACKED"
+	case encodingDeltaLengthByteArray:
+		return "DELTA_LENGTH_BYTE_ARRAY"
+	case encodingDeltaByteArray:
+		return "DELTA_BYTE_ARRAY"
+	case encodingRLEDictionary:
+		return "RLE_DICTIONARY"
+	case encodingByteStreamSplit:
+		return "BYTE_STREAM_SPLIT"
+	}
+
+	return fmt.Sprintf("%d", encoding)
+}
+
+// page types
+const (
+	pageData       int32 = 0
+	pageDictionary int32 = 2
+	pageDataV2     int32 = 3
+)
+
+var errInvalidMetadata = errors.New("invalid parquet metadata")
+
+type schemaElement struct {
+	typ           int32
+	hasType       bool
+	typeLength    int32
+	repetition    int32
+	name          string
+	numChildren   int32
+	convertedType int32
+	hasConverted  bool
+	scale         int32
+	logicalType   thriftStruct
+}
+
+func decodeSchemaElement(st thriftStruct) schemaElement {
+	return schemaElement{
+		typ:           int32(st.int(1)),
+		hasType:       st.has(1),
+		typeLength:    int32(st.int(2)),
+		repetition:    int32(st.int(3)),
+		name:          st.str(4),
+		numChildren:   int32(st.int(5)),
+		convertedType: int32(st.int(6)),
+		hasConverted:  st.has(6),
+		scale:         int32(st.int(7)),
+		logicalType:   st.structField(10),
+	}
+}
+
+func (se schemaElement) fields() []thriftField {
+	var fields []thriftField
+	if se.hasType {
+		fields = append(fields, thriftField{1, se.typ})
+	}
+
+	if se.typeLength != 0 {
+		fields = append(fields, thriftField{2, se.typeLength})
+	}
+
+	if se.hasType {
+		fields = append(fields, thriftField{3, se.repetition})
+	}
+
+	fields = append(fields, thriftField{4, se.name})
+
+	if !se.hasType {
+		fields = append(fields, thriftField{5, se.numChildren})
+	}
+
+	if se.hasConverted {
+		fields = append(fields, thriftField{6, se.convertedType})
+	}
+
+	if se.logicalType != nil {
+		fields = append(fields, thriftField{10, logicalTypeFields(se.logicalType)})
+	}
+
+	return fields
+}
+
+// logicalTypeFields encodes a logical type union. Only the logical types written by dolt are supported.
+func logicalTypeFields(lt thriftStruct) []thriftField {
+	for id, val := range lt {
+		var fields []thriftField
+		if params, ok := val.(thriftStruct); ok && id == logicalInteger {
+			fields = []thriftField{{1, int8(params.int(1))}, {2, params.bool(2)}}
+		}
+
+		return []thriftField{{id, fields}}
+	}
+
+	return nil
+}
+
+type keyValue struct {
+	key   string
+	value string
+}
+
+type columnMetaData struct {
+	typ                   int32
+	encodings             []int32
+	path                  []string
+	codec                 int32
+	numValues             int64
+	totalUncompressedSize int64
+	totalCompressedSize   int64
+	dataPageOffset        int64
+	dictionaryPageOffset  int64
+}
+
+func decodeColumnMetaData(st thriftStruct) columnMetaData {
+	cmd := columnMetaData{
+		typ:                   int32(st.int(1)),
+		codec:                 int32(st.int(4)),
+		numValues:             st.int(5),
+		totalUncompressedSize: st.int(6),
+		totalCompressedSize:   st.int(7),
+		dataPageOffset:        st.int(9),
+		dictionaryPageOffset:  st.int(11),
+	}
+
+	for _, enc := range st.list(2) {
+		n, _ := enc.(int64)
+		cmd.encodings = append(cmd.encodings, int32(n))
+	}
+
+	for _, elem := range st.list(3) {
+		data, _ := elem.([]byte)
+		cmd.path = append(cmd.path, string(data))
+	}
+
+	return cmd
+}
+
+func (cmd columnMetaData) fields() []thriftField {
+	encodings := thriftList{elemType: ctI32}
+	for _, enc := range cmd.encodings {
+		encodings.elems = append(encodings.elems, enc)
+	}
+
+	path := thriftList{elemType: ctBinary}
+	for _, elem := range cmd.path {
+		path.elems = append(path.elems, elem)
+	}
+
+	fields := []thriftField{
+		{1, cmd.typ},
+		{2, encodings},
+		{3, path},
+		{4, cmd.codec},
+		{5, cmd.numValues},
+		{6, cmd.totalUncompressedSize},
+		{7, cmd.totalCompressedSize},
+		{9, cmd.dataPageOffset},
+	}
+
+	if cmd.dictionaryPageOffset > 0 {
+		fields = append(fields, thriftField{11, cmd.dictionaryPageOffset})
+	}
+
+	return fields
+}
+
+// chunkOffset returns the offset of the first page of the column chunk.
+func (cmd columnMetaData) chunkOffset() int64 {
+	if cmd.dictionaryPageOffset > 0 && cmd.dictionaryPageOffset < cmd.dataPageOffset {
+		return cmd.dictionaryPageOffset
+	}
+
+	return cmd.dataPageOffset
+}
+
+type rowGroup struct {
+	columns       []columnMetaData
+	totalByteSize int64
+	numRows       int64
+}
+
+func decodeRowGroup(st thriftStruct) (rowGroup, error) {
+	rg := rowGroup{totalByteSize: st.int(2), numRows: st.int(3)}
+
+	for _, elem := range st.list(1) {
+		chunk, _ := elem.(thriftStruct)
+
+		if chunk.str(1) != "" {
+			return rowGroup{}, errors.New("parquet files with columns stored in other files are not supported")
+		} else if !chunk.has(3) {
+			return rowGroup{}, errInvalidMetadata
+		}
+
+		rg.columns = append(rg.columns, decodeColumnMetaData(chunk.structField(3)))
+	}
+
+	return rg, nil
+}
+
+func (rg rowGroup) fields() []thriftField {
+	chunks := thriftList{elemType: ctStruct}
+	for _, cmd := range rg.columns {
+		chunks.elems = append(chunks.elems, []thriftField{{2, cmd.chunkOffset()}, {3, cmd.fields()}})
+	}
+
+	return []thriftField{{1, chunks}, {2, rg.totalByteSize}, {3, rg.numRows}}
+}
+
+type fileMetaData struct {
+	version   int32
+	schema    []schemaElement
+	numRows   int64
+	rowGroups []rowGroup
+	keyValues []keyValue
+	createdBy string
+}
+
+func decodeFileMetaData(data []byte) (*fileMetaData, error) {
+	r := &thriftReader{buf: data}
+	st, err := r.readStruct()
+
+	if err != nil {
+		return nil, err
+	}
+
+	fmd := &fileMetaData{version: int32(st.int(1)), numRows: st.int(3), createdBy: st.str(6)}
+
+	for _, elem := range st.list(2) {
+		se, _ := elem.(thriftStruct)
+		fmd.schema = append(fmd.schema, decodeSchemaElement(se))
+	}
+
+	for _, elem := range st.list(4) {
+		rgSt, _ := elem.(thriftStruct)
+		rg, err := decodeRowGroup(rgSt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		fmd.rowGroups = append(fmd.rowGroups, rg)
+	}
+
+	for _, elem := range st.list(5) {
+		kv, _ := elem.(thriftStruct)
+		fmd.keyValues = append(fmd.keyValues, keyValue{kv.str(1), kv.str(2)})
+	}
+
+	return fmd, nil
+}
+
+func (fmd *fileMetaData) encode() []byte {
+	schemaList := thriftList{elemType: ctStruct}
+	for _, se := range fmd.schema {
+		schemaList.elems = append(schemaList.elems, se.fields())
+	}
+
+	rowGroups := thriftList{elemType: ctStruct}
+	for _, rg := range fmd.rowGroups {
+		rowGroups.elems = append(rowGroups.elems, rg.fields())
+	}
+
+	keyValues := thriftList{elemType: ctStruct}
+	for _, kv := range fmd.keyValues {
+		keyValues.elems = append(keyValues.elems, []thriftField{{1, kv.key}, {2, kv.value}})
+	}
+
+	var buf bytes.Buffer
+	writeThriftStruct(&buf, []thriftField{
+		{1, fmd.version},
+		{2, schemaList},
+		{3, fmd.numRows},
+		{4, rowGroups},
+		{5, keyValues},
+		{6, fmd.createdBy},
+	})
+
+	return buf.Bytes()
+}
+
+func (fmd *fileMetaData) keyValue(key string) (string, bool) {
+	for _, kv := range fmd.keyValues {
+		if kv.key == key {
+			return kv.value, true
+		}
+	}
+
+	return "", false
+}
+
+type pageHeader struct {
+	typ              int32
+	uncompressedSize int32
+	compressedSize   int32
+	numValues        int32
+	encoding         int32
+
+	// fields of v2 data pages, whose levels aren't compressed
+	defLevelsLen int32
+	repLevelsLen int32
+	isCompressed bool
+}
+
+func readPageHeader(r *thriftReader) (pageHeader, error) {
+	st, err := r.readStruct()
+
+	if err != nil {
+		return pageHeader{}, err
+	}
+
+	ph := pageHeader{
+		typ:              int32(st.int(1)),
+		uncompressedSize: int32(st.int(2)),
+		compressedSize:   int32(st.int(3)),
+	}
+
+	switch ph.typ {
+	case pageData:
+		dph := st.structField(5)
+		ph.numValues = int32(dph.int(1))
+		ph.encoding = int32(dph.int(2))
+	case pageDictionary:
+		dph := st.structField(7)
+		ph.numValues = int32(dph.int(1))
+		ph.encoding = int32(dph.int(2))
+	case pageDataV2:
+		dph := st.structField(8)
+		ph.numValues = int32(dph.int(1))
+		ph.encoding = int32(dph.int(4))
+		ph.defLevelsLen = int32(dph.int(5))
+		ph.repLevelsLen = int32(dph.int(6))
+		// is_compressed defaults to true
+		ph.isCompressed = !dph.has(7) || dph.bool(7)
+	}
+
+	if ph.compressedSize < 0 || ph.uncompressedSize < 0 || ph.numValues < 0 || ph.defLevelsLen < 0 || ph.repLevelsLen < 0 {
+		return pageHeader{}, errInvalidMetadata
+	}
+
+	return ph, nil
+}
+
+func (ph pageHeader) encode() []byte {
+	var buf bytes.Buffer
+	writeThriftStruct(&buf, []thriftField{
+		{1, ph.typ},
+		{2, ph.uncompressedSize},
+		{3, ph.compressedSize},
+		{5, []thriftField{
+			{1, ph.numValues},
+			{2, ph.encoding},
+			{3, encodingRLE},
+			{4, encodingRLE},
+		}},
+	})
+
+	return buf.Bytes()
+}
@@ -0,0 +1,454 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func testSchema(t *testing.T) schema.Schema {
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn("id", 7, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("name", 3, types.StringKind, false),
+		schema.NewColumn("score", 12, types.FloatKind, false),
+		schema.NewColumn("flag", 4, types.BoolKind, false, schema.NotNullConstraint{}),
+		schema.NewColumn("uuid", 5, types.UUIDKind, false),
+		schema.NewColumn("big", 9, types.UintKind, false),
+		schema.NewJSONColumn("doc", 10, false),
+	)
+	require.NoError(t, err)
+
+	return schema.SchemaFromCols(colColl)
+}
+
+func testRows(t *testing.T, sch schema.Schema, n int) []row.Row {
+	var rows []row.Row
+	for i := 0; i < n; i++ {
+		taggedVals := row.TaggedValues{7: types.Int(i - n/2), 4: types.Bool(i%3 == 0)}
+
+		if i%2 == 0 {
+			taggedVals[3] = types.String("name " + string(rune('a'+i%26)))
+			taggedVals[12] = types.Float(float64(i) / 4)
+		}
+
+		if i%5 != 0 {
+			taggedVals[5] = types.UUID(uuid.New())
+			taggedVals[9] = types.Uint(uint64(1<<63) + uint64(i))
+			taggedVals[10] = types.String(`{"n":1}`)
+		}
+
+		r, err := row.New(types.Format_7_18, sch, taggedVals)
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+
+	return rows
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		numRows      int
+		rowGroupSize int
+		compression  CompressionCodec
+	}{
+		{"empty", 0, DefaultRowGroupSize, Snappy},
+		{"one row group", 10, DefaultRowGroupSize, Snappy},
+		{"many row groups", 25, 4, Snappy},
+		{"gzip", 25, 7, Gzip},
+		{"zstd", 25, 7, Zstd},
+		{"uncompressed", 25, 25, Uncompressed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			sch := testSchema(t)
+			rows := testRows(t, sch, tt.numRows)
+			fs := filesys.NewInMemFS(nil, nil, "/")
+			info := NewParquetInfo().SetRowGroupSize(tt.rowGroupSize).SetCompression(tt.compression)
+
+			wr, err := OpenParquetWriter("/data/file.parquet", fs, sch, info)
+			require.NoError(t, err)
+
+			for _, r := range rows {
+				require.NoError(t, wr.WriteRow(ctx, r))
+			}
+
+			require.NoError(t, wr.Close(ctx))
+
+			rd, err := OpenParquetReader(types.Format_7_18, "/data/file.parquet", fs, NewParquetInfo())
+			require.NoError(t, err)
+			defer rd.Close(ctx)
+
+			eq, err := schema.SchemasAreEqual(sch, rd.GetSchema())
+			require.NoError(t, err)
+			assert.True(t, eq)
+
+			for _, expected := range rows {
+				r, err := rd.ReadRow(ctx)
+				require.NoError(t, err)
+				assert.True(t, row.AreEqual(expected, r, sch))
+			}
+
+			_, err = rd.ReadRow(ctx)
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+}
+
+func TestWriteRequiredNull(t *testing.T) {
+	ctx := context.Background()
+	sch := testSchema(t)
+	r, err := row.New(types.Format_7_18, sch, row.TaggedValues{7: types.Int(1)})
+	require.NoError(t, err)
+
+	wr, err := OpenParquetWriter("/file.parquet", filesys.NewInMemFS(nil, nil, "/"), sch, NewParquetInfo())
+	require.NoError(t, err)
+	defer wr.Close(ctx)
+
+	err = wr.WriteRow(ctx, r)
+	assert.EqualError(t, err, "column 'flag' is required, but a row has no value for it")
+}
+
+// TestReadWithoutDoltSchema reads a file like one written by another tool, with no dolt schema in its metadata, an INT32
+// column, and a BYTE_ARRAY column with a dictionary encoded v2 data page.
+func TestReadWithoutDoltSchema(t *testing.T) {
+	var file bytes.Buffer
+	file.WriteString(magic)
+
+	// column a: a required INT32 with values 1, 2, 3 in a PLAIN encoded v1 data page
+	aOffset := int64(file.Len())
+	aData := []byte{1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0}
+	file.Write(pageHeader{typ: pageData, uncompressedSize: 12, compressedSize: 12, numValues: 3, encoding: encodingPlain}.encode())
+	file.Write(aData)
+	aSize := int64(file.Len()) - aOffset
+
+	// column b: an optional UTF8 BYTE_ARRAY with values "x", null, "x" in a dictionary encoded v2 data page
+	bOffset := int64(file.Len())
+	dict := []byte{1, 0, 0, 0, 'x'}
+	var dictHeader bytes.Buffer
+	writeThriftStruct(&dictHeader, []thriftField{{1, pageDictionary}, {2, int32(len(dict))}, {3, int32(len(dict))},
+		{7, []thriftField{{1, int32(1)}, {2, encodingPlainDictionary}}}})
+	file.Write(dictHeader.Bytes())
+	file.Write(dict)
+
+	levels := []byte{3, 5} // one bit-packed group of levels 1, 0, 1
+	indices := []byte{0, 4}
+	var dataHeader bytes.Buffer
+	writeThriftStruct(&dataHeader, []thriftField{{1, pageDataV2}, {2, int32(len(levels) + len(indices))},
+		{3, int32(len(levels) + len(indices))}, {8, []thriftField{{1, int32(3)}, {2, int32(1)}, {3, int32(3)},
+			{4, encodingRLEDictionary}, {5, int32(len(levels))}, {6, int32(0)}, {7, false}}}})
+	file.Write(dataHeader.Bytes())
+	file.Write(levels)
+	file.Write(indices)
+	bSize := int64(file.Len()) - bOffset
+
+	meta := &fileMetaData{
+		version: 1,
+		schema: []schemaElement{
+			{name: "schema", numChildren: 2},
+			{name: "a", hasType: true, typ: typeInt32, repetition: repetitionRequired},
+			{name: "b", hasType: true, typ: typeByteArray, repetition: repetitionOptional, convertedType: convertedUTF8, hasConverted: true},
+		},
+		numRows: 3,
+		rowGroups: []rowGroup{{numRows: 3, columns: []columnMetaData{
+			{typ: typeInt32, path: []string{"a"}, numValues: 3, totalCompressedSize: aSize, dataPageOffset: aOffset},
+			{typ: typeByteArray, path: []string{"b"}, numValues: 3, totalCompressedSize: bSize, dataPageOffset: bOffset + int64(dictHeader.Len()+len(dict)), dictionaryPageOffset: bOffset},
+		}}},
+	}
+
+	metaData := meta.encode()
+	file.Write(metaData)
+	file.Write([]byte{byte(len(metaData)), byte(len(metaData) >> 8), 0, 0})
+	file.WriteString(magic)
+
+	fs := filesys.NewInMemFS(nil, map[string][]byte{"/file.parquet": file.Bytes()}, "/")
+	rd, err := OpenParquetReader(types.Format_7_18, "/file.parquet", fs, NewParquetInfo())
+	require.NoError(t, err)
+	defer rd.Close(context.Background())
+
+	sch := rd.GetSchema()
+	assert.Equal(t, 0, sch.GetPKCols().Size())
+
+	a, ok := sch.GetAllCols().GetByName("a")
+	require.True(t, ok)
+	assert.Equal(t, types.IntKind, a.Kind)
+
+	b, ok := sch.GetAllCols().GetByName("b")
+	require.True(t, ok)
+	assert.Equal(t, types.StringKind, b.Kind)
+
+	expected := []row.TaggedValues{
+		{a.Tag: types.Int(1), b.Tag: types.String("x")},
+		{a.Tag: types.Int(2)},
+		{a.Tag: types.Int(3), b.Tag: types.String("x")},
+	}
+
+	for _, taggedVals := range expected {
+		r, err := rd.ReadRow(context.Background())
+		require.NoError(t, err)
+
+		for tag, val := range taggedVals {
+			actual, _ := r.GetColVal(tag)
+			assert.Equal(t, val, actual)
+		}
+
+		_, ok := r.GetColVal(b.Tag)
+		assert.Equal(t, taggedVals[b.Tag] != nil, ok)
+	}
+
+	_, err = rd.ReadRow(context.Background())
+	assert.Equal(t, io.EOF, err)
+}
+
+// TestReadFixtures reads files written by Spark, pyarrow and other tools. The expected values were checked against
+// another parquet implementation. See testdata/README.md for where each file comes from.
+func TestReadFixtures(t *testing.T) {
+	tests := []struct {
+		file        string
+		numRows     int
+		expected    map[int]map[string]types.Value
+		expectedErr string
+	}{
+		{
+			file:    "delta_encoding_required_column.parquet",
+			numRows: 100,
+			expected: map[int]map[string]types.Value{
+				0: {
+					"c_customer_sk:":   types.Int(105),
+					"c_birth_year:":    types.Int(1945),
+					"c_first_name:":    types.String("Frank"),
+					"c_birth_country:": types.String("VIRGIN ISLANDS, U.S."),
+				},
+				99: {
+					"c_customer_sk:":   types.Int(1),
+					"c_last_name:":     types.String("Lewis"),
+					"c_email_address:": types.String("Javier.Lewis@VFAxlnZEvOx.org"),
+				},
+			},
+		},
+		{
+			file:     "fixed_length_decimal_legacy.parquet",
+			numRows:  24,
+			expected: map[int]map[string]types.Value{0: {"value": types.Float(1)}, 23: {"value": types.Float(24)}},
+		},
+		{
+			file:     "int32_decimal.parquet",
+			numRows:  24,
+			expected: map[int]map[string]types.Value{0: {"value": types.Float(1)}, 23: {"value": types.Float(24)}},
+		},
+		{
+			file:    "null_columns.parquet",
+			numRows: 4,
+			expected: map[int]map[string]types.Value{
+				0: {"name": types.String("test1"), "value": nil},
+				3: {"name": types.String("test4"), "value": nil},
+			},
+		},
+		{
+			file:    "delta_length_byte_array.parquet",
+			numRows: 1000,
+			expected: map[int]map[string]types.Value{
+				0:   {"FRUIT": types.String("apple_banana_mango0")},
+				999: {"FRUIT": types.String("apple_banana_mango998001")},
+			},
+		},
+		{
+			file:    "rle_boolean_encoding.parquet",
+			numRows: 68,
+			expected: map[int]map[string]types.Value{
+				0:  {"datatype_boolean": types.Bool(true)},
+				1:  {"datatype_boolean": types.Bool(false)},
+				2:  {"datatype_boolean": nil},
+				67: {"datatype_boolean": types.Bool(true)},
+			},
+		},
+		{
+			file:        "lz4_raw_compressed.parquet",
+			expectedErr: "column 'c0' is compressed with LZ4_RAW, which isn't supported",
+		},
+		{
+			file:        "malformed/negative_page_size.parquet",
+			expectedErr: "invalid parquet metadata",
+		},
+		{
+			file:        "malformed/negative_row_group_num_rows.parquet",
+			expectedErr: "invalid parquet metadata",
+		},
+		{
+			file:        "malformed/num_values_exceeds_data.parquet",
+			expectedErr: "invalid parquet file: column 'id' has more values in its pages than in its metadata",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			ctx := context.Background()
+			rd, err := OpenParquetReader(types.Format_7_18, filepath.Join("testdata", tt.file), filesys.LocalFS, NewParquetInfo())
+			require.NoError(t, err)
+			defer rd.Close(ctx)
+
+			allCols := rd.GetSchema().GetAllCols()
+
+			var rows []row.Row
+			for {
+				r, err := rd.ReadRow(ctx)
+
+				if err == io.EOF {
+					break
+				} else if tt.expectedErr != "" {
+					require.EqualError(t, err, tt.expectedErr)
+					return
+				}
+
+				require.NoError(t, err)
+				rows = append(rows, r)
+			}
+
+			require.Empty(t, tt.expectedErr)
+			require.Len(t, rows, tt.numRows)
+
+			for i, expected := range tt.expected {
+				for name, expectedVal := range expected {
+					col, ok := allCols.GetByName(name)
+					require.True(t, ok)
+
+					val, ok := rows[i].GetColVal(col.Tag)
+					assert.Equal(t, expectedVal != nil, ok, "row %d column %s", i, name)
+
+					if expectedVal != nil {
+						assert.Equal(t, expectedVal, val, "row %d column %s", i, name)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestReadCorruptSizes checks that sizes read from a file are bounded by the data they describe before anything is
+// allocated for them.
+func TestReadCorruptSizes(t *testing.T) {
+	col := column{name: "a", kind: types.IntKind, physical: typeInt64, required: true}
+
+	t.Run("huge thrift list", func(t *testing.T) {
+		var buf bytes.Buffer
+		buf.WriteByte(0x29) // field 2, a list
+		buf.WriteByte(0xfc) // of structs, with its size following
+		writeUvarint(&buf, 1<<40)
+		_, err := decodeFileMetaData(buf.Bytes())
+		assert.Equal(t, errThriftTruncated, err)
+	})
+
+	t.Run("deeply nested thrift", func(t *testing.T) {
+		_, err := decodeFileMetaData(bytes.Repeat([]byte{0x1c}, 1000))
+		assert.Equal(t, errThriftTooDeep, err)
+	})
+
+	t.Run("page with more values than its chunk", func(t *testing.T) {
+		page := pageHeader{typ: pageData, uncompressedSize: 8, compressedSize: 8, numValues: 1 << 30, encoding: encodingPlain}.encode()
+		page = append(page, make([]byte, 8)...)
+		_, err := readColumnChunk(page, col, Uncompressed, 1)
+		assert.EqualError(t, err, "invalid parquet file: column 'a' has more values in its pages than in its metadata")
+	})
+
+	t.Run("page with more values than data", func(t *testing.T) {
+		page := pageHeader{typ: pageData, uncompressedSize: 8, compressedSize: 8, numValues: 1 << 30, encoding: encodingPlain}.encode()
+		page = append(page, make([]byte, 8)...)
+		_, err := readColumnChunk(page, col, Uncompressed, 1<<30)
+		assert.Equal(t, errTruncatedPage, err)
+	})
+
+	t.Run("compressed page larger than its header says", func(t *testing.T) {
+		data, err := compress(Gzip, make([]byte, 1<<20))
+		require.NoError(t, err)
+
+		page := pageHeader{typ: pageData, uncompressedSize: 8, compressedSize: int32(len(data)), numValues: 1, encoding: encodingPlain}.encode()
+		page = append(page, data...)
+		_, err = readColumnChunk(page, col, Gzip, 1)
+		assert.Equal(t, errPageSize, err)
+	})
+
+	t.Run("column chunk past the end of the file", func(t *testing.T) {
+		var file bytes.Buffer
+		file.WriteString(magic)
+
+		meta := &fileMetaData{
+			version: 1,
+			schema:  []schemaElement{{name: "schema", numChildren: 1}, {name: "a", hasType: true, typ: typeInt64}},
+			numRows: 1,
+			rowGroups: []rowGroup{{numRows: 1, columns: []columnMetaData{
+				{typ: typeInt64, path: []string{"a"}, numValues: 1, totalCompressedSize: 1 << 40, dataPageOffset: 4},
+			}}},
+		}
+
+		metaData := meta.encode()
+		file.Write(metaData)
+		file.Write([]byte{byte(len(metaData)), byte(len(metaData) >> 8), 0, 0})
+		file.WriteString(magic)
+
+		fs := filesys.NewInMemFS(nil, map[string][]byte{"/file.parquet": file.Bytes()}, "/")
+		rd, err := OpenParquetReader(types.Format_7_18, "/file.parquet", fs, NewParquetInfo())
+		require.NoError(t, err)
+		defer rd.Close(context.Background())
+
+		_, err = rd.ReadRow(context.Background())
+		assert.EqualError(t, err, "invalid parquet file: column 'a' has a column chunk outside of the data of the file")
+	})
+}
+
+func TestUnsupportedTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		element     schemaElement
+		expectedErr string
+	}{
+		{
+			name:        "int96",
+			element:     schemaElement{name: "ts", hasType: true, typ: typeInt96},
+			expectedErr: "column 'ts' has the deprecated INT96 type, which isn't supported",
+		},
+		{
+			name:        "date",
+			element:     schemaElement{name: "d", hasType: true, typ: typeInt32, convertedType: convertedDate, hasConverted: true},
+			expectedErr: "column 'd' has a date or time type, which isn't supported",
+		},
+		{
+			name:        "repeated",
+			element:     schemaElement{name: "r", hasType: true, typ: typeInt64, repetition: repetitionRepeated},
+			expectedErr: "column 'r' is nested or repeated, which isn't supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := leafColumn(tt.element, 0)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
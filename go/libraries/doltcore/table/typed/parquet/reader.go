@@ -0,0 +1,465 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+var ErrNotParquet = errors.New("not a parquet file")
+
+// ParquetReader reads the rows of a parquet file. Row groups are read and decoded one at a time, so only a single row
+// group of a file needs to fit in memory.
+type ParquetReader struct {
+	closer io.Closer
+	rd     io.ReaderAt
+	nbf    *types.NomsBinFormat
+	sch    schema.Schema
+	cols   []column
+	meta   *fileMetaData
+
+	// the offset of the file metadata, which the column chunks must come before
+	metaOffset int64
+
+	// the values of the current row group by column, with nil for nulls
+	nextRowGroup int
+	vals         [][]types.Value
+	numRows      int
+	rowInd       int
+}
+
+func OpenParquetReader(nbf *types.NomsBinFormat, path string, fs filesys.ReadableFS, info *ParquetFileInfo) (*ParquetReader, error) {
+	r, err := fs.OpenForRead(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewParquetReader(nbf, r, info)
+}
+
+// NewParquetReader returns a reader for the parquet file read from r. The footer of a parquet file is at its end, so files
+// that can't be read at arbitrary offsets are read into memory first.
+func NewParquetReader(nbf *types.NomsBinFormat, r io.ReadCloser, info *ParquetFileInfo) (*ParquetReader, error) {
+	var rd io.ReaderAt
+	var size int64
+	if f, ok := r.(interface {
+		io.ReaderAt
+		Stat() (os.FileInfo, error)
+	}); ok {
+		fi, err := f.Stat()
+
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+
+		rd, size = f, fi.Size()
+	} else {
+		data, err := ioutil.ReadAll(r)
+
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+
+		rd, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	pr, err := newParquetReader(nbf, rd, size, r)
+
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func newParquetReader(nbf *types.NomsBinFormat, rd io.ReaderAt, size int64, closer io.Closer) (*ParquetReader, error) {
+	footerSize := int64(len(magic) + 4)
+
+	if size < int64(len(magic))+footerSize {
+		return nil, ErrNotParquet
+	}
+
+	header := make([]byte, len(magic))
+	footer := make([]byte, footerSize)
+
+	if _, err := rd.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+
+	if _, err := rd.ReadAt(footer, size-footerSize); err != nil {
+		return nil, err
+	}
+
+	if string(header) != magic || string(footer[4:]) != magic {
+		return nil, ErrNotParquet
+	}
+
+	metaSize := int64(binary.LittleEndian.Uint32(footer))
+
+	if metaSize > size-int64(len(magic))-footerSize {
+		return nil, errInvalidMetadata
+	}
+
+	metaData := make([]byte, metaSize)
+
+	if _, err := rd.ReadAt(metaData, size-footerSize-metaSize); err != nil {
+		return nil, err
+	}
+
+	meta, err := decodeFileMetaData(metaData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sch, cols, err := schemaFromMetadata(meta)
+
+	if err != nil {
+		return nil, err
+	}
+
+	metaOffset := size - footerSize - metaSize
+	return &ParquetReader{closer: closer, rd: rd, nbf: nbf, sch: sch, cols: cols, meta: meta, metaOffset: metaOffset}, nil
+}
+
+// GetSchema gets the schema of the rows that this reader will return
+func (pr *ParquetReader) GetSchema() schema.Schema {
+	return pr.sch
+}
+
+// ReadRow reads a row from a table. If there is a bad row the returned error will be non nil, and calling IsBadRow(err)
+// will be return true. This is a potentially non-fatal error and callers can decide if they want to continue on a bad row,
+// or fail.
+func (pr *ParquetReader) ReadRow(ctx context.Context) (row.Row, error) {
+	for pr.rowInd >= pr.numRows {
+		if pr.nextRowGroup >= len(pr.meta.rowGroups) {
+			return nil, io.EOF
+		}
+
+		if err := pr.readRowGroup(pr.meta.rowGroups[pr.nextRowGroup]); err != nil {
+			return nil, err
+		}
+
+		pr.nextRowGroup++
+	}
+
+	taggedVals := make(row.TaggedValues, len(pr.cols))
+	for i, col := range pr.cols {
+		if val := pr.vals[i][pr.rowInd]; val != nil {
+			taggedVals[col.tag] = val
+		}
+	}
+
+	pr.rowInd++
+
+	return row.New(pr.nbf, pr.sch, taggedVals)
+}
+
+// readRowGroup reads and decodes the column chunks of a row group. The sizes and offsets of the chunks come from the file,
+// so they're checked against the file before anything is read.
+func (pr *ParquetReader) readRowGroup(rg rowGroup) error {
+	if len(rg.columns) != len(pr.cols) || rg.numRows < 0 {
+		return errInvalidMetadata
+	}
+
+	vals := make([][]types.Value, len(pr.cols))
+	for i, cmd := range rg.columns {
+		if cmd.numValues != rg.numRows {
+			return fmt.Errorf("invalid parquet file: column '%s' has %d values in a row group of %d rows", pr.cols[i].name, cmd.numValues, rg.numRows)
+		}
+
+		offset := cmd.chunkOffset()
+
+		if offset < int64(len(magic)) || cmd.totalCompressedSize < 0 || cmd.totalCompressedSize > pr.metaOffset-offset {
+			return fmt.Errorf("invalid parquet file: column '%s' has a column chunk outside of the data of the file", pr.cols[i].name)
+		}
+
+		data := make([]byte, cmd.totalCompressedSize)
+
+		if _, err := pr.rd.ReadAt(data, offset); err != nil {
+			return err
+		}
+
+		colVals, err := readColumnChunk(data, pr.cols[i], CompressionCodec(cmd.codec), int(cmd.numValues))
+
+		if err != nil {
+			return err
+		}
+
+		vals[i] = colVals
+	}
+
+	pr.vals = vals
+	pr.numRows = int(rg.numRows)
+	pr.rowInd = 0
+
+	return nil
+}
+
+// readColumnChunk decodes the pages of a column chunk, returning its values with nil for nulls. The number of values in
+// each page comes from its header, and may not exceed the number of values left in the chunk.
+func readColumnChunk(data []byte, col column, codec CompressionCodec, numValues int) ([]types.Value, error) {
+	if !codec.supported() {
+		return nil, fmt.Errorf("column '%s' is compressed with %s, which isn't supported", col.name, codec)
+	}
+
+	r := &thriftReader{buf: data}
+
+	var dict []types.Value
+	var vals []types.Value
+	for len(vals) < numValues {
+		ph, err := readPageHeader(r)
+
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := r.readBytes(int(ph.compressedSize))
+
+		if err != nil {
+			return nil, errTruncatedPage
+		}
+
+		if ph.typ != pageDictionary && int(ph.numValues) > numValues-len(vals) {
+			return nil, fmt.Errorf("invalid parquet file: column '%s' has more values in its pages than in its metadata", col.name)
+		}
+
+		var present []bool
+		switch ph.typ {
+		case pageDictionary:
+			body, err = decompress(codec, body, int(ph.uncompressedSize))
+
+			if err != nil {
+				return nil, err
+			}
+
+			dict, err = col.decodePlain(body, int(ph.numValues))
+
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+
+		case pageData:
+			body, err = decompress(codec, body, int(ph.uncompressedSize))
+
+			if err != nil {
+				return nil, err
+			}
+
+			present, body, err = readLevels(body, col, int(ph.numValues), true)
+
+			if err != nil {
+				return nil, err
+			}
+
+		case pageDataV2:
+			// the levels of v2 data pages aren't compressed, and come before the values. Repeated columns aren't
+			// supported, so every repetition level is 0 and they're skipped.
+			repLen, defLen := int(ph.repLevelsLen), int(ph.defLevelsLen)
+
+			if repLen+defLen > len(body) || repLen+defLen > int(ph.uncompressedSize) {
+				return nil, errTruncatedPage
+			}
+
+			present, _, err = readLevels(body[repLen:repLen+defLen], col, int(ph.numValues), false)
+
+			if err != nil {
+				return nil, err
+			}
+
+			body = body[repLen+defLen:]
+
+			if ph.isCompressed {
+				body, err = decompress(codec, body, int(ph.uncompressedSize)-repLen-defLen)
+
+				if err != nil {
+					return nil, err
+				}
+			}
+
+		default:
+			// index pages aren't needed to read the values
+			continue
+		}
+
+		pageVals, err := decodeValues(body, col, ph.encoding, int(ph.numValues), present, dict)
+
+		if err != nil {
+			return nil, err
+		}
+
+		vals = append(vals, pageVals...)
+	}
+
+	return vals, nil
+}
+
+// readLevels decodes the definition levels of a data page, returning whether each value is present and the rest of the
+// page. Definition levels of v1 data pages are prefixed with their length. Every value of a required column is present,
+// which is returned as nil.
+func readLevels(data []byte, col column, n int, hasLen bool) ([]bool, []byte, error) {
+	if col.required {
+		return nil, data, nil
+	}
+
+	levels := data
+	if hasLen {
+		if len(data) < 4 {
+			return nil, nil, errTruncatedPage
+		}
+
+		size := int(binary.LittleEndian.Uint32(data))
+
+		if size < 0 || 4+size > len(data) {
+			return nil, nil, errTruncatedPage
+		}
+
+		levels, data = data[4:4+size], data[4+size:]
+	}
+
+	defs, err := decodeHybrid(levels, 1, n)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	present := make([]bool, len(defs))
+	for i, def := range defs {
+		present[i] = def == 1
+	}
+
+	return present, data, nil
+}
+
+// decodeValues decodes the n values of a data page, with nil for the values that aren't present.
+func decodeValues(data []byte, col column, encoding int32, n int, present []bool, dict []types.Value) ([]types.Value, error) {
+	numPresent := n
+	if present != nil {
+		numPresent = 0
+		for _, p := range present {
+			if p {
+				numPresent++
+			}
+		}
+	}
+
+	var decoded []types.Value
+	switch encoding {
+	case encodingPlain:
+		var err error
+		decoded, err = col.decodePlain(data, numPresent)
+
+		if err != nil {
+			return nil, err
+		}
+
+	case encodingPlainDictionary, encodingRLEDictionary:
+		if len(data) == 0 {
+			if numPresent > 0 {
+				return nil, errTruncatedPage
+			}
+
+			break
+		}
+
+		indices, err := decodeHybrid(data[1:], int(data[0]), numPresent)
+
+		if err != nil {
+			return nil, err
+		}
+
+		decoded = make([]types.Value, numPresent)
+		for i, ind := range indices {
+			if int(ind) >= len(dict) {
+				return nil, fmt.Errorf("invalid parquet file: column '%s' has a dictionary index out of range", col.name)
+			}
+
+			decoded[i] = dict[ind]
+		}
+
+	case encodingRLE:
+		if col.physical != typeBoolean {
+			return nil, fmt.Errorf("column '%s' uses the RLE encoding, which is only supported for booleans", col.name)
+		} else if len(data) < 4 {
+			return nil, errTruncatedPage
+		}
+
+		bits, err := decodeHybrid(data[4:], 1, numPresent)
+
+		if err != nil {
+			return nil, err
+		}
+
+		decoded = make([]types.Value, numPresent)
+		for i, bit := range bits {
+			decoded[i] = types.Bool(bit == 1)
+		}
+
+	case encodingDeltaBinaryPacked, encodingDeltaLengthByteArray, encodingDeltaByteArray:
+		var err error
+		decoded, err = col.decodeDelta(data, encoding, numPresent)
+
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("column '%s' uses parquet encoding %s, which isn't supported", col.name, encodingName(encoding))
+	}
+
+	if present == nil {
+		return decoded, nil
+	}
+
+	vals := make([]types.Value, len(present))
+	for i, j := 0, 0; i < len(present); i++ {
+		if present[i] {
+			vals[i] = decoded[j]
+			j++
+		}
+	}
+
+	return vals, nil
+}
+
+// Close should release resources being held
+func (pr *ParquetReader) Close(ctx context.Context) error {
+	if pr.closer != nil {
+		err := pr.closer.Close()
+		pr.closer = nil
+
+		return err
+	}
+
+	return errors.New("already closed")
+}
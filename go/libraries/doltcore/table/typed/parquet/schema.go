@@ -0,0 +1,526 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// DoltSchemaKey is the key of the file metadata entry that holds the dolt schema of an exported table. Importing a file
+// with this entry recreates the table with the same tags, primary key and constraints.
+const DoltSchemaKey = "dolt.schema"
+
+// column describes how the values of a dolt column are stored in a parquet column.
+type column struct {
+	tag        uint64
+	name       string
+	kind       types.NomsKind
+	physical   int32
+	typeLength int32
+	required   bool
+
+	// scale of decimal values, which are imported as floats
+	scale int32
+
+	// json is true for string columns holding JSON documents
+	json bool
+}
+
+// schemaElements returns the parquet schema elements for a dolt schema, and the columns written for it. Each column is
+// mapped to a leaf of the root element as follows:
+//
+//	string: BYTE_ARRAY annotated as STRING
+//	int:    INT64 annotated as a signed 64 bit INTEGER
+//	uint:   INT64 annotated as an unsigned 64 bit INTEGER
+//	float:  DOUBLE
+//	bool:   BOOLEAN
+//	uuid:   FIXED_LEN_BYTE_ARRAY of length 16 annotated as UUID
+//	json:   BYTE_ARRAY annotated as JSON
+//
+// Columns with a NOT NULL constraint are REQUIRED, and all others are OPTIONAL.
+func schemaElements(sch schema.Schema) ([]schemaElement, []column, error) {
+	allCols := sch.GetAllCols()
+	elements := []schemaElement{{name: "schema", numChildren: int32(allCols.Size())}}
+
+	var cols []column
+	err := allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		se := schemaElement{name: col.Name, hasType: true, repetition: repetitionOptional}
+		if !col.IsNullable() {
+			se.repetition = repetitionRequired
+		}
+
+		switch col.Kind {
+		case types.StringKind:
+			se.typ = typeByteArray
+			se.convertedType, se.hasConverted = convertedUTF8, true
+
+			se.logicalType = thriftStruct{logicalString: thriftStruct{}}
+
+			if col.IsJSON() {
+				se.convertedType = convertedJSON
+				se.logicalType = thriftStruct{logicalJSON: thriftStruct{}}
+			}
+		case types.IntKind:
+			se.typ = typeInt64
+			se.convertedType, se.hasConverted = convertedInt64, true
+			se.logicalType = thriftStruct{logicalInteger: thriftStruct{1: int64(64), 2: true}}
+		case types.UintKind:
+			se.typ = typeInt64
+			se.convertedType, se.hasConverted = convertedUint64, true
+			se.logicalType = thriftStruct{logicalInteger: thriftStruct{1: int64(64), 2: false}}
+		case types.FloatKind:
+			se.typ = typeDouble
+		case types.BoolKind:
+			se.typ = typeBoolean
+		case types.UUIDKind:
+			se.typ = typeFixedLenByteArray
+			se.typeLength = 16
+			se.logicalType = thriftStruct{logicalUUID: thriftStruct{}}
+		default:
+			return true, fmt.Errorf("column '%s' has type %s, which can't be written to a parquet file", col.Name, col.TypeString())
+		}
+
+		elements = append(elements, se)
+		cols = append(cols, column{
+			tag:        tag,
+			name:       col.Name,
+			kind:       col.Kind,
+			json:       col.IsJSON(),
+			physical:   se.typ,
+			typeLength: se.typeLength,
+			required:   se.repetition == repetitionRequired,
+		})
+
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return elements, cols, nil
+}
+
+// schemaFromMetadata returns the dolt schema of a parquet file, and the columns to read for it. Files written by dolt
+// have their original schema in their metadata. The schema of other files is mapped from their parquet types, with tags
+// assigned in column order and no primary key.
+func schemaFromMetadata(fmd *fileMetaData) (schema.Schema, []column, error) {
+	if len(fmd.schema) == 0 {
+		return nil, nil, errInvalidMetadata
+	}
+
+	var cols []column
+	for i, se := range fmd.schema[1:] {
+		col, err := leafColumn(se, uint64(i))
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cols = append(cols, col)
+	}
+
+	if int(fmd.schema[0].numChildren) != len(cols) {
+		return nil, nil, errors.New("nested parquet columns are not supported")
+	}
+
+	if schJson, ok := fmd.keyValue(DoltSchemaKey); ok {
+		sch, err := encoding.UnmarshalJson(schJson)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if err := matchDoltSchema(sch, cols); err != nil {
+			return nil, nil, err
+		}
+
+		return sch, cols, nil
+	}
+
+	schCols := make([]schema.Column, len(cols))
+	for i, col := range cols {
+		var constraints []schema.ColConstraint
+		if col.required {
+			constraints = append(constraints, schema.NotNullConstraint{})
+		}
+
+		schCols[i] = schema.NewColumn(col.name, col.tag, col.kind, false, constraints...)
+
+		if col.json {
+			schCols[i].TypeName = schema.JSONTypeName
+		}
+	}
+
+	colColl, err := schema.NewColCollection(schCols...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return schema.UnkeyedSchemaFromCols(colColl), cols, nil
+}
+
+// matchDoltSchema checks that the dolt schema in a file's metadata matches its parquet columns, and sets their tags.
+func matchDoltSchema(sch schema.Schema, cols []column) error {
+	allCols := sch.GetAllCols()
+
+	if allCols.Size() != len(cols) {
+		return errors.New("the dolt schema of the parquet file doesn't match its columns")
+	}
+
+	for i := range cols {
+		schCol, ok := allCols.GetByName(cols[i].name)
+
+		if !ok || schCol.Kind != cols[i].kind {
+			return fmt.Errorf("the dolt schema of the parquet file doesn't match its column '%s'", cols[i].name)
+		}
+
+		cols[i].tag = schCol.Tag
+	}
+
+	return nil
+}
+
+// leafColumn returns the dolt column for a leaf of a parquet schema, mapping its type to the closest noms kind. Date and
+// time types and INT96 timestamps aren't supported.
+func leafColumn(se schemaElement, tag uint64) (column, error) {
+	if !se.hasType || se.numChildren > 0 || se.repetition == repetitionRepeated {
+		return column{}, fmt.Errorf("column '%s' is nested or repeated, which isn't supported", se.name)
+	}
+
+	col := column{
+		tag:        tag,
+		name:       se.name,
+		physical:   se.typ,
+		typeLength: se.typeLength,
+		required:   se.repetition == repetitionRequired,
+	}
+
+	lt := se.logicalType
+	isConverted := func(converted ...int32) bool {
+		for _, ct := range converted {
+			if se.hasConverted && se.convertedType == ct {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if lt.has(logicalDate) || lt.has(logicalTime) || lt.has(logicalTimestamp) || isConverted(convertedDate,
+		convertedTimeMillis, convertedTimeMicros, convertedTimestampMillis, convertedTimestampMicros) {
+		return column{}, fmt.Errorf("column '%s' has a date or time type, which isn't supported", se.name)
+	}
+
+	isDecimal := lt.has(logicalDecimal) || isConverted(convertedDecimal)
+	if isDecimal {
+		col.scale = se.scale
+
+		if lt.has(logicalDecimal) {
+			col.scale = int32(lt.structField(logicalDecimal).int(1))
+		}
+	}
+
+	switch se.typ {
+	case typeBoolean:
+		col.kind = types.BoolKind
+
+	case typeInt32, typeInt64:
+		if isDecimal {
+			col.kind = types.FloatKind
+		} else if (lt.has(logicalInteger) && !lt.structField(logicalInteger).bool(2)) ||
+			isConverted(convertedUint8, convertedUint16, convertedUint32, convertedUint64) {
+			col.kind = types.UintKind
+		} else {
+			col.kind = types.IntKind
+		}
+
+	case typeFloat, typeDouble:
+		col.kind = types.FloatKind
+
+	case typeByteArray:
+		col.kind = types.StringKind
+		col.json = lt.has(logicalJSON) || isConverted(convertedJSON)
+
+		if isDecimal {
+			col.kind = types.FloatKind
+		}
+
+	case typeFixedLenByteArray:
+		if isDecimal && se.typeLength > 0 {
+			col.kind = types.FloatKind
+		} else if lt.has(logicalUUID) && se.typeLength == 16 {
+			col.kind = types.UUIDKind
+		} else {
+			return column{}, fmt.Errorf("column '%s' is a fixed length byte array, which is only supported for uuids and decimals", se.name)
+		}
+
+	case typeInt96:
+		return column{}, fmt.Errorf("column '%s' has the deprecated INT96 type, which isn't supported", se.name)
+
+	default:
+		return column{}, fmt.Errorf("column '%s' has unknown parquet type %d", se.name, se.typ)
+	}
+
+	return col, nil
+}
+
+// decodePlain decodes n PLAIN encoded values of the column. The number of values comes from a page header, so it's
+// checked against the size of the data before the values are allocated.
+func (col column) decodePlain(data []byte, n int) ([]types.Value, error) {
+	var minSize int
+	switch col.physical {
+	case typeBoolean:
+		minSize = (n + 7) / 8
+	case typeInt32, typeFloat, typeByteArray:
+		// byte arrays are prefixed with their 4 byte length
+		minSize = 4 * n
+	case typeInt64, typeDouble:
+		minSize = 8 * n
+	case typeFixedLenByteArray:
+		minSize = int(col.typeLength) * n
+	}
+
+	if n < 0 || minSize < 0 || len(data) < minSize {
+		return nil, errTruncatedPage
+	}
+
+	vals := make([]types.Value, n)
+
+	var err error
+	switch col.physical {
+	case typeBoolean:
+		for i := range vals {
+			vals[i] = types.Bool(data[i/8]>>uint(i%8)&1 == 1)
+		}
+
+	case typeInt32, typeFloat:
+		for i := range vals {
+			bits := binary.LittleEndian.Uint32(data[4*i:])
+
+			if col.physical == typeFloat {
+				vals[i] = types.Float(math.Float32frombits(bits))
+			} else {
+				vals[i] = col.intValue(int64(int32(bits)), uint64(bits))
+			}
+		}
+
+	case typeInt64, typeDouble:
+		for i := range vals {
+			bits := binary.LittleEndian.Uint64(data[8*i:])
+
+			if col.physical == typeDouble {
+				vals[i] = types.Float(math.Float64frombits(bits))
+			} else {
+				vals[i] = col.intValue(int64(bits), bits)
+			}
+		}
+
+	case typeByteArray:
+		pos := 0
+		for i := range vals {
+			if pos+4 > len(data) {
+				return nil, errTruncatedPage
+			}
+
+			size := int(binary.LittleEndian.Uint32(data[pos:]))
+			pos += 4
+
+			if size < 0 || size > len(data)-pos {
+				return nil, errTruncatedPage
+			}
+
+			vals[i], err = col.bytesValue(data[pos : pos+size])
+			pos += size
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	case typeFixedLenByteArray:
+		size := int(col.typeLength)
+		for i := range vals {
+			vals[i], err = col.bytesValue(data[size*i : size*(i+1)])
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return vals, nil
+}
+
+// decodeDelta decodes n values of the column encoded with one of the delta encodings. DELTA_BINARY_PACKED is used for
+// integers, and DELTA_LENGTH_BYTE_ARRAY and DELTA_BYTE_ARRAY for byte arrays.
+func (col column) decodeDelta(data []byte, encoding int32, n int) ([]types.Value, error) {
+	vals := make([]types.Value, n)
+
+	switch {
+	case encoding == encodingDeltaBinaryPacked && (col.physical == typeInt32 || col.physical == typeInt64):
+		ints, _, err := decodeDeltaBinaryPacked(data, n)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i, val := range ints {
+			if col.physical == typeInt32 {
+				vals[i] = col.intValue(int64(int32(val)), uint64(uint32(val)))
+			} else {
+				vals[i] = col.intValue(val, uint64(val))
+			}
+		}
+
+	case encoding != encodingDeltaBinaryPacked && (col.physical == typeByteArray || col.physical == typeFixedLenByteArray):
+		var byteArrays [][]byte
+		var err error
+		if encoding == encodingDeltaLengthByteArray {
+			byteArrays, _, err = decodeDeltaLengthByteArray(data, n)
+		} else {
+			byteArrays, err = decodeDeltaByteArray(data, n)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i, b := range byteArrays {
+			vals[i], err = col.bytesValue(b)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("column '%s' uses parquet encoding %s, which isn't supported for its type", col.name, encodingName(encoding))
+	}
+
+	return vals, nil
+}
+
+// bytesValue returns the value of a byte array of the column, which is a string, a JSON document, a uuid, or a decimal
+// stored as a big-endian two's complement integer.
+func (col column) bytesValue(b []byte) (types.Value, error) {
+	if col.kind == types.FloatKind {
+		unscaled := new(big.Int).SetBytes(b)
+
+		if len(b) > 0 && b[0]&0x80 != 0 {
+			unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+		}
+
+		f, _ := new(big.Float).Quo(new(big.Float).SetInt(unscaled), new(big.Float).SetFloat64(math.Pow10(int(col.scale)))).Float64()
+		return types.Float(f), nil
+	}
+
+	if col.kind == types.UUIDKind {
+		if len(b) != 16 {
+			return nil, fmt.Errorf("invalid parquet file: column '%s' has a uuid of %d bytes", col.name, len(b))
+		}
+
+		var u types.UUID
+		copy(u[:], b)
+		return u, nil
+	}
+
+	if col.json {
+		val, err := doltcore.ParseJSON(string(b))
+
+		if err != nil {
+			return nil, fmt.Errorf("column '%s' has an invalid JSON value: %v", col.name, err)
+		}
+
+		return val, nil
+	}
+
+	return types.String(b), nil
+}
+
+func (col column) intValue(signed int64, unsigned uint64) types.Value {
+	switch col.kind {
+	case types.UintKind:
+		return types.Uint(unsigned)
+	case types.FloatKind:
+		return types.Float(float64(signed) / math.Pow10(int(col.scale)))
+	}
+
+	return types.Int(signed)
+}
+
+// encodePlain PLAIN encodes the non-null values of the column.
+func (col column) encodePlain(buf *bytes.Buffer, vals []types.Value) error {
+	var scratch [8]byte
+
+	if col.physical == typeBoolean {
+		packed := make([]byte, (len(vals)+7)/8)
+		for i, val := range vals {
+			b, ok := val.(types.Bool)
+
+			if !ok {
+				return col.kindErr(val)
+			}
+
+			if b {
+				packed[i/8] |= 1 << uint(i%8)
+			}
+		}
+
+		buf.Write(packed)
+		return nil
+	}
+
+	for _, val := range vals {
+		if val.Kind() != col.kind {
+			return col.kindErr(val)
+		}
+
+		switch v := val.(type) {
+		case types.Int:
+			binary.LittleEndian.PutUint64(scratch[:], uint64(v))
+			buf.Write(scratch[:])
+		case types.Uint:
+			binary.LittleEndian.PutUint64(scratch[:], uint64(v))
+			buf.Write(scratch[:])
+		case types.Float:
+			binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(float64(v)))
+			buf.Write(scratch[:])
+		case types.String:
+			binary.LittleEndian.PutUint32(scratch[:], uint32(len(v)))
+			buf.Write(scratch[:4])
+			buf.WriteString(string(v))
+		case types.UUID:
+			buf.Write(v[:])
+		}
+	}
+
+	return nil
+}
+
+func (col column) kindErr(val types.Value) error {
+	return fmt.Errorf("column '%s' has a value of type %s, but should have type %s", col.name, types.KindToString[val.Kind()], types.KindToString[col.kind])
+}
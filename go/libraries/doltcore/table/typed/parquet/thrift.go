@@ -0,0 +1,405 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Parquet metadata is serialized with the Thrift compact protocol. Only the parts of the protocol used by the Parquet
+// format are implemented here.
+
+// Thrift compact protocol type ids
+const (
+	ctStop   byte = 0
+	ctTrue   byte = 1
+	ctFalse  byte = 2
+	ctByte   byte = 3
+	ctI16    byte = 4
+	ctI32    byte = 5
+	ctI64    byte = 6
+	ctDouble byte = 7
+	ctBinary byte = 8
+	ctList   byte = 9
+	ctSet    byte = 10
+	ctMap    byte = 11
+	ctStruct byte = 12
+)
+
+var errThriftTruncated = errors.New("invalid parquet metadata: unexpected end of data")
+var errThriftTooDeep = errors.New("invalid parquet metadata: structs are nested too deeply")
+
+// maxThriftDepth is the deepest that structs and containers are decoded, which the parquet format never comes close to
+const maxThriftDepth = 64
+
+// thriftStruct is a decoded Thrift struct, mapping field ids to their values. Integers are decoded as int64, doubles as
+// float64, binary values as []byte, lists and sets as []interface{}, and structs as thriftStruct.
+type thriftStruct map[int16]interface{}
+
+func (ts thriftStruct) has(id int16) bool {
+	_, ok := ts[id]
+	return ok
+}
+
+func (ts thriftStruct) int(id int16) int64 {
+	n, _ := ts[id].(int64)
+	return n
+}
+
+func (ts thriftStruct) bool(id int16) bool {
+	b, _ := ts[id].(bool)
+	return b
+}
+
+func (ts thriftStruct) str(id int16) string {
+	data, _ := ts[id].([]byte)
+	return string(data)
+}
+
+func (ts thriftStruct) list(id int16) []interface{} {
+	l, _ := ts[id].([]interface{})
+	return l
+}
+
+func (ts thriftStruct) structField(id int16) thriftStruct {
+	st, _ := ts[id].(thriftStruct)
+	return st
+}
+
+// thriftReader decodes compact protocol values from a buffer. Sizes read from the buffer are checked against the data
+// left in it before anything is allocated for them.
+type thriftReader struct {
+	buf   []byte
+	pos   int
+	depth int
+}
+
+func (r *thriftReader) readByte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, errThriftTruncated
+	}
+
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *thriftReader) readBytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.buf)-r.pos {
+		return nil, errThriftTruncated
+	}
+
+	data := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return data, nil
+}
+
+func (r *thriftReader) readUvarint() (uint64, error) {
+	n, size := binary.Uvarint(r.buf[r.pos:])
+
+	if size <= 0 {
+		return 0, errThriftTruncated
+	}
+
+	r.pos += size
+	return n, nil
+}
+
+func (r *thriftReader) readZigzag() (int64, error) {
+	n, err := r.readUvarint()
+	return int64(n>>1) ^ -int64(n&1), err
+}
+
+func (r *thriftReader) readStruct() (thriftStruct, error) {
+	if r.depth >= maxThriftDepth {
+		return nil, errThriftTooDeep
+	}
+
+	r.depth++
+	defer func() { r.depth-- }()
+
+	st := make(thriftStruct)
+
+	var lastID int16
+	for {
+		b, err := r.readByte()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if b == ctStop {
+			return st, nil
+		}
+
+		typ := b & 0x0f
+		id := lastID + int16(b>>4)
+
+		if b>>4 == 0 {
+			n, err := r.readZigzag()
+
+			if err != nil {
+				return nil, err
+			}
+
+			id = int16(n)
+		}
+
+		lastID = id
+
+		switch typ {
+		case ctTrue:
+			st[id] = true
+		case ctFalse:
+			st[id] = false
+		default:
+			st[id], err = r.readValue(typ)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (r *thriftReader) readValue(typ byte) (interface{}, error) {
+	switch typ {
+	case ctTrue, ctFalse:
+		// booleans in containers are a byte each
+		b, err := r.readByte()
+		return b == ctTrue, err
+
+	case ctByte:
+		b, err := r.readByte()
+		return int64(int8(b)), err
+
+	case ctI16, ctI32, ctI64:
+		return r.readZigzag()
+
+	case ctDouble:
+		data, err := r.readBytes(8)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return math.Float64frombits(binary.LittleEndian.Uint64(data)), nil
+
+	case ctBinary:
+		n, err := r.readUvarint()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return r.readBytes(int(n))
+
+	case ctList, ctSet:
+		b, err := r.readByte()
+
+		if err != nil {
+			return nil, err
+		}
+
+		size := uint64(b >> 4)
+		if size == 15 {
+			size, err = r.readUvarint()
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// every element takes at least a byte
+		if size > uint64(len(r.buf)-r.pos) {
+			return nil, errThriftTruncated
+		}
+
+		if r.depth >= maxThriftDepth {
+			return nil, errThriftTooDeep
+		}
+
+		r.depth++
+		defer func() { r.depth-- }()
+
+		elems := make([]interface{}, 0, size)
+		for i := uint64(0); i < size; i++ {
+			elem, err := r.readValue(b & 0x0f)
+
+			if err != nil {
+				return nil, err
+			}
+
+			elems = append(elems, elem)
+		}
+
+		return elems, nil
+
+	case ctMap:
+		// maps aren't used by the parquet format, so their contents are skipped
+		size, err := r.readUvarint()
+
+		if err != nil || size == 0 {
+			return nil, err
+		}
+
+		kv, err := r.readByte()
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := uint64(0); i < size; i++ {
+			if _, err := r.readValue(kv >> 4); err != nil {
+				return nil, err
+			}
+
+			if _, err := r.readValue(kv & 0x0f); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+
+	case ctStruct:
+		return r.readStruct()
+	}
+
+	return nil, fmt.Errorf("invalid parquet metadata: unknown thrift type %d", typ)
+}
+
+// thriftField is a field of a struct being encoded. Values are encoded according to their Go type: bool, int8, int16,
+// int32, int64, float64, string and []byte values as the corresponding Thrift types, thriftList values as lists, and
+// []thriftField values as structs.
+type thriftField struct {
+	id  int16
+	val interface{}
+}
+
+// thriftList is a list being encoded, whose elements all have the Thrift type given.
+type thriftList struct {
+	elemType byte
+	elems    []interface{}
+}
+
+// writeThriftStruct encodes a struct with the fields given, which must be in increasing id order.
+func writeThriftStruct(buf *bytes.Buffer, fields []thriftField) {
+	var lastID int16
+	for _, f := range fields {
+		typ := thriftType(f.val)
+
+		if b, ok := f.val.(bool); ok && !b {
+			typ = ctFalse
+		}
+
+		if delta := f.id - lastID; delta > 0 && delta <= 15 {
+			buf.WriteByte(byte(delta<<4) | typ)
+		} else {
+			buf.WriteByte(typ)
+			writeZigzag(buf, int64(f.id))
+		}
+
+		lastID = f.id
+
+		if _, ok := f.val.(bool); !ok {
+			writeThriftValue(buf, f.val)
+		}
+	}
+
+	buf.WriteByte(ctStop)
+}
+
+func thriftType(val interface{}) byte {
+	switch val.(type) {
+	case bool:
+		return ctTrue
+	case int8:
+		return ctByte
+	case int16:
+		return ctI16
+	case int32:
+		return ctI32
+	case int64:
+		return ctI64
+	case float64:
+		return ctDouble
+	case string, []byte:
+		return ctBinary
+	case thriftList:
+		return ctList
+	case []thriftField:
+		return ctStruct
+	}
+
+	panic(fmt.Sprintf("unsupported thrift value %T", val))
+}
+
+func writeThriftValue(buf *bytes.Buffer, val interface{}) {
+	switch v := val.(type) {
+	case bool:
+		if v {
+			buf.WriteByte(ctTrue)
+		} else {
+			buf.WriteByte(ctFalse)
+		}
+	case int8:
+		buf.WriteByte(byte(v))
+	case int16:
+		writeZigzag(buf, int64(v))
+	case int32:
+		writeZigzag(buf, int64(v))
+	case int64:
+		writeZigzag(buf, v)
+	case float64:
+		var data [8]byte
+		binary.LittleEndian.PutUint64(data[:], math.Float64bits(v))
+		buf.Write(data[:])
+	case string:
+		writeUvarint(buf, uint64(len(v)))
+		buf.WriteString(v)
+	case []byte:
+		writeUvarint(buf, uint64(len(v)))
+		buf.Write(v)
+	case thriftList:
+		if len(v.elems) < 15 {
+			buf.WriteByte(byte(len(v.elems)<<4) | v.elemType)
+		} else {
+			buf.WriteByte(0xf0 | v.elemType)
+			writeUvarint(buf, uint64(len(v.elems)))
+		}
+
+		for _, elem := range v.elems {
+			writeThriftValue(buf, elem)
+		}
+	case []thriftField:
+		writeThriftStruct(buf, v)
+	default:
+		panic(fmt.Sprintf("unsupported thrift value %T", val))
+	}
+}
+
+func writeUvarint(buf *bytes.Buffer, n uint64) {
+	var data [binary.MaxVarintLen64]byte
+	size := binary.PutUvarint(data[:], n)
+	buf.Write(data[:size])
+}
+
+func writeZigzag(buf *bytes.Buffer, n int64) {
+	writeUvarint(buf, uint64((n<<1)^(n>>63)))
+}
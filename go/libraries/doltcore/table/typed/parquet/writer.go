@@ -0,0 +1,269 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+var WriteBufSize = 256 * 1024
+
+// maxRowGroupBytes limits the size of a buffered row group when rows are too large for the configured row group size.
+const maxRowGroupBytes = 64 * 1024 * 1024
+
+const createdBy = "dolt"
+
+// ParquetWriter writes rows to a parquet file. Rows are buffered in memory until a row group is full, and each row group
+// is written with a single PLAIN encoded data page per column.
+type ParquetWriter struct {
+	closer io.Closer
+	bWr    *bufio.Writer
+	info   *ParquetFileInfo
+	sch    schema.Schema
+	meta   *fileMetaData
+	cols   []column
+	offset int64
+
+	// values of the buffered rows by column, with nil for nulls
+	vals          [][]types.Value
+	numBuffered   int
+	bufferedBytes int
+}
+
+func OpenParquetWriter(path string, fs filesys.WritableFS, outSch schema.Schema, info *ParquetFileInfo) (*ParquetWriter, error) {
+	err := fs.MkDirs(filepath.Dir(path))
+
+	if err != nil {
+		return nil, err
+	}
+
+	wr, err := fs.OpenForWrite(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewParquetWriter(wr, outSch, info)
+}
+
+func NewParquetWriter(wr io.WriteCloser, outSch schema.Schema, info *ParquetFileInfo) (*ParquetWriter, error) {
+	elements, cols, err := schemaElements(outSch)
+
+	if err != nil {
+		wr.Close()
+		return nil, err
+	}
+
+	schJson, err := encoding.MarshalAsJson(outSch)
+
+	if err != nil {
+		wr.Close()
+		return nil, err
+	}
+
+	bwr := bufio.NewWriterSize(wr, WriteBufSize)
+	err = iohelp.WriteAll(bwr, []byte(magic))
+
+	if err != nil {
+		wr.Close()
+		return nil, err
+	}
+
+	meta := &fileMetaData{
+		version:   1,
+		schema:    elements,
+		keyValues: []keyValue{{DoltSchemaKey, schJson}},
+		createdBy: createdBy,
+	}
+
+	return &ParquetWriter{
+		closer: wr,
+		bWr:    bwr,
+		info:   info,
+		sch:    outSch,
+		meta:   meta,
+		cols:   cols,
+		offset: int64(len(magic)),
+		vals:   make([][]types.Value, len(cols)),
+	}, nil
+}
+
+func (pw *ParquetWriter) GetSchema() schema.Schema {
+	return pw.sch
+}
+
+// WriteRow will write a row to a table
+func (pw *ParquetWriter) WriteRow(ctx context.Context, r row.Row) error {
+	for _, col := range pw.cols {
+		val, ok := r.GetColVal(col.tag)
+
+		if (!ok || types.IsNull(val)) && col.required {
+			return fmt.Errorf("column '%s' is required, but a row has no value for it", col.name)
+		}
+	}
+
+	for i, col := range pw.cols {
+		val, ok := r.GetColVal(col.tag)
+
+		if !ok || types.IsNull(val) {
+			pw.vals[i] = append(pw.vals[i], nil)
+			continue
+		}
+
+		pw.vals[i] = append(pw.vals[i], val)
+
+		switch v := val.(type) {
+		case types.String:
+			pw.bufferedBytes += len(v)
+		default:
+			pw.bufferedBytes += 8
+		}
+	}
+
+	pw.numBuffered++
+
+	if pw.numBuffered >= pw.info.RowGroupSize || pw.bufferedBytes >= maxRowGroupBytes {
+		return pw.flushRowGroup()
+	}
+
+	return nil
+}
+
+// flushRowGroup writes the buffered rows as a row group.
+func (pw *ParquetWriter) flushRowGroup() error {
+	rg := rowGroup{numRows: int64(pw.numBuffered)}
+
+	for i, col := range pw.cols {
+		var page bytes.Buffer
+		var present []bool
+		var nonNull []types.Value
+
+		for _, val := range pw.vals[i] {
+			present = append(present, val != nil)
+
+			if val != nil {
+				nonNull = append(nonNull, val)
+			}
+		}
+
+		if !col.required {
+			page.Write(encodeLevels(present))
+		}
+
+		if err := col.encodePlain(&page, nonNull); err != nil {
+			return err
+		}
+
+		compressed, err := compress(pw.info.Compression, page.Bytes())
+
+		if err != nil {
+			return err
+		}
+
+		header := pageHeader{
+			typ:              pageData,
+			uncompressedSize: int32(page.Len()),
+			compressedSize:   int32(len(compressed)),
+			numValues:        int32(pw.numBuffered),
+			encoding:         encodingPlain,
+		}.encode()
+
+		cmd := columnMetaData{
+			typ:                   col.physical,
+			encodings:             []int32{encodingPlain, encodingRLE},
+			path:                  []string{col.name},
+			codec:                 int32(pw.info.Compression),
+			numValues:             int64(pw.numBuffered),
+			totalUncompressedSize: int64(len(header) + page.Len()),
+			totalCompressedSize:   int64(len(header) + len(compressed)),
+			dataPageOffset:        pw.offset,
+		}
+
+		if err := iohelp.WriteAll(pw.bWr, header); err != nil {
+			return err
+		}
+
+		if err := iohelp.WriteAll(pw.bWr, compressed); err != nil {
+			return err
+		}
+
+		pw.offset += cmd.totalCompressedSize
+		rg.totalByteSize += cmd.totalUncompressedSize
+		rg.columns = append(rg.columns, cmd)
+		pw.vals[i] = pw.vals[i][:0]
+	}
+
+	pw.meta.rowGroups = append(pw.meta.rowGroups, rg)
+	pw.meta.numRows += rg.numRows
+	pw.numBuffered = 0
+	pw.bufferedBytes = 0
+
+	return nil
+}
+
+// Close should flush all writes, release resources being held
+func (pw *ParquetWriter) Close(ctx context.Context) error {
+	if pw.closer == nil {
+		return errors.New("already closed")
+	}
+
+	defer func() {
+		pw.closer = nil
+	}()
+
+	if pw.numBuffered > 0 {
+		if err := pw.flushRowGroup(); err != nil {
+			pw.closer.Close()
+			return err
+		}
+	}
+
+	metaData := pw.meta.encode()
+	footer := make([]byte, 4, 4+len(magic))
+	binary.LittleEndian.PutUint32(footer, uint32(len(metaData)))
+	footer = append(footer, magic...)
+
+	errWr := iohelp.WriteAll(pw.bWr, metaData)
+
+	if errWr == nil {
+		errWr = iohelp.WriteAll(pw.bWr, footer)
+	}
+
+	if errWr == nil {
+		errWr = pw.bWr.Flush()
+	}
+
+	errCl := pw.closer.Close()
+
+	if errWr != nil {
+		return errWr
+	}
+
+	return errCl
+}
@@ -0,0 +1,204 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// SupportedEncodings are the names of the character encodings csv files can be read and written in
+var SupportedEncodings = []string{"utf-8", "utf-16", "utf-16le", "utf-16be", "latin1", "windows-1252"}
+
+type textEncoding int
+
+const (
+	utf8Encoding textEncoding = iota
+	utf16Encoding
+	utf16LEEncoding
+	utf16BEEncoding
+	latin1Encoding
+	windows1252Encoding
+)
+
+const byteOrderMark = "\uFEFF"
+
+func lookupEncoding(name string) (textEncoding, error) {
+	switch strings.ToLower(strings.Replace(name, "_", "-", -1)) {
+	case "", "utf-8", "utf8":
+		return utf8Encoding, nil
+	case "utf-16", "utf16":
+		return utf16Encoding, nil
+	case "utf-16le", "utf16le":
+		return utf16LEEncoding, nil
+	case "utf-16be", "utf16be":
+		return utf16BEEncoding, nil
+	case "latin1", "latin-1", "iso-8859-1":
+		return latin1Encoding, nil
+	case "windows-1252", "cp1252":
+		return windows1252Encoding, nil
+	default:
+		return 0, fmt.Errorf("'%s' isn't a supported encoding. Supported encodings are %s", name, strings.Join(SupportedEncodings, ", "))
+	}
+}
+
+// xEncoding returns the encoding that converts text between enc and utf-8. Byte order marks are left to the caller,
+// except for utf-16 without a byte order, which is decoded in the order of its byte order mark.
+func (enc textEncoding) xEncoding() encoding.Encoding {
+	switch enc {
+	case utf16Encoding:
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM)
+	case utf16LEEncoding:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	case utf16BEEncoding:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	case latin1Encoding:
+		return charmap.ISO8859_1
+	case windows1252Encoding:
+		return charmap.Windows1252
+	default:
+		return unicode.UTF8
+	}
+}
+
+func (enc textEncoding) String() string {
+	switch enc {
+	case utf16Encoding:
+		return "utf-16"
+	case utf16LEEncoding:
+		return "utf-16le"
+	case utf16BEEncoding:
+		return "utf-16be"
+	case latin1Encoding:
+		return "latin1"
+	case windows1252Encoding:
+		return "windows-1252"
+	default:
+		return "utf-8"
+	}
+}
+
+// newDecodingReader returns a reader that reads the text of r, in the given encoding, as utf-8. A byte order mark at
+// the start of the text is skipped, and selects the byte order of utf-16.
+func newDecodingReader(r io.Reader, encName string) (io.Reader, error) {
+	enc, err := lookupEncoding(encName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rd := bufio.NewReader(r)
+
+	switch enc {
+	case utf8Encoding:
+		if bom, err := rd.Peek(len(byteOrderMark)); err == nil && string(bom) == byteOrderMark {
+			rd.Discard(len(byteOrderMark))
+		}
+
+		return rd, nil
+
+	case utf16LEEncoding, utf16BEEncoding:
+		// a byte order mark in the byte order given is skipped, as utf-16 decoding selects the byte order from it
+		if bom, err := rd.Peek(2); err == nil {
+			if (enc == utf16LEEncoding && bom[0] == 0xFF && bom[1] == 0xFE) || (enc == utf16BEEncoding && bom[0] == 0xFE && bom[1] == 0xFF) {
+				rd.Discard(2)
+			}
+		}
+	}
+
+	return transform.NewReader(rd, enc.xEncoding().NewDecoder()), nil
+}
+
+// encodingWriter writes utf-8 text to a writer in another encoding
+type encodingWriter struct {
+	wr      io.Writer
+	enc     textEncoding
+	encoder *encoding.Encoder
+	partial []byte
+}
+
+// newEncodingWriter returns a writer that writes utf-8 text to wr in the given encoding. utf-16 is written little endian
+// with a byte order mark.
+func newEncodingWriter(wr io.Writer, encName string) (io.Writer, error) {
+	enc, err := lookupEncoding(encName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch enc {
+	case utf8Encoding:
+		return wr, nil
+
+	case utf16Encoding:
+		if _, err := wr.Write([]byte{0xFF, 0xFE}); err != nil {
+			return nil, err
+		}
+
+		enc = utf16LEEncoding
+	}
+
+	return &encodingWriter{wr: wr, enc: enc, encoder: enc.xEncoding().NewEncoder()}, nil
+}
+
+func (ew *encodingWriter) Write(p []byte) (int, error) {
+	text := append(ew.partial, p...)
+
+	// a character split across writes is kept until the rest of it is written
+	n := len(text)
+	for i := len(text) - 1; i >= 0 && i >= len(text)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(text[i]) {
+			if !utf8.FullRune(text[i:]) {
+				n = i
+			}
+
+			break
+		}
+	}
+
+	encoded, err := ew.encoder.Bytes(text[:n])
+
+	if err != nil {
+		return 0, ew.encodingErr(text[:n], err)
+	}
+
+	ew.partial = append([]byte(nil), text[n:]...)
+
+	if _, err := ew.wr.Write(encoded); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// encodingErr returns an error naming the first character of text that can't be written in the writer's encoding, or
+// err if there isn't one.
+func (ew *encodingWriter) encodingErr(text []byte, err error) error {
+	for _, r := range string(text) {
+		if _, rErr := ew.encoder.String(string(r)); rErr != nil {
+			return fmt.Errorf("the character %q can't be written as %s", r, ew.enc)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodings(t *testing.T) {
+	const text = "name,city\nJosé,Zürich €\n𝄞,x\n"
+
+	tests := []struct {
+		encoding string
+		text     string
+		encoded  []byte
+	}{
+		{"utf-8", text, []byte(text)},
+		{"latin1", "José,Zürich\n", []byte("Jos\xe9,Z\xfcrich\n")},
+		{"windows-1252", "José ‘€’\n", []byte("Jos\xe9 \x91\x80\x92\n")},
+		{"utf-16le", "aé𝄞", []byte{'a', 0, 0xe9, 0, 0x34, 0xd8, 0x1e, 0xdd}},
+		{"utf-16be", "aé𝄞", []byte{0, 'a', 0, 0xe9, 0xd8, 0x34, 0xdd, 0x1e}},
+		{"utf-16", "aé", []byte{0xff, 0xfe, 'a', 0, 0xe9, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.encoding, func(t *testing.T) {
+			var buf bytes.Buffer
+			wr, err := newEncodingWriter(&buf, test.encoding)
+			require.NoError(t, err)
+
+			// write a byte at a time so that characters are split across writes
+			for i := 0; i < len(test.text); i++ {
+				_, err = wr.Write([]byte{test.text[i]})
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.encoded, buf.Bytes())
+
+			rd, err := newDecodingReader(bytes.NewReader(test.encoded), test.encoding)
+			require.NoError(t, err)
+
+			decoded, err := ioutil.ReadAll(rd)
+			require.NoError(t, err)
+			assert.Equal(t, test.text, string(decoded))
+		})
+	}
+}
+
+func TestByteOrderMarks(t *testing.T) {
+	rd, err := newDecodingReader(bytes.NewReader([]byte("\xef\xbb\xbfa,b")), "")
+	require.NoError(t, err)
+	decoded, err := ioutil.ReadAll(rd)
+	require.NoError(t, err)
+	assert.Equal(t, "a,b", string(decoded))
+
+	rd, err = newDecodingReader(bytes.NewReader([]byte{0xfe, 0xff, 0, 'a'}), "utf-16")
+	require.NoError(t, err)
+	decoded, err = ioutil.ReadAll(rd)
+	require.NoError(t, err)
+	assert.Equal(t, "a", string(decoded))
+}
+
+func TestUnencodableCharacters(t *testing.T) {
+	wr, err := newEncodingWriter(&bytes.Buffer{}, "latin1")
+	require.NoError(t, err)
+
+	_, err = wr.Write([]byte("€"))
+	assert.EqualError(t, err, `the character '€' can't be written as latin1`)
+
+	_, err = lookupEncoding("ebcdic")
+	assert.Error(t, err)
+}
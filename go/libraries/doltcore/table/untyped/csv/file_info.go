@@ -14,7 +14,21 @@
 
 package csv
 
-// CSVFileInfo describes a csv file
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Line terminators that lines of a csv file can end with
+const (
+	LF   = "\n"
+	CRLF = "\r\n"
+	CR   = "\r"
+)
+
+// CSVFileInfo describes a csv file.  The zero values of Quote, Escape, NullStrings, Encoding and LineTerminator are the
+// defaults of a standard csv file.
 type CSVFileInfo struct {
 	// Delim says which character is used as a field delimiter
 	Delim string
@@ -24,11 +38,26 @@ type CSVFileInfo struct {
 	Columns []string
 	// EscapeQuotes says whether quotes should be escaped when parsing the csv
 	EscapeQuotes bool
+	// Quote is the character used to quote fields. Defaults to a double quote.
+	Quote string
+	// Escape is a character that escapes a quote or itself within a field, as in \" with a backslash. By default quotes
+	// are escaped by doubling them.
+	Escape string
+	// NullStrings are values that are read as NULL. Empty values are always NULL, and NULLs are written as the first of
+	// these, or as empty values if there are none. Quoted values aren't read as NULL, so strings equal to one of these
+	// are written quoted, except when EscapeQuotes is false, and then they're read back as NULL. Empty strings are always
+	// read back as NULL.
+	NullStrings []string
+	// Encoding is the character encoding of the file. Defaults to utf-8.
+	Encoding string
+	// LineTerminator is the line terminator lines end with, one of LF, CRLF or CR. Defaults to LF. When it's LF, lines
+	// ending in CRLF are read as well.
+	LineTerminator string
 }
 
 // NewCSVInfo creates a new CSVInfo struct with default values
 func NewCSVInfo() *CSVFileInfo {
-	return &CSVFileInfo{",", true, nil, true}
+	return &CSVFileInfo{Delim: ",", HasHeaderLine: true, Columns: nil, EscapeQuotes: true}
 }
 
 // SetDelim sets the Delim member and returns the CSVFileInfo
@@ -54,3 +83,92 @@ func (info *CSVFileInfo) SetEscapeQuotes(escapeQuotes bool) *CSVFileInfo {
 	info.EscapeQuotes = escapeQuotes
 	return info
 }
+
+// SetQuote sets the Quote member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetQuote(quote string) *CSVFileInfo {
+	info.Quote = quote
+	return info
+}
+
+// SetEscape sets the Escape member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetEscape(escape string) *CSVFileInfo {
+	info.Escape = escape
+	return info
+}
+
+// SetNullStrings sets the NullStrings member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetNullStrings(nullStrings []string) *CSVFileInfo {
+	info.NullStrings = nullStrings
+	return info
+}
+
+// SetEncoding sets the Encoding member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetEncoding(encoding string) *CSVFileInfo {
+	info.Encoding = encoding
+	return info
+}
+
+// SetLineTerminator sets the LineTerminator member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetLineTerminator(lineTerminator string) *CSVFileInfo {
+	info.LineTerminator = lineTerminator
+	return info
+}
+
+// Validate returns an error if the CSVFileInfo can't be used to read or write a csv file.
+func (info *CSVFileInfo) Validate() error {
+	if info.Delim == "" {
+		return errors.New("the delimiter can't be empty")
+	}
+
+	if len(info.Quote) > 1 || len(info.Escape) > 1 {
+		return errors.New("the quote and escape characters must be single characters")
+	}
+
+	if strings.Contains(info.Delim, string(info.quote())) {
+		return fmt.Errorf("the delimiter can't contain the quote character %s", string(info.quote()))
+	}
+
+	switch info.LineTerminator {
+	case "", LF, CRLF, CR:
+	default:
+		return fmt.Errorf("%q isn't a supported line terminator", info.LineTerminator)
+	}
+
+	_, err := lookupEncoding(info.Encoding)
+	return err
+}
+
+func (info *CSVFileInfo) quote() byte {
+	if info.Quote == "" {
+		return '"'
+	}
+
+	return info.Quote[0]
+}
+
+// escape returns the escape character, or 0 if quotes are escaped by doubling them
+func (info *CSVFileInfo) escape() byte {
+	if info.Escape == "" || info.Escape[0] == info.quote() {
+		return 0
+	}
+
+	return info.Escape[0]
+}
+
+func (info *CSVFileInfo) lineTerminator() string {
+	if info.LineTerminator == "" {
+		return LF
+	}
+
+	return info.LineTerminator
+}
+
+func (info *CSVFileInfo) isNullString(str string) bool {
+	for _, nullStr := range info.NullStrings {
+		if str == nullStr {
+			return true
+		}
+	}
+
+	return false
+}
@@ -37,3 +37,26 @@ func TestCSVFileInfo(t *testing.T) {
 		t.Error("Unexpected values")
 	}
 }
+
+func TestCSVFileInfoValidate(t *testing.T) {
+	tests := []struct {
+		info      *CSVFileInfo
+		expectErr bool
+	}{
+		{NewCSVInfo(), false},
+		{NewCSVInfo().SetDelim("\t").SetQuote("'").SetEscape(`\`).SetEncoding("utf-16").SetLineTerminator(CRLF), false},
+		{NewCSVInfo().SetDelim(""), true},
+		{NewCSVInfo().SetDelim(`"`), true},
+		{NewCSVInfo().SetQuote("''"), true},
+		{NewCSVInfo().SetEncoding("ebcdic"), true},
+		{NewCSVInfo().SetLineTerminator("\n\r"), true},
+	}
+
+	for _, test := range tests {
+		err := test.info.Validate()
+
+		if (err != nil) != test.expectErr {
+			t.Errorf("Unexpected result validating %+v: %v", test.info, err)
+		}
+	}
+}
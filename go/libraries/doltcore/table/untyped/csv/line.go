@@ -16,7 +16,6 @@ package csv
 
 import (
 	"errors"
-	"math"
 	"strings"
 )
 
@@ -25,103 +24,110 @@ func csvSplitLineRuneDelim(str string, delim rune, escapedQuotes bool) ([]string
 }
 
 func csvSplitLine(str string, delim string, escapedQuotes bool) ([]string, error) {
-	if strings.IndexRune(delim, '"') != -1 {
+	return splitLine(str, delim, '"', 0, escapedQuotes)
+}
+
+// splitLine splits a line into its fields. When escapedQuotes is true, delimiters within quotes are part of a field,
+// quotes are removed from fields, and a quote is escaped by doubling it or, when escape is not 0, by preceding it with
+// the escape character.
+func splitLine(str string, delim string, quote, escape byte, escapedQuotes bool) ([]string, error) {
+	tokens, _, err := splitQuotedLine(str, delim, quote, escape, escapedQuotes)
+	return tokens, err
+}
+
+// splitQuotedLine splits a line into its fields like splitLine, and returns whether each field was quoted as well.
+func splitQuotedLine(str string, delim string, quote, escape byte, escapedQuotes bool) ([]string, []bool, error) {
+	if !escapedQuotes {
+		tokens := strings.Split(str, delim)
+		for i, token := range tokens {
+			tokens[i] = trimWhitespace(token)
+		}
+
+		return tokens, make([]bool, len(tokens)), nil
+	}
+
+	if strings.IndexByte(delim, quote) != -1 {
 		panic("delims cannot contain quotes")
 	}
 
 	var tokens []string
-	delimLen := len(delim)
-
-	done := false
-	escaped := false
-	currPos := 0
+	var quotedTokens []bool
+	quoted := false
 	cellStart := 0
-	for !done {
-		remainingStr := str[currPos:]
-		nextQuote := strings.Index(remainingStr, "\"")
-		nextDelim := strings.Index(remainingStr, delim)
-
-		if nextQuote == -1 || !escapedQuotes {
-			nextQuote = math.MaxInt32
+	lastDelim := strings.LastIndex(str, delim)
+	for pos := 0; pos < len(str); {
+		if !quoted && pos > lastDelim {
+			// the rest of the line is the last field, so any quotes left in it don't need to be closed
+			break
 		}
 
-		if !escaped && nextDelim < nextQuote {
-			if nextDelim == -1 {
-				nextDelim = len(remainingStr)
-				done = true
-			}
-
-			tokens = appendToken(tokens, str, cellStart, currPos+nextDelim, escapedQuotes)
-			cellStart = currPos + nextDelim + delimLen
-			currPos = cellStart
-		} else if escapedQuotes && nextQuote != -1 && nextQuote != math.MaxInt32 {
-			escaped = !escaped
-			currPos += nextQuote + 1
+		c := str[pos]
+
+		if escape != 0 && c == escape && pos+1 < len(str) && (str[pos+1] == quote || str[pos+1] == escape) {
+			pos += 2
+		} else if c == quote {
+			quoted = !quoted
+			pos++
+		} else if !quoted && strings.HasPrefix(str[pos:], delim) {
+			tokens = append(tokens, unquoteToken(str[cellStart:pos], quote, escape))
+			quotedTokens = append(quotedTokens, isQuoted(str[cellStart:pos], quote))
+			pos += len(delim)
+			cellStart = pos
 		} else {
-			if escapedQuotes {
-				return nil, errors.New(str[cellStart:] + ` has an unclosed quotation mark`)
-			}
-
-			break
+			pos++
 		}
 	}
 
-	return tokens, nil
-}
+	if quoted {
+		return nil, nil, errors.New(str[cellStart:] + ` has an unclosed quotation mark`)
+	}
 
-func appendToken(tokens []string, line string, start, pos int, escapedQuotes bool) []string {
-	quotations := 0
+	tokens = append(tokens, unquoteToken(str[cellStart:], quote, escape))
+	quotedTokens = append(quotedTokens, isQuoted(str[cellStart:], quote))
 
-	if escapedQuotes {
-		for _, c := range line {
-			if c == '"' {
-				quotations++
-			}
-		}
-	}
+	return tokens, quotedTokens, nil
+}
 
-	if start == pos {
-		return append(tokens, "")
-	}
+// isQuoted returns whether a field starts with a quote once the whitespace around it is trimmed.
+func isQuoted(token string, quote byte) bool {
+	token = trimWhitespace(token)
+	return len(token) > 0 && token[0] == quote
+}
 
-	for isWhitespace(line[start]) {
-		start++
-	}
+// unquoteToken trims the whitespace around a field and removes its quotes, keeping the quotes that are escaped.
+func unquoteToken(token string, quote, escape byte) string {
+	token = trimWhitespace(token)
 
-	if start == pos {
-		return append(tokens, "")
+	if strings.IndexByte(token, quote) == -1 && (escape == 0 || strings.IndexByte(token, escape) == -1) {
+		return token
 	}
 
-	for isWhitespace(line[pos-1]) {
-		pos--
-	}
+	unquoted := make([]byte, 0, len(token))
+	for i := 0; i < len(token); i++ {
+		c := token[i]
 
-	if quotations == 0 {
-		return append(tokens, line[start:pos])
-	} else if quotations == 2 && line[start] == '"' && line[pos-1] == '"' {
-		return append(tokens, line[start+1:pos-1])
+		if i+1 < len(token) && ((c == escape && escape != 0 && (token[i+1] == quote || token[i+1] == escape)) || (c == quote && token[i+1] == quote)) {
+			unquoted = append(unquoted, token[i+1])
+			i++
+		} else if c != quote {
+			unquoted = append(unquoted, c)
+		}
 	}
 
-	token := make([]byte, len(line)-start)
+	return string(unquoted)
+}
 
-	end := 0
-	for i := start; i < pos; i++ {
-		c := line[i]
+func trimWhitespace(str string) string {
+	start, end := 0, len(str)
+	for start < end && isWhitespace(str[start]) {
+		start++
+	}
 
-		if c == '"' {
-			if i+1 < len(line) && line[i+1] == '"' {
-				token[end] = c
-				end++
-				i++
-			}
-		} else {
-			token[end] = c
-			end++
-		}
+	for end > start && isWhitespace(str[end-1]) {
+		end--
 	}
 
-	s := string(token[:end])
-	return append(tokens, s)
+	return str[start:end]
 }
 
 func isWhitespace(c uint8) bool {
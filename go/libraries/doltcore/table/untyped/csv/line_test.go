@@ -14,7 +14,10 @@
 
 package csv
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestCSVSplitLine(t *testing.T) {
 	splitTests := []struct {
@@ -84,3 +87,32 @@ func TestCSVSplitLine(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitLineQuoteAndEscape(t *testing.T) {
+	splitTests := []struct {
+		ToSplit        string
+		quote          byte
+		escape         byte
+		expectedTokens []string
+	}{
+		{`one,'two, three'`, '\'', 0, []string{"one", "two, three"}},
+		{`one,'it''s'`, '\'', 0, []string{"one", "it's"}},
+		{`one,"two, \"three\""`, '"', '\\', []string{"one", `two, "three"`}},
+		{`one,"back\\slash",three`, '"', '\\', []string{"one", `back\slash`, "three"}},
+		{`one,c:\temp,three`, '"', '\\', []string{"one", `c:\temp`, "three"}},
+		{`one,"two ""three""",four`, '"', '\\', []string{"one", `two "three"`, "four"}},
+	}
+
+	for _, test := range splitTests {
+		results, err := splitLine(test.ToSplit, ",", test.quote, test.escape, true)
+
+		if err != nil {
+			t.Error("Unexpected error: " + err.Error())
+			continue
+		}
+
+		if !reflect.DeepEqual(results, test.expectedTokens) {
+			t.Errorf("%s split test failure. expected: %v, actual: %v", test.ToSplit, test.expectedTokens, results)
+		}
+	}
+}
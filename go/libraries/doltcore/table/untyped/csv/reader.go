@@ -59,7 +59,21 @@ func OpenCSVReader(nbf *types.NomsBinFormat, path string, fs filesys.ReadableFS,
 
 // NewCSVReader creates a CSVReader from a given ReadCloser.  The CSVFileInfo should describe the csv file being read.
 func NewCSVReader(nbf *types.NomsBinFormat, r io.ReadCloser, info *CSVFileInfo) (*CSVReader, error) {
-	br := bufio.NewReaderSize(r, ReadBufSize)
+	err := info.Validate()
+
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	decoded, err := newDecodingReader(r, info.Encoding)
+
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	br := bufio.NewReaderSize(decoded, ReadBufSize)
 	colStrs, err := getColHeaders(br, info)
 
 	if err != nil {
@@ -75,7 +89,7 @@ func NewCSVReader(nbf *types.NomsBinFormat, r io.ReadCloser, info *CSVFileInfo)
 func getColHeaders(br *bufio.Reader, info *CSVFileInfo) ([]string, error) {
 	colStrs := info.Columns
 	if info.HasHeaderLine {
-		line, _, err := iohelp.ReadLine(br)
+		line, _, err := readLine(br, info)
 
 		if err != nil {
 			return nil, err
@@ -83,7 +97,7 @@ func getColHeaders(br *bufio.Reader, info *CSVFileInfo) ([]string, error) {
 			return nil, errors.New("Header line is empty")
 		}
 
-		colStrsFromFile, err := csvSplitLine(line, info.Delim, info.EscapeQuotes)
+		colStrsFromFile, err := splitLine(line, info.Delim, info.quote(), info.escape(), info.EscapeQuotes)
 
 		if err != nil {
 			return nil, err
@@ -94,9 +108,28 @@ func getColHeaders(br *bufio.Reader, info *CSVFileInfo) ([]string, error) {
 		}
 	}
 
+	if len(colStrs) == 0 {
+		return nil, errors.New("the names of the columns must be given to read a csv file without a header line")
+	}
+
 	return colStrs, nil
 }
 
+// readLine reads a line ending in the line terminator of the file
+func readLine(br *bufio.Reader, info *CSVFileInfo) (string, bool, error) {
+	if info.lineTerminator() != CR {
+		return iohelp.ReadLine(br)
+	}
+
+	line, err := br.ReadString('\r')
+
+	if err != nil && err != io.EOF {
+		return "", true, err
+	}
+
+	return strings.TrimRight(line, "\r\n"), err != nil, nil
+}
+
 // ReadRow reads a row from a table.  If there is a bad row the returned error will be non nil, and callin IsBadRow(err)
 // will be return true. This is a potentially non-fatal error and callers can decide if they want to continue on a bad row, or fail.
 func (csvr *CSVReader) ReadRow(ctx context.Context) (row.Row, error) {
@@ -108,7 +141,7 @@ func (csvr *CSVReader) ReadRow(ctx context.Context) (row.Row, error) {
 	var err error
 	isDone := false
 	for line == "" && !isDone && err == nil {
-		line, isDone, err = iohelp.ReadLine(csvr.bRd)
+		line, isDone, err = readLine(csvr.bRd, csvr.info)
 
 		if err != nil && err != io.EOF {
 			return nil, err
@@ -145,7 +178,7 @@ func (csvr *CSVReader) Close(ctx context.Context) error {
 }
 
 func (csvr *CSVReader) parseRow(line string) (row.Row, error) {
-	colVals, err := csvSplitLine(line, csvr.info.Delim, csvr.info.EscapeQuotes)
+	colVals, quoted, err := splitQuotedLine(line, csvr.info.Delim, csvr.info.quote(), csvr.info.escape(), csvr.info.EscapeQuotes)
 
 	if err != nil {
 		return nil, table.NewBadRow(nil, err.Error())
@@ -163,7 +196,8 @@ func (csvr *CSVReader) parseRow(line string) (row.Row, error) {
 
 	taggedVals := make(row.TaggedValues)
 	for i := 0; i < allCols.Size(); i++ {
-		if len(colVals[i]) > 0 {
+		// a quoted value is the string it holds, even if it's one of the strings read as NULL
+		if len(colVals[i]) > 0 && (quoted[i] || !csvr.info.isNullString(colVals[i])) {
 			col := allCols.GetByIndex(i)
 			taggedVals[col.Tag] = types.String(colVals[i])
 		}
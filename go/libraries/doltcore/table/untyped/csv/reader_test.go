@@ -158,3 +158,103 @@ func readTestRows(t *testing.T, inputStr string, info *CSVFileInfo) ([]row.Row,
 
 	return rows, badRows, err
 }
+
+func TestReaderDialects(t *testing.T) {
+	colNames := []string{"name", "age", "title"}
+	_, sch := untyped.NewUntypedSchema(colNames...)
+
+	tests := []struct {
+		name     string
+		inputStr string
+		info     *CSVFileInfo
+	}{
+		{
+			"tab delimited",
+			"name\tage\ttitle\nBill, Jr.\t32\t\\N\nRob\t25\tDufus",
+			NewCSVInfo().SetDelim("\t").SetNullStrings([]string{`\N`}),
+		},
+		{
+			"headerless",
+			"'Bill, Jr.';32;NULL\r\nRob;25;Dufus\r\n",
+			NewCSVInfo().SetDelim(";").SetHasHeaderLine(false).SetColumns(colNames).SetQuote("'").SetNullStrings([]string{"NULL"}),
+		},
+		{
+			"backslash escapes",
+			"name,age,title\n\"Bill, Jr.\",32,\nRob,25,\"Dufus\"",
+			NewCSVInfo().SetEscape(`\`),
+		},
+		{
+			"cr line terminator",
+			"name,age,title\r\"Bill, Jr.\",32,\rRob,25,Dufus\r",
+			NewCSVInfo().SetLineTerminator(CR),
+		},
+	}
+
+	expectedRows := []row.Row{
+		mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{0: types.String("Bill, Jr."), 1: types.String("32")})),
+		mustRow(untyped.NewRowFromStrings(types.Format_7_18, sch, []string{"Rob", "25", "Dufus"})),
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows, numBad, err := readTestRows(t, test.inputStr, test.info)
+
+			if err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+
+			if numBad != 0 || len(rows) != len(expectedRows) {
+				t.Fatal("Unexpected rows. bad rows:", numBad, "rows:", len(rows))
+			}
+
+			for i, r := range rows {
+				if !row.AreEqual(r, expectedRows[i], sch) {
+					t.Error(row.Fmt(context.Background(), r, sch), "!=", row.Fmt(context.Background(), expectedRows[i], sch))
+				}
+			}
+		})
+	}
+}
+
+func TestReaderEncodings(t *testing.T) {
+	_, sch := untyped.NewUntypedSchema("name", "city")
+	expected := mustRow(untyped.NewRowFromStrings(types.Format_7_18, sch, []string{"José", "Zürich"}))
+
+	tests := []struct {
+		encoding string
+		input    []byte
+	}{
+		{"latin1", []byte("name,city\nJos\xe9,Z\xfcrich\n")},
+		{"utf-16", []byte{0xff, 0xfe, 'n', 0, 'a', 0, 'm', 0, 'e', 0, ',', 0, 'c', 0, 'i', 0, 't', 0, 'y', 0, '\n', 0,
+			'J', 0, 'o', 0, 's', 0, 0xe9, 0, ',', 0, 'Z', 0, 0xfc, 0, 'r', 0, 'i', 0, 'c', 0, 'h', 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.encoding, func(t *testing.T) {
+			rows, _, err := readTestRows(t, string(test.input), NewCSVInfo().SetEncoding(test.encoding))
+
+			if err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+
+			if len(rows) != 1 || !row.AreEqual(rows[0], expected, sch) {
+				t.Error("Unexpected rows:", rows)
+			}
+		})
+	}
+}
+
+func TestReaderQuotedNullStrings(t *testing.T) {
+	_, sch := untyped.NewUntypedSchema("name", "age", "title")
+	expected := mustRow(row.New(types.Format_7_18, sch, row.TaggedValues{1: types.String("NULL")}))
+
+	rows, _, err := readTestRows(t, "name,age,title\nNULL,\"NULL\",\n", NewCSVInfo().SetNullStrings([]string{"NULL"}))
+
+	if err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if len(rows) != 1 || !row.AreEqual(rows[0], expected, sch) {
+		t.Error("Unexpected rows:", rows)
+	}
+}
@@ -37,8 +37,10 @@ var WriteBufSize = 256 * 1024
 type CSVWriter struct {
 	closer   io.Closer
 	bWr      *bufio.Writer
+	wr       io.Writer
 	info     *CSVFileInfo
 	delimStr string
+	nullStr  string
 	sch      schema.Schema
 }
 
@@ -62,15 +64,34 @@ func OpenCSVWriter(path string, fs filesys.WritableFS, outSch schema.Schema, inf
 
 // NewCSVWriter writes rows to the given WriteCloser based on the Schema and CSVFileInfo provided
 func NewCSVWriter(wr io.WriteCloser, outSch schema.Schema, info *CSVFileInfo) (*CSVWriter, error) {
+	err := info.Validate()
+
+	if err != nil {
+		wr.Close()
+		return nil, err
+	}
+
 	bwr := bufio.NewWriterSize(wr, WriteBufSize)
-	delimStr := string(info.Delim)
+	encWr, err := newEncodingWriter(bwr, info.Encoding)
+
+	if err != nil {
+		wr.Close()
+		return nil, err
+	}
+
+	var nullStr string
+	if len(info.NullStrings) > 0 {
+		nullStr = info.NullStrings[0]
+	}
+
+	csvw := &CSVWriter{wr, bwr, encWr, info, info.Delim, nullStr, outSch}
 
 	if info.HasHeaderLine {
 		allCols := outSch.GetAllCols()
 		numCols := allCols.Size()
 		colNames := make([]string, 0, numCols)
 		err := allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
-			colNames = append(colNames, col.Name)
+			colNames = append(colNames, csvw.quoteField(col.Name))
 			return false, nil
 		})
 
@@ -79,8 +100,7 @@ func NewCSVWriter(wr io.WriteCloser, outSch schema.Schema, info *CSVFileInfo) (*
 			return nil, err
 		}
 
-		headerLine := strings.Join(colNames, delimStr)
-		err = iohelp.WriteLine(bwr, headerLine)
+		err = csvw.writeLine(colNames)
 
 		if err != nil {
 			wr.Close()
@@ -88,7 +108,7 @@ func NewCSVWriter(wr io.WriteCloser, outSch schema.Schema, info *CSVFileInfo) (*
 		}
 	}
 
-	return &CSVWriter{wr, bwr, info, delimStr, outSch}, nil
+	return csvw, nil
 }
 
 // GetSchema gets the schema of the rows that this writer writes
@@ -106,7 +126,7 @@ func (csvw *CSVWriter) WriteRow(ctx context.Context, r row.Row) error {
 		val, ok := r.GetColVal(tag)
 		if ok && !types.IsNull(val) {
 			if val.Kind() == types.StringKind {
-				colValStrs[i] = string(val.(types.String))
+				colValStrs[i] = csvw.quoteField(string(val.(types.String)))
 			} else {
 				var err error
 				colValStrs[i], err = types.EncodedValue(ctx, val)
@@ -114,7 +134,11 @@ func (csvw *CSVWriter) WriteRow(ctx context.Context, r row.Row) error {
 				if err != nil {
 					return false, err
 				}
+
+				colValStrs[i] = csvw.quoteField(colValStrs[i])
 			}
+		} else {
+			colValStrs[i] = csvw.nullStr
 		}
 
 		i++
@@ -125,8 +149,44 @@ func (csvw *CSVWriter) WriteRow(ctx context.Context, r row.Row) error {
 		return err
 	}
 
-	rowStr := strings.Join(colValStrs, csvw.delimStr)
-	return iohelp.WriteLine(csvw.bWr, rowStr)
+	return csvw.writeLine(colValStrs)
+}
+
+func (csvw *CSVWriter) writeLine(fields []string) error {
+	line := strings.Join(fields, csvw.delimStr) + csvw.info.lineTerminator()
+	return iohelp.WriteAll(csvw.wr, []byte(line))
+}
+
+// quoteField quotes a field if it couldn't be read back otherwise: if it contains the delimiter, a quote, or a line
+// break, has whitespace around it that would be trimmed, or is one of the strings read as NULL.
+func (csvw *CSVWriter) quoteField(field string) string {
+	if !csvw.info.EscapeQuotes {
+		return field
+	}
+
+	quote, escape := csvw.info.quote(), csvw.info.escape()
+
+	if trimWhitespace(field) == field && (field == "" || !csvw.info.isNullString(field)) &&
+		!strings.Contains(field, csvw.delimStr) && !strings.ContainsAny(field, "\r\n") &&
+		strings.IndexByte(field, quote) == -1 && (escape == 0 || strings.IndexByte(field, escape) == -1) {
+		return field
+	}
+
+	quoted := make([]byte, 0, len(field)+2)
+	quoted = append(quoted, quote)
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+
+		if c == quote && escape == 0 {
+			quoted = append(quoted, quote)
+		} else if c == quote || c == escape {
+			quoted = append(quoted, escape)
+		}
+
+		quoted = append(quoted, c)
+	}
+
+	return string(append(quoted, quote))
 }
 
 // Close should flush all writes, release resources being held
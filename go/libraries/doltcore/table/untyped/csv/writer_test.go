@@ -97,3 +97,54 @@ John Johnson,21,Intern Dufus
 		t.Errorf(`%s != %s`, results, expected)
 	}
 }
+
+func TestWriterDialect(t *testing.T) {
+	const expected = "'it''s';32;NULL\r\n' padded';21;'Intern; Dufus'\r\nNULL;'NULL';\r\n"
+
+	info := NewCSVInfo().
+		SetDelim(";").
+		SetHasHeaderLine(false).
+		SetQuote("'").
+		SetNullStrings([]string{"NULL", ""}).
+		SetLineTerminator(CRLF)
+
+	_, outSch := untyped.NewUntypedSchema(nameColName, ageColName, titleColName)
+	rows := []row.Row{
+		mustRow(row.New(types.Format_7_18, outSch, row.TaggedValues{
+			nameColTag: types.String("it's"),
+			ageColTag:  types.String("32")})),
+		mustRow(row.New(types.Format_7_18, outSch, row.TaggedValues{
+			nameColTag:  types.String(" padded"),
+			ageColTag:   types.String("21"),
+			titleColTag: types.String("Intern; Dufus")})),
+		mustRow(row.New(types.Format_7_18, outSch, row.TaggedValues{
+			ageColTag:   types.String("NULL"),
+			titleColTag: types.String("")})),
+	}
+
+	fs := filesys.NewInMemFS(nil, nil, "/")
+	csvWr, err := OpenCSVWriter("/file.csv", fs, outSch, info)
+
+	if err != nil {
+		t.Fatal("Could not open CSVWriter", err)
+	}
+
+	for _, r := range rows {
+		err := csvWr.WriteRow(context.Background(), r)
+
+		if err != nil {
+			t.Fatal("Failed to write row", err)
+		}
+	}
+
+	err = csvWr.Close(context.Background())
+
+	if err != nil {
+		t.Fatal("Failed to close CSVWriter", err)
+	}
+
+	results, err := fs.ReadFile("/file.csv")
+	if string(results) != expected {
+		t.Errorf(`%q != %q`, results, expected)
+	}
+}
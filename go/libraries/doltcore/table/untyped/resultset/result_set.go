@@ -230,7 +230,9 @@ func concatSchemas(srcSchemas ...schema.Schema) (schema.Schema, error) {
 	var itag uint64
 	for _, col := range srcSchemas {
 		err := col.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
-			cols = append(cols, schema.NewColumn(col.Name, itag, col.Kind, false))
+			newCol := schema.NewColumn(col.Name, itag, col.Kind, false)
+			newCol.TypeName = col.TypeName
+			cols = append(cols, newCol)
 			itag++
 			return false, nil
 		})
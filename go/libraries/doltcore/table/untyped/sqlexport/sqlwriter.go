@@ -20,7 +20,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/sql"
@@ -29,8 +28,6 @@ import (
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
-const doubleQuot = "\""
-
 // SqlExportWriter is a TableWriter that writes SQL drop, create and insert statements to re-create a dolt table in a
 // SQL database.
 type SqlExportWriter struct {
@@ -52,7 +49,12 @@ func OpenSQLExportWriter(path string, tableName string, fs filesys.WritableFS, s
 		return nil, err
 	}
 
-	return &SqlExportWriter{tableName: tableName, sch: sch, wr: wr}, nil
+	return NewSQLExportWriter(wr, tableName, sch), nil
+}
+
+// NewSQLExportWriter returns a new SqlWriter for the table given writing to wr.
+func NewSQLExportWriter(wr io.WriteCloser, tableName string, sch schema.Schema) *SqlExportWriter {
+	return &SqlExportWriter{tableName: tableName, sch: sch, wr: wr}
 }
 
 // Returns the schema of this TableWriter.
@@ -127,7 +129,7 @@ func (w *SqlExportWriter) insertStatementForRow(r row.Row) (string, error) {
 		if seenOne {
 			b.WriteRune(',')
 		}
-		b.WriteString(w.sqlString(val))
+		b.WriteString(sql.FmtValue(val))
 		seenOne = true
 		return false, nil
 	})
@@ -150,30 +152,3 @@ func (w *SqlExportWriter) dropCreateStatement() string {
 
 	return b.String()
 }
-
-func (w *SqlExportWriter) sqlString(value types.Value) string {
-	if types.IsNull(value) {
-		return "NULL"
-	}
-
-	switch value.Kind() {
-	case types.BoolKind:
-		if value.(types.Bool) {
-			return "TRUE"
-		} else {
-			return "FALSE"
-		}
-	case types.UUIDKind:
-		convFn := doltcore.GetConvFunc(value.Kind(), types.StringKind)
-		str, _ := convFn(value)
-		return doubleQuot + string(str.(types.String)) + doubleQuot
-	case types.StringKind:
-		s := string(value.(types.String))
-		s = strings.ReplaceAll(s, doubleQuot, "\\\"")
-		return doubleQuot + s + doubleQuot
-	default:
-		convFn := doltcore.GetConvFunc(value.Kind(), types.StringKind)
-		str, _ := convFn(value)
-		return string(str.(types.String))
-	}
-}
@@ -18,13 +18,30 @@ import "github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 
 type XLSXFileInfo struct {
 	Rows []row.Row
+
+	// SheetName is the name of the sheet that is read or written. The name of the table is used when it's empty.
+	SheetName string
+
+	// Workbook is a workbook shared by several writers, which each add a sheet to it. A writer writes a workbook with
+	// only its own sheet when it's nil.
+	Workbook *Workbook
 }
 
 func NewXLSXInfo() *XLSXFileInfo {
-	return &XLSXFileInfo{nil}
+	return &XLSXFileInfo{}
 }
 
 func (info *XLSXFileInfo) SetRows(rows []row.Row) *XLSXFileInfo {
 	info.Rows = rows
 	return info
 }
+
+func (info *XLSXFileInfo) SetSheetName(sheetName string) *XLSXFileInfo {
+	info.SheetName = sheetName
+	return info
+}
+
+func (info *XLSXFileInfo) SetWorkbook(wb *Workbook) *XLSXFileInfo {
+	info.Workbook = wb
+	return info
+}
@@ -52,15 +52,20 @@ func decodeXLSXRows(nbf *types.NomsBinFormat, xlData [][][]string, sch schema.Sc
 	header := dataVals[0]
 	numRows := len(dataVals) - 1
 
-	taggedVals := make(row.TaggedValues, len(header))
-
 	for j := 0; j < numSheets; j++ {
 		for i := 0; i < numRows; i++ {
+			taggedVals := make(row.TaggedValues, len(header))
 			for k, v := range header {
 				col, ok := cols.GetByName(v)
 				if !ok {
 					return nil, errors.New(v + "is not a valid column")
 				}
+
+				// trailing empty cells aren't stored in a row
+				if k >= len(dataVals[i+1]) {
+					continue
+				}
+
 				valString := dataVals[i+1][k]
 				taggedVals[col.Tag], err = doltcore.StringToValue(valString, col.Kind)
 				if err != nil {
@@ -74,7 +79,6 @@ func decodeXLSXRows(nbf *types.NomsBinFormat, xlData [][][]string, sch schema.Sc
 			}
 
 			rows = append(rows, r)
-			fmt.Println(rows)
 		}
 
 	}
@@ -105,5 +109,5 @@ func getXlsxRows(path string, tblName string) ([][][]string, error) {
 		}
 
 	}
-	return nil, errors.New("table name must match excel sheet name.")
+	return nil, fmt.Errorf("table name must match excel sheet name unless another sheet is given. The workbook doesn't have a sheet named '%s'", tblName)
 }
@@ -18,6 +18,7 @@ import (
 	"bufio"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
@@ -47,16 +48,26 @@ func OpenXLSXReader(nbf *types.NomsBinFormat, path string, fs filesys.ReadableFS
 	return NewXLSXReader(nbf, r, info, fs, path, tblName)
 }
 
+// NewXLSXReader returns a reader for the sheet of the workbook at path named by info.SheetName, or by the name of the
+// table if no sheet name is given.
 func NewXLSXReader(nbf *types.NomsBinFormat, r io.ReadCloser, info *XLSXFileInfo, fs filesys.ReadableFS, path string, tblName string) (*XLSXReader, error) {
 	br := bufio.NewReaderSize(r, ReadBufSize)
-	colStrs, err := getColHeaders(path, tblName)
+
+	sheetName := info.SheetName
+	if sheetName == "" {
+		sheetName = tblName
+	}
+
+	colStrs, err := getColHeaders(path, sheetName)
 
 	if err != nil {
+		r.Close()
 		return nil, err
 	}
 
-	data, err := getXlsxRows(path, tblName)
+	data, err := getXlsxRows(path, sheetName)
 	if err != nil {
+		r.Close()
 		return nil, err
 	}
 
@@ -79,6 +90,10 @@ func getColHeaders(path string, sheetName string) ([]string, error) {
 		return nil, err
 	}
 
+	if len(data[0]) == 0 {
+		return nil, fmt.Errorf("the sheet '%s' doesn't have a header row", sheetName)
+	}
+
 	colHeaders := data[0][0]
 	return colHeaders, nil
 }
@@ -0,0 +1,106 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xlsx
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/tealeg/xlsx"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+)
+
+// Workbook is an xlsx workbook that is written once all of its sheets have been added, so that several tables can be
+// exported to one file with a sheet for each table.
+type Workbook struct {
+	file *xlsx.File
+}
+
+func NewWorkbook() *Workbook {
+	return &Workbook{xlsx.NewFile()}
+}
+
+// addSheet adds a sheet with a header row holding the names of the columns of the schema
+func (wb *Workbook) addSheet(name string, sch schema.Schema) (*xlsx.Sheet, error) {
+	if _, ok := wb.file.Sheet[name]; ok {
+		return nil, fmt.Errorf("the workbook already has a sheet named '%s'", name)
+	}
+
+	sheet, err := wb.file.AddSheet(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	header := sheet.AddRow()
+	err = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		header.AddCell().SetString(col.Name)
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sheet, nil
+}
+
+// Write writes the workbook to wr
+func (wb *Workbook) Write(wr io.Writer) error {
+	return wb.file.Write(wr)
+}
+
+// Save writes the workbook to the file at path
+func (wb *Workbook) Save(path string, fs filesys.WritableFS) error {
+	err := fs.MkDirs(filepath.Dir(path))
+
+	if err != nil {
+		return err
+	}
+
+	wr, err := fs.OpenForWrite(path)
+
+	if err != nil {
+		return err
+	}
+
+	err = wb.Write(wr)
+	errCl := wr.Close()
+
+	if err != nil {
+		return err
+	}
+
+	return errCl
+}
+
+// SheetNames returns the names of the sheets of the workbook at path, in the order they appear in the workbook
+func SheetNames(path string) ([]string, error) {
+	data, err := xlsx.OpenFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(data.Sheets))
+	for i, sheet := range data.Sheets {
+		names[i] = sheet.Name
+	}
+
+	return names, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xlsx
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestWorkbookSheets(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "xlsx")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "tables.xlsx")
+	wb := NewWorkbook()
+
+	sheets := map[string][][]string{
+		"people": {{"1", "bill"}, {"2", "jane"}},
+		"pets":   {{"1", "fido"}},
+	}
+
+	for _, name := range []string{"people", "pets"} {
+		_, sch := untyped.NewUntypedSchema("id", "name")
+		wr, err := OpenXLSXWriter(path, filesys.LocalFS, sch, NewXLSXInfo().SetSheetName(name).SetWorkbook(wb))
+		require.NoError(t, err)
+
+		for _, vals := range sheets[name] {
+			r, err := row.New(types.Format_7_18, sch, row.TaggedValues{0: types.String(vals[0]), 1: types.String(vals[1])})
+			require.NoError(t, err)
+			require.NoError(t, wr.WriteRow(ctx, r))
+		}
+
+		require.NoError(t, wr.Close(ctx))
+	}
+
+	exists, _ := filesys.LocalFS.Exists(path)
+	assert.False(t, exists, "the workbook should only be written when it's saved")
+
+	_, sch := untyped.NewUntypedSchema("id", "name")
+	_, err = OpenXLSXWriter(path, filesys.LocalFS, sch, NewXLSXInfo().SetSheetName("pets").SetWorkbook(wb))
+	assert.Error(t, err)
+
+	require.NoError(t, wb.Save(path, filesys.LocalFS))
+
+	names, err := SheetNames(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"people", "pets"}, names)
+
+	for name, expected := range sheets {
+		rd, err := OpenXLSXReader(types.Format_7_18, path, filesys.LocalFS, NewXLSXInfo().SetSheetName(name), "other")
+		require.NoError(t, err)
+
+		var actual [][]string
+		for {
+			r, err := rd.ReadRow(ctx)
+
+			if err == io.EOF {
+				break
+			}
+
+			require.NoError(t, err)
+
+			var vals []string
+			err = rd.GetSchema().GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+				val, _ := r.GetColVal(tag)
+				vals = append(vals, string(val.(types.String)))
+				return false, nil
+			})
+			require.NoError(t, err)
+
+			actual = append(actual, vals)
+		}
+
+		assert.Equal(t, expected, actual, name)
+		require.NoError(t, rd.Close(ctx))
+	}
+
+	_, err = OpenXLSXReader(types.Format_7_18, path, filesys.LocalFS, NewXLSXInfo(), "other")
+	assert.Error(t, err)
+}
@@ -15,29 +15,36 @@
 package xlsx
 
 import (
-	"bufio"
 	"context"
 	"errors"
 	"io"
 	"path/filepath"
 
+	"github.com/tealeg/xlsx"
+
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
-	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
-var WriteBufSize = 256 * 1024
+// DefaultSheetName is the name of the sheet written when no sheet name is given
+const DefaultSheetName = "Sheet1"
 
+// XLSXWriter writes rows to a sheet of a workbook. A writer with its own workbook writes it when it's closed, and a
+// writer that adds its sheet to a shared workbook leaves writing the workbook to the owner of the workbook.
 type XLSXWriter struct {
-	closer io.Closer
-	bWr    *bufio.Writer
-	info   *XLSXFileInfo
+	closer io.WriteCloser
+	wb     *Workbook
+	sheet  *xlsx.Sheet
 	sch    schema.Schema
 }
 
 func OpenXLSXWriter(path string, fs filesys.WritableFS, outSch schema.Schema, info *XLSXFileInfo) (*XLSXWriter, error) {
+	if info.Workbook != nil {
+		return NewXLSXWriter(nil, outSch, info)
+	}
+
 	err := fs.MkDirs(filepath.Dir(path))
 
 	if err != nil {
@@ -50,68 +57,98 @@ func OpenXLSXWriter(path string, fs filesys.WritableFS, outSch schema.Schema, in
 		return nil, err
 	}
 
-	return NewXLSXWriter(wr, outSch, info)
+	xlsxw, err := NewXLSXWriter(wr, outSch, info)
+
+	if err != nil {
+		wr.Close()
+		return nil, err
+	}
+
+	return xlsxw, nil
 }
 
+// NewXLSXWriter returns a writer that writes a sheet to info.Workbook, or to a new workbook that is written to wr when
+// the writer is closed.
 func NewXLSXWriter(wr io.WriteCloser, outSch schema.Schema, info *XLSXFileInfo) (*XLSXWriter, error) {
+	wb := info.Workbook
+	if wb == nil {
+		wb = NewWorkbook()
+	}
+
+	sheetName := info.SheetName
+	if sheetName == "" {
+		sheetName = DefaultSheetName
+	}
+
+	sheet, err := wb.addSheet(sheetName, outSch)
 
-	bwr := bufio.NewWriterSize(wr, WriteBufSize)
+	if err != nil {
+		return nil, err
+	}
 
-	return &XLSXWriter{wr, bwr, info, outSch}, nil
+	return &XLSXWriter{wr, wb, sheet, outSch}, nil
 }
 
 func (xlsxw *XLSXWriter) GetSchema() schema.Schema {
 	return xlsxw.sch
 }
 
+// WriteRow adds a row to the sheet, with a cell for each column. Numbers are written as numeric cells, and other values
+// as text.
 func (xlsxw *XLSXWriter) WriteRow(ctx context.Context, r row.Row) error {
-	allCols := xlsxw.sch.GetAllCols()
-
-	i := 0
-	colValStrs := make([]string, allCols.Size())
-	var xlStr [][]string
-	var rowStr [][][]string
+	if xlsxw.sheet == nil {
+		return errors.New("already closed")
+	}
 
-	err := allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+	xlRow := xlsxw.sheet.AddRow()
+	return xlsxw.sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		cell := xlRow.AddCell()
 		val, ok := r.GetColVal(tag)
-		if ok && !types.IsNull(val) {
-			if val.Kind() == types.StringKind {
-				colValStrs[0] = string(val.(types.String))
-			} else {
-				colValStrs[0], err = types.EncodedValue(ctx, val)
-
-				if err != nil {
-					return false, err
-				}
+
+		if !ok || types.IsNull(val) {
+			return false, nil
+		}
+
+		switch v := val.(type) {
+		case types.String:
+			cell.SetString(string(v))
+		case types.Int:
+			cell.SetInt64(int64(v))
+		case types.Float:
+			cell.SetFloat(float64(v))
+		default:
+			str, err := types.EncodedValue(ctx, val)
+
+			if err != nil {
+				return false, err
 			}
+
+			cell.SetString(str)
 		}
 
-		xlStr = append(xlStr, colValStrs)
-		rowStr = append(rowStr, xlStr)
-		i++
 		return false, nil
 	})
+}
 
-	if err != nil {
-		return err
+// Close writes the workbook if the writer owns it, and releases resources being held
+func (xlsxw *XLSXWriter) Close(ctx context.Context) error {
+	if xlsxw.sheet == nil {
+		return errors.New("already closed")
 	}
 
-	return iohelp.WritePrimIfNoErr(xlsxw.bWr, rowStr, nil)
-}
+	xlsxw.sheet = nil
 
-// Close should flush all writes, release resources being held
-func (xlsxw *XLSXWriter) Close(ctx context.Context) error {
-	if xlsxw.closer != nil {
-		errFl := xlsxw.bWr.Flush()
-		errCl := xlsxw.closer.Close()
-		xlsxw.closer = nil
+	if xlsxw.closer == nil {
+		return nil
+	}
 
-		if errCl != nil {
-			return errCl
-		}
+	err := xlsxw.wb.Write(xlsxw.closer)
+	errCl := xlsxw.closer.Close()
+	xlsxw.closer = nil
 
-		return errFl
+	if err != nil {
+		return err
 	}
-	return errors.New("already closed")
 
+	return errCl
 }
@@ -148,10 +148,13 @@ func writeAll(f *os.File, readers ...io.Reader) error {
 func (bs *LocalBlobstore) Put(ctx context.Context, key string, reader io.Reader) (string, error) {
 	ver := uuid.New()
 
+	path := filepath.Join(bs.RootDir, key) + bsExt
+
 	// written as temp file and renamed so the file corresponding to this key
-	// never exists in a partially written state
+	// never exists in a partially written state. The temp file is created
+	// next to the blob so that the rename doesn't cross filesystems
 	tempFile, err := func() (string, error) {
-		temp, err := ioutil.TempFile("", ver.String())
+		temp, err := ioutil.TempFile(filepath.Dir(path), ver.String())
 
 		if err != nil {
 			return "", err
@@ -168,7 +171,6 @@ func (bs *LocalBlobstore) Put(ctx context.Context, key string, reader io.Reader)
 		return "", err
 	}
 
-	path := filepath.Join(bs.RootDir, key) + bsExt
 	err = os.Rename(tempFile, path)
 
 	if err != nil {
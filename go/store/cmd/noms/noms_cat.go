@@ -27,12 +27,12 @@ import (
 	"path/filepath"
 	"strconv"
 
-	"github.com/golang/snappy"
 	flag "github.com/juju/gnuflag"
 
 	"github.com/liquidata-inc/dolt/go/store/chunks"
 	"github.com/liquidata-inc/dolt/go/store/cmd/noms/util"
 	"github.com/liquidata-inc/dolt/go/store/d"
+	"github.com/liquidata-inc/dolt/go/store/nbs"
 	"github.com/liquidata-inc/dolt/go/store/spec"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
@@ -49,7 +49,8 @@ const (
 	totalUncmpSize = u64Size
 	magicSize      = u64Size
 
-	magicNumber uint64 = 0xffb5d8c22463ee50
+	magicNumber     uint64 = 0xffb5d8c22463ee50
+	zstdMagicNumber uint64 = 0xffb5d8c22463ee5a
 )
 
 var (
@@ -205,10 +206,12 @@ func parseFooter(bytes []byte, pos int) (int, footer) {
 	chunkCntBytes := bytes[pos-chunkCntSize : pos]
 	pos -= chunkCntSize
 
+	magic := binary.BigEndian.Uint64(magicBytes)
+
 	return pos, footer{
 		chunkCnt:   binary.BigEndian.Uint32(chunkCntBytes),
 		uncompSize: binary.BigEndian.Uint64(totalSizeBytes),
-		magicMatch: binary.BigEndian.Uint64(magicBytes) == magicNumber,
+		magicMatch: magic == magicNumber || magic == zstdMagicNumber,
 	}
 }
 
@@ -288,7 +291,7 @@ func parseChunks(bytes []byte, pos int, sizes []int) (int, []chunkData) {
 
 	var cd []chunkData
 	for i := len(sizes) - 1; i >= 0; i-- {
-		uncompressed, err := snappy.Decode(nil, chunkBytes[i])
+		uncompressed, err := nbs.DecompressChunk(chunkBytes[i])
 
 		cd = append(cd, chunkData{
 			compressed:    chunkBytes[i],
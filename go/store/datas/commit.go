@@ -23,6 +23,7 @@ package datas
 
 import (
 	"context"
+	"fmt"
 	"sort"
 
 	"github.com/liquidata-inc/dolt/go/store/d"
@@ -64,7 +65,8 @@ func NewCommit(value types.Value, parents types.Set, meta types.Struct) (types.S
 
 // FindCommonAncestor returns the most recent common ancestor of c1 and c2, if
 // one exists, setting ok to true. If there is no common ancestor, ok is set
-// to false.
+// to false. History past the shallow boundary of vr, if it's a Database, isn't
+// searched, but any other commit missing from vr is an error.
 func FindCommonAncestor(ctx context.Context, c1, c2 types.Ref, vr types.ValueReader) (a types.Ref, ok bool, err error) {
 	t1, err := types.TypeOf(c1)
 
@@ -87,6 +89,7 @@ func FindCommonAncestor(ctx context.Context, c1, c2 types.Ref, vr types.ValueRea
 		d.Panic("second reference is not a commit")
 	}
 
+	sr := &shallowReader{vr: vr}
 	c1Q, c2Q := &types.RefByHeight{c1}, &types.RefByHeight{c2}
 	for !c1Q.Empty() && !c2Q.Empty() {
 		c1Ht, c2Ht := c1Q.MaxHeight(), c2Q.MaxHeight()
@@ -95,24 +98,69 @@ func FindCommonAncestor(ctx context.Context, c1, c2 types.Ref, vr types.ValueRea
 			if common, ok := findCommonRef(c1Parents, c2Parents); ok {
 				return common, true, nil
 			}
-			parentsToQueue(ctx, c1Parents, c1Q, vr)
-			parentsToQueue(ctx, c2Parents, c2Q, vr)
+
+			err = parentsToQueue(ctx, c1Parents, c1Q, sr)
+
+			if err == nil {
+				err = parentsToQueue(ctx, c2Parents, c2Q, sr)
+			}
 		} else if c1Ht > c2Ht {
-			parentsToQueue(ctx, c1Q.PopRefsOfHeight(c1Ht), c1Q, vr)
+			err = parentsToQueue(ctx, c1Q.PopRefsOfHeight(c1Ht), c1Q, sr)
 		} else {
-			parentsToQueue(ctx, c2Q.PopRefsOfHeight(c2Ht), c2Q, vr)
+			err = parentsToQueue(ctx, c2Q.PopRefsOfHeight(c2Ht), c2Q, sr)
+		}
+
+		if err != nil {
+			return types.Ref{}, false, err
 		}
 	}
 
 	return a, ok, nil
 }
 
-func parentsToQueue(ctx context.Context, refs types.RefSlice, q *types.RefByHeight, vr types.ValueReader) error {
+// shallowReader reads the commits searched by FindCommonAncestor, and reads the shallow boundary of the database the
+// first time a commit is missing
+type shallowReader struct {
+	vr      types.ValueReader
+	shallow hash.HashSet
+}
+
+// isShallow returns whether the commit |h| is on the shallow boundary of the database, and so is expected to be missing
+func (sr *shallowReader) isShallow(ctx context.Context, h hash.Hash) (bool, error) {
+	if sr.shallow == nil {
+		db, ok := sr.vr.(Database)
+
+		if !ok {
+			return false, nil
+		}
+
+		shallow, err := ShallowCommits(ctx, db)
+
+		if err != nil {
+			return false, err
+		}
+
+		sr.shallow = shallow
+	}
+
+	return sr.shallow.Has(h), nil
+}
+
+func parentsToQueue(ctx context.Context, refs types.RefSlice, q *types.RefByHeight, sr *shallowReader) error {
 	for _, r := range refs {
-		v, err := r.TargetValue(ctx, vr)
+		v, err := r.TargetValue(ctx, sr.vr)
 
 		if err != nil {
 			return err
+		} else if v == nil {
+			if shallow, err := sr.isShallow(ctx, r.TargetHash()); err != nil {
+				return err
+			} else if !shallow {
+				return fmt.Errorf("commit %s is missing from the database", r.TargetHash().String())
+			}
+
+			// the commit is past the shallow boundary of the database, so its history can't be searched
+			continue
 		}
 
 		c := v.(types.Struct)
@@ -128,6 +176,10 @@ func parentsToQueue(ctx context.Context, refs types.RefSlice, q *types.RefByHeig
 				q.PushBack(v.(types.Ref))
 				return nil
 			})
+
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -0,0 +1,223 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// StoreVerifier is a ChunkStore that can check the files its chunks are persisted to
+type StoreVerifier interface {
+	chunks.ChunkStore
+
+	// Verify returns a description of each problem found in the files of the store
+	Verify(ctx context.Context) ([]string, error)
+}
+
+// FsckReport is the result of checking the chunks of a database
+type FsckReport struct {
+	// Root is the root of the database when it was checked
+	Root hash.Hash
+
+	// StoreProblems are the problems found in the files of the database's ChunkStore, if it's a StoreVerifier
+	StoreProblems []string
+
+	// Missing are the chunks that are reachable but aren't in the store
+	Missing hash.HashSet
+
+	// Corrupt are the chunks that don't hash to their address, or can't be read or decoded
+	Corrupt hash.HashSet
+
+	// Shallow are the commits of the database's shallow boundary, which are reachable but were never pulled
+	Shallow hash.HashSet
+
+	damaged hash.HashSet
+}
+
+// OK returns whether no problems were found
+func (r *FsckReport) OK() bool {
+	return len(r.StoreProblems) == 0 && len(r.Missing) == 0 && len(r.Corrupt) == 0
+}
+
+// IsBad returns whether the chunk with the hash given is missing or corrupt
+func (r *FsckReport) IsBad(h hash.Hash) bool {
+	return r.Missing.Has(h) || r.Corrupt.Has(h)
+}
+
+// IsDamaged returns whether the chunk with the hash given, or any chunk reachable from it, is missing or corrupt
+func (r *FsckReport) IsDamaged(h hash.Hash) bool {
+	return r.damaged.Has(h)
+}
+
+// Fsck checks that every chunk reachable from the root of the database, or from any of the |roots| given, is in the
+// store and hashes to its address. The commits of the database's shallow boundary are expected to be missing. If the
+// ChunkStore of the database is a StoreVerifier the files of the store are checked as well.
+func Fsck(ctx context.Context, db Database, roots ...hash.Hash) (*FsckReport, error) {
+	cs := db.chunkStore()
+	err := cs.Rebase(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dbRoot, err := cs.Root(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	shallow, err := ShallowCommits(ctx, db)
+
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FsckReport{Root: dbRoot, Missing: hash.HashSet{}, Corrupt: hash.HashSet{}, Shallow: hash.HashSet{}, damaged: hash.HashSet{}}
+
+	if verifier, ok := cs.(StoreVerifier); ok {
+		report.StoreProblems, err = verifier.Verify(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// parents maps each chunk to the chunks that reference it, so damage can be traced back to the roots
+	parents := make(map[hash.Hash]hash.HashSlice)
+	visited := hash.HashSet{}
+	level := hash.HashSlice{}
+	for _, h := range append([]hash.Hash{dbRoot}, roots...) {
+		if !h.IsEmpty() && !visited.Has(h) {
+			visited.Insert(h)
+			level = append(level, h)
+		}
+	}
+
+	for len(level) > 0 {
+		var next hash.HashSlice
+		for start := 0; start < len(level); start += walkBatchSize {
+			end := start + walkBatchSize
+			if end > len(level) {
+				end = len(level)
+			}
+
+			batch := level[start:end].HashSet()
+			found, err := fsckFetch(ctx, cs, batch, report.Corrupt)
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, c := range found {
+				delete(batch, c.Hash())
+
+				if hash.Of(c.Data()) != c.Hash() {
+					report.Corrupt.Insert(c.Hash())
+					continue
+				}
+
+				var children hash.HashSlice
+				err := types.WalkRefs(*c, db.Format(), func(r types.Ref) error {
+					children = append(children, r.TargetHash())
+					return nil
+				})
+
+				if err != nil {
+					report.Corrupt.Insert(c.Hash())
+					continue
+				}
+
+				for _, child := range children {
+					parents[child] = append(parents[child], c.Hash())
+
+					if !visited.Has(child) {
+						visited.Insert(child)
+						next = append(next, child)
+					}
+				}
+			}
+
+			for h := range batch {
+				if shallow.Has(h) {
+					report.Shallow.Insert(h)
+				} else if !report.Corrupt.Has(h) {
+					report.Missing.Insert(h)
+				}
+			}
+		}
+
+		level = next
+	}
+
+	var damaged hash.HashSlice
+	for h := range report.Missing {
+		damaged = append(damaged, h)
+	}
+
+	for h := range report.Corrupt {
+		damaged = append(damaged, h)
+	}
+
+	for len(damaged) > 0 {
+		h := damaged[len(damaged)-1]
+		damaged = damaged[:len(damaged)-1]
+
+		if report.damaged.Has(h) {
+			continue
+		}
+
+		report.damaged.Insert(h)
+		damaged = append(damaged, parents[h]...)
+	}
+
+	return report, nil
+}
+
+// fsckFetch reads the chunks of |batch| from the store. The store fails to read a batch with a chunk that can't be
+// read, so when it does the chunks are read one at a time and the ones that fail are added to |corrupt|.
+func fsckFetch(ctx context.Context, cs chunks.ChunkStore, batch hash.HashSet, corrupt hash.HashSet) ([]*chunks.Chunk, error) {
+	foundChan := make(chan *chunks.Chunk, len(batch))
+	err := cs.GetMany(ctx, batch, foundChan)
+	close(foundChan)
+
+	var found []*chunks.Chunk
+	if err == nil {
+		for c := range foundChan {
+			found = append(found, c)
+		}
+
+		return found, nil
+	}
+
+	for h := range batch {
+		c, err := cs.Get(ctx, h)
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			corrupt.Insert(h)
+		} else if !c.IsEmpty() {
+			found = append(found, &c)
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestFsck(t *testing.T) {
+	ctx := context.Background()
+	storage := &chunks.MemoryStorage{}
+	db := NewDatabase(storage.NewView())
+	defer db.Close()
+
+	ds, err := db.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+	_, err = db.CommitValue(ctx, ds, types.String("value"))
+	require.NoError(t, err)
+
+	report, err := Fsck(ctx, db)
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+
+	// a chunk holding a ref to a value that was never written
+	missingRef, err := types.NewRef(types.String("never written"), db.Format())
+	require.NoError(t, err)
+	dangling, err := types.EncodeValue(missingRef, db.Format())
+	require.NoError(t, err)
+	require.NoError(t, db.chunkStore().Put(ctx, dangling))
+
+	corrupt := chunks.NewChunkWithHash(hash.Of([]byte("original")), []byte("changed"))
+	require.NoError(t, db.chunkStore().Put(ctx, corrupt))
+
+	report, err = Fsck(ctx, db, dangling.Hash(), corrupt.Hash())
+	require.NoError(t, err)
+	assert.False(t, report.OK())
+
+	assert.Equal(t, hash.NewHashSet(missingRef.TargetHash()), report.Missing)
+	assert.Equal(t, hash.NewHashSet(corrupt.Hash()), report.Corrupt)
+	assert.True(t, report.IsBad(missingRef.TargetHash()))
+	assert.False(t, report.IsBad(dangling.Hash()))
+	assert.True(t, report.IsDamaged(dangling.Hash()))
+	assert.True(t, report.IsDamaged(corrupt.Hash()))
+	assert.False(t, report.IsDamaged(report.Root))
+}
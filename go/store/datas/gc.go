@@ -0,0 +1,185 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/liquidata-inc/dolt/go/store/atomicerr"
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// ErrGCUnsupported is returned by CollectGarbage for databases whose ChunkStore can't collect garbage
+var ErrGCUnsupported = errors.New("the chunk store of this database does not support garbage collection")
+
+// GarbageCollector is a ChunkStore that can remove the chunks that are no longer needed
+type GarbageCollector interface {
+	chunks.ChunkStore
+
+	// GCRoot returns the root of the store and a lock identifying its contents. Unlike the root, the lock changes
+	// whenever chunks are committed to the store.
+	GCRoot(ctx context.Context) (root hash.Hash, lock hash.Hash, err error)
+
+	// CollectGarbage removes every chunk of the store that isn't in |keepers|, which holds every chunk reachable from
+	// the root returned by GCRoot along with |lock|. It fails if the store has been written to since, so that its lock
+	// isn't |lock|.
+	CollectGarbage(ctx context.Context, lock hash.Hash, keepers hash.HashSet) error
+}
+
+// GCStats are the number of chunks kept and removed by a garbage collection
+type GCStats struct {
+	Kept    int
+	Removed int
+}
+
+const walkBatchSize = 1 << 12
+
+// CollectGarbage removes the chunks of the database that aren't reachable from its root, which references every
+// dataset, or from any of the |roots| given. |roots| are values the caller holds on to outside of the database, like
+// the working set of a repository.
+func CollectGarbage(ctx context.Context, db Database, roots ...hash.Hash) (GCStats, error) {
+	gc, ok := db.chunkStore().(GarbageCollector)
+
+	if !ok {
+		return GCStats{}, ErrGCUnsupported
+	}
+
+	err := gc.Rebase(ctx)
+
+	if err != nil {
+		return GCStats{}, err
+	}
+
+	dbRoot, lock, err := gc.GCRoot(ctx)
+
+	if err != nil {
+		return GCStats{}, err
+	}
+
+	before, err := gcChunkCount(gc)
+
+	if err != nil {
+		return GCStats{}, err
+	}
+
+	shallow, err := ShallowCommits(ctx, db)
+
+	if err != nil {
+		return GCStats{}, err
+	}
+
+	keepers, err := reachableChunks(ctx, gc, db.Format(), append([]hash.Hash{dbRoot}, roots...), shallow)
+
+	if err != nil {
+		return GCStats{}, err
+	}
+
+	err = gc.CollectGarbage(ctx, lock, keepers)
+
+	if err != nil {
+		return GCStats{}, err
+	}
+
+	return GCStats{Kept: len(keepers), Removed: before - len(keepers)}, nil
+}
+
+// gcChunkCount returns the number of chunks in the store, or 0 if the store can't count them
+func gcChunkCount(cs chunks.ChunkStore) (int, error) {
+	counter, ok := cs.(interface {
+		Count() (uint32, error)
+	})
+
+	if !ok {
+		return 0, nil
+	}
+
+	cnt, err := counter.Count()
+	return int(cnt), err
+}
+
+// reachableChunks returns the hashes of every chunk reachable from |roots|, walking the refs of the chunks a level at
+// a time. A chunk that is reachable but isn't in the store is an error, as the store is already missing data, unless
+// it's a commit of the database's |shallow| boundary.
+func reachableChunks(ctx context.Context, cs chunks.ChunkStore, nbf *types.NomsBinFormat, roots []hash.Hash, shallow hash.HashSet) (hash.HashSet, error) {
+	reachable := hash.HashSet{}
+	level := hash.HashSlice{}
+	for _, h := range roots {
+		if !h.IsEmpty() && !reachable.Has(h) {
+			reachable.Insert(h)
+			level = append(level, h)
+		}
+	}
+
+	for len(level) > 0 {
+		var next hash.HashSlice
+		for start := 0; start < len(level); start += walkBatchSize {
+			end := start + walkBatchSize
+			if end > len(level) {
+				end = len(level)
+			}
+
+			batch := level[start:end].HashSet()
+			found := make(chan *chunks.Chunk, walkBatchSize)
+
+			ae := atomicerr.New()
+			go func() {
+				defer close(found)
+				err := cs.GetMany(ctx, batch, found)
+				ae.SetIfError(err)
+			}()
+
+			for c := range found {
+				delete(batch, c.Hash())
+
+				if ae.IsSet() {
+					continue
+				}
+
+				err := types.WalkRefs(*c, nbf, func(r types.Ref) error {
+					h := r.TargetHash()
+
+					if !reachable.Has(h) {
+						reachable.Insert(h)
+						next = append(next, h)
+					}
+
+					return nil
+				})
+
+				ae.SetIfError(err)
+			}
+
+			if err := ae.Get(); err != nil {
+				return nil, err
+			}
+
+			for h := range batch {
+				if !shallow.Has(h) {
+					return nil, fmt.Errorf("chunk %s is reachable but is missing from the store", h.String())
+				}
+
+				delete(reachable, h)
+			}
+		}
+
+		level = next
+	}
+
+	return reachable, nil
+}
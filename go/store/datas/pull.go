@@ -43,8 +43,30 @@ type PullProgress struct {
 const (
 	bytesWrittenSampleRate = .10
 	defaultBatchSize       = 1 << 12 // 4096 chunks
+	checkpointBatchSize    = 1 << 16 // 65536 chunks
+	checkpointInterval     = 1 << 26 // 64MB of chunk data
 )
 
+// PullState is the progress of a pull. It's the chunks of the level of the tree being pulled that are still to be
+// pulled, and the chunks of the next level found so far.
+type PullState struct {
+	Absent hash.HashSlice
+	Next   hash.HashSlice
+}
+
+// PullCheckpoint saves the progress of pulls so that an interrupted pull can pick up where it left off. The chunks
+// pulled before a checkpoint are persisted in the sink before the state is saved.
+type PullCheckpoint interface {
+	// Load returns the last state saved for the pull of the chunk with the hash given, and whether there is one.
+	Load(ctx context.Context, h hash.Hash) (PullState, bool, error)
+
+	// Save saves the state of the pull of the chunk with the hash given.
+	Save(ctx context.Context, h hash.Hash, state PullState) error
+
+	// Clear removes the state of the pull of the chunk with the hash given, once it's complete.
+	Clear(ctx context.Context, h hash.Hash) error
+}
+
 func makeProgTrack(progressCh chan PullProgress) func(moreDone, moreKnown, moreApproxBytesWritten uint64) {
 	var doneCount, knownCount, approxBytesWritten uint64
 	return func(moreDone, moreKnown, moreApproxBytesWritten uint64) {
@@ -58,10 +80,31 @@ func makeProgTrack(progressCh chan PullProgress) func(moreDone, moreKnown, moreA
 
 // Pull objects that descend from sourceRef from srcDB to sinkDB.
 func Pull(ctx context.Context, srcDB, sinkDB Database, sourceRef types.Ref, progressCh chan PullProgress) error {
-	return pull(ctx, srcDB, sinkDB, sourceRef, progressCh, defaultBatchSize)
+	return pull(ctx, srcDB, sinkDB, sourceRef, progressCh, defaultBatchSize, hash.HashSet{}, nil)
+}
+
+// PullWithCheckpoint is Pull for transfers that may be interrupted. Its progress is saved to |checkpoint| every so
+// often, and a pull of the same ref that finds saved progress picks up from there rather than starting over.
+func PullWithCheckpoint(ctx context.Context, srcDB, sinkDB Database, sourceRef types.Ref, progressCh chan PullProgress, checkpoint PullCheckpoint) error {
+	return pull(ctx, srcDB, sinkDB, sourceRef, progressCh, checkpointBatchSize, hash.HashSet{}, checkpoint)
 }
 
-func pull(ctx context.Context, srcDB, sinkDB Database, sourceRef types.Ref, progressCh chan PullProgress, batchSize int) error {
+// PullShallow pulls the commit referenced by sourceRef from srcDB to sinkDB along with only the last |depth| commits of
+// its history. The parents of the oldest commits pulled are added to the shallow boundary of sinkDB.
+func PullShallow(ctx context.Context, srcDB, sinkDB Database, sourceRef types.Ref, depth int, progressCh chan PullProgress) error {
+	boundary, err := shallowBoundary(ctx, srcDB, sourceRef, depth)
+
+	if err != nil {
+		return err
+	}
+
+	return pull(ctx, srcDB, sinkDB, sourceRef, progressCh, math.MaxInt32, boundary, nil)
+}
+
+// pull copies the chunks reachable from sourceRef that sinkDB is missing from srcDB. The commits of |boundary| and the
+// shallow boundary of srcDB aren't pulled. The ones that are reached and that sinkDB doesn't have are added to its
+// shallow boundary. If |checkpoint| isn't nil the progress of the pull is saved to it.
+func pull(ctx context.Context, srcDB, sinkDB Database, sourceRef types.Ref, progressCh chan PullProgress, batchSize int, boundary hash.HashSet, checkpoint PullCheckpoint) error {
 	// Sanity Check
 	exists, err := srcDB.chunkStore().Has(ctx, sourceRef.TargetHash())
 
@@ -73,13 +116,29 @@ func pull(ctx context.Context, srcDB, sinkDB Database, sourceRef types.Ref, prog
 		return errors.New("not found")
 	}
 
+	state := PullState{Absent: hash.HashSlice{sourceRef.TargetHash()}}
+	resumed := false
+
+	if checkpoint != nil {
+		var saved PullState
+		saved, resumed, err = checkpoint.Load(ctx, sourceRef.TargetHash())
+
+		if err != nil {
+			return err
+		} else if resumed {
+			state = saved
+		}
+	}
+
+	// The chunk of sourceRef is persisted by the first checkpoint of a pull, so it's only up to date when there's no
+	// saved progress.
 	exists, err = sinkDB.chunkStore().Has(ctx, sourceRef.TargetHash())
 
 	if err != nil {
 		return err
 	}
 
-	if exists {
+	if exists && !resumed {
 		return nil // already up to date
 	}
 
@@ -87,17 +146,41 @@ func pull(ctx context.Context, srcDB, sinkDB Database, sourceRef types.Ref, prog
 		return fmt.Errorf("cannot pull from src to sink; src version is %v and sink version is %v", srcDB.chunkStore().Version(), sinkDB.chunkStore().Version())
 	}
 
+	srcShallow, err := ShallowCommits(ctx, srcDB)
+
+	if err != nil {
+		return err
+	}
+
+	for h := range srcShallow {
+		boundary.Insert(h)
+	}
+
+	if checkpoint != nil && !resumed {
+		// Saved before anything is persisted, so a pull interrupted after its first checkpoint is never taken to be
+		// up to date.
+		err = checkpoint.Save(ctx, sourceRef.TargetHash(), state)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	var sampleSize, sampleCount uint64
+	var sinceCheckpoint int
 	updateProgress := makeProgTrack(progressCh)
+	reachedBoundary := hash.HashSet{}
 
 	// TODO: This batches based on limiting the _number_ of chunks processed at the same time. We really want to batch based on the _amount_ of chunk data being processed simultaneously. We also want to consider the chunks in a particular order, however, and the current GetMany() interface doesn't provide any ordering guarantees. Once BUG 3750 is fixed, we should be able to revisit this and do a better job.
-	absent := hash.HashSlice{sourceRef.TargetHash()}
+	absent := state.Absent
 	for absentCount := len(absent); absentCount != 0; absentCount = len(absent) {
 		updateProgress(0, uint64(absentCount), 0)
 
-		// For gathering up the hashes in the next level of the tree
-		nextLevel := hash.HashSet{}
-		uniqueOrdered := hash.HashSlice{}
+		// For gathering up the hashes in the next level of the tree, starting with the ones found before the pull was
+		// interrupted if it was resumed part way through this level
+		nextLevel := state.Next.HashSet()
+		uniqueOrdered := append(hash.HashSlice{}, state.Next...)
+		state.Next = nil
 
 		// Process all absent chunks in this level of the tree in quanta of at most |batchSize|
 		for start, end := 0, batchSize; start < absentCount; start, end = end, end+batchSize {
@@ -112,11 +195,29 @@ func pull(ctx context.Context, srcDB, sinkDB Database, sourceRef types.Ref, prog
 				return err
 			}
 
-			uniqueOrdered, err = putChunks(ctx, sinkDB, batch, neededChunks, nextLevel, uniqueOrdered)
+			uniqueOrdered, err = putChunks(ctx, sinkDB, batch, neededChunks, nextLevel, uniqueOrdered, boundary, reachedBoundary)
 
 			if err != nil {
 				return err
 			}
+
+			if checkpoint == nil {
+				continue
+			}
+
+			for _, c := range neededChunks {
+				sinceCheckpoint += len(c.Data())
+			}
+
+			if sinceCheckpoint >= checkpointInterval {
+				err = saveCheckpoint(ctx, sinkDB, checkpoint, sourceRef.TargetHash(), PullState{Absent: absent[end:], Next: uniqueOrdered})
+
+				if err != nil {
+					return err
+				}
+
+				sinceCheckpoint = 0
+			}
 		}
 
 		absent, err = nextLevelMissingChunks(ctx, sinkDB, nextLevel, absent, uniqueOrdered)
@@ -132,7 +233,37 @@ func pull(ctx context.Context, srcDB, sinkDB Database, sourceRef types.Ref, prog
 		return err
 	}
 
-	return nil
+	if checkpoint != nil {
+		err = checkpoint.Clear(ctx, sourceRef.TargetHash())
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(reachedBoundary) == 0 {
+		return nil
+	}
+
+	missingFromSink, err := sinkDB.chunkStore().HasMany(ctx, reachedBoundary)
+
+	if err != nil {
+		return err
+	}
+
+	return addShallowCommits(ctx, sinkDB, missingFromSink)
+}
+
+// saveCheckpoint persists the chunks put in the sink so far and then saves |state|. If the pull is interrupted between
+// the two it resumes from the previous checkpoint, which only pulls some of the chunks again.
+func saveCheckpoint(ctx context.Context, sinkDB Database, checkpoint PullCheckpoint, h hash.Hash, state PullState) error {
+	err := persistChunks(ctx, sinkDB.chunkStore())
+
+	if err != nil {
+		return err
+	}
+
+	return checkpoint.Save(ctx, h, state)
 }
 
 func persistChunks(ctx context.Context, cs chunks.ChunkStore) error {
@@ -159,7 +290,7 @@ func persistChunks(ctx context.Context, cs chunks.ChunkStore) error {
 // optimization problem down to the chunk store which can make smarter decisions.
 func PullWithoutBatching(ctx context.Context, srcDB, sinkDB Database, sourceRef types.Ref, progressCh chan PullProgress) error {
 	// by increasing the batch size to MaxInt32 we effectively remove batching here.
-	return pull(ctx, srcDB, sinkDB, sourceRef, progressCh, math.MaxInt32)
+	return pull(ctx, srcDB, sinkDB, sourceRef, progressCh, math.MaxInt32, hash.HashSet{}, nil)
 }
 
 // concurrently pull all chunks from this batch that the sink is missing out of the source
@@ -197,10 +328,16 @@ func getChunks(ctx context.Context, srcDB Database, batch hash.HashSlice, sample
 }
 
 // put the chunks that were downloaded into the sink IN ORDER and at the same time gather up an ordered, uniquified list
-// of all the children of the chunks and add them to the list of the next level tree chunks.
-func putChunks(ctx context.Context, sinkDB Database, hashes hash.HashSlice, neededChunks map[hash.Hash]*chunks.Chunk, nextLevel hash.HashSet, uniqueOrdered hash.HashSlice) (hash.HashSlice, error) {
+// of all the children of the chunks and add them to the list of the next level tree chunks. Children in |boundary|
+// aren't added, and are gathered in |reachedBoundary| instead.
+func putChunks(ctx context.Context, sinkDB Database, hashes hash.HashSlice, neededChunks map[hash.Hash]*chunks.Chunk, nextLevel hash.HashSet, uniqueOrdered hash.HashSlice, boundary, reachedBoundary hash.HashSet) (hash.HashSlice, error) {
 	for _, h := range hashes {
-		c := neededChunks[h]
+		c, ok := neededChunks[h]
+
+		if !ok {
+			return hash.HashSlice{}, fmt.Errorf("chunk %s is missing from the source", h.String())
+		}
+
 		err := sinkDB.chunkStore().Put(ctx, *c)
 
 		if err != nil {
@@ -208,7 +345,9 @@ func putChunks(ctx context.Context, sinkDB Database, hashes hash.HashSlice, need
 		}
 
 		err = types.WalkRefs(*c, sinkDB.Format(), func(r types.Ref) error {
-			if !nextLevel.Has(r.TargetHash()) {
+			if boundary.Has(r.TargetHash()) {
+				reachedBoundary.Insert(r.TargetHash())
+			} else if !nextLevel.Has(r.TargetHash()) {
 				uniqueOrdered = append(uniqueOrdered, r.TargetHash())
 				nextLevel.Insert(r.TargetHash())
 			}
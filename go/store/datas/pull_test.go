@@ -29,6 +29,7 @@ import (
 
 	"github.com/liquidata-inc/dolt/go/store/chunks"
 	"github.com/liquidata-inc/dolt/go/store/d"
+	"github.com/liquidata-inc/dolt/go/store/hash"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -182,6 +183,61 @@ func (suite *PullSuite) TestPullEverything() {
 	suite.True(l.Equals(mustGetValue(v.MaybeGet(ValueField))))
 }
 
+// memCheckpoint is a PullCheckpoint that keeps the state of pulls in memory
+type memCheckpoint map[hash.Hash]PullState
+
+func (mc memCheckpoint) Load(ctx context.Context, h hash.Hash) (PullState, bool, error) {
+	state, ok := mc[h]
+	return state, ok, nil
+}
+
+func (mc memCheckpoint) Save(ctx context.Context, h hash.Hash, state PullState) error {
+	mc[h] = state
+	return nil
+}
+
+func (mc memCheckpoint) Clear(ctx context.Context, h hash.Hash) error {
+	delete(mc, h)
+	return nil
+}
+
+func (suite *PullSuite) TestPullWithCheckpoint() {
+	l := buildListOfHeight(2, suite.source)
+	sourceRef := suite.commitToSource(l, mustSet(types.NewSet(context.Background(), suite.source)))
+	checkpoint := memCheckpoint{}
+
+	err := PullWithCheckpoint(context.Background(), suite.source, suite.sink, sourceRef, nil, checkpoint)
+	suite.NoError(err)
+	suite.Empty(checkpoint)
+
+	v := mustValue(suite.sink.ReadValue(context.Background(), sourceRef.TargetHash())).(types.Struct)
+	suite.NotNil(v)
+	suite.True(l.Equals(mustGetValue(v.MaybeGet(ValueField))))
+}
+
+// A pull interrupted after its first checkpoint has persisted the chunk being pulled, but not the chunks it references.
+func (suite *PullSuite) TestResumeInterruptedPull() {
+	l := buildListOfHeight(2, suite.source)
+	sourceRef := suite.commitToSource(l, mustSet(types.NewSet(context.Background(), suite.source)))
+
+	c, err := suite.sourceCS.Get(context.Background(), sourceRef.TargetHash())
+	suite.NoError(err)
+	suite.NoError(suite.sinkCS.Put(context.Background(), c))
+	suite.NoError(persistChunks(context.Background(), suite.sinkCS))
+
+	checkpoint := memCheckpoint{sourceRef.TargetHash(): {Absent: hash.HashSlice{sourceRef.TargetHash()}}}
+	err = PullWithCheckpoint(context.Background(), suite.source, suite.sink, sourceRef, nil, checkpoint)
+	suite.NoError(err)
+	suite.Empty(checkpoint)
+
+	_, err = reachableChunks(context.Background(), suite.sinkCS, suite.sink.Format(), []hash.Hash{sourceRef.TargetHash()}, nil)
+	suite.NoError(err)
+
+	v := mustValue(suite.sink.ReadValue(context.Background(), sourceRef.TargetHash())).(types.Struct)
+	suite.NotNil(v)
+	suite.True(l.Equals(mustGetValue(v.MaybeGet(ValueField))))
+}
+
 // Source: -6-> C3(L5) -1-> N
 //               .  \  -5-> L4 -1-> N
 //                .          \ -4-> L3 -1-> N
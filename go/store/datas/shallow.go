@@ -0,0 +1,227 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// shallowDatasetID is the dataset holding the shallow boundary of a database. Its value is a Set of the hash strings
+// of the boundary commits, which aren't refs so that nothing walking the database tries to follow them.
+const shallowDatasetID = "shallow"
+
+// ErrInvalidDepth is returned when a shallow pull is asked for less than one commit of history
+var ErrInvalidDepth = errors.New("the depth of a shallow pull must be at least 1")
+
+// ShallowCommits returns the shallow boundary of the database. These are commits that are parents of commits in the
+// database but were left out of it by a shallow pull, along with the history behind them.
+func ShallowCommits(ctx context.Context, db Database) (hash.HashSet, error) {
+	shallow := hash.HashSet{}
+	ds, err := db.GetDataset(ctx, shallowDatasetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	val, ok, err := ds.MaybeHeadValue()
+
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return shallow, nil
+	}
+
+	set, ok := val.(types.Set)
+
+	if !ok {
+		return nil, errors.New("the shallow boundary of the database isn't a set")
+	}
+
+	err = set.IterAll(ctx, func(v types.Value) error {
+		h, ok := hash.MaybeParse(string(v.(types.String)))
+
+		if !ok {
+			return errors.New("the shallow boundary of the database has an invalid hash")
+		}
+
+		shallow.Insert(h)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return shallow, nil
+}
+
+// MissingShallowCommits returns the commits of the shallow boundary of srcDB that sinkDB has neither in it nor on its
+// own shallow boundary. A pull from srcDB to sinkDB which reaches them would make sinkDB shallow.
+func MissingShallowCommits(ctx context.Context, srcDB, sinkDB Database) (hash.HashSet, error) {
+	srcShallow, err := ShallowCommits(ctx, srcDB)
+
+	if err != nil {
+		return nil, err
+	} else if len(srcShallow) == 0 {
+		return srcShallow, nil
+	}
+
+	missing, err := sinkDB.chunkStore().HasMany(ctx, srcShallow)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sinkShallow, err := ShallowCommits(ctx, sinkDB)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for h := range sinkShallow {
+		missing.Remove(h)
+	}
+
+	return missing, nil
+}
+
+// AllowShallow lets values that reference the commits past the shallow boundary of |db| be written to it. Writing a
+// value that references a chunk which isn't in the database otherwise fails. It must be called on a shallow database
+// before its refs are updated.
+func AllowShallow(db Database) {
+	if d, ok := db.(*database); ok {
+		d.SetEnforceCompleteness(false)
+	}
+}
+
+// addShallowCommits adds the commits given to the shallow boundary of |db|
+func addShallowCommits(ctx context.Context, db Database, commits hash.HashSet) error {
+	if len(commits) == 0 {
+		return nil
+	}
+
+	shallow, err := ShallowCommits(ctx, db)
+
+	if err != nil {
+		return err
+	}
+
+	vals := make([]types.Value, 0, len(shallow)+len(commits))
+	for h := range shallow {
+		vals = append(vals, types.String(h.String()))
+	}
+
+	for h := range commits {
+		if !shallow.Has(h) {
+			vals = append(vals, types.String(h.String()))
+		}
+	}
+
+	if len(vals) == len(shallow) {
+		return nil
+	}
+
+	set, err := types.NewSet(ctx, db, vals...)
+
+	if err != nil {
+		return err
+	}
+
+	ds, err := db.GetDataset(ctx, shallowDatasetID)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = db.CommitValue(ctx, ds, set)
+
+	if err != nil {
+		return err
+	}
+
+	AllowShallow(db)
+	return nil
+}
+
+// shallowBoundary walks the commits of |db| from |head| and returns the parents of the commits within |depth| commits
+// of it, which make up the boundary of a shallow pull of |head|.
+func shallowBoundary(ctx context.Context, db Database, head types.Ref, depth int) (hash.HashSet, error) {
+	if depth < 1 {
+		return nil, ErrInvalidDepth
+	}
+
+	included := hash.NewHashSet(head.TargetHash())
+	level := []hash.Hash{head.TargetHash()}
+	var parents hash.HashSet
+	for i := 0; i < depth && len(level) > 0; i++ {
+		parents = hash.HashSet{}
+		for _, h := range level {
+			val, err := db.ReadValue(ctx, h)
+
+			if err != nil {
+				return nil, err
+			} else if val == nil {
+				// a commit past the source's own shallow boundary
+				continue
+			}
+
+			if isCommit, err := IsCommit(val); err != nil {
+				return nil, err
+			} else if !isCommit {
+				return nil, errors.New("the value being pulled isn't a commit")
+			}
+
+			ps, ok, err := val.(types.Struct).MaybeGet(ParentsField)
+
+			if err != nil {
+				return nil, err
+			} else if !ok {
+				continue
+			}
+
+			err = ps.(types.Set).IterAll(ctx, func(v types.Value) error {
+				parents.Insert(v.(types.Ref).TargetHash())
+				return nil
+			})
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		level = level[:0]
+		if i < depth-1 {
+			for h := range parents {
+				if !included.Has(h) {
+					included.Insert(h)
+					level = append(level, h)
+				}
+			}
+		}
+	}
+
+	boundary := hash.HashSet{}
+	for h := range parents {
+		if !included.Has(h) {
+			boundary.Insert(h)
+		}
+	}
+
+	return boundary, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestPullShallow(t *testing.T) {
+	ctx := context.Background()
+	srcStorage, sinkStorage := &chunks.MemoryStorage{}, &chunks.MemoryStorage{}
+	src, sink := NewDatabase(srcStorage.NewView()), NewDatabase(sinkStorage.NewView())
+	defer src.Close()
+	defer sink.Close()
+
+	ds, err := src.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+
+	var commits []types.Ref
+	for _, val := range []string{"one", "two", "three", "four"} {
+		ds, err = src.CommitValue(ctx, ds, types.String(val))
+		require.NoError(t, err)
+		commits = append(commits, mustHeadRef(ds))
+	}
+
+	head := commits[3]
+	err = PullShallow(ctx, src, sink, head, 0, nil)
+	assert.Equal(t, ErrInvalidDepth, err)
+
+	err = PullShallow(ctx, src, sink, head, 2, nil)
+	require.NoError(t, err)
+
+	shallow, err := ShallowCommits(ctx, sink)
+	require.NoError(t, err)
+	assert.Equal(t, hash.NewHashSet(commits[1].TargetHash()), shallow)
+
+	for i, cm := range commits {
+		val, err := sink.ReadValue(ctx, cm.TargetHash())
+		require.NoError(t, err)
+		assert.Equal(t, i >= 2, val != nil)
+	}
+
+	sinkDS, err := sink.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+	_, err = sink.SetHead(ctx, sinkDS, head)
+	require.NoError(t, err)
+
+	ancestor, ok, err := FindCommonAncestor(ctx, head, commits[2], sink)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, commits[2].TargetHash(), ancestor.TargetHash())
+
+	report, err := Fsck(ctx, sink)
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+	assert.Equal(t, shallow, report.Shallow)
+}
+
+func TestFindCommonAncestorAcrossShallowBoundary(t *testing.T) {
+	ctx := context.Background()
+	srcStorage, sinkStorage := &chunks.MemoryStorage{}, &chunks.MemoryStorage{}
+	src, sink := NewDatabase(srcStorage.NewView()), NewDatabase(sinkStorage.NewView())
+	defer src.Close()
+	defer sink.Close()
+
+	ds, err := src.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+
+	var commits []types.Ref
+	for _, val := range []string{"one", "two", "three", "four"} {
+		ds, err = src.CommitValue(ctx, ds, types.String(val))
+		require.NoError(t, err)
+		commits = append(commits, mustHeadRef(ds))
+	}
+
+	// a branch from the first commit, whose common ancestor with the head is past the shallow boundary
+	other, err := src.GetDataset(ctx, "other")
+	require.NoError(t, err)
+	other, err = src.SetHead(ctx, other, commits[0])
+	require.NoError(t, err)
+	other, err = src.CommitValue(ctx, other, types.String("five"))
+	require.NoError(t, err)
+	branch := mustHeadRef(other)
+
+	head := commits[3]
+	err = PullShallow(ctx, src, sink, head, 2, nil)
+	require.NoError(t, err)
+	err = PullShallow(ctx, src, sink, branch, 1, nil)
+	require.NoError(t, err)
+
+	ancestor, ok, err := FindCommonAncestor(ctx, head, branch, src)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, commits[0].TargetHash(), ancestor.TargetHash())
+
+	_, ok, err = FindCommonAncestor(ctx, head, branch, sink)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = FindCommonAncestor(ctx, branch, commits[2], sink)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// without the shallow boundary the missing commits are an error rather than the end of the history
+	shallowDS, err := sink.GetDataset(ctx, shallowDatasetID)
+	require.NoError(t, err)
+	_, err = sink.Delete(ctx, shallowDS)
+	require.NoError(t, err)
+
+	_, _, err = FindCommonAncestor(ctx, head, branch, sink)
+	assert.Error(t, err)
+}
+
+func TestMissingShallowCommits(t *testing.T) {
+	ctx := context.Background()
+	srcStorage, shallowStorage, emptyStorage := &chunks.MemoryStorage{}, &chunks.MemoryStorage{}, &chunks.MemoryStorage{}
+	src, shallow, empty := NewDatabase(srcStorage.NewView()), NewDatabase(shallowStorage.NewView()), NewDatabase(emptyStorage.NewView())
+	defer src.Close()
+	defer shallow.Close()
+	defer empty.Close()
+
+	ds, err := src.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+
+	var commits []types.Ref
+	for _, val := range []string{"one", "two", "three"} {
+		ds, err = src.CommitValue(ctx, ds, types.String(val))
+		require.NoError(t, err)
+		commits = append(commits, mustHeadRef(ds))
+	}
+
+	missing, err := MissingShallowCommits(ctx, src, empty)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	err = PullShallow(ctx, src, shallow, commits[2], 1, nil)
+	require.NoError(t, err)
+
+	// the source of the shallow pull has the boundary, and a database with the same boundary is shallow already
+	missing, err = MissingShallowCommits(ctx, shallow, src)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	missing, err = MissingShallowCommits(ctx, shallow, shallow)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	missing, err = MissingShallowCommits(ctx, shallow, empty)
+	require.NoError(t, err)
+	assert.Equal(t, hash.NewHashSet(commits[1].TargetHash()), missing)
+}
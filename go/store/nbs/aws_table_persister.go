@@ -73,8 +73,9 @@ func (al awsLimits) tableFitsInDynamo(name addr, dataLen int, chunkCount uint32)
 	return chunkCount <= al.chunkMax && calcItemSize(name, dataLen) < al.itemMax
 }
 
+// tableMayBeInDynamo is false for every table of stores without DynamoDB, whose limits have no |itemMax|
 func (al awsLimits) tableMayBeInDynamo(chunkCount uint32) bool {
-	return chunkCount <= al.chunkMax
+	return al.itemMax > 0 && chunkCount <= al.chunkMax
 }
 
 func (s3p awsTablePersister) Open(ctx context.Context, name addr, chunkCount uint32, stats *Stats) (chunkSource, error) {
@@ -302,7 +303,7 @@ func (s3p awsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 		return emptyChunkSource{}, nil
 	}
 	t1 := time.Now()
-	name := nameFromSuffixes(plan.suffixes())
+	name := nameFromSuffixes(plan.suffixes(), plan.compression)
 	err = s3p.executeCompactionPlan(ctx, plan, name.String())
 
 	if err != nil {
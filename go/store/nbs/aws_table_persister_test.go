@@ -39,7 +39,7 @@ import (
 
 func TestAWSTablePersisterPersist(t *testing.T) {
 	calcPartSize := func(rdr chunkReader, maxPartNum uint64) uint64 {
-		return maxTableSize(uint64(mustUint32(rdr.count())), mustUint64(rdr.uncompressedLen())) / maxPartNum
+		return maxTableSize(uint64(mustUint32(rdr.count())), mustUint64(rdr.uncompressedLen()), nil) / maxPartNum
 	}
 
 	mt := newMemTable(testMemTableSize)
@@ -548,7 +548,7 @@ func bytesToChunkSource(t *testing.T, bs ...[]byte) chunkSource {
 	for _, b := range bs {
 		sum += len(b)
 	}
-	maxSize := maxTableSize(uint64(len(bs)), uint64(sum))
+	maxSize := maxTableSize(uint64(len(bs)), uint64(sum), nil)
 	buff := make([]byte, maxSize)
 	tw := newTableWriter(buff, nil)
 	for _, b := range bs {
@@ -81,7 +81,10 @@ func (bsm blobstoreManifest) Update(ctx context.Context, lastLock addr, newConte
 	ver, contents, err := manifestVersionAndContents(ctx, bsm.bs)
 
 	if err != nil {
-		return manifestContents{}, err
+		// a store without a manifest is written for the first time with an expected version of ""
+		if !blobstore.IsNotFoundError(err) {
+			return manifestContents{}, err
+		}
 	}
 
 	if contents.lock == lastLock {
@@ -0,0 +1,92 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/blobstore"
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/constants"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+func TestBSStoreUploadedTableFile(t *testing.T) {
+	ctx := context.Background()
+	srcBS, sinkBS := blobstore.NewInMemoryBlobstore(), blobstore.NewInMemoryBlobstore()
+
+	src, err := NewBSStore(ctx, constants.FormatDefaultString, srcBS, testMemTableSize)
+	require.NoError(t, err)
+
+	c1 := chunks.NewChunk([]byte("chunk 1"))
+	c2 := chunks.NewChunk([]byte("chunk 2"))
+	require.NoError(t, src.Put(ctx, c1))
+	require.NoError(t, src.Put(ctx, c2))
+
+	last, err := src.Root(ctx)
+	require.NoError(t, err)
+	ok, err := src.Commit(ctx, c1.Hash(), last)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	locs, err := src.GetChunkLocations(hash.NewHashSet(c1.Hash(), c2.Hash()))
+	require.NoError(t, err)
+	require.Len(t, locs, 1)
+
+	// copy the table file the way a remote client uploads it, then add it to the sink's manifest
+	for name, ranges := range locs {
+		assert.Len(t, ranges, 2)
+
+		data, _, err := blobstore.GetBytes(ctx, srcBS, name.String(), blobstore.AllRange)
+		require.NoError(t, err)
+		_, err = blobstore.PutBytes(ctx, sinkBS, name.String(), data)
+		require.NoError(t, err)
+
+		for _, r := range ranges {
+			chunkData, _, err := blobstore.GetBytes(ctx, sinkBS, name.String(), blobstore.NewBlobRange(int64(r.Offset), int64(r.Length)))
+			require.NoError(t, err)
+			assert.Len(t, chunkData, int(r.Length))
+		}
+
+		sink, err := NewBSStore(ctx, constants.FormatDefaultString, sinkBS, testMemTableSize)
+		require.NoError(t, err)
+
+		_, err = sink.UpdateManifest(ctx, map[hash.Hash]uint32{name: 2})
+		require.NoError(t, err)
+
+		last, err = sink.Root(ctx)
+		require.NoError(t, err)
+		ok, err = sink.Commit(ctx, c1.Hash(), last)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	reopened, err := NewBSStore(ctx, constants.FormatDefaultString, sinkBS, testMemTableSize)
+	require.NoError(t, err)
+
+	root, err := reopened.Root(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, c1.Hash(), root)
+
+	for _, c := range []chunks.Chunk{c1, c2} {
+		read, err := reopened.Get(ctx, c.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, c.Data(), read.Data())
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression identifies the codec used to compress the chunk records of a table. Every record in a table uses the
+// same codec, which is recorded in the table's footer by its magic number.
+type Compression uint8
+
+const (
+	// SnappyCompression is the codec of every table written before the codec was recorded, and the default
+	SnappyCompression Compression = iota
+
+	// ZstdCompression compresses each chunk record as its own zstd frame
+	//
+	// TODO: a zstd dictionary shared by the tables of a store. It would have to be persisted along with the tables
+	// which use it, and sent to the clients of a remote, which decompress the ranges of tables they download.
+	ZstdCompression
+)
+
+const (
+	zstdMagicNumber = "\xff\xb5\xd8\xc2\x24\x63\xee\x5a"
+
+	// every zstd frame begins with these bytes. A snappy block never does, as its first element would be a copy
+	// from before the start of the block.
+	zstdFrameMagic = "\x28\xb5\x2f\xfd"
+
+	// zstd frame headers, block headers and the bound on incompressible data aren't part of ZSTD_COMPRESSBOUND
+	zstdFrameOverhead = 32
+)
+
+var ErrUnknownCompression = errors.New("unknown compression")
+var ErrMixedCompression = errors.New("tables with different compressions can't be conjoined")
+
+func (c Compression) String() string {
+	switch c {
+	case SnappyCompression:
+		return "snappy"
+	case ZstdCompression:
+		return "zstd"
+	}
+
+	return fmt.Sprintf("Compression(%d)", c)
+}
+
+// ParseCompression returns the Compression named |str|
+func ParseCompression(str string) (Compression, error) {
+	switch str {
+	case "snappy":
+		return SnappyCompression, nil
+	case "zstd":
+		return ZstdCompression, nil
+	}
+
+	return 0, ErrUnknownCompression
+}
+
+func (c Compression) magic() string {
+	if c == ZstdCompression {
+		return zstdMagicNumber
+	}
+
+	return magicNumber
+}
+
+// hashCompression adds |c| to the hash of the suffixes of a table, which names it. Tables of the same chunks written
+// with different codecs have different indexes, so they can't share a name. Snappy tables keep the names they had
+// before the codec was recorded.
+func hashCompression(h hash.Hash, c Compression) {
+	if c != SnappyCompression {
+		h.Write([]byte(c.magic()))
+	}
+}
+
+func compressionForMagic(magic []byte) (Compression, bool) {
+	switch string(magic) {
+	case magicNumber:
+		return SnappyCompression, true
+	case zstdMagicNumber:
+		return ZstdCompression, true
+	}
+
+	return 0, false
+}
+
+// decode decompresses a single chunk record, not including its checksum
+func (c Compression) decode(data []byte) ([]byte, error) {
+	if c == ZstdCompression {
+		return decodeZstd(data)
+	}
+
+	return snappy.Decode(nil, data)
+}
+
+// DecompressChunk decompresses a single chunk record, not including its checksum, of a table whose footer isn't
+// available, as is the case for the ranges of tables downloaded from a remote.
+func DecompressChunk(data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, []byte(zstdFrameMagic)) {
+		return ZstdCompression.decode(data)
+	}
+
+	return SnappyCompression.decode(data)
+}
+
+// chunkEncoder compresses chunk records into the buffer of a tableWriter
+type chunkEncoder interface {
+	// Encode compresses |src| into |dst| and returns the compressed bytes. When |dst| is at least
+	// MaxEncodedLen(len(src)) long, the returned slice must begin at the start of |dst|.
+	Encode(dst, src []byte) []byte
+
+	// MaxEncodedLen returns the maximum length of the compressed form of |n| bytes
+	MaxEncodedLen(n int) int
+
+	// Compression returns the codec recorded in the footer of tables written with this encoder
+	Compression() Compression
+}
+
+type snappyEncoder struct{}
+
+func (e snappyEncoder) Encode(dst, src []byte) []byte {
+	return snappy.Encode(dst, src)
+}
+
+func (e snappyEncoder) MaxEncodedLen(n int) int {
+	return snappy.MaxEncodedLen(n)
+}
+
+func (e snappyEncoder) Compression() Compression {
+	return SnappyCompression
+}
+
+type zstdEncoder struct {
+	enc *zstd.Encoder
+}
+
+// newChunkEncoder returns the encoder for |c|
+func newChunkEncoder(c Compression) (chunkEncoder, error) {
+	switch c {
+	case SnappyCompression:
+		return snappyEncoder{}, nil
+
+	case ZstdCompression:
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderCRC(false))
+
+		if err != nil {
+			return nil, err
+		}
+
+		return zstdEncoder{enc}, nil
+	}
+
+	return nil, ErrUnknownCompression
+}
+
+func (e zstdEncoder) Encode(dst, src []byte) []byte {
+	return e.enc.EncodeAll(src, dst[:0])
+}
+
+// MaxEncodedLen is ZSTD_COMPRESSBOUND, with the extra room it allows for inputs smaller than 128KB at its largest so
+// that the bound stays linear, plus room for the frame. maxTableSize relies on the bound being linear.
+func (e zstdEncoder) MaxEncodedLen(n int) int {
+	return n + n>>8 + (128<<10)>>11 + zstdFrameOverhead
+}
+
+func (e zstdEncoder) Compression() Compression {
+	return ZstdCompression
+}
+
+// zstdDecoder is shared by every store of the process. Its DecodeAll is safe for concurrent use.
+var zstdDecoder struct {
+	once sync.Once
+	dec  *zstd.Decoder
+	err  error
+}
+
+func decodeZstd(data []byte) ([]byte, error) {
+	zstdDecoder.once.Do(func() {
+		zstdDecoder.dec, zstdDecoder.err = zstd.NewReader(nil)
+	})
+
+	if zstdDecoder.err != nil {
+		return nil, zstdDecoder.err
+	}
+
+	return zstdDecoder.dec.DecodeAll(data, nil)
+}
@@ -0,0 +1,249 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/blobstore"
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/constants"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+func buildTableWithCompression(t *testing.T, chunks [][]byte, c Compression) []byte {
+	encoder, err := newChunkEncoder(c)
+	require.NoError(t, err)
+
+	totalData := uint64(0)
+	for _, chunk := range chunks {
+		totalData += uint64(len(chunk))
+	}
+
+	buff := make([]byte, maxTableSize(uint64(len(chunks)), totalData, encoder))
+	tw := newTableWriter(buff, encoder)
+
+	for _, chunk := range chunks {
+		tw.addChunk(computeAddr(chunk), chunk)
+	}
+
+	length, _, err := tw.finish()
+	require.NoError(t, err)
+
+	return buff[:length]
+}
+
+func TestTableCompression(t *testing.T) {
+	chunks := [][]byte{
+		[]byte("hello2"),
+		[]byte("goodbye2"),
+		[]byte("badbye2"),
+		make([]byte, 1<<16),
+	}
+
+	for _, c := range []Compression{SnappyCompression, ZstdCompression} {
+		t.Run(c.String(), func(t *testing.T) {
+			tableData := buildTableWithCompression(t, chunks, c)
+			assert.Equal(t, c.magic(), string(tableData[len(tableData)-magicNumberSize:]))
+
+			ti, err := parseTableIndex(tableData)
+			require.NoError(t, err)
+			assert.Equal(t, c, ti.compression)
+
+			tr := newTableReader(ti, tableReaderAtFromBytes(tableData), fileBlockSize)
+			assertChunksInReader(chunks, tr, assert.New(t))
+
+			for _, chunk := range chunks {
+				data, err := tr.get(context.Background(), computeAddr(chunk), &Stats{})
+				require.NoError(t, err)
+				assert.Equal(t, chunk, data)
+			}
+		})
+	}
+
+	// tables written before the compression was recorded are snappy tables
+	tableData, _, err := buildTable(chunks)
+	require.NoError(t, err)
+	ti, err := parseTableIndex(tableData)
+	require.NoError(t, err)
+	assert.Equal(t, SnappyCompression, ti.compression)
+}
+
+func TestDecompressChunk(t *testing.T) {
+	data := []byte("a chunk which is compressed on its own")
+
+	for _, c := range []Compression{SnappyCompression, ZstdCompression} {
+		encoder, err := newChunkEncoder(c)
+		require.NoError(t, err)
+
+		compressed := encoder.Encode(make([]byte, encoder.MaxEncodedLen(len(data))), data)
+		decompressed, err := DecompressChunk(compressed)
+		require.NoError(t, err)
+		assert.Equal(t, data, decompressed)
+	}
+}
+
+func TestNewChunkEncoder(t *testing.T) {
+	_, err := newChunkEncoder(Compression(100))
+	assert.Equal(t, ErrUnknownCompression, err)
+
+	c, err := ParseCompression("zstd")
+	require.NoError(t, err)
+	assert.Equal(t, ZstdCompression, c)
+
+	_, err = ParseCompression("lz4")
+	assert.Equal(t, ErrUnknownCompression, err)
+}
+
+func TestConjoinMixedCompressions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := newFSTablePersister(dir, newFDCache(defaultMaxTables), nil)
+
+	var count byte
+	persist := func(c Compression, numChunks int) chunkSource {
+		encoder, err := newChunkEncoder(c)
+		require.NoError(t, err)
+
+		mt := newMemTable(testMemTableSize)
+		mt.encoder = encoder
+		for i := 0; i < numChunks; i++ {
+			chunk := []byte{count, count, count}
+			count++
+			mt.addChunk(computeAddr(chunk), chunk)
+		}
+
+		src, err := p.Persist(context.Background(), mt, nil, &Stats{})
+		require.NoError(t, err)
+		return src
+	}
+
+	zstd1, snappy1, zstd2, snappy2, zstd3 := persist(ZstdCompression, 1), persist(SnappyCompression, 1), persist(ZstdCompression, 2), persist(SnappyCompression, 2), persist(ZstdCompression, 4)
+
+	_, err = planConjoin(chunkSources{zstd1, snappy1}, &Stats{})
+	assert.Equal(t, ErrMixedCompression, err)
+
+	toConjoin, toKeep, err := chooseConjoinees(chunkSources{zstd1, snappy1, zstd2, snappy2, zstd3})
+	require.NoError(t, err)
+	assert.Len(t, toConjoin, 2)
+	assert.Len(t, toKeep, 3)
+
+	for _, src := range toConjoin {
+		index, err := src.index()
+		require.NoError(t, err)
+		assert.Equal(t, ZstdCompression, index.compression)
+	}
+
+	conjoined, err := p.ConjoinAll(context.Background(), toConjoin, &Stats{})
+	require.NoError(t, err)
+
+	index, err := conjoined.index()
+	require.NoError(t, err)
+	assert.Equal(t, ZstdCompression, index.compression)
+	assert.Equal(t, uint32(3), mustUint32(conjoined.count()))
+
+	rdrs := append(chunkReaderGroup{}, toConjoin[0], toConjoin[1])
+	chunkChan := make(chan extractRecord, mustUint32(rdrs.count()))
+	require.NoError(t, rdrs.extract(context.Background(), chunkChan))
+	close(chunkChan)
+
+	for rec := range chunkChan {
+		data, err := conjoined.get(context.Background(), rec.a, &Stats{})
+		require.NoError(t, err)
+		assert.Equal(t, rec.data, data)
+	}
+}
+
+func TestStoreSetCompression(t *testing.T) {
+	ctx := context.Background()
+	bs := blobstore.NewInMemoryBlobstore()
+
+	store, err := NewBSStore(ctx, constants.FormatDefaultString, bs, testMemTableSize)
+	require.NoError(t, err)
+	require.NoError(t, store.SetCompression(ZstdCompression))
+
+	c1 := chunks.NewChunk([]byte("chunk 1"))
+	c2 := chunks.NewChunk([]byte("chunk 2"))
+	require.NoError(t, store.Put(ctx, c1))
+	require.NoError(t, store.Put(ctx, c2))
+
+	last, err := store.Root(ctx)
+	require.NoError(t, err)
+	ok, err := store.Commit(ctx, c1.Hash(), last)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	locs, err := store.GetChunkLocations(hash.NewHashSet(c1.Hash(), c2.Hash()))
+	require.NoError(t, err)
+	require.Len(t, locs, 1)
+
+	// the table records its compression, and its ranges can be decompressed the way a remote client does
+	for name, ranges := range locs {
+		data, _, err := blobstore.GetBytes(ctx, bs, name.String(), blobstore.AllRange)
+		require.NoError(t, err)
+		assert.Equal(t, zstdMagicNumber, string(data[len(data)-magicNumberSize:]))
+
+		for h, r := range ranges {
+			chunkData, err := DecompressChunk(data[r.Offset : r.Offset+uint64(r.Length)-checksumSize])
+			require.NoError(t, err)
+			assert.Equal(t, h, chunks.NewChunk(chunkData).Hash())
+		}
+	}
+
+	// a store which writes snappy tables still reads the zstd table
+	reopened, err := NewBSStore(ctx, constants.FormatDefaultString, bs, testMemTableSize)
+	require.NoError(t, err)
+
+	for _, c := range []chunks.Chunk{c1, c2} {
+		read, err := reopened.Get(ctx, c.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, c.Data(), read.Data())
+	}
+}
+
+// TestTableNameCompression checks that tables of the same chunks written with different codecs have different names,
+// as their indexes are cached by name.
+func TestTableNameCompression(t *testing.T) {
+	chunk := []byte("chunk")
+
+	names := map[Compression]addr{}
+	for _, c := range []Compression{SnappyCompression, ZstdCompression} {
+		encoder, err := newChunkEncoder(c)
+		require.NoError(t, err)
+
+		mt := newMemTable(testMemTableSize)
+		mt.encoder = encoder
+		mt.addChunk(computeAddr(chunk), chunk)
+
+		name, data, _, err := mt.write(nil, &Stats{})
+		require.NoError(t, err)
+
+		index, err := parseTableIndex(data)
+		require.NoError(t, err)
+		assert.Equal(t, name, nameFromSuffixes(index.suffixes, c))
+
+		names[c] = name
+	}
+
+	assert.NotEqual(t, names[SnappyCompression], names[ZstdCompression])
+}
@@ -194,8 +194,11 @@ func conjoinTables(ctx context.Context, p tablePersister, upstream []tableSpec,
 
 // Current approach is to choose the smallest N tables which, when removed and replaced with the conjoinment, will leave the conjoinment as the smallest table.
 func chooseConjoinees(upstream chunkSources) (toConjoin, toKeep chunkSources, err error) {
-	sortedUpstream := make(chunkSources, len(upstream))
-	copy(sortedUpstream, upstream)
+	sortedUpstream, otherCompressions, err := partitionByCompression(upstream)
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	csbac := chunkSourcesByAscendingCount{sortedUpstream, nil}
 	sort.Sort(csbac)
@@ -233,7 +236,42 @@ func chooseConjoinees(upstream chunkSources) (toConjoin, toKeep chunkSources, er
 		partition++
 	}
 
-	return sortedUpstream[:partition], sortedUpstream[partition:], nil
+	toKeep = append(sortedUpstream[partition:len(sortedUpstream):len(sortedUpstream)], otherCompressions...)
+	return sortedUpstream[:partition], toKeep, nil
+}
+
+// partitionByCompression splits |upstream| into the tables with the compression most of them share, and all the
+// others. Tables can only be conjoined with tables that have the same compression.
+func partitionByCompression(upstream chunkSources) (common, others chunkSources, err error) {
+	compressions := make([]Compression, len(upstream))
+	counts := make(map[Compression]int)
+	for i, src := range upstream {
+		index, err := src.index()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		compressions[i] = index.compression
+		counts[index.compression]++
+	}
+
+	most := SnappyCompression
+	for c, cnt := range counts {
+		if cnt > counts[most] || (cnt == counts[most] && c < most) {
+			most = c
+		}
+	}
+
+	for i, src := range upstream {
+		if compressions[i] == most {
+			common = append(common, src)
+		} else {
+			others = append(others, src)
+		}
+	}
+
+	return common, others, nil
 }
 
 func toSpecs(srcs chunkSources) ([]tableSpec, error) {
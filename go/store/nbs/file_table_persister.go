@@ -139,7 +139,7 @@ func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 		return emptyChunkSource{}, nil
 	}
 
-	name := nameFromSuffixes(plan.suffixes())
+	name := nameFromSuffixes(plan.suffixes(), plan.compression)
 	tempName, err := func() (tempName string, ferr error) {
 		var temp *os.File
 		temp, ferr = ioutil.TempFile(ftp.dir, tempTablePrefix)
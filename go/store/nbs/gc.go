@@ -0,0 +1,268 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+// ErrGCStoreChanged is returned by CollectGarbage when the store is written to while garbage is being collected, so that
+// its manifest isn't the one that the chunks to keep were found from.
+var ErrGCStoreChanged = errors.New("the store changed during garbage collection")
+
+// ErrGCUncommittedChunks is returned by CollectGarbage when chunks have been put to the store but not committed
+var ErrGCUncommittedChunks = errors.New("can't collect garbage while the store has uncommitted chunks")
+
+// tableRemover is implemented by tablePersisters that can remove the tables they persisted
+type tableRemover interface {
+	// removeTables removes the tables |names|, which are no longer in the manifest. Tables in |live|, which are in the
+	// manifest, must be kept.
+	removeTables(ctx context.Context, names []addr, live []tableSpec) error
+}
+
+// GCRoot returns the root of the store and the lock of its manifest. Unlike the root, the lock changes whenever tables
+// are added to the manifest, including by commits that don't move the root.
+func (nbs *NomsBlockStore) GCRoot(ctx context.Context) (root hash.Hash, lock hash.Hash, err error) {
+	nbs.mu.RLock()
+	defer nbs.mu.RUnlock()
+	return nbs.upstream.root, hash.Hash(nbs.upstream.lock), nil
+}
+
+// CollectGarbage rewrites the tables of the store so that they hold only the chunks in |keepers|, which must include
+// every chunk reachable from the root the store had when its manifest had |lock|. Returns ErrGCStoreChanged if the
+// manifest no longer has |lock|, as chunks committed since then may be missing from |keepers|. The new tables are
+// persisted before the manifest is swapped to them, so the store is left as it was if the collection fails or the
+// manifest changes in the meantime. The tables that are no longer in the manifest are removed afterwards, if the
+// persister can remove them.
+func (nbs *NomsBlockStore) CollectGarbage(ctx context.Context, lock hash.Hash, keepers hash.HashSet) (err error) {
+	nbs.mm.LockForUpdate()
+	defer func() {
+		unlockErr := nbs.mm.UnlockForUpdate()
+
+		if err == nil {
+			err = unlockErr
+		}
+	}()
+
+	nbs.mu.Lock()
+	defer nbs.mu.Unlock()
+
+	if nbs.mt != nil {
+		if cnt, err := nbs.mt.count(); err != nil {
+			return err
+		} else if cnt > 0 {
+			return ErrGCUncommittedChunks
+		}
+	}
+
+	if nbs.tables.Novel() > 0 {
+		return ErrGCUncommittedChunks
+	}
+
+	exists, contents, err := nbs.mm.Fetch(ctx, nbs.stats)
+
+	if err != nil {
+		return err
+	} else if !exists {
+		return nil
+	} else if contents.lock != addr(lock) {
+		return ErrGCStoreChanged
+	}
+
+	nbs.upstream = contents
+	nbs.tables, err = nbs.tables.Rebase(ctx, contents.specs, nbs.stats)
+
+	if err != nil {
+		return err
+	}
+
+	specs, err := nbs.persistKeepers(ctx, keepers)
+
+	if err != nil {
+		return err
+	}
+
+	newContents := manifestContents{
+		vers:  contents.vers,
+		root:  contents.root,
+		lock:  generateLockHash(contents.root, specs),
+		specs: specs,
+	}
+
+	upstream, err := nbs.mm.Update(ctx, contents.lock, newContents, nbs.stats, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if upstream.lock != newContents.lock {
+		nbs.upstream = upstream
+		nbs.tables, err = nbs.tables.Rebase(ctx, upstream.specs, nbs.stats)
+
+		if err != nil {
+			return err
+		}
+
+		return ErrGCStoreChanged
+	}
+
+	nbs.upstream = newContents
+	nbs.tables, err = nbs.tables.Rebase(ctx, newContents.specs, nbs.stats)
+
+	if err != nil {
+		return err
+	}
+
+	if remover, ok := nbs.p.(tableRemover); ok {
+		return remover.removeTables(ctx, removedTables(contents.specs, specs), specs)
+	}
+
+	return nil
+}
+
+// persistKeepers copies the chunks in |keepers| from the tables of the store to new tables, and returns their specs
+func (nbs *NomsBlockStore) persistKeepers(ctx context.Context, keepers hash.HashSet) ([]tableSpec, error) {
+	sorted := make(hash.HashSlice, 0, len(keepers))
+	for h := range keepers {
+		sorted = append(sorted, h)
+	}
+
+	sort.Sort(sorted)
+
+	var specs []tableSpec
+	persist := func(mt *memTable) error {
+		src, err := nbs.p.Persist(ctx, mt, nil, nbs.stats)
+
+		if err != nil {
+			return err
+		}
+
+		cnt, err := src.count()
+
+		if err != nil || cnt == 0 {
+			return err
+		}
+
+		name, err := src.hash()
+
+		if err != nil {
+			return err
+		}
+
+		specs = append(specs, tableSpec{name, cnt})
+		return nil
+	}
+
+	mt := nbs.newMemTable()
+	for _, h := range sorted {
+		a := addr(h)
+		data, err := nbs.tables.get(ctx, a, nbs.stats)
+
+		if err != nil {
+			return nil, err
+		} else if data == nil {
+			return nil, fmt.Errorf("chunk %s can't be kept because it isn't in the store", h.String())
+		}
+
+		if !mt.addChunk(a, data) {
+			if err := persist(mt); err != nil {
+				return nil, err
+			}
+
+			mt = nbs.newMemTable()
+			if !mt.addChunk(a, data) {
+				return nil, fmt.Errorf("chunk %s is larger than the memtable size of the store", h.String())
+			}
+		}
+	}
+
+	if err := persist(mt); err != nil {
+		return nil, err
+	}
+
+	return specs, nil
+}
+
+// removedTables returns the names of the tables of |oldSpecs| that aren't in |newSpecs|
+func removedTables(oldSpecs, newSpecs []tableSpec) []addr {
+	kept := make(map[addr]bool, len(newSpecs))
+	for _, spec := range newSpecs {
+		kept[spec.name] = true
+	}
+
+	var removed []addr
+	for _, spec := range oldSpecs {
+		if !kept[spec.name] {
+			removed = append(removed, spec.name)
+		}
+	}
+
+	return removed
+}
+
+// gcPendingFileName is the file of a local store that lists the tables removed from its manifest by the last garbage
+// collection
+const gcPendingFileName = "gc_pending"
+
+// removeTables deletes the tables removed from the manifest by the previous garbage collection, and records |names| to
+// be deleted by the next one. The tables aren't deleted straight away because processes that read the manifest before
+// the collection, like a running sql-server, open them by name until they rebase.
+func (ftp *fsTablePersister) removeTables(ctx context.Context, names []addr, live []tableSpec) error {
+	pendingPath := filepath.Join(ftp.dir, gcPendingFileName)
+	pending, err := ioutil.ReadFile(pendingPath)
+
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	isLive := make(map[addr]bool, len(live))
+	for _, spec := range live {
+		isLive[spec.name] = true
+	}
+
+	for _, line := range strings.Fields(string(pending)) {
+		name, err := parseAddr([]byte(line))
+
+		if err != nil {
+			return err
+		} else if isLive[name] {
+			continue
+		}
+
+		err = os.Remove(filepath.Join(ftp.dir, name.String()))
+
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		buf.WriteString(name.String())
+		buf.WriteByte('\n')
+	}
+
+	return ioutil.WriteFile(pendingPath, buf.Bytes(), 0666)
+}
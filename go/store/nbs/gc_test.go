@@ -0,0 +1,171 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/constants"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+// tableFileCount returns the number of table files in the directory of a local store
+func tableFileCount(t *testing.T, dir string) int {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+
+	cnt := 0
+	for _, info := range infos {
+		if info.Name() != manifestFileName && info.Name() != lockFileName && info.Name() != gcPendingFileName {
+			cnt++
+		}
+	}
+
+	return cnt
+}
+
+func TestCollectGarbage(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewLocalStore(ctx, constants.FormatDefaultString, dir, testMemTableSize)
+	require.NoError(t, err)
+
+	var live, garbage []chunks.Chunk
+	for i, data := range []string{"live 1", "garbage 1", "live 2", "garbage 2", "live 3"} {
+		c := chunks.NewChunk([]byte(data))
+		require.NoError(t, store.Put(ctx, c))
+
+		if i%2 == 0 {
+			live = append(live, c)
+		} else {
+			garbage = append(garbage, c)
+		}
+
+		// commit each chunk, so that the store has a table for each of them
+		last, err := store.Root(ctx)
+		require.NoError(t, err)
+		ok, err := store.Commit(ctx, live[0].Hash(), last)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	assert.Equal(t, 5, tableFileCount(t, dir))
+
+	keepers := hash.HashSet{}
+	for _, c := range live {
+		keepers.Insert(c.Hash())
+	}
+
+	// a store opened before the collection reads the old tables until it rebases
+	before, err := NewLocalStore(ctx, constants.FormatDefaultString, dir, testMemTableSize)
+	require.NoError(t, err)
+
+	_, lock, err := store.GCRoot(ctx)
+	require.NoError(t, err)
+	require.NoError(t, store.CollectGarbage(ctx, lock, keepers))
+	assert.Equal(t, 6, tableFileCount(t, dir))
+
+	// the old tables are opened by name once their file descriptors are closed
+	globalFDCache.Drop()
+	for _, c := range append(live, garbage...) {
+		actual, err := before.Get(ctx, c.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, c.Data(), actual.Data())
+	}
+
+	// the next collection removes the tables the previous one left behind
+	_, lock, err = store.GCRoot(ctx)
+	require.NoError(t, err)
+	require.NoError(t, store.CollectGarbage(ctx, lock, keepers))
+	assert.Equal(t, 1, tableFileCount(t, dir))
+
+	reopened, err := NewLocalStore(ctx, constants.FormatDefaultString, dir, testMemTableSize)
+	require.NoError(t, err)
+
+	for _, store := range []*NomsBlockStore{store, reopened} {
+		root, err := store.Root(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, live[0].Hash(), root)
+
+		for _, c := range live {
+			actual, err := store.Get(ctx, c.Hash())
+			require.NoError(t, err)
+			assert.Equal(t, c.Data(), actual.Data())
+		}
+
+		for _, c := range garbage {
+			has, err := store.Has(ctx, c.Hash())
+			require.NoError(t, err)
+			assert.False(t, has)
+		}
+	}
+
+	c := chunks.NewChunk([]byte("uncommitted"))
+	require.NoError(t, store.Put(ctx, c))
+	_, lock, err = store.GCRoot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ErrGCUncommittedChunks, store.CollectGarbage(ctx, lock, keepers))
+}
+
+// TestCollectGarbageStoreChanged checks that a collection fails if chunks are committed after the chunks to keep are
+// found, even when the commit doesn't move the root.
+func TestCollectGarbageStoreChanged(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewLocalStore(ctx, constants.FormatDefaultString, dir, testMemTableSize)
+	require.NoError(t, err)
+
+	root := chunks.NewChunk([]byte("root"))
+	require.NoError(t, store.Put(ctx, root))
+	ok, err := store.Commit(ctx, root.Hash(), hash.Hash{})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, lock, err := store.GCRoot(ctx)
+	require.NoError(t, err)
+
+	// another writer persists a chunk without moving the root, as writing a value does before a ref points to it
+	writer, err := NewLocalStore(ctx, constants.FormatDefaultString, dir, testMemTableSize)
+	require.NoError(t, err)
+
+	written := chunks.NewChunk([]byte("written"))
+	require.NoError(t, writer.Put(ctx, written))
+	ok, err = writer.Commit(ctx, root.Hash(), root.Hash())
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	err = store.CollectGarbage(ctx, lock, hash.HashSet{root.Hash(): struct{}{}})
+	assert.Equal(t, ErrGCStoreChanged, err)
+
+	reopened, err := NewLocalStore(ctx, constants.FormatDefaultString, dir, testMemTableSize)
+	require.NoError(t, err)
+
+	has, err := reopened.Has(ctx, written.Hash())
+	require.NoError(t, err)
+	assert.True(t, has)
+}
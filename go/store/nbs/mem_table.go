@@ -66,7 +66,7 @@ type memTable struct {
 	order              []hasRecord // Must maintain the invariant that these are sorted by rec.order
 	maxData, totalData uint64
 
-	snapper snappyEncoder
+	encoder chunkEncoder
 }
 
 func newMemTable(memTableSize uint64) *memTable {
@@ -158,9 +158,9 @@ func (mt *memTable) extract(ctx context.Context, chunks chan<- extractRecord) er
 }
 
 func (mt *memTable) write(haver chunkReader, stats *Stats) (name addr, data []byte, count uint32, err error) {
-	maxSize := maxTableSize(uint64(len(mt.order)), mt.totalData)
+	maxSize := maxTableSize(uint64(len(mt.order)), mt.totalData, mt.encoder)
 	buff := make([]byte, maxSize)
-	tw := newTableWriter(buff, mt.snapper)
+	tw := newTableWriter(buff, mt.encoder)
 
 	if haver != nil {
 		sort.Sort(hasRecordByPrefix(mt.order)) // hasMany() requires addresses to be sorted.
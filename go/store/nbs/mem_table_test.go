@@ -192,12 +192,13 @@ func TestMemTableSnappyWriteOutOfLine(t *testing.T) {
 	for _, c := range chunks {
 		assert.True(mt.addChunk(computeAddr(c), c))
 	}
-	mt.snapper = &outOfLineSnappy{[]bool{false, true, false}} // chunks[1] should trigger a panic
+	mt.encoder = &outOfLineSnappy{policy: []bool{false, true, false}} // chunks[1] should trigger a panic
 
 	assert.Panics(func() { mt.write(nil, &Stats{}) })
 }
 
 type outOfLineSnappy struct {
+	snappyEncoder
 	policy []bool
 }
 
@@ -485,7 +485,7 @@ func compactSourcesToBuffer(sources chunkSources) (name addr, data []byte, chunk
 		return
 	}
 
-	maxSize := maxTableSize(uint64(chunkCount), totalData)
+	maxSize := maxTableSize(uint64(chunkCount), totalData, nil)
 	buff := make([]byte, maxSize) // This can blow up RAM
 	tw := newTableWriter(buff, nil)
 	errString := ""
@@ -25,6 +25,7 @@ import (
 	"bytes"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
@@ -66,6 +67,9 @@ type fakeS3 struct {
 	inProgress        map[string]fakeS3Multipart // Key -> {UploadId, Etags...}
 	parts             map[string][]byte          // ETag -> data
 	getCount          int
+
+	// ignoreConditions makes PutObject ignore If-Match and If-None-Match, like object stores without conditional writes
+	ignoreConditions bool
 }
 
 type fakeS3Multipart struct {
@@ -235,9 +239,14 @@ func (m *fakeS3) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput,
 	return &s3.GetObjectOutput{
 		Body:          ioutil.NopCloser(bytes.NewReader(obj)),
 		ContentLength: aws.Int64(int64(len(obj))),
+		ETag:          aws.String(fakeETag(m.data[*input.Key])),
 	}, nil
 }
 
+func fakeETag(data []byte) string {
+	return `"` + hash.Of(data).String() + `"`
+}
+
 func parseRange(hdr string, total int) (start, end int) {
 	d.PanicIfFalse(len(hdr) > len(s3RangePrefix))
 	hdr = hdr[len(s3RangePrefix):]
@@ -267,7 +276,22 @@ func (m *fakeS3) PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput,
 	m.assert.NoError(err)
 	m.mu.Lock()
 	defer m.mu.Unlock()
+
+	// conditional writes are requested with headers set by request options
+	req := &request.Request{HTTPRequest: &http.Request{Header: http.Header{}}}
+	req.ApplyOptions(opts...)
+	existing, present := m.data[*input.Key]
+	if m.ignoreConditions {
+		req.HTTPRequest.Header = http.Header{}
+	}
+	if ifMatch := req.HTTPRequest.Header.Get("If-Match"); ifMatch != "" && (!present || ifMatch != fakeETag(existing)) {
+		return nil, mockAWSError("PreconditionFailed")
+	}
+	if req.HTTPRequest.Header.Get("If-None-Match") == "*" && present {
+		return nil, mockAWSError("PreconditionFailed")
+	}
+
 	m.data[*input.Key] = buff.Bytes()
 
-	return &s3.PutObjectOutput{}, nil
+	return &s3.PutObjectOutput{ETag: aws.String(fakeETag(buff.Bytes()))}, nil
 }
@@ -0,0 +1,213 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/juju/fslock"
+)
+
+// conditionalWriteCheckFile is the object written to check that the object store supports conditional writes
+const conditionalWriteCheckFile = "conditional-write-check"
+
+// ErrS3ConditionalWritesUnsupported is returned when opening a store whose manifest is updated with S3 conditional
+// writes, if the object store ignores them. Concurrent writers could then overwrite each other's updates.
+var ErrS3ConditionalWritesUnsupported = errors.New("the object store doesn't support conditional writes (If-Match and If-None-Match on PutObject), use a lock file instead")
+
+// s3Manifest stores a NomsBlockStore manifest as an object in S3, for S3-compatible object stores which don't come
+// with DynamoDB. Updates are made atomic with S3 conditional writes, or, for object stores which don't support them,
+// by holding a lock on the local file |lockFile|, which every process writing to the store must share.
+type s3Manifest struct {
+	s3       s3svc
+	bucket   string
+	key      string
+	lockFile string
+}
+
+func newS3Manifest(s3 s3svc, bucket, namespace, lockFile string) s3Manifest {
+	key := manifestFile
+	if namespace != "" {
+		key = namespace + "/" + manifestFile
+	}
+
+	return s3Manifest{s3, bucket, key, lockFile}
+}
+
+// checkConditionalWrites returns ErrS3ConditionalWritesUnsupported if the object store doesn't fail writes whose
+// If-Match or If-None-Match preconditions aren't met. An object is written next to the manifest, and then written again
+// with each precondition it doesn't meet.
+func (s3m s3Manifest) checkConditionalWrites(ctx context.Context) error {
+	key := conditionalWriteCheckFile
+	if i := strings.LastIndex(s3m.key, "/"); i >= 0 {
+		key = s3m.key[:i+1] + conditionalWriteCheckFile
+	}
+
+	put := func(opts ...request.Option) error {
+		_, err := s3m.s3.PutObjectWithContext(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(s3m.bucket),
+			Key:    aws.String(key),
+			Body:   bytes.NewReader([]byte(conditionalWriteCheckFile)),
+		}, opts...)
+		return err
+	}
+
+	err := put()
+
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range []request.Option{withHeader("If-None-Match", "*"), withHeader("If-Match", `"`+conditionalWriteCheckFile+`"`)} {
+		err = put(opt)
+
+		if err == nil {
+			return ErrS3ConditionalWritesUnsupported
+		} else if !errIsS3Code(err, "PreconditionFailed") && !errIsS3Code(err, "ConditionalRequestConflict") {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s3m s3Manifest) Name() string {
+	return s3m.bucket + "/" + s3m.key
+}
+
+func (s3m s3Manifest) ParseIfExists(ctx context.Context, stats *Stats, readHook func() error) (bool, manifestContents, error) {
+	t1 := time.Now()
+	defer func() { stats.ReadManifestLatency.SampleTimeSince(t1) }()
+
+	exists, _, contents, err := s3m.fetch(ctx)
+	return exists, contents, err
+}
+
+// fetch reads the manifest and the ETag of the object holding it
+func (s3m s3Manifest) fetch(ctx context.Context) (bool, string, manifestContents, error) {
+	result, err := s3m.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s3m.bucket),
+		Key:    aws.String(s3m.key),
+	})
+
+	if err != nil {
+		if errIsS3Code(err, s3.ErrCodeNoSuchKey) {
+			return false, "", manifestContents{}, nil
+		}
+
+		return false, "", manifestContents{}, err
+	}
+
+	defer result.Body.Close()
+	contents, err := parseManifest(result.Body)
+
+	if err != nil {
+		return false, "", manifestContents{}, err
+	}
+
+	return true, aws.StringValue(result.ETag), contents, nil
+}
+
+func (s3m s3Manifest) Update(ctx context.Context, lastLock addr, newContents manifestContents, stats *Stats, writeHook func() error) (manifestContents, error) {
+	t1 := time.Now()
+	defer func() { stats.WriteManifestLatency.SampleTimeSince(t1) }()
+
+	if s3m.lockFile != "" {
+		lck := fslock.New(s3m.lockFile)
+		err := lck.Lock()
+
+		if err != nil {
+			return manifestContents{}, err
+		}
+
+		defer lck.Unlock()
+	}
+
+	exists, etag, upstream, err := s3m.fetch(ctx)
+
+	if err != nil {
+		return manifestContents{}, err
+	}
+
+	if upstream.lock != lastLock {
+		return upstream, nil
+	}
+
+	if writeHook != nil {
+		err = writeHook()
+
+		if err != nil {
+			return manifestContents{}, err
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	err = writeManifest(buffer, newContents)
+
+	if err != nil {
+		return manifestContents{}, err
+	}
+
+	// with a lock file, no other writer can have changed the manifest since it was read
+	var opts []request.Option
+	if s3m.lockFile == "" {
+		if exists {
+			opts = append(opts, withHeader("If-Match", etag))
+		} else {
+			opts = append(opts, withHeader("If-None-Match", "*"))
+		}
+	}
+
+	_, err = s3m.s3.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s3m.bucket),
+		Key:    aws.String(s3m.key),
+		Body:   bytes.NewReader(buffer.Bytes()),
+	}, opts...)
+
+	if err != nil {
+		if errIsS3Code(err, "PreconditionFailed") || errIsS3Code(err, "ConditionalRequestConflict") {
+			_, _, upstream, err = s3m.fetch(ctx)
+
+			if err != nil {
+				return manifestContents{}, err
+			}
+
+			return upstream, nil
+		}
+
+		return manifestContents{}, err
+	}
+
+	return newContents, nil
+}
+
+func withHeader(name, value string) request.Option {
+	return func(r *request.Request) {
+		r.HTTPRequest.Header.Set(name, value)
+	}
+}
+
+func errIsS3Code(err error, code string) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == code
+}
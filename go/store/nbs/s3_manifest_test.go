@@ -0,0 +1,177 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/juju/fslock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/constants"
+)
+
+// Simulate another process writing the manifest object in |s3svc|
+func putS3Manifest(t *testing.T, s3svc *fakeS3, contents manifestContents) {
+	buff := &bytes.Buffer{}
+	require.NoError(t, writeManifest(buff, contents))
+
+	s3svc.mu.Lock()
+	defer s3svc.mu.Unlock()
+	s3svc.data[db+"/"+manifestFile] = buff.Bytes()
+}
+
+func TestS3ManifestParseIfExists(t *testing.T) {
+	s3svc := makeFakeS3(t)
+	mm := newS3Manifest(s3svc, "bucket", db, "")
+	stats := &Stats{}
+
+	exists, _, err := mm.ParseIfExists(context.Background(), stats, nil)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	contents := makeContents("locker", "new root", []tableSpec{{computeAddr([]byte("table1")), 3}})
+	putS3Manifest(t, s3svc, contents)
+
+	exists, upstream, err := mm.ParseIfExists(context.Background(), stats, nil)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, contents, upstream)
+}
+
+func TestS3ManifestUpdate(t *testing.T) {
+	s3svc := makeFakeS3(t)
+	mm := newS3Manifest(s3svc, "bucket", db, "")
+	stats := &Stats{}
+
+	contents := makeContents("locker", "nuroot", []tableSpec{{computeAddr([]byte("a")), 3}})
+	upstream, err := mm.Update(context.Background(), addr{}, contents, stats, nil)
+	require.NoError(t, err)
+	assert.Equal(t, contents, upstream)
+
+	// a stale lock returns the manifest without writing
+	rejected := makeContents("locker 2", "new root 2", []tableSpec{{computeAddr([]byte("b")), 2}})
+	upstream, err = mm.Update(context.Background(), addr{}, rejected, stats, nil)
+	require.NoError(t, err)
+	assert.Equal(t, contents, upstream)
+
+	// another process writing between the read and the write of the manifest fails the conditional write
+	jerk := makeContents("jerk", "jerk root", []tableSpec{{computeAddr([]byte("table1")), 1}})
+	upstream, err = mm.Update(context.Background(), contents.lock, rejected, stats, func() error {
+		putS3Manifest(t, s3svc, jerk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, jerk, upstream)
+
+	upstream, err = mm.Update(context.Background(), jerk.lock, rejected, stats, nil)
+	require.NoError(t, err)
+	assert.Equal(t, rejected, upstream)
+
+	_, upstream, err = mm.ParseIfExists(context.Background(), stats, nil)
+	require.NoError(t, err)
+	assert.Equal(t, rejected, upstream)
+}
+
+func TestS3ManifestUpdateCreateRace(t *testing.T) {
+	s3svc := makeFakeS3(t)
+	mm := newS3Manifest(s3svc, "bucket", db, "")
+
+	// another process creating the manifest first fails the conditional create
+	jerk := makeContents("jerk", "jerk root", []tableSpec{{computeAddr([]byte("table1")), 1}})
+	contents := makeContents("locker", "nuroot", []tableSpec{{computeAddr([]byte("a")), 3}})
+	upstream, err := mm.Update(context.Background(), addr{}, contents, &Stats{}, func() error {
+		putS3Manifest(t, s3svc, jerk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, jerk, upstream)
+}
+
+func TestS3ManifestLockFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	lockFile := filepath.Join(dir, "LOCK")
+	s3svc := makeFakeS3(t)
+	mm := newS3Manifest(s3svc, "bucket", db, lockFile)
+
+	contents := makeContents("locker", "nuroot", []tableSpec{{computeAddr([]byte("a")), 3}})
+	upstream, err := mm.Update(context.Background(), addr{}, contents, &Stats{}, func() error {
+		// the lock is held while the manifest is updated
+		assert.Equal(t, fslock.ErrLocked, fslock.New(lockFile).TryLock())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, contents, upstream)
+
+	lck := fslock.New(lockFile)
+	require.NoError(t, lck.TryLock())
+	require.NoError(t, lck.Unlock())
+}
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+	s3svc := makeFakeS3(t)
+
+	store, err := NewS3Store(ctx, constants.FormatDefaultString, db, "bucket", s3svc, "", testMemTableSize)
+	require.NoError(t, err)
+
+	c := chunks.NewChunk([]byte("a chunk"))
+	require.NoError(t, store.Put(ctx, c))
+
+	last, err := store.Root(ctx)
+	require.NoError(t, err)
+	ok, err := store.Commit(ctx, c.Hash(), last)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// small tables are written to S3 as there's no DynamoDB to put them in
+	reopened, err := NewS3Store(ctx, constants.FormatDefaultString, db, "bucket", s3svc, "", testMemTableSize)
+	require.NoError(t, err)
+
+	root, err := reopened.Root(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, c.Hash(), root)
+
+	read, err := reopened.Get(ctx, c.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, c.Data(), read.Data())
+}
+
+func TestS3StoreRequiresConditionalWrites(t *testing.T) {
+	ctx := context.Background()
+	s3svc := makeFakeS3(t)
+	s3svc.ignoreConditions = true
+
+	_, err := NewS3Store(ctx, constants.FormatDefaultString, db, "bucket", s3svc, "", testMemTableSize)
+	assert.Equal(t, ErrS3ConditionalWritesUnsupported, err)
+
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// a lock file doesn't need conditional writes
+	_, err = NewS3Store(ctx, constants.FormatDefaultString, db, "bucket", s3svc, filepath.Join(dir, "LOCK"), testMemTableSize)
+	assert.NoError(t, err)
+}
@@ -86,6 +86,7 @@ type NomsBlockStore struct {
 
 	mtSize   uint64
 	putCount uint64
+	encoder  chunkEncoder
 
 	stats *Stats
 }
@@ -101,6 +102,17 @@ func (nbs *NomsBlockStore) GetChunkLocations(hashes hash.HashSet) (map[hash.Hash
 	ranges := make(map[hash.Hash]map[hash.Hash]Range)
 	f := func(css chunkSources) error {
 		for _, cs := range css {
+			// the tables committed by this store are read once they've been persisted
+			if pcs, ok := cs.(*persistingChunkSource); ok {
+				err := pcs.wait()
+
+				if err != nil {
+					return err
+				}
+
+				cs = pcs.cs
+			}
+
 			switch tr := cs.(type) {
 			case *mmapTableReader:
 				offsetRecSlice, _ := tr.findOffsets(gr)
@@ -252,14 +264,50 @@ func NewAWSStore(ctx context.Context, nbfVerStr string, table, ns, bucket string
 	return newNomsBlockStore(ctx, nbfVerStr, mm, p, inlineConjoiner{defaultMaxTables}, memTableSize)
 }
 
-// NewGCSStore returns an nbs implementation backed by a GCSBlobstore
-func NewGCSStore(ctx context.Context, nbfVerStr string, bucketName, path string, gcs *storage.Client, memTableSize uint64) (*NomsBlockStore, error) {
+// NewS3Store returns an nbs implementation backed by S3 alone, for S3-compatible object stores which don't come with
+// DynamoDB. Every table is written to S3, and the manifest is an object in |bucket| which is updated with S3
+// conditional writes or, when |lockFile| isn't "", while holding a lock on that local file. Without a lock file, the
+// object store must support conditional writes, and ErrS3ConditionalWritesUnsupported is returned if it doesn't.
+func NewS3Store(ctx context.Context, nbfVerStr string, ns, bucket string, s3 s3svc, lockFile string, memTableSize uint64) (*NomsBlockStore, error) {
 	cacheOnce.Do(makeGlobalCaches)
+	readRateLimiter := make(chan struct{}, 32)
+	p := &awsTablePersister{
+		s3,
+		bucket,
+		readRateLimiter,
+		nil,
+		nil,
+		awsLimits{defaultS3PartSize, minS3PartSize, maxS3PartSize, 0, 0},
+		globalIndexCache,
+		ns,
+	}
+	s3m := newS3Manifest(s3, bucket, ns, lockFile)
+
+	if lockFile == "" {
+		err := s3m.checkConditionalWrites(ctx)
 
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mm := makeManifestManager(s3m)
+	return newNomsBlockStore(ctx, nbfVerStr, mm, p, inlineConjoiner{defaultMaxTables}, memTableSize)
+}
+
+// NewGCSStore returns an nbs implementation backed by a GCSBlobstore
+func NewGCSStore(ctx context.Context, nbfVerStr string, bucketName, path string, gcs *storage.Client, memTableSize uint64) (*NomsBlockStore, error) {
 	bucket := gcs.Bucket(bucketName)
 	bs := blobstore.NewGCSBlobstore(bucket, path)
-	mm := makeManifestManager(blobstoreManifest{"manifest", bs})
+	return NewBSStore(ctx, nbfVerStr, bs, memTableSize)
+}
 
+// NewBSStore returns an nbs implementation backed by a Blobstore, which holds its manifest and each of its table files
+// under the table file's name
+func NewBSStore(ctx context.Context, nbfVerStr string, bs blobstore.Blobstore, memTableSize uint64) (*NomsBlockStore, error) {
+	cacheOnce.Do(makeGlobalCaches)
+
+	mm := makeManifestManager(blobstoreManifest{"manifest", bs})
 	p := &blobstorePersister{bs, s3BlockSize, globalIndexCache}
 	return newNomsBlockStore(ctx, nbfVerStr, mm, p, inlineConjoiner{defaultMaxTables}, memTableSize)
 }
@@ -344,16 +392,43 @@ func (nbs *NomsBlockStore) addChunk(ctx context.Context, h addr, data []byte) bo
 	nbs.mu.Lock()
 	defer nbs.mu.Unlock()
 	if nbs.mt == nil {
-		nbs.mt = newMemTable(nbs.mtSize)
+		nbs.mt = nbs.newMemTable()
 	}
 	if !nbs.mt.addChunk(h, data) {
 		nbs.tables = nbs.tables.Prepend(ctx, nbs.mt, nbs.stats)
-		nbs.mt = newMemTable(nbs.mtSize)
+		nbs.mt = nbs.newMemTable()
 		return nbs.mt.addChunk(h, data)
 	}
 	return true
 }
 
+// newMemTable returns a memTable whose tables will be written with the store's compression
+func (nbs *NomsBlockStore) newMemTable() *memTable {
+	mt := newMemTable(nbs.mtSize)
+	mt.encoder = nbs.encoder
+	return mt
+}
+
+// SetCompression sets the compression of the tables the store writes from now on. Tables which were already written
+// keep their compression, and are read using the compression recorded in their footers.
+func (nbs *NomsBlockStore) SetCompression(c Compression) error {
+	encoder, err := newChunkEncoder(c)
+
+	if err != nil {
+		return err
+	}
+
+	nbs.mu.Lock()
+	defer nbs.mu.Unlock()
+	nbs.encoder = encoder
+
+	if nbs.mt != nil {
+		nbs.mt.encoder = encoder
+	}
+
+	return nil
+}
+
 func (nbs *NomsBlockStore) Get(ctx context.Context, h hash.Hash) (chunks.Chunk, error) {
 	t1 := time.Now()
 	defer func() {
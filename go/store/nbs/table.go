@@ -96,7 +96,9 @@ import (
    +----------------------+----------------------------------------+------------------+
 
      -Total Uncompressed Chunk Data is the sum of the uncompressed byte lengths of all contained chunk byte slices.
-     -Magic Number is the first 8 bytes of the SHA256 hash of "https://github.com/attic-labs/nbs".
+     -Magic Number is the first 8 bytes of the SHA256 hash of "https://github.com/attic-labs/nbs" in tables whose Chunk
+      Data is compressed with snappy. Tables whose Chunk Data is compressed with zstd, each chunk as its own frame, use
+      the same bytes with the last one replaced by 0x5a.
 
     NOTE: Unsigned integer quanities, hashes and hash suffix are all encoded big-endian
 
@@ -202,6 +202,7 @@ type compactionPlan struct {
 	mergedIndex         []byte
 	chunkCount          uint32
 	totalCompressedData uint64
+	compression         Compression
 }
 
 func (cp compactionPlan) suffixes() []byte {
@@ -211,7 +212,8 @@ func (cp compactionPlan) suffixes() []byte {
 
 func planConjoin(sources chunkSources, stats *Stats) (plan compactionPlan, err error) {
 	var totalUncompressedData uint64
-	for _, src := range sources {
+	var compression Compression
+	for i, src := range sources {
 		var uncmp uint64
 		uncmp, err = src.uncompressedLen()
 
@@ -226,6 +228,13 @@ func planConjoin(sources chunkSources, stats *Stats) (plan compactionPlan, err e
 			return compactionPlan{}, err
 		}
 
+		if i == 0 {
+			compression = index.compression
+			plan.compression = compression
+		} else if index.compression != compression {
+			return compactionPlan{}, ErrMixedCompression
+		}
+
 		plan.chunkCount += index.chunkCount
 
 		// Calculate the amount of chunk data in |src|
@@ -295,15 +304,17 @@ func planConjoin(sources chunkSources, stats *Stats) (plan compactionPlan, err e
 		pfxPos += ordinalSize
 	}
 
-	writeFooter(plan.mergedIndex[uint64(len(plan.mergedIndex))-footerSize:], plan.chunkCount, totalUncompressedData)
+	writeFooter(plan.mergedIndex[uint64(len(plan.mergedIndex))-footerSize:], plan.chunkCount, totalUncompressedData, compression)
 
 	stats.BytesPerConjoin.Sample(uint64(plan.totalCompressedData) + uint64(len(plan.mergedIndex)))
 	return plan, nil
 }
 
-func nameFromSuffixes(suffixes []byte) (name addr) {
+// nameFromSuffixes returns the name of a table of chunks with the address suffixes given, compressed with |c|
+func nameFromSuffixes(suffixes []byte, c Compression) (name addr) {
 	sha := sha512.New()
 	sha.Write(suffixes)
+	hashCompression(sha, c)
 
 	var h []byte
 	h = sha.Sum(h) // Appends hash to h
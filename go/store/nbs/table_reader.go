@@ -30,8 +30,6 @@ import (
 	"sort"
 	"sync"
 
-	"github.com/golang/snappy"
-
 	"github.com/liquidata-inc/dolt/go/store/atomicerr"
 	"github.com/liquidata-inc/dolt/go/store/chunks"
 	"github.com/liquidata-inc/dolt/go/store/hash"
@@ -45,6 +43,7 @@ type tableIndex struct {
 	prefixes, offsets     []uint64
 	lengths, ordinals     []uint32
 	suffixes              []byte
+	compression           Compression
 }
 
 type tableReaderAt interface {
@@ -71,7 +70,9 @@ func parseTableIndex(buff []byte) (tableIndex, error) {
 	// footer
 	pos -= magicNumberSize
 
-	if string(buff[pos:]) != magicNumber {
+	compression, ok := compressionForMagic(buff[pos:])
+
+	if !ok {
 		return tableIndex{}, ErrInvalidTableFile
 	}
 
@@ -126,6 +127,7 @@ func parseTableIndex(buff []byte) (tableIndex, error) {
 		prefixes, offsets,
 		lengths, ordinals,
 		suffixes,
+		compression,
 	}, nil
 }
 
@@ -522,7 +524,7 @@ func (tr tableReader) parseChunk(buff []byte) ([]byte, error) {
 		return nil, errors.New("checksum error")
 	}
 
-	data, err := snappy.Decode(nil, buff[:dataLen])
+	data, err := tr.compression.decode(buff[:dataLen])
 
 	if err != nil {
 		return nil, errors.New("decode error - likely corrupt data")
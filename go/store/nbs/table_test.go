@@ -41,7 +41,7 @@ func buildTable(chunks [][]byte) ([]byte, addr, error) {
 	for _, chunk := range chunks {
 		totalData += uint64(len(chunk))
 	}
-	capacity := maxTableSize(uint64(len(chunks)), totalData)
+	capacity := maxTableSize(uint64(len(chunks)), totalData, nil)
 
 	buff := make([]byte, capacity)
 
@@ -161,7 +161,7 @@ func TestHasManySequentialPrefix(t *testing.T) {
 	bogusData := []byte("bogus") // doesn't matter what this is. hasMany() won't check chunkRecords
 	totalData := uint64(len(bogusData) * len(addrs))
 
-	capacity := maxTableSize(uint64(len(addrs)), totalData)
+	capacity := maxTableSize(uint64(len(addrs)), totalData, nil)
 	buff := make([]byte, capacity)
 	tw := newTableWriter(buff, nil)
 
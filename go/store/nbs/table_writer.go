@@ -29,8 +29,6 @@ import (
 	"hash"
 	"sort"
 
-	"github.com/golang/snappy"
-
 	"github.com/liquidata-inc/dolt/go/store/d"
 )
 
@@ -43,25 +41,18 @@ type tableWriter struct {
 	prefixes              prefixIndexSlice // TODO: This is in danger of exploding memory
 	blockHash             hash.Hash
 
-	snapper snappyEncoder
-}
-
-type snappyEncoder interface {
-	Encode(dst, src []byte) []byte
-}
-
-type realSnappyEncoder struct{}
-
-func (r realSnappyEncoder) Encode(dst, src []byte) []byte {
-	return snappy.Encode(dst, src)
+	encoder chunkEncoder
 }
 
-func maxTableSize(numChunks, totalData uint64) uint64 {
+func maxTableSize(numChunks, totalData uint64, encoder chunkEncoder) uint64 {
+	if encoder == nil {
+		encoder = snappyEncoder{}
+	}
 	avgChunkSize := totalData / numChunks
 	d.Chk.True(avgChunkSize < maxChunkSize)
-	maxSnappySize := snappy.MaxEncodedLen(int(avgChunkSize))
-	d.Chk.True(maxSnappySize > 0)
-	return numChunks*(prefixTupleSize+lengthSize+addrSuffixSize+checksumSize+uint64(maxSnappySize)) + footerSize
+	maxEncodedSize := encoder.MaxEncodedLen(int(avgChunkSize))
+	d.Chk.True(maxEncodedSize > 0)
+	return numChunks*(prefixTupleSize+lengthSize+addrSuffixSize+checksumSize+uint64(maxEncodedSize)) + footerSize
 }
 
 func indexSize(numChunks uint32) uint64 {
@@ -76,15 +67,15 @@ func suffixesOffset(numChunks uint32) uint64 {
 	return uint64(numChunks) * (prefixTupleSize + lengthSize)
 }
 
-// len(buff) must be >= maxTableSize(numChunks, totalData)
-func newTableWriter(buff []byte, snapper snappyEncoder) *tableWriter {
-	if snapper == nil {
-		snapper = realSnappyEncoder{}
+// len(buff) must be >= maxTableSize(numChunks, totalData, encoder). A nil |encoder| writes snappy tables.
+func newTableWriter(buff []byte, encoder chunkEncoder) *tableWriter {
+	if encoder == nil {
+		encoder = snappyEncoder{}
 	}
 	return &tableWriter{
 		buff:      buff,
 		blockHash: sha512.New(),
-		snapper:   snapper,
+		encoder:   encoder,
 	}
 }
 
@@ -94,16 +85,15 @@ func (tw *tableWriter) addChunk(h addr, data []byte) bool {
 	}
 
 	// Compress data straight into tw.buff
-	compressed := tw.snapper.Encode(tw.buff[tw.pos:], data)
+	compressed := tw.encoder.Encode(tw.buff[tw.pos:], data)
 	dataLength := uint64(len(compressed))
 	tw.totalCompressedData += dataLength
 
 	// BUG 3156 indicated that, sometimes, snappy decided that there's not enough space in tw.buff[tw.pos:] to encode into.
-	// This _should never happen anymore be_, because we iterate over all chunks to be added and sum the max amount of space that snappy says it might need.
-	// Since we know that |data| can't be 0-length, we also know that the compressed version of |data| has length greater than zero. The first element in a snappy-encoded blob is a Uvarint indicating how much data is present. Therefore, if there's a Uvarint-encoded 0 at tw.buff[tw.pos:], we know that snappy did not write anything there and we have a problem.
-	if v, n := binary.Uvarint(tw.buff[tw.pos:]); v == 0 {
-		d.Chk.True(n != 0)
-		panic(fmt.Errorf("bug 3156: unbuffered chunk %s: uncompressed %d, compressed %d, snappy max %d, tw.buff %d", h.String(), len(data), dataLength, snappy.MaxEncodedLen(len(data)), len(tw.buff[tw.pos:])))
+	// This _should never happen anymore be_, because we iterate over all chunks to be added and sum the max amount of space that the encoder says it might need.
+	// Since we know that |data| can't be 0-length, we also know that the compressed version of |data| has length greater than zero, so it must start at tw.buff[tw.pos]. If it doesn't, the encoder wrote it somewhere else and we have a problem.
+	if dataLength == 0 || &compressed[0] != &tw.buff[tw.pos] {
+		panic(fmt.Errorf("bug 3156: unbuffered chunk %s: uncompressed %d, compressed %d, %s max %d, tw.buff %d", h.String(), len(data), dataLength, tw.encoder.Compression(), tw.encoder.MaxEncodedLen(len(data)), len(tw.buff[tw.pos:])))
 	}
 
 	tw.pos += dataLength
@@ -134,6 +124,8 @@ func (tw *tableWriter) finish() (uncompressedLength uint64, blockAddr addr, err
 	tw.writeFooter()
 	uncompressedLength = tw.pos
 
+	hashCompression(tw.blockHash, tw.encoder.Compression())
+
 	var h []byte
 	h = tw.blockHash.Sum(h) // Appends hash to h
 	copy(blockAddr[:], h)
@@ -195,10 +187,10 @@ func (tw *tableWriter) writeIndex() error {
 }
 
 func (tw *tableWriter) writeFooter() {
-	tw.pos += writeFooter(tw.buff[tw.pos:], uint32(len(tw.prefixes)), tw.totalUncompressedData)
+	tw.pos += writeFooter(tw.buff[tw.pos:], uint32(len(tw.prefixes)), tw.totalUncompressedData, tw.encoder.Compression())
 }
 
-func writeFooter(dst []byte, chunkCount uint32, uncData uint64) (consumed uint64) {
+func writeFooter(dst []byte, chunkCount uint32, uncData uint64, compression Compression) (consumed uint64) {
 	// chunk count
 	binary.BigEndian.PutUint32(dst[consumed:], chunkCount)
 	consumed += uint32Size
@@ -207,8 +199,8 @@ func writeFooter(dst []byte, chunkCount uint32, uncData uint64) (consumed uint64
 	binary.BigEndian.PutUint64(dst[consumed:], uncData)
 	consumed += uint64Size
 
-	// magic number
-	copy(dst[consumed:], magicNumber)
+	// magic number, which also records the compression of the chunk records
+	copy(dst[consumed:], compression.magic())
 	consumed += magicNumberSize
 	return
 }
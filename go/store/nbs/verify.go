@@ -0,0 +1,123 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+// Verify checks the manifest of the store and every table file it lists. The index of each table file must be well
+// formed and match the name and chunk count given in the manifest, and every chunk in the table file must be readable
+// and hash to its address. It returns a description of each problem found. The error returned is for failures that
+// keep the store from being checked at all.
+func (nbs *NomsBlockStore) Verify(ctx context.Context) ([]string, error) {
+	exists, contents, err := nbs.mm.Fetch(ctx, nbs.stats)
+
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, nil
+	}
+
+	// the lock isn't checked against the root and table files, as UpdateManifest adds table files without changing it
+	var problems []string
+	seen := make(map[addr]bool, len(contents.specs))
+	for _, spec := range contents.specs {
+		if seen[spec.name] {
+			problems = append(problems, fmt.Sprintf("manifest %s: table file %s is listed more than once", nbs.mm.Name(), spec.name.String()))
+			continue
+		}
+
+		seen[spec.name] = true
+
+		tableProblems, err := nbs.verifyTable(ctx, spec)
+
+		if err != nil {
+			return nil, err
+		}
+
+		problems = append(problems, tableProblems...)
+	}
+
+	return problems, nil
+}
+
+// verifyTable returns a description of each problem found in the table file of |spec|
+func (nbs *NomsBlockStore) verifyTable(ctx context.Context, spec tableSpec) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	tableProblem := func(format string, args ...interface{}) string {
+		return fmt.Sprintf("table file %s: %s", spec.name.String(), fmt.Sprintf(format, args...))
+	}
+
+	src, err := nbs.p.Open(ctx, spec.name, spec.chunkCount, nbs.stats)
+
+	if err != nil {
+		return []string{tableProblem("can't be opened: %v", err)}, nil
+	}
+
+	index, err := src.index()
+
+	if err != nil {
+		return []string{tableProblem("the index can't be read: %v", err)}, nil
+	}
+
+	if index.chunkCount != spec.chunkCount {
+		return []string{tableProblem("the index has %d chunks but the manifest lists %d", index.chunkCount, spec.chunkCount)}, nil
+	}
+
+	if nameFromSuffixes(index.suffixes, index.compression) != spec.name {
+		return []string{tableProblem("the index doesn't match the name of the table file")}, nil
+	}
+
+	var problems []string
+	addrs := make([]addr, index.chunkCount)
+	ordinalSeen := make([]bool, index.chunkCount)
+	for i, prefix := range index.prefixes {
+		if i > 0 && prefix < index.prefixes[i-1] {
+			return []string{tableProblem("the prefixes of the index aren't sorted")}, nil
+		}
+
+		ordinal := index.ordinals[i]
+		if ordinal >= index.chunkCount || ordinalSeen[ordinal] {
+			return []string{tableProblem("the index has an invalid ordinal %d", ordinal)}, nil
+		}
+
+		ordinalSeen[ordinal] = true
+		binary.BigEndian.PutUint64(addrs[ordinal][:], prefix)
+		li := uint64(ordinal) * addrSuffixSize
+		copy(addrs[ordinal][addrPrefixSize:], index.suffixes[li:li+addrSuffixSize])
+	}
+
+	for _, a := range addrs {
+		data, err := src.get(ctx, a, nbs.stats)
+
+		if err != nil {
+			problems = append(problems, tableProblem("chunk %s can't be read: %v", a.String(), err))
+		} else if data == nil {
+			problems = append(problems, tableProblem("chunk %s is in the index but can't be found", a.String()))
+		} else if addr(hash.Of(data)) != a {
+			problems = append(problems, tableProblem("chunk %s doesn't hash to its address", a.String()))
+		}
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/constants"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewLocalStore(ctx, constants.FormatDefaultString, dir, testMemTableSize)
+	require.NoError(t, err)
+
+	c1 := chunks.NewChunk([]byte("chunk 1"))
+	c2 := chunks.NewChunk([]byte("chunk 2"))
+	require.NoError(t, store.Put(ctx, c1))
+	require.NoError(t, store.Put(ctx, c2))
+
+	last, err := store.Root(ctx)
+	require.NoError(t, err)
+	ok, err := store.Commit(ctx, c1.Hash(), last)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	problems, err := store.Verify(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	require.Equal(t, 1, tableFileCount(t, dir))
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+
+	var tableName, tablePath string
+	for _, info := range infos {
+		if info.Name() != manifestFileName && info.Name() != lockFileName {
+			tableName = info.Name()
+			tablePath = filepath.Join(dir, tableName)
+		}
+	}
+
+	// a table file added to the manifest the way a remote client uploads it doesn't change the lock
+	uploadDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(uploadDir)
+
+	data, err := ioutil.ReadFile(tablePath)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(uploadDir, tableName), data, 0644))
+
+	uploaded, err := NewLocalStore(ctx, constants.FormatDefaultString, uploadDir, testMemTableSize)
+	require.NoError(t, err)
+	_, err = uploaded.UpdateManifest(ctx, map[hash.Hash]uint32{hash.Parse(tableName): 2})
+	require.NoError(t, err)
+
+	problems, err = uploaded.Verify(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	// the chunk data starts at the beginning of the table file
+	data, err = ioutil.ReadFile(tablePath)
+	require.NoError(t, err)
+	data[0] ^= 0xff
+	require.NoError(t, ioutil.WriteFile(tablePath, data, 0644))
+
+	corrupted, err := NewLocalStore(ctx, constants.FormatDefaultString, dir, testMemTableSize)
+	require.NoError(t, err)
+
+	problems, err = corrupted.Verify(ctx)
+	require.NoError(t, err)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], "can't be read")
+}
@@ -0,0 +1,160 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/creds"
+)
+
+// ErrInvalidRepoName is returned for an org or repository name which isn't a single path element
+var ErrInvalidRepoName = errors.New("invalid org or repository name")
+
+// Access is the level of access a key has to a repository
+type Access int
+
+const (
+	NoAccess Access = iota
+	ReadAccess
+	WriteAccess
+)
+
+var accessNames = map[string]Access{
+	"none":  NoAccess,
+	"read":  ReadAccess,
+	"write": WriteAccess,
+}
+
+func (a Access) String() string {
+	switch a {
+	case NoAccess:
+		return "none"
+	case ReadAccess:
+		return "read"
+	case WriteAccess:
+		return "write"
+	}
+
+	return "unknown"
+}
+
+// aclFile is the json form of an ACL. Keys are the base32 public keys printed by "dolt creds ls", and map
+// repositories to the access the key has to them. A repository is given as org/repo, org/* for every repository in
+// an org, or * for every repository.
+//
+//	{"keys": {"<public key>": {"org/repo": "write", "org/*": "read"}}}
+type aclFile struct {
+	Keys map[string]map[string]string `json:"keys"`
+}
+
+// ACL is the access each known key has to the repositories on the server
+type ACL struct {
+	pubKeys map[string][]byte
+	access  map[string]map[string]Access
+}
+
+// LoadACL reads an ACL from the json file at |path|
+func LoadACL(path string) (*ACL, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseACL(data)
+}
+
+// ParseACL parses the json form of an ACL
+func ParseACL(data []byte) (*ACL, error) {
+	var af aclFile
+	err := json.Unmarshal(data, &af)
+
+	if err != nil {
+		return nil, err
+	}
+
+	acl := &ACL{make(map[string][]byte), make(map[string]map[string]Access)}
+	for pubKeyStr, repos := range af.Keys {
+		pubKey, err := creds.B32CredsEncoding.DecodeString(pubKeyStr)
+
+		if err != nil || !(creds.DoltCreds{PubKey: pubKey}).IsPubKeyValid() {
+			return nil, fmt.Errorf("invalid public key in acl: %s", pubKeyStr)
+		}
+
+		kid := creds.PubKeyToKIDStr(pubKey)
+		acl.pubKeys[kid] = pubKey
+		acl.access[kid] = make(map[string]Access)
+
+		for repo, accessStr := range repos {
+			access, ok := accessNames[accessStr]
+
+			if !ok {
+				return nil, fmt.Errorf("invalid access '%s' for %s in acl. Must be one of none, read or write", accessStr, repo)
+			}
+
+			acl.access[kid][repo] = access
+		}
+	}
+
+	return acl, nil
+}
+
+// PubKey returns the public key with the base32 encoded key id |kid|, and false if the key isn't in the ACL
+func (acl *ACL) PubKey(kid string) ([]byte, bool) {
+	pubKey, ok := acl.pubKeys[kid]
+	return pubKey, ok
+}
+
+// Access returns the access the key with the base32 encoded key id |kid| has to the repository |org|/|repo|. The
+// most specific entry for the repository is used.
+func (acl *ACL) Access(kid, org, repo string) Access {
+	repos, ok := acl.access[kid]
+
+	if !ok {
+		return NoAccess
+	}
+
+	for _, pattern := range repoPatterns(org, repo) {
+		if access, ok := repos[pattern]; ok {
+			return access
+		}
+	}
+
+	return NoAccess
+}
+
+// repoPatterns returns the entries that can apply to the repository |org|/|repo| in a file of settings for
+// repositories, from the most specific to the least
+func repoPatterns(org, repo string) []string {
+	return []string{path.Join(org, repo), path.Join(org, "*"), "*"}
+}
+
+// validateRepoName checks that |org| and |repo| are each a single path element. Names are joined into paths and acl
+// patterns, which would otherwise let a name like "x/../../other/repo" refer to a different repository.
+func validateRepoName(org, repo string) error {
+	for _, name := range []string{org, repo} {
+		if name == "" || name == "." || strings.Contains(name, "..") || strings.ContainsAny(name, `/\`) {
+			return ErrInvalidRepoName
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	remotesapi "github.com/liquidata-inc/dolt/go/gen/proto/dolt/services/remotesapi_v1alpha1"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/creds"
+)
+
+const (
+	signedURLTTL = 15 * time.Minute
+
+	expiresParam   = "expires"
+	signatureParam = "signature"
+)
+
+// rpcAccess is the access to the repository in a request needed to call each rpc. Rpcs that aren't listed need write
+// access.
+var rpcAccess = map[string]Access{
+	"GetRepoMetadata":      ReadAccess,
+	"HasChunks":            ReadAccess,
+	"GetDownloadLocations": ReadAccess,
+	"Rebase":               ReadAccess,
+	"Root":                 ReadAccess,
+	"GetUploadLocations":   WriteAccess,
+	"Commit":               WriteAccess,
+}
+
+type repoRequest interface {
+	GetRepoId() *remotesapi.RepoId
+}
+
+type kidKey struct{}
+
+// callerKID returns the base32 encoded key id of the credentials an rpc was called with, and false if the server
+// doesn't authenticate its callers
+func callerKID(ctx context.Context) (string, bool) {
+	kid, ok := ctx.Value(kidKey{}).(string)
+	return kid, ok
+}
+
+// Authenticator verifies the credentials that rpcs are called with, and checks that the caller has the access the rpc
+// needs to the repository in its request
+type Authenticator struct {
+	acl *ACL
+}
+
+func NewAuthenticator(acl *ACL) *Authenticator {
+	return &Authenticator{acl}
+}
+
+// UnaryInterceptor is a grpc.UnaryServerInterceptor which rejects rpcs whose caller can't be authenticated, or
+// doesn't have access to the repository in the request
+func (auth *Authenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	kid, err := auth.authenticate(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rpcName := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	needed, ok := rpcAccess[rpcName]
+
+	if !ok {
+		needed = WriteAccess
+	}
+
+	rr, ok := req.(repoRequest)
+
+	if !ok || rr.GetRepoId() == nil {
+		return nil, status.Error(codes.InvalidArgument, "request has no repository")
+	}
+
+	org, repo := rr.GetRepoId().Org, rr.GetRepoId().RepoName
+	if validateRepoName(org, repo) != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid repository %q/%q", org, repo)
+	}
+
+	if auth.acl.Access(kid, org, repo) < needed {
+		return nil, status.Errorf(codes.PermissionDenied, "%s access to %s/%s is required", needed, org, repo)
+	}
+
+	return handler(context.WithValue(ctx, kidKey{}, kid), req)
+}
+
+func (auth *Authenticator) authenticate(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+
+	if !ok || len(md.Get("authorization")) == 0 {
+		return "", status.Error(codes.Unauthenticated, "credentials are required")
+	}
+
+	token := md.Get("authorization")[0]
+
+	if !strings.HasPrefix(token, "Bearer ") {
+		return "", status.Error(codes.Unauthenticated, "credentials must be a bearer token")
+	}
+
+	kid, err := creds.VerifyBearerToken(strings.TrimPrefix(token, "Bearer "), auth.acl.PubKey, time.Now())
+
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return kid, nil
+}
+
+// URLSigner signs the urls of table files handed out by the grpc server, so that the http server only serves them to
+// callers with access to their repository, and only for a short time
+type URLSigner struct {
+	secret []byte
+}
+
+// NewURLSigner returns a URLSigner with a random secret. Urls it signs are only valid for the process they were
+// signed by.
+func NewURLSigner() (*URLSigner, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &URLSigner{secret}, nil
+}
+
+func (us *URLSigner) signature(access Access, path string, expires int64) string {
+	mac := hmac.New(sha256.New, us.secret)
+	mac.Write([]byte(fmt.Sprintf("%s\n%s\n%d", access, path, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign returns the query string which grants |access| to the file at |path| until the url expires
+func (us *URLSigner) Sign(access Access, path string, now time.Time) string {
+	expires := now.Add(signedURLTTL).Unix()
+	params := url.Values{}
+	params.Set(expiresParam, strconv.FormatInt(expires, 10))
+	params.Set(signatureParam, us.signature(access, path, expires))
+
+	return params.Encode()
+}
+
+// Verify returns whether |query| grants |access| to the file at |path|
+func (us *URLSigner) Verify(access Access, path string, query url.Values, now time.Time) bool {
+	expires, err := strconv.ParseInt(query.Get(expiresParam), 10, 64)
+
+	if err != nil || now.Unix() > expires {
+		return false
+	}
+
+	expected := us.signature(access, path, expires)
+	return hmac.Equal([]byte(expected), []byte(query.Get(signatureParam)))
+}
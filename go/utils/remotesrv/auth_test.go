@@ -0,0 +1,83 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	remotesapi "github.com/liquidata-inc/dolt/go/gen/proto/dolt/services/remotesapi_v1alpha1"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/creds"
+)
+
+func TestRepoTraversal(t *testing.T) {
+	dc, err := creds.GenerateCredentials()
+	require.NoError(t, err)
+
+	acl, err := ParseACL([]byte(`{"keys": {"` + dc.PubKeyBase32Str() + `": {"evil/*": "write"}}}`))
+	require.NoError(t, err)
+
+	md, err := dc.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", md["authorization"]))
+
+	auth := NewAuthenticator(acl)
+	info := &grpc.UnaryServerInfo{FullMethod: "/dolt.services.remotesapi.v1alpha1.ChunkStoreService/Commit"}
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	}
+
+	_, err = auth.UnaryInterceptor(ctx, &remotesapi.CommitRequest{RepoId: &remotesapi.RepoId{Org: "evil", RepoName: "repo"}}, info, handler)
+	require.NoError(t, err)
+	assert.True(t, called)
+
+	// the grant for evil/* must not reach into another org's repository
+	for _, repoId := range []*remotesapi.RepoId{
+		{Org: "evil", RepoName: "x/../../victim/repo"},
+		{Org: "evil", RepoName: `x\..\..\victim\repo`},
+		{Org: "evil", RepoName: ".."},
+		{Org: "evil", RepoName: "."},
+		{Org: "evil", RepoName: ""},
+		{Org: "", RepoName: "repo"},
+	} {
+		called = false
+		_, err = auth.UnaryInterceptor(ctx, &remotesapi.CommitRequest{RepoId: repoId}, info, handler)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "%s/%s", repoId.Org, repoId.RepoName)
+		assert.False(t, called)
+	}
+
+	dbCache := NewCSCache(InMemoryBlobstores())
+	_, err = dbCache.Get("evil", "x/../../victim/repo")
+	assert.Equal(t, ErrInvalidRepoName, err)
+
+	httpServer := httptest.NewServer(newFileHandler(dbCache, nil))
+	defer httpServer.Close()
+
+	resp, err := http.Get(httpServer.URL + "/evil/../0123456789abcdefghijklmnopqrstuv")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
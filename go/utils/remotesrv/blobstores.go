@@ -0,0 +1,100 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/storage"
+
+	"github.com/liquidata-inc/dolt/go/store/blobstore"
+)
+
+const (
+	fileScheme = "file"
+	memScheme  = "mem"
+	gcsScheme  = "gs"
+)
+
+// BlobstoreFactory returns the blobstore holding the manifest and table files of the repository |org|/|repo|. It is
+// called once for each repository the server is asked for.
+type BlobstoreFactory func(ctx context.Context, org, repo string) (blobstore.Blobstore, error)
+
+// LocalBlobstores stores each repository in the directory |dir|/org/repo
+func LocalBlobstores(dir string) BlobstoreFactory {
+	return func(ctx context.Context, org, repo string) (blobstore.Blobstore, error) {
+		repoDir := filepath.Join(dir, org, repo)
+		err := os.MkdirAll(repoDir, os.ModePerm)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return blobstore.NewLocalBlobstore(repoDir), nil
+	}
+}
+
+// InMemoryBlobstores stores each repository in memory. Everything stored is lost when the server exits.
+func InMemoryBlobstores() BlobstoreFactory {
+	return func(ctx context.Context, org, repo string) (blobstore.Blobstore, error) {
+		return blobstore.NewInMemoryBlobstore(), nil
+	}
+}
+
+// GCSBlobstores stores each repository under |prefix|/org/repo/ in the GCS bucket |bucket|
+func GCSBlobstores(ctx context.Context, bucket, prefix string) (BlobstoreFactory, error) {
+	gcs, err := storage.NewClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bucketHandle := gcs.Bucket(bucket)
+	return func(ctx context.Context, org, repo string) (blobstore.Blobstore, error) {
+		repoPrefix := strings.TrimLeft(path.Join(prefix, org, repo), "/") + "/"
+		return blobstore.NewGCSBlobstore(bucketHandle, repoPrefix), nil
+	}, nil
+}
+
+// BlobstoresForURL returns the BlobstoreFactory for |urlStr|, which is file:///path or a path without a scheme to store
+// repositories in a local directory, mem:// to store them in memory, or gs://bucket/prefix to store them in GCS.
+func BlobstoresForURL(ctx context.Context, urlStr string) (BlobstoreFactory, error) {
+	if !strings.Contains(urlStr, "://") {
+		return LocalBlobstores(urlStr), nil
+	}
+
+	urlObj, err := url.Parse(urlStr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(urlObj.Scheme) {
+	case fileScheme:
+		return LocalBlobstores(urlObj.Path), nil
+	case memScheme:
+		return InMemoryBlobstores(), nil
+	case gcsScheme:
+		return GCSBlobstores(ctx, urlObj.Host, urlObj.Path)
+	}
+
+	return nil, fmt.Errorf("unsupported blobstore scheme: %s", urlObj.Scheme)
+}
@@ -16,10 +16,10 @@ package main
 
 import (
 	"context"
-	"path/filepath"
+	"path"
 	"sync"
 
-	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/blobstore"
 	"github.com/liquidata-inc/dolt/go/store/nbs"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
@@ -28,47 +28,81 @@ const (
 	defaultMemTableSize = 128 * 1024 * 1024
 )
 
+// repoStore is the chunk store of a repository along with the blobstore holding its manifest and table files
+type repoStore struct {
+	cs *nbs.NomsBlockStore
+	bs blobstore.Blobstore
+}
+
 type DBCache struct {
 	mu  *sync.Mutex
-	dbs map[string]*nbs.NomsBlockStore
+	dbs map[string]repoStore
 
-	fs filesys.Filesys
+	newBS BlobstoreFactory
 }
 
-func NewLocalCSCache(filesys filesys.Filesys) *DBCache {
+// NewCSCache returns a DBCache which stores each repository in the blobstore |newBS| returns for it
+func NewCSCache(newBS BlobstoreFactory) *DBCache {
 	return &DBCache{
 		&sync.Mutex{},
-		make(map[string]*nbs.NomsBlockStore),
-		filesys,
+		make(map[string]repoStore),
+		newBS,
 	}
 }
 
+// Get returns the chunk store of the repository |org|/|repo|, creating it if it doesn't exist
 func (cache *DBCache) Get(org, repo string) (*nbs.NomsBlockStore, error) {
+	rs, err := cache.get(org, repo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.cs, nil
+}
+
+// GetBlobstore returns the blobstore holding the table files of the repository |org|/|repo|
+func (cache *DBCache) GetBlobstore(org, repo string) (blobstore.Blobstore, error) {
+	rs, err := cache.get(org, repo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.bs, nil
+}
+
+func (cache *DBCache) get(org, repo string) (repoStore, error) {
+	err := validateRepoName(org, repo)
+
+	if err != nil {
+		return repoStore{}, err
+	}
+
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
 
-	id := filepath.Join(org, repo)
+	id := path.Join(org, repo)
 
-	if cs, ok := cache.dbs[id]; ok {
-		return cs, nil
+	if rs, ok := cache.dbs[id]; ok {
+		return rs, nil
 	}
 
-	var newCS *nbs.NomsBlockStore
-	if cache.fs != nil {
-		err := cache.fs.MkDirs(id)
+	ctx := context.TODO()
+	bs, err := cache.newBS(ctx, org, repo)
 
-		if err != nil {
-			return nil, err
-		}
+	if err != nil {
+		return repoStore{}, err
+	}
 
-		newCS, err = nbs.NewLocalStore(context.TODO(), types.Format_Default.VersionString(), id, defaultMemTableSize)
+	newCS, err := nbs.NewBSStore(ctx, types.Format_Default.VersionString(), bs, defaultMemTableSize)
 
-		if err != nil {
-			return nil, err
-		}
+	if err != nil {
+		return repoStore{}, err
 	}
 
-	cache.dbs[id] = newCS
+	rs := repoStore{newCS, bs}
+	cache.dbs[id] = rs
 
-	return newCS, nil
+	return rs, nil
 }
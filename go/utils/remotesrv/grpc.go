@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"log"
 	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -27,19 +28,27 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/remotestorage"
 	"github.com/liquidata-inc/dolt/go/store/hash"
 	"github.com/liquidata-inc/dolt/go/store/nbs"
+	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
 type RemoteChunkStore struct {
 	HttpHost string
 	csCache  *DBCache
 	bucket   string
+	signer   *URLSigner
+	policies *Policies
 }
 
-func NewHttpFSBackedChunkStore(httpHost string, csCache *DBCache) *RemoteChunkStore {
+// NewHttpFSBackedChunkStore returns a RemoteChunkStore whose table files are served by the http server at |httpHost|.
+// When |signer| is not nil the urls of table files are signed by it, and when |policies| is not nil each commit is
+// checked against the policy of its repository.
+func NewHttpFSBackedChunkStore(httpHost string, csCache *DBCache, signer *URLSigner, policies *Policies) *RemoteChunkStore {
 	return &RemoteChunkStore{
 		httpHost,
 		csCache,
 		"",
+		signer,
+		policies,
 	}
 }
 
@@ -123,7 +132,18 @@ func (rs *RemoteChunkStore) GetDownloadLocations(ctx context.Context, req *remot
 }
 
 func (rs *RemoteChunkStore) getDownloadUrl(logger func(string), org, repoName, fileId string) (string, error) {
-	return fmt.Sprintf("http://%s/%s/%s/%s", rs.HttpHost, org, repoName, fileId), nil
+	return rs.getUrl(ReadAccess, org, repoName, fileId), nil
+}
+
+func (rs *RemoteChunkStore) getUrl(access Access, org, repoName, fileId string) string {
+	path := fmt.Sprintf("%s/%s/%s", org, repoName, fileId)
+	url := fmt.Sprintf("http://%s/%s", rs.HttpHost, path)
+
+	if rs.signer != nil {
+		url += "?" + rs.signer.Sign(access, path, time.Now())
+	}
+
+	return url
 }
 
 func (rs *RemoteChunkStore) GetUploadLocations(ctx context.Context, req *remotesapi.GetUploadLocsRequest) (*remotesapi.GetUploadLocsResponse, error) {
@@ -169,7 +189,7 @@ func (rs *RemoteChunkStore) GetUploadLocations(ctx context.Context, req *remotes
 }
 
 func (rs *RemoteChunkStore) getUploadUrl(logger func(string), org, repoName, fileId string) (string, error) {
-	return fmt.Sprintf("http://%s/%s/%s/%s", rs.HttpHost, org, repoName, fileId), nil
+	return rs.getUrl(WriteAccess, org, repoName, fileId), nil
 }
 
 func (rs *RemoteChunkStore) Rebase(ctx context.Context, req *remotesapi.RebaseRequest) (*remotesapi.RebaseResponse, error) {
@@ -242,6 +262,19 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 	currHash := hash.New(req.Current)
 	lastHash := hash.New(req.Last)
 
+	if policy := rs.policies.Get(req.RepoId.Org, req.RepoId.RepoName); policy != nil {
+		kid, _ := callerKID(ctx)
+		err = policy.Check(ctx, types.NewValueStore(cs), kid, lastHash, currHash)
+
+		if pv, ok := err.(PolicyViolation); ok {
+			logger(fmt.Sprintf("rejected commit of %s/%s: %s", req.RepoId.Org, req.RepoId.RepoName, pv.Reason))
+			return nil, status.Error(pv.Code, pv.Reason)
+		} else if err != nil {
+			logger(fmt.Sprintf("error occurred checking the policy of %s/%s details: %v", req.RepoId.Org, req.RepoId.RepoName, err))
+			return nil, status.Error(codes.Internal, "policy check error")
+		}
+	}
+
 	var ok bool
 	ok, err = cs.Commit(ctx, currHash, lastHash)
 
@@ -15,21 +15,32 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
+	"github.com/liquidata-inc/dolt/go/store/blobstore"
 	"github.com/liquidata-inc/dolt/go/store/hash"
 )
 
-func ServeHTTP(respWr http.ResponseWriter, req *http.Request) {
+// fileHandler serves the table files of the repositories in a DBCache over http. When signer is not nil only
+// requests for urls it signed are served.
+type fileHandler struct {
+	dbCache *DBCache
+	signer  *URLSigner
+}
+
+func newFileHandler(dbCache *DBCache, signer *URLSigner) fileHandler {
+	return fileHandler{dbCache, signer}
+}
+
+func (fh fileHandler) ServeHTTP(respWr http.ResponseWriter, req *http.Request) {
 	logger := getReqLogger("HTTP_"+req.Method, req.RequestURI)
 	defer func() { logger("finished") }()
 
@@ -39,20 +50,48 @@ func ServeHTTP(respWr http.ResponseWriter, req *http.Request) {
 	if len(tokens) != 3 {
 		logger(fmt.Sprintf("response to: %v method: %v http response code: %v", req.RequestURI, req.Method, http.StatusNotFound))
 		respWr.WriteHeader(http.StatusNotFound)
+		return
 	}
 
 	org := tokens[0]
 	repo := tokens[1]
 	hashStr := tokens[2]
 
+	if validateRepoName(org, repo) != nil {
+		logger(fmt.Sprintf("response to: %v method: %v http response code: %v", req.RequestURI, req.Method, http.StatusBadRequest))
+		respWr.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if fh.signer != nil {
+		access := WriteAccess
+		if req.Method == http.MethodGet {
+			access = ReadAccess
+		}
+
+		if !fh.signer.Verify(access, path, req.URL.Query(), time.Now()) {
+			logger(fmt.Sprintf("response to: %v method: %v http response code: %v", req.RequestURI, req.Method, http.StatusForbidden))
+			respWr.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
+	bs, err := fh.dbCache.GetBlobstore(org, repo)
+
+	if err != nil {
+		logger(fmt.Sprintf("failed to get the blobstore for %s/%s: %v", org, repo, err))
+		respWr.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	statusCode := http.StatusMethodNotAllowed
 	switch req.Method {
 	case http.MethodGet:
 		rangeStr := req.Header.Get("Range")
-		statusCode = readChunk(logger, org, repo, hashStr, rangeStr, respWr)
+		statusCode = readChunk(req.Context(), logger, bs, hashStr, rangeStr, respWr)
 
 	case http.MethodPost, http.MethodPut:
-		statusCode = writeChunk(logger, org, repo, hashStr, req)
+		statusCode = writeChunk(req.Context(), logger, bs, hashStr, req)
 	}
 
 	if statusCode != -1 {
@@ -60,7 +99,7 @@ func ServeHTTP(respWr http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func writeChunk(logger func(string), org, repo, fileId string, request *http.Request) int {
+func writeChunk(ctx context.Context, logger func(string), bs blobstore.Blobstore, fileId string, request *http.Request) int {
 	_, ok := hash.MaybeParse(fileId)
 
 	if !ok {
@@ -69,35 +108,15 @@ func writeChunk(logger func(string), org, repo, fileId string, request *http.Req
 	}
 
 	logger(fileId + " is valid")
-	data, err := ioutil.ReadAll(request.Body)
+	_, err := bs.Put(ctx, fileId, request.Body)
 
 	if err != nil {
-		logger("failed to read body " + err.Error())
+		logger(fmt.Sprintf("failed to write %s: %v", fileId, err))
 		return http.StatusInternalServerError
 	}
 
-	err = writeLocal(logger, org, repo, fileId, data)
-
-	if err != nil {
-		return http.StatusInternalServerError
-	}
-
-	return http.StatusOK
-}
-
-func writeLocal(logger func(string), org, repo, fileId string, data []byte) error {
-	path := filepath.Join(org, repo, fileId)
-
-	err := ioutil.WriteFile(path, data, os.ModePerm)
-
-	if err != nil {
-		logger(fmt.Sprintf("failed to write file %s", path))
-		return err
-	}
-
 	logger("Successfully wrote object to storage")
-
-	return nil
+	return http.StatusOK
 }
 
 func offsetAndLenFromRange(rngStr string) (int64, int64, error) {
@@ -130,7 +149,7 @@ func offsetAndLenFromRange(rngStr string) (int64, int64, error) {
 	return int64(start), int64(end-start) + 1, nil
 }
 
-func readChunk(logger func(string), org, repo, fileId, rngStr string, writer io.Writer) int {
+func readChunk(ctx context.Context, logger func(string), bs blobstore.Blobstore, fileId, rngStr string, writer io.Writer) int {
 	offset, length, err := offsetAndLenFromRange(rngStr)
 
 	if err != nil {
@@ -138,7 +157,7 @@ func readChunk(logger func(string), org, repo, fileId, rngStr string, writer io.
 		return http.StatusBadRequest
 	}
 
-	data, retVal := readLocalRange(logger, org, repo, fileId, int64(offset), int64(length))
+	data, retVal := readRange(ctx, logger, bs, fileId, offset, length)
 
 	if retVal != -1 {
 		return retVal
@@ -156,49 +175,32 @@ func readChunk(logger func(string), org, repo, fileId, rngStr string, writer io.
 	return -1
 }
 
-func readLocalRange(logger func(string), org, repo, fileId string, offset, length int64) ([]byte, int) {
-	path := filepath.Join(org, repo, fileId)
-
-	logger(fmt.Sprintf("Attempting to read bytes %d to %d from %s", offset, offset+length, path))
-	info, err := os.Stat(path)
-
-	if err != nil {
-		logger(fmt.Sprintf("file %s not found", path))
-		return nil, http.StatusNotFound
+func readRange(ctx context.Context, logger func(string), bs blobstore.Blobstore, fileId string, offset, length int64) ([]byte, int) {
+	br := blobstore.AllRange
+	if offset != -1 {
+		logger(fmt.Sprintf("Attempting to read bytes %d to %d from %s", offset, offset+length, fileId))
+		br = blobstore.NewBlobRange(offset, length)
+	} else {
+		logger(fmt.Sprintf("Attempting to read all of %s", fileId))
 	}
 
-	logger(fmt.Sprintf("Verified file %s exists", path))
-
-	if info.Size() < int64(offset+length) {
-		logger(fmt.Sprintf("Attempted to read bytes %d to %d, but the file is only %d bytes in size", offset, offset+length, info.Size()))
-		return nil, http.StatusBadRequest
-	}
-
-	logger(fmt.Sprintf("Verified the file is large enough to contain the range"))
-	f, err := os.Open(path)
+	data, _, err := blobstore.GetBytes(ctx, bs, fileId, br)
 
 	if err != nil {
-		logger(fmt.Sprintf("Failed to open %s", path))
-		return nil, http.StatusInternalServerError
-	}
-
-	logger(fmt.Sprintf("Successfully opened file"))
-	pos, err := f.Seek(int64(offset), 0)
+		if blobstore.IsNotFoundError(err) {
+			logger(fmt.Sprintf("file %s not found", fileId))
+			return nil, http.StatusNotFound
+		}
 
-	if err != nil {
-		logger(fmt.Sprintf("Failed to seek to %d", offset))
+		logger(fmt.Sprintf("Failed to read %s: %v", fileId, err))
 		return nil, http.StatusInternalServerError
 	}
 
-	logger(fmt.Sprintf("Seek succeeded.  Current position is %d", pos))
-	diff := int64(offset) - pos
-	data, err := iohelp.ReadNBytes(f, int(diff+int64(length)))
-
-	if err != nil {
-		logger(fmt.Sprintf("Failed to read %d bytes", diff+int64(length)))
-		return nil, http.StatusInternalServerError
+	if offset != -1 && int64(len(data)) < length {
+		logger(fmt.Sprintf("Attempted to read bytes %d to %d, but only %d bytes were available", offset, offset+length, len(data)))
+		return nil, http.StatusBadRequest
 	}
 
 	logger(fmt.Sprintf("Successfully read %d bytes", len(data)))
-	return data[diff:], -1
+	return data, -1
 }
@@ -28,29 +28,35 @@ import (
 	"google.golang.org/grpc"
 
 	remotesapi "github.com/liquidata-inc/dolt/go/gen/proto/dolt/services/remotesapi_v1alpha1"
-	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
 )
 
 func main() {
-	dirParam := flag.String("dir", "", "root directory that this command will run in.")
+	dirParam := flag.String("dir", "", "root directory where files will be stored to and served from.")
+	blobstoreParam := flag.String("blobstore", "", "where files will be stored to and served from: file:///path, gs://bucket/prefix or mem://. Overrides 'dir'.")
 	grpcPortParam := flag.Int("grpc-port", -1, "root directory that this command will run in.")
 	httpPortParam := flag.Int("http-port", -1, "root directory that this command will run in.")
+	aclParam := flag.String("acl", "", "json file of the keys allowed to access each repository. When not provided any caller can read and write any repository.")
+	policyParam := flag.String("policy", "", "json file of the branch policies of repositories.")
 	flag.Parse()
 
-	if dirParam != nil && len(*dirParam) > 0 {
-		err := os.Chdir(*dirParam)
-
-		if err != nil {
-			log.Fatalln("failed to chdir to:", *dirParam)
-			log.Fatalln("error:", err.Error())
-			os.Exit(1)
+	storeURL := *blobstoreParam
+	if len(storeURL) == 0 {
+		if len(*dirParam) > 0 {
+			storeURL = *dirParam
 		} else {
-			log.Println("cwd set to " + *dirParam)
+			log.Println("'dir' parameter not provided. Using the current working dir.")
+			storeURL = "."
 		}
-	} else {
-		log.Println("'dir' parameter not provided. Using the current working dir.")
 	}
 
+	newBS, err := BlobstoresForURL(context.Background(), storeURL)
+
+	if err != nil {
+		log.Fatalln("failed to open blobstore", storeURL, "error:", err.Error())
+	}
+
+	log.Println("storing files in " + storeURL)
+
 	httpHost := "localhost"
 
 	if *httpPortParam != -1 {
@@ -65,7 +71,40 @@ func main() {
 		log.Println("'grpc-port' parameter not provided. Using default port 50051")
 	}
 
-	stopChan, wg := startServer(httpHost, *httpPortParam, *grpcPortParam)
+	var auth *Authenticator
+	var signer *URLSigner
+	if len(*aclParam) > 0 {
+		acl, err := LoadACL(*aclParam)
+
+		if err != nil {
+			log.Fatalln("failed to load acl", *aclParam, "error:", err.Error())
+		}
+
+		signer, err = NewURLSigner()
+
+		if err != nil {
+			log.Fatalln("failed to create url signer error:", err.Error())
+		}
+
+		auth = NewAuthenticator(acl)
+		log.Println("authenticating callers with the acl " + *aclParam)
+	} else {
+		log.Println("'acl' parameter not provided. Any caller can read and write any repository.")
+	}
+
+	var policies *Policies
+	if len(*policyParam) > 0 {
+		policies, err = LoadPolicies(*policyParam)
+
+		if err != nil {
+			log.Fatalln("failed to load policies", *policyParam, "error:", err.Error())
+		}
+
+		log.Println("enforcing the branch policies in " + *policyParam)
+	}
+
+	dbCache := NewCSCache(newBS)
+	stopChan, wg := startServer(dbCache, auth, signer, policies, httpHost, *httpPortParam, *grpcPortParam)
 	waitForSignal()
 
 	close(stopChan)
@@ -80,39 +119,43 @@ func waitForSignal() {
 	<-c
 }
 
-func startServer(httpHost string, httpPort, grpcPort int) (chan interface{}, *sync.WaitGroup) {
+func startServer(dbCache *DBCache, auth *Authenticator, signer *URLSigner, policies *Policies, httpHost string, httpPort, grpcPort int) (chan interface{}, *sync.WaitGroup) {
 	wg := sync.WaitGroup{}
 	stopChan := make(chan interface{})
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		httpServer(httpPort, stopChan)
+		httpServer(dbCache, signer, httpPort, stopChan)
 	}()
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		grpcServer(httpHost, grpcPort, stopChan)
+		grpcServer(dbCache, auth, signer, policies, httpHost, grpcPort, stopChan)
 	}()
 
 	return stopChan, &wg
 }
 
-func grpcServer(httpHost string, grpcPort int, stopChan chan interface{}) {
+func grpcServer(dbCache *DBCache, auth *Authenticator, signer *URLSigner, policies *Policies, httpHost string, grpcPort int, stopChan chan interface{}) {
 	defer func() {
 		log.Println("exiting grpc Server go routine")
 	}()
 
-	dbCache := NewLocalCSCache(filesys.LocalFS)
-	chnkSt := NewHttpFSBackedChunkStore(httpHost, dbCache)
+	chnkSt := NewHttpFSBackedChunkStore(httpHost, dbCache, signer, policies)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcPort))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer(grpc.MaxRecvMsgSize(128 * 1024 * 1024))
+	opts := []grpc.ServerOption{grpc.MaxRecvMsgSize(128 * 1024 * 1024)}
+	if auth != nil {
+		opts = append(opts, grpc.UnaryInterceptor(auth.UnaryInterceptor))
+	}
+
+	grpcServer := grpc.NewServer(opts...)
 	go func() {
 		remotesapi.RegisterChunkStoreServiceServer(grpcServer, chnkSt)
 
@@ -125,14 +168,14 @@ func grpcServer(httpHost string, grpcPort int, stopChan chan interface{}) {
 	grpcServer.GracefulStop()
 }
 
-func httpServer(httpPort int, stopChan chan interface{}) {
+func httpServer(dbCache *DBCache, signer *URLSigner, httpPort int, stopChan chan interface{}) {
 	defer func() {
 		log.Println("exiting http Server go routine")
 	}()
 
 	server := http.Server{
 		Addr:    fmt.Sprintf(":%d", httpPort),
-		Handler: http.HandlerFunc(ServeHTTP),
+		Handler: newFileHandler(dbCache, signer),
 	}
 
 	go func() {
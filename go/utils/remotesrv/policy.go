@@ -0,0 +1,273 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/creds"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/store/datas"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// BranchPolicy is checked each time a new root is committed to a repository
+type BranchPolicy struct {
+	// ProtectedBranches are the branches which can only be fast-forwarded. Names can use the wildcards of path.Match.
+	ProtectedBranches []string `json:"protected_branches"`
+
+	// ValidateSchemas requires the tables at the head of each branch that is created or moved to have valid schemas
+	ValidateSchemas bool `json:"validate_schemas"`
+
+	// BranchDeleters are the public keys allowed to delete branches. When it isn't given any caller can delete
+	// branches, and when it is empty no one can.
+	BranchDeleters []string `json:"branch_deleters"`
+
+	deleterKIDs map[string]bool
+}
+
+// policyFile is the json form of Policies. Repositories are given in the same way as in an acl file.
+//
+//	{"repos": {"org/*": {"protected_branches": ["master"], "validate_schemas": true, "branch_deleters": ["<public key>"]}}}
+type policyFile struct {
+	Repos map[string]*BranchPolicy `json:"repos"`
+}
+
+// Policies are the branch policies of the repositories on the server
+type Policies struct {
+	repos map[string]*BranchPolicy
+}
+
+// PolicyViolation is the error returned when a commit breaks the branch policy of its repository
+type PolicyViolation struct {
+	Code   codes.Code
+	Reason string
+}
+
+func (pv PolicyViolation) Error() string {
+	return pv.Reason
+}
+
+// LoadPolicies reads Policies from the json file at |path|
+func LoadPolicies(path string) (*Policies, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePolicies(data)
+}
+
+// ParsePolicies parses the json form of Policies
+func ParsePolicies(data []byte) (*Policies, error) {
+	var pf policyFile
+	err := json.Unmarshal(data, &pf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for repo, bp := range pf.Repos {
+		for _, pattern := range bp.ProtectedBranches {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid protected branch '%s' for %s in policies", pattern, repo)
+			}
+		}
+
+		bp.deleterKIDs = make(map[string]bool)
+		for _, pubKeyStr := range bp.BranchDeleters {
+			kid, err := creds.PubKeyStrToKIDStr(pubKeyStr)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid public key '%s' for %s in policies", pubKeyStr, repo)
+			}
+
+			bp.deleterKIDs[kid] = true
+		}
+	}
+
+	return &Policies{pf.Repos}, nil
+}
+
+// Get returns the most specific policy for the repository |org|/|repo|, or nil if there isn't one
+func (p *Policies) Get(org, repo string) *BranchPolicy {
+	if p == nil {
+		return nil
+	}
+
+	for _, pattern := range repoPatterns(org, repo) {
+		if bp, ok := p.repos[pattern]; ok {
+			return bp
+		}
+	}
+
+	return nil
+}
+
+func (bp *BranchPolicy) isProtected(branch string) bool {
+	for _, pattern := range bp.ProtectedBranches {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (bp *BranchPolicy) canDelete(kid string) bool {
+	return bp.BranchDeleters == nil || bp.deleterKIDs[kid]
+}
+
+// Check returns a PolicyViolation if moving the root of a repository from |last| to |curr| breaks the policy. |kid| is
+// the base32 encoded key id of the caller, or "" if callers aren't authenticated.
+func (bp *BranchPolicy) Check(ctx context.Context, vrw types.ValueReadWriter, kid string, last, curr hash.Hash) error {
+	oldHeads, err := branchHeads(ctx, vrw, last)
+
+	if err != nil {
+		return err
+	}
+
+	newHeads, err := branchHeads(ctx, vrw, curr)
+
+	if err != nil {
+		return err
+	}
+
+	for branch, oldHead := range oldHeads {
+		newHead, ok := newHeads[branch]
+
+		if !ok {
+			if !bp.canDelete(kid) {
+				return PolicyViolation{codes.PermissionDenied, fmt.Sprintf("deleting the branch %s is not allowed", branch)}
+			}
+
+			continue
+		}
+
+		if newHead.Equals(oldHead) || !bp.isProtected(branch) {
+			continue
+		}
+
+		oldCommit, err := commitRef(ctx, vrw, oldHead)
+
+		if err != nil {
+			return err
+		}
+
+		newCommit, err := commitRef(ctx, vrw, newHead)
+
+		if err != nil {
+			return err
+		}
+
+		ancestor, ok, err := datas.FindCommonAncestor(ctx, oldCommit, newCommit, vrw)
+
+		if err != nil {
+			return err
+		}
+
+		if !ok || ancestor.TargetHash() != oldHead.TargetHash() {
+			return PolicyViolation{codes.FailedPrecondition, fmt.Sprintf("the branch %s is protected and can only be fast-forwarded", branch)}
+		}
+	}
+
+	if bp.ValidateSchemas {
+		for branch, newHead := range newHeads {
+			if oldHead, ok := oldHeads[branch]; ok && oldHead.Equals(newHead) {
+				continue
+			}
+
+			err = doltdb.ValidateCommit(ctx, vrw, newHead.TargetHash())
+
+			if err != nil {
+				return PolicyViolation{codes.FailedPrecondition, fmt.Sprintf("the head of the branch %s is invalid: %v", branch, err)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// branchHeads returns the heads of the branches in the root of a repository with the hash |root|
+// commitRef returns a ref of commit type to the commit |r| refers to. The heads in the map of datasets are refs of
+// values.
+func commitRef(ctx context.Context, vrw types.ValueReadWriter, r types.Ref) (types.Ref, error) {
+	val, err := vrw.ReadValue(ctx, r.TargetHash())
+
+	if err != nil {
+		return types.Ref{}, err
+	}
+
+	if val == nil {
+		return types.Ref{}, fmt.Errorf("the head %s isn't in the repository", r.TargetHash().String())
+	}
+
+	isCommit, err := datas.IsCommit(val)
+
+	if err != nil {
+		return types.Ref{}, err
+	} else if !isCommit {
+		return types.Ref{}, fmt.Errorf("the head %s isn't a commit", r.TargetHash().String())
+	}
+
+	return types.NewRef(val, vrw.Format())
+}
+
+func branchHeads(ctx context.Context, vrw types.ValueReadWriter, root hash.Hash) (map[string]types.Ref, error) {
+	heads := make(map[string]types.Ref)
+
+	if root.IsEmpty() {
+		return heads, nil
+	}
+
+	val, err := vrw.ReadValue(ctx, root)
+
+	if err != nil {
+		return nil, err
+	}
+
+	datasets, ok := val.(types.Map)
+
+	if !ok {
+		return nil, fmt.Errorf("the root %s isn't a map of datasets", root.String())
+	}
+
+	branchPrefix := ref.PrefixForType(ref.BranchRefType)
+	err = datasets.IterAll(ctx, func(key, value types.Value) error {
+		id := string(key.(types.String))
+
+		if strings.HasPrefix(id, branchPrefix) {
+			heads[strings.TrimPrefix(id, branchPrefix)] = value.(types.Ref)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return heads, nil
+}
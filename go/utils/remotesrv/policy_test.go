@@ -0,0 +1,182 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	remotesapi "github.com/liquidata-inc/dolt/go/gen/proto/dolt/services/remotesapi_v1alpha1"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/remotestorage"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+type testServer struct {
+	httpHost   string
+	client     remotesapi.ChunkStoreServiceClient
+	httpServer *httptest.Server
+	grpcServer *grpc.Server
+	conn       *grpc.ClientConn
+}
+
+// startTestServer starts a remote server which stores its repositories in memory
+func startTestServer(t *testing.T, policies *Policies) *testServer {
+	dbCache := NewCSCache(InMemoryBlobstores())
+	httpServer := httptest.NewServer(newFileHandler(dbCache, nil))
+	httpHost := strings.TrimPrefix(httpServer.URL, "http://")
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	remotesapi.RegisterChunkStoreServiceServer(grpcServer, NewHttpFSBackedChunkStore(httpHost, dbCache, nil, policies))
+	go grpcServer.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+
+	return &testServer{httpHost, remotesapi.NewChunkStoreServiceClient(conn), httpServer, grpcServer, conn}
+}
+
+func (ts *testServer) stop() {
+	ts.conn.Close()
+	ts.grpcServer.Stop()
+	ts.httpServer.Close()
+}
+
+func (ts *testServer) remoteDB(t *testing.T, org, repo string) *doltdb.DoltDB {
+	cs, err := remotestorage.NewDoltChunkStore(context.Background(), types.Format_Default, org, repo, ts.httpHost, ts.client)
+	require.NoError(t, err)
+
+	return doltdb.DoltDBFromCS(cs)
+}
+
+func resolveBranch(t *testing.T, ddb *doltdb.DoltDB, branch string) *doltdb.Commit {
+	cs, err := doltdb.NewCommitSpec("HEAD", branch)
+	require.NoError(t, err)
+	cm, err := ddb.Resolve(context.Background(), cs)
+	require.NoError(t, err)
+
+	return cm
+}
+
+func assertHead(t *testing.T, ts *testServer, org, repo, branch string, expected *doltdb.Commit) {
+	expectedHash, err := expected.HashOf()
+	require.NoError(t, err)
+	actualHash, err := resolveBranch(t, ts.remoteDB(t, org, repo), branch).HashOf()
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedHash, actualHash)
+}
+
+func commitRoot(ddb *doltdb.DoltDB, branch string, root *doltdb.RootValue) (*doltdb.Commit, error) {
+	ctx := context.Background()
+	h, err := ddb.WriteRootValue(ctx, root)
+
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := doltdb.NewCommitMeta("Bill Billerson", "bill@billerson.com", "a commit")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ddb.Commit(ctx, h, ref.NewBranchRef(branch), meta)
+}
+
+func rootWithInvalidSchema(t *testing.T, ddb *doltdb.DoltDB, root *doltdb.RootValue) *doltdb.RootValue {
+	ctx := context.Background()
+	cols, err := schema.NewColCollection(schema.NewColumn("col", 0, types.StringKind, false))
+	require.NoError(t, err)
+
+	// tables must have a primary key
+	schVal, err := encoding.MarshalAsNomsValue(ctx, ddb.ValueReadWriter(), schema.UnkeyedSchemaFromCols(cols))
+	require.NoError(t, err)
+	rows, err := types.NewMap(ctx, ddb.ValueReadWriter())
+	require.NoError(t, err)
+	tbl, err := doltdb.NewTable(ctx, ddb.ValueReadWriter(), schVal, rows)
+	require.NoError(t, err)
+
+	root, err = root.PutTable(ctx, ddb, "test", tbl)
+	require.NoError(t, err)
+
+	return root
+}
+
+func TestBranchPolicies(t *testing.T) {
+	ctx := context.Background()
+	policies, err := ParsePolicies([]byte(`{
+		"repos": {
+			"test-org/*": {"protected_branches": ["master"], "validate_schemas": true, "branch_deleters": []}
+		}
+	}`))
+	require.NoError(t, err)
+
+	ts := startTestServer(t, policies)
+	defer ts.stop()
+
+	ddb := ts.remoteDB(t, "test-org", "test-repo")
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bill@billerson.com"))
+
+	first := resolveBranch(t, ddb, "master")
+	root, err := first.GetRootValue()
+	require.NoError(t, err)
+	second, err := commitRoot(ddb, "master", root)
+	require.NoError(t, err)
+
+	// master can only be fast-forwarded
+	err = ts.remoteDB(t, "test-org", "test-repo").NewBranchAtCommit(ctx, ref.NewBranchRef("master"), first)
+	assert.Error(t, err)
+	assertHead(t, ts, "test-org", "test-repo", "master", second)
+
+	// other branches can be moved anywhere
+	other := ref.NewBranchRef("other")
+	require.NoError(t, ts.remoteDB(t, "test-org", "test-repo").NewBranchAtCommit(ctx, other, second))
+	require.NoError(t, ts.remoteDB(t, "test-org", "test-repo").NewBranchAtCommit(ctx, other, first))
+
+	// no one can delete branches
+	err = ts.remoteDB(t, "test-org", "test-repo").DeleteBranch(ctx, other)
+	assert.Error(t, err)
+	assertHead(t, ts, "test-org", "test-repo", "other", first)
+
+	// the tables of each branch need valid schemas
+	ddb = ts.remoteDB(t, "test-org", "test-repo")
+	_, err = commitRoot(ddb, "other", rootWithInvalidSchema(t, ddb, root))
+	assert.Error(t, err)
+
+	// and none of it applies to repositories without a policy
+	ddb = ts.remoteDB(t, "other-org", "test-repo")
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bill@billerson.com"))
+	first = resolveBranch(t, ddb, "master")
+	root, err = first.GetRootValue()
+	require.NoError(t, err)
+	_, err = commitRoot(ddb, "master", rootWithInvalidSchema(t, ddb, root))
+	require.NoError(t, err)
+	require.NoError(t, ts.remoteDB(t, "other-org", "test-repo").NewBranchAtCommit(ctx, ref.NewBranchRef("master"), first))
+	require.NoError(t, ts.remoteDB(t, "other-org", "test-repo").DeleteBranch(ctx, ref.NewBranchRef("master")))
+}